@@ -0,0 +1,432 @@
+// Package actions changes cluster resources on behalf of the user: it
+// describes each action for its confirmation, checks the access it needs
+// and runs it.
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vladimirvivien/ktop/k8s"
+	"github.com/vladimirvivien/ktop/views/model"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// ErrForbidden is returned by Check when the user may not run an action
+var ErrForbidden = errors.New("forbidden")
+
+// evictionRetryInterval is how long a drain waits before evicting again the
+// pods a PodDisruptionBudget kept; deletionPollInterval how often it lists
+// the node's pods while waiting for the evicted ones to terminate
+var (
+	evictionRetryInterval = 5 * time.Second
+	deletionPollInterval  = 2 * time.Second
+)
+
+// Cluster is the part of k8s.ClusterSource the actions use
+type Cluster interface {
+	CheckAccess(ctx context.Context, access k8s.Access) (bool, string, error)
+	GetPodsOnNode(ctx context.Context, nodeName string) ([]v1.Pod, error)
+	DeletePod(ctx context.Context, namespace, podName string) error
+	EvictPod(ctx context.Context, namespace, podName string) error
+	CordonNode(ctx context.Context, nodeName string, cordon bool) error
+	ScaleWorkload(ctx context.Context, kind, namespace, name string, replicas int32) error
+	RestartWorkload(ctx context.Context, kind, namespace, name string) error
+}
+
+// Kind is what an action does
+type Kind int
+
+const (
+	DeletePod Kind = iota
+	EvictPod
+	Cordon
+	Uncordon
+	Drain
+	Scale
+	Restart
+)
+
+// Action is a change to one pod, node or workload
+type Action struct {
+	Kind      Kind
+	Workload  string // kind of the workload scaled or restarted, e.g. "Deployment"
+	Namespace string // empty for nodes
+	Name      string
+	Replicas  int32 // replicas to scale to
+}
+
+// PodActions returns the actions on a pod
+func PodActions(namespace, name string) []Action {
+	return []Action{
+		{Kind: DeletePod, Namespace: namespace, Name: name},
+		{Kind: EvictPod, Namespace: namespace, Name: name},
+	}
+}
+
+// NodeActions returns the actions on a node: cordon or uncordon, whichever
+// changes it, and drain
+func NodeActions(node *v1.Node) []Action {
+	cordon := Action{Kind: Cordon, Name: node.Name}
+	if node.Spec.Unschedulable {
+		cordon.Kind = Uncordon
+	}
+	return []Action{cordon, {Kind: Drain, Name: node.Name}}
+}
+
+// WorkloadActions returns the restart of a workload, after its scale to
+// each of ReplicaChoices when it has a replica count
+func WorkloadActions(kind, namespace, name string, replicas int32) []Action {
+	var list []Action
+	if kind == model.WorkloadKindDeployment || kind == model.WorkloadKindStatefulSet {
+		for _, n := range ReplicaChoices(replicas) {
+			list = append(list, Action{Kind: Scale, Workload: kind, Namespace: namespace, Name: name, Replicas: n})
+		}
+	}
+	return append(list, Action{Kind: Restart, Workload: kind, Namespace: namespace, Name: name})
+}
+
+// ReplicaChoices returns the replica counts offered to scale from current:
+// one fewer and one more, double, none, and a few small counts
+func ReplicaChoices(current int32) []int32 {
+	seen := map[int32]bool{current: true}
+	var choices []int32
+	for _, n := range []int32{0, 1, 2, 3, 5, 10, current - 1, current + 1, current * 2} {
+		if n < 0 || seen[n] {
+			continue
+		}
+		seen[n] = true
+		choices = append(choices, n)
+	}
+	sort.Slice(choices, func(i, j int) bool { return choices[i] < choices[j] })
+	return choices
+}
+
+// Target names the resource, e.g. "pod default/web-0" or "node worker-1"
+func (a Action) Target() string {
+	switch a.Kind {
+	case DeletePod, EvictPod:
+		return "pod " + a.Namespace + "/" + a.Name
+	case Cordon, Uncordon, Drain:
+		return "node " + a.Name
+	default:
+		return a.Workload + " " + a.Namespace + "/" + a.Name
+	}
+}
+
+// Title is the action in a menu, e.g. "Evict pod"
+func (a Action) Title() string {
+	switch a.Kind {
+	case DeletePod:
+		return "Delete pod"
+	case EvictPod:
+		return "Evict pod"
+	case Cordon:
+		return "Cordon node"
+	case Uncordon:
+		return "Uncordon node"
+	case Drain:
+		return "Drain node"
+	case Scale:
+		return fmt.Sprintf("Scale to %d", a.Replicas)
+	default:
+		return "Rollout restart"
+	}
+}
+
+// Confirmation asks the user to confirm the action and says what it does
+func (a Action) Confirmation() string {
+	switch a.Kind {
+	case DeletePod:
+		return fmt.Sprintf("Delete %s?\nA pod without a controller is not recreated.", a.Target())
+	case EvictPod:
+		return fmt.Sprintf("Evict %s?\nThe eviction is refused if a PodDisruptionBudget does not allow it.", a.Target())
+	case Cordon:
+		return fmt.Sprintf("Cordon %s?\nNo new pods are scheduled on it.", a.Target())
+	case Uncordon:
+		return fmt.Sprintf("Uncordon %s?\nPods can be scheduled on it again.", a.Target())
+	case Drain:
+		return fmt.Sprintf("Drain %s?\nIt is cordoned and its pods are evicted, respecting PodDisruptionBudgets. "+
+			"DaemonSet and static pods stay, as do pods without a controller or with emptyDir data.", a.Target())
+	case Scale:
+		return fmt.Sprintf("Scale %s to %d replicas?", a.Target(), a.Replicas)
+	default:
+		return fmt.Sprintf("Restart %s?\nIts pods are replaced as in a rollout.", a.Target())
+	}
+}
+
+// Progress is shown while the action runs
+func (a Action) Progress() string {
+	switch a.Kind {
+	case DeletePod:
+		return "Deleting " + a.Target() + "..."
+	case EvictPod:
+		return "Evicting " + a.Target() + "..."
+	case Cordon:
+		return "Cordoning " + a.Target() + "..."
+	case Uncordon:
+		return "Uncordoning " + a.Target() + "..."
+	case Drain:
+		return "Draining " + a.Target() + "..."
+	case Scale:
+		return fmt.Sprintf("Scaling %s to %d...", a.Target(), a.Replicas)
+	default:
+		return "Restarting " + a.Target() + "..."
+	}
+}
+
+// Done is shown when the action succeeded
+func (a Action) Done() string {
+	switch a.Kind {
+	case DeletePod:
+		return "Deleted " + a.Target()
+	case EvictPod:
+		return "Evicted " + a.Target()
+	case Cordon:
+		return "Cordoned " + a.Target()
+	case Uncordon:
+		return "Uncordoned " + a.Target()
+	case Drain:
+		return "Drained " + a.Target()
+	case Scale:
+		return fmt.Sprintf("Scaled %s to %d", a.Target(), a.Replicas)
+	default:
+		return "Restarted " + a.Target()
+	}
+}
+
+// Access returns the API requests the action makes. A drain also evicts in
+// the namespaces of the node's pods, which Check adds once they are listed.
+func (a Action) Access() []k8s.Access {
+	switch a.Kind {
+	case DeletePod:
+		return []k8s.Access{{Verb: "delete", Resource: "pods", Namespace: a.Namespace, Name: a.Name}}
+	case EvictPod:
+		return []k8s.Access{{Verb: "create", Resource: "pods", Subresource: "eviction", Namespace: a.Namespace, Name: a.Name}}
+	case Cordon, Uncordon:
+		return []k8s.Access{{Verb: "patch", Resource: "nodes", Name: a.Name}}
+	case Drain:
+		return []k8s.Access{
+			{Verb: "patch", Resource: "nodes", Name: a.Name},
+			{Verb: "list", Resource: "pods"},
+		}
+	case Scale:
+		return []k8s.Access{{Verb: "patch", Group: "apps", Resource: workloadResource(a.Workload),
+			Subresource: "scale", Namespace: a.Namespace, Name: a.Name}}
+	default:
+		return []k8s.Access{{Verb: "patch", Group: "apps", Resource: workloadResource(a.Workload),
+			Namespace: a.Namespace, Name: a.Name}}
+	}
+}
+
+// workloadResource returns the API resource of a workload kind
+func workloadResource(kind string) string {
+	return strings.ToLower(kind) + "s"
+}
+
+// Check asks the API server whether the user may make each request of the
+// action, and returns an error wrapping ErrForbidden for the first one
+// denied. For a drain, the evictions are checked in each namespace of the
+// pods it would evict.
+func Check(ctx context.Context, c Cluster, a Action) error {
+	if err := checkAccess(ctx, c, a.Access()); err != nil {
+		return err
+	}
+	if a.Kind != Drain {
+		return nil
+	}
+
+	pods, err := c.GetPodsOnNode(ctx, a.Name)
+	if err != nil {
+		return fmt.Errorf("listing pods: %w", err)
+	}
+	evict, _ := DrainPods(pods)
+	var access []k8s.Access
+	seen := make(map[string]bool)
+	for _, pod := range evict {
+		if !seen[pod.Namespace] {
+			seen[pod.Namespace] = true
+			access = append(access, k8s.Access{Verb: "create", Resource: "pods", Subresource: "eviction", Namespace: pod.Namespace})
+		}
+	}
+	return checkAccess(ctx, c, access)
+}
+
+func checkAccess(ctx context.Context, c Cluster, list []k8s.Access) error {
+	for _, access := range list {
+		allowed, reason, err := c.CheckAccess(ctx, access)
+		if err != nil {
+			return fmt.Errorf("checking access to %s: %w", access, err)
+		}
+		if !allowed {
+			if reason != "" {
+				return fmt.Errorf("%w: cannot %s (%s)", ErrForbidden, access, reason)
+			}
+			return fmt.Errorf("%w: cannot %s", ErrForbidden, access)
+		}
+	}
+	return nil
+}
+
+// Run runs the action. A drain reports its progress to progress, which may
+// be nil.
+func Run(ctx context.Context, c Cluster, a Action, progress func(string)) error {
+	switch a.Kind {
+	case DeletePod:
+		return c.DeletePod(ctx, a.Namespace, a.Name)
+	case EvictPod:
+		return c.EvictPod(ctx, a.Namespace, a.Name)
+	case Cordon:
+		return c.CordonNode(ctx, a.Name, true)
+	case Uncordon:
+		return c.CordonNode(ctx, a.Name, false)
+	case Drain:
+		if progress == nil {
+			progress = func(string) {}
+		}
+		return drain(ctx, c, a.Name, progress)
+	case Scale:
+		return c.ScaleWorkload(ctx, a.Workload, a.Namespace, a.Name, a.Replicas)
+	default:
+		return c.RestartWorkload(ctx, a.Workload, a.Namespace, a.Name)
+	}
+}
+
+// drain cordons a node, evicts its pods and waits for them to terminate,
+// as kubectl drain does. Pods kept by a PodDisruptionBudget are evicted
+// again every evictionRetryInterval until ctx is done.
+func drain(ctx context.Context, c Cluster, nodeName string, progress func(string)) error {
+	if err := c.CordonNode(ctx, nodeName, true); err != nil {
+		return fmt.Errorf("cordoning: %w", err)
+	}
+	pods, err := c.GetPodsOnNode(ctx, nodeName)
+	if err != nil {
+		return fmt.Errorf("listing pods: %w", err)
+	}
+
+	pending, skipped := DrainPods(pods)
+	if len(skipped) > 0 {
+		progress(fmt.Sprintf("Draining node %s: leaving %s", nodeName, strings.Join(skipped, ", ")))
+	}
+	total := len(pending)
+	var evicted []v1.Pod
+	for len(pending) > 0 {
+		var blocked []v1.Pod
+		for _, pod := range pending {
+			err := c.EvictPod(ctx, pod.Namespace, pod.Name)
+			switch {
+			case err == nil:
+				evicted = append(evicted, pod)
+			case apierrors.IsNotFound(err):
+			case apierrors.IsTooManyRequests(err):
+				blocked = append(blocked, pod)
+			default:
+				return fmt.Errorf("evicting pod %s/%s: %w", pod.Namespace, pod.Name, err)
+			}
+		}
+		pending = blocked
+		if len(pending) == 0 {
+			break
+		}
+
+		progress(fmt.Sprintf("Draining node %s: %d of %d pods evicted, %d waiting on a PodDisruptionBudget",
+			nodeName, total-len(pending), total, len(pending)))
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%d of %d pods evicted, PodDisruptionBudgets kept %s", total-len(pending), total, podNames(pending))
+		case <-time.After(evictionRetryInterval):
+		}
+	}
+	return waitForDeletion(ctx, c, nodeName, evicted, progress)
+}
+
+// waitForDeletion lists the node's pods every deletionPollInterval until
+// none of the evicted ones is left or ctx is done. A pod recreated under the
+// same name is another pod, told apart by its UID.
+func waitForDeletion(ctx context.Context, c Cluster, nodeName string, evicted []v1.Pod, progress func(string)) error {
+	uids := make(map[types.UID]bool, len(evicted))
+	for _, pod := range evicted {
+		uids[pod.UID] = true
+	}
+	reported := 0
+	for {
+		pods, err := c.GetPodsOnNode(ctx, nodeName)
+		if err != nil {
+			return fmt.Errorf("listing pods: %w", err)
+		}
+		var terminating []v1.Pod
+		for _, pod := range pods {
+			if uids[pod.UID] {
+				terminating = append(terminating, pod)
+			}
+		}
+		if len(terminating) == 0 {
+			return nil
+		}
+
+		if len(terminating) != reported {
+			reported = len(terminating)
+			progress(fmt.Sprintf("Draining node %s: waiting for %d pods to terminate", nodeName, reported))
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("pods still terminating: %s", podNames(terminating))
+		case <-time.After(deletionPollInterval):
+		}
+	}
+}
+
+func podNames(pods []v1.Pod) string {
+	names := make([]string, len(pods))
+	for i, pod := range pods {
+		names[i] = pod.Namespace + "/" + pod.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+// DrainPods returns the pods a drain evicts: all but the finished and
+// terminating ones, static (mirror) pods and the pods of DaemonSets, which
+// would be scheduled on the node again. As kubectl drain does without
+// --force and --delete-emptydir-data, it also leaves pods without a
+// controller, which would not be recreated, and pods with emptyDir volumes,
+// whose data would be lost; these are returned in skipped, each as
+// namespace/name and the reason.
+func DrainPods(pods []v1.Pod) (evict []v1.Pod, skipped []string) {
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed || pod.DeletionTimestamp != nil {
+			continue
+		}
+		if _, mirror := pod.Annotations[v1.MirrorPodAnnotationKey]; mirror {
+			continue
+		}
+		owner := metav1.GetControllerOf(&pod)
+		switch {
+		case owner != nil && owner.Kind == model.WorkloadKindDaemonSet:
+			continue
+		case owner == nil:
+			skipped = append(skipped, pod.Namespace+"/"+pod.Name+" (no controller)")
+			continue
+		case hasEmptyDir(&pod):
+			skipped = append(skipped, pod.Namespace+"/"+pod.Name+" (emptyDir data)")
+			continue
+		}
+		evict = append(evict, pod)
+	}
+	return evict, skipped
+}
+
+func hasEmptyDir(pod *v1.Pod) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vladimirvivien/ktop/k8s"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// fakeCluster records the changes made to it. It refuses the eviction of a
+// pod in refusals that many times, as a PodDisruptionBudget would, or
+// always when the count is negative. An evicted pod in terminating is still
+// listed that many times, or always when the count is negative.
+type fakeCluster struct {
+	denied      map[string]string // access → reason
+	pods        []v1.Pod
+	refusals    map[string]int
+	terminating map[string]int
+	cordoned    map[string]bool
+	evicted     []string
+}
+
+func newFakeCluster(pods ...v1.Pod) *fakeCluster {
+	return &fakeCluster{pods: pods, denied: map[string]string{}, refusals: map[string]int{},
+		terminating: map[string]int{}, cordoned: map[string]bool{}}
+}
+
+func (f *fakeCluster) CheckAccess(_ context.Context, access k8s.Access) (bool, string, error) {
+	reason, denied := f.denied[access.String()]
+	return !denied, reason, nil
+}
+
+func (f *fakeCluster) GetPodsOnNode(context.Context, string) ([]v1.Pod, error) {
+	var pods []v1.Pod
+	for _, p := range f.pods {
+		if slices.Contains(f.evicted, p.Namespace+"/"+p.Name) {
+			n := f.terminating[p.Name]
+			if n == 0 {
+				continue
+			}
+			f.terminating[p.Name] = n - 1
+		}
+		pods = append(pods, p)
+	}
+	return pods, nil
+}
+
+func (f *fakeCluster) DeletePod(context.Context, string, string) error { return nil }
+
+func (f *fakeCluster) EvictPod(_ context.Context, namespace, podName string) error {
+	if n := f.refusals[podName]; n != 0 {
+		f.refusals[podName] = n - 1
+		return apierrors.NewTooManyRequests("Cannot evict pod as it would violate the pod's disruption budget.", 0)
+	}
+	f.evicted = append(f.evicted, namespace+"/"+podName)
+	return nil
+}
+
+func (f *fakeCluster) CordonNode(_ context.Context, nodeName string, cordon bool) error {
+	f.cordoned[nodeName] = cordon
+	return nil
+}
+
+func (f *fakeCluster) ScaleWorkload(context.Context, string, string, string, int32) error { return nil }
+
+func (f *fakeCluster) RestartWorkload(context.Context, string, string, string) error { return nil }
+
+func pod(name string, mutate ...func(*v1.Pod)) v1.Pod {
+	p := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, UID: types.UID(name)},
+		Status:     v1.PodStatus{Phase: v1.PodRunning},
+	}
+	for _, m := range mutate {
+		m(&p)
+	}
+	return p
+}
+
+func ownedBy(kind string) func(*v1.Pod) {
+	return func(p *v1.Pod) {
+		controller := true
+		p.OwnerReferences = []metav1.OwnerReference{{Kind: kind, Name: "owner", Controller: &controller}}
+	}
+}
+
+func inNamespace(namespace string) func(*v1.Pod) {
+	return func(p *v1.Pod) { p.Namespace = namespace }
+}
+
+func TestDrainPods(t *testing.T) {
+	pods := []v1.Pod{
+		pod("web-0", ownedBy("ReplicaSet")),
+		pod("bare"),
+		pod("cache", ownedBy("ReplicaSet"), func(p *v1.Pod) {
+			p.Spec.Volumes = []v1.Volume{{Name: "tmp", VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}}}}
+		}),
+		pod("fluentd", ownedBy("DaemonSet")),
+		pod("etcd", func(p *v1.Pod) { p.Annotations = map[string]string{v1.MirrorPodAnnotationKey: "x"} }),
+		pod("job", func(p *v1.Pod) { p.Status.Phase = v1.PodSucceeded }),
+		pod("leaving", func(p *v1.Pod) { p.DeletionTimestamp = &metav1.Time{Time: time.Now()} }),
+	}
+
+	evict, skipped := DrainPods(pods)
+	var names []string
+	for _, p := range evict {
+		names = append(names, p.Name)
+	}
+	if !reflect.DeepEqual(names, []string{"web-0"}) {
+		t.Errorf("Expected only web-0 evicted, got %v", names)
+	}
+	if want := []string{"default/bare (no controller)", "default/cache (emptyDir data)"}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("Expected %v skipped, got %v", want, skipped)
+	}
+}
+
+func TestRun_Drain(t *testing.T) {
+	defer func(interval time.Duration) { evictionRetryInterval = interval }(evictionRetryInterval)
+	defer func(interval time.Duration) { deletionPollInterval = interval }(deletionPollInterval)
+	evictionRetryInterval, deletionPollInterval = time.Millisecond, time.Millisecond
+
+	c := newFakeCluster(pod("web-0", ownedBy("ReplicaSet")), pod("db-0", ownedBy("StatefulSet")),
+		pod("fluentd", ownedBy("DaemonSet")), pod("bare"))
+	c.refusals["db-0"] = 1     // goes on the second try
+	c.terminating["web-0"] = 2 // listed twice more after its eviction
+	var updates []string
+
+	err := Run(context.Background(), c, Action{Kind: Drain, Name: "worker-1"}, func(msg string) { updates = append(updates, msg) })
+	if err != nil {
+		t.Fatalf("Drain failed: %v", err)
+	}
+	if !c.cordoned["worker-1"] {
+		t.Error("Expected the node cordoned")
+	}
+	if !reflect.DeepEqual(c.evicted, []string{"default/web-0", "default/db-0"}) {
+		t.Errorf("Unexpected evictions %v", c.evicted)
+	}
+	want := []string{
+		"Draining node worker-1: leaving default/bare (no controller)",
+		"Draining node worker-1: 1 of 2 pods evicted, 1 waiting on a PodDisruptionBudget",
+		"Draining node worker-1: waiting for 1 pods to terminate",
+	}
+	if !reflect.DeepEqual(updates, want) {
+		t.Errorf("Expected progress %v, got %v", want, updates)
+	}
+}
+
+func TestRun_DrainWaitsForTermination(t *testing.T) {
+	defer func(interval time.Duration) { deletionPollInterval = interval }(deletionPollInterval)
+	deletionPollInterval = time.Millisecond
+
+	c := newFakeCluster(pod("web-0", ownedBy("ReplicaSet")))
+	c.terminating["web-0"] = -1
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := Run(ctx, c, Action{Kind: Drain, Name: "worker-1"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "still terminating: default/web-0") {
+		t.Fatalf("Expected the drain to time out on web-0, got %v", err)
+	}
+}
+
+func TestRun_DrainBlocked(t *testing.T) {
+	defer func(interval time.Duration) { evictionRetryInterval = interval }(evictionRetryInterval)
+	evictionRetryInterval = time.Millisecond
+
+	c := newFakeCluster(pod("web-0", ownedBy("ReplicaSet")), pod("db-0", ownedBy("StatefulSet")))
+	c.refusals["db-0"] = -1
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := Run(ctx, c, Action{Kind: Drain, Name: "worker-1"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "default/db-0") {
+		t.Fatalf("Expected the drain to fail on db-0, got %v", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	c := newFakeCluster()
+	evict := Action{Kind: EvictPod, Namespace: "default", Name: "web-0"}
+	if err := Check(context.Background(), c, evict); err != nil {
+		t.Fatalf("Expected the eviction allowed, got %v", err)
+	}
+
+	c.denied["create pods/eviction in default"] = "no RBAC policy matched"
+	err := Check(context.Background(), c, evict)
+	if !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Expected ErrForbidden, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "no RBAC policy matched") {
+		t.Errorf("Expected the reason in %q", err)
+	}
+}
+
+func TestCheck_DrainNamespaces(t *testing.T) {
+	c := newFakeCluster(pod("web-0", ownedBy("ReplicaSet")), pod("pg-0", ownedBy("StatefulSet"), inNamespace("db")),
+		pod("bare", inNamespace("sandbox")))
+	drain := Action{Kind: Drain, Name: "worker-1"}
+	c.denied["create pods/eviction in sandbox"] = "no RBAC policy matched"
+	if err := Check(context.Background(), c, drain); err != nil {
+		t.Fatalf("Expected the drain allowed, the sandbox pod is not evicted, got %v", err)
+	}
+
+	c.denied["create pods/eviction in db"] = "no RBAC policy matched"
+	err := Check(context.Background(), c, drain)
+	if !errors.Is(err, ErrForbidden) || !strings.Contains(err.Error(), "pods/eviction in db") {
+		t.Fatalf("Expected the eviction in db forbidden, got %v", err)
+	}
+}
+
+func TestAccess(t *testing.T) {
+	scale := Action{Kind: Scale, Workload: "StatefulSet", Namespace: "db", Name: "pg", Replicas: 3}
+	want := []k8s.Access{{Verb: "patch", Group: "apps", Resource: "statefulsets", Subresource: "scale", Namespace: "db", Name: "pg"}}
+	if got := scale.Access(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+	if got := scale.Access()[0].String(); got != "patch statefulsets.apps/scale in db" {
+		t.Errorf("Unexpected access %q", got)
+	}
+}
+
+func TestWorkloadActions(t *testing.T) {
+	if got := ReplicaChoices(3); !reflect.DeepEqual(got, []int32{0, 1, 2, 4, 5, 6, 10}) {
+		t.Errorf("Unexpected choices from 3: %v", got)
+	}
+	if got := ReplicaChoices(0); !reflect.DeepEqual(got, []int32{1, 2, 3, 5, 10}) {
+		t.Errorf("Unexpected choices from 0: %v", got)
+	}
+
+	list := WorkloadActions("DaemonSet", "kube-system", "fluentd", 0)
+	if len(list) != 1 || list[0].Kind != Restart {
+		t.Errorf("Expected only a restart for a DaemonSet, got %+v", list)
+	}
+	list = WorkloadActions("Deployment", "default", "web", 3)
+	if last := list[len(list)-1]; last.Kind != Restart || list[0].Kind != Scale {
+		t.Errorf("Expected scales then a restart, got %+v", list)
+	}
+}
@@ -0,0 +1,328 @@
+package alerts
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vladimirvivien/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// maxHistory bounds the number of resolved alerts kept for display
+const maxHistory = 100
+
+// Alert is a rule firing for one node or pod
+type Alert struct {
+	Rule      string
+	Severity  Severity
+	Target    Target
+	Subject   string // node name or namespace/pod
+	Condition Condition
+	Value     float64
+
+	FiredAt    time.Time
+	ResolvedAt time.Time // zero while firing
+}
+
+// Firing reports whether the alert has not been resolved
+func (a Alert) Firing() bool {
+	return a.ResolvedAt.IsZero()
+}
+
+// Message describes the alert, e.g. "node-1: cpu 93% (cpu > 90%)"
+func (a Alert) Message() string {
+	return fmt.Sprintf("%s: %s %s (%s)", a.Subject, a.Condition.Metric, a.Condition.FormatValue(a.Value), a.Condition)
+}
+
+// sample is one observation of a counter metric
+type sample struct {
+	at    time.Time
+	value float64
+}
+
+// state tracks one rule against one subject between evaluations
+type state struct {
+	target       Target
+	pendingSince time.Time
+	samples      []sample
+	alert        *Alert
+}
+
+// Engine evaluates rules against the models delivered on each refresh. It
+// is safe for concurrent use.
+type Engine struct {
+	mu      sync.Mutex
+	rules   []Rule
+	states  map[string]*state // keyed by rule name and subject
+	history []Alert
+	notify  func([]Alert)
+	now     func() time.Time
+}
+
+// NewEngine returns an engine for rules. An engine without rules never
+// fires.
+func NewEngine(rules []Rule) *Engine {
+	return &Engine{
+		rules:  rules,
+		states: make(map[string]*state),
+		now:    time.Now,
+	}
+}
+
+// SetNotifyFunc registers fn to receive the alerts that fired or resolved
+// during an evaluation. It is called outside the engine lock.
+func (e *Engine) SetNotifyFunc(fn func([]Alert)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.notify = fn
+}
+
+// Rules returns the rules being evaluated
+func (e *Engine) Rules() []Rule {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Rule(nil), e.rules...)
+}
+
+// Reset clears firing alerts, pending state and history, e.g. after
+// switching to another cluster.
+func (e *Engine) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.states = make(map[string]*state)
+	e.history = nil
+}
+
+// Firing returns the alerts currently firing, critical first then by time
+func (e *Engine) Firing() []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var firing []Alert
+	for _, s := range e.states {
+		if s.alert != nil {
+			firing = append(firing, *s.alert)
+		}
+	}
+	sort.Slice(firing, func(i, j int) bool {
+		if firing[i].Severity != firing[j].Severity {
+			return firing[i].Severity == SeverityCritical
+		}
+		if !firing[i].FiredAt.Equal(firing[j].FiredAt) {
+			return firing[i].FiredAt.After(firing[j].FiredAt)
+		}
+		return firing[i].Subject < firing[j].Subject
+	})
+	return firing
+}
+
+// History returns resolved alerts, most recent first
+func (e *Engine) History() []Alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	history := make([]Alert, len(e.history))
+	for i, a := range e.history {
+		history[len(e.history)-1-i] = a
+	}
+	return history
+}
+
+// EvaluateNodes evaluates the node rules against nodes. Nodes missing
+// from the list resolve their alerts.
+func (e *Engine) EvaluateNodes(nodes []model.NodeModel) {
+	values := make(map[string]func(string, time.Time) (float64, bool), len(nodes))
+	for i := range nodes {
+		node := &nodes[i]
+		values[node.Name] = func(metric string, _ time.Time) (float64, bool) {
+			return nodeValue(node, metric)
+		}
+	}
+	e.evaluate(TargetNode, values)
+}
+
+// EvaluatePods evaluates the pod rules against pods. Pods missing from
+// the list resolve their alerts.
+func (e *Engine) EvaluatePods(pods []model.PodModel) {
+	values := make(map[string]func(string, time.Time) (float64, bool), len(pods))
+	for i := range pods {
+		pod := &pods[i]
+		values[pod.Namespace+"/"+pod.Name] = func(metric string, since time.Time) (float64, bool) {
+			return podValue(pod, metric, since)
+		}
+	}
+	e.evaluate(TargetPod, values)
+}
+
+// evaluate applies the rules for target to each subject's values. Event
+// metrics count the events since the start of the rule's window.
+func (e *Engine) evaluate(target Target, subjects map[string]func(string, time.Time) (float64, bool)) {
+	e.mu.Lock()
+	now := e.now()
+	var changed []Alert
+	seen := make(map[string]bool)
+
+	for _, rule := range e.rules {
+		if rule.Target != target {
+			continue
+		}
+		m := metrics[rule.Condition.Metric]
+		window := rule.Window
+		if m.event && window == 0 {
+			window = eventWindow
+		}
+		since := now.Add(-window)
+		for subject, valueOf := range subjects {
+			// A subject whose value is unavailable (e.g. no metrics yet)
+			// keeps its current state
+			key := rule.Name + "|" + subject
+			seen[key] = true
+			value, ok := valueOf(rule.Condition.Metric, since)
+			if !ok {
+				continue
+			}
+			s := e.states[key]
+			if s == nil {
+				s = &state{target: target}
+				e.states[key] = s
+			}
+			if m.counter && rule.Window > 0 {
+				value = s.increase(now, value, rule.Window)
+			}
+
+			if !rule.Condition.Matches(value) {
+				s.pendingSince = time.Time{}
+				if s.alert != nil {
+					changed = append(changed, e.resolve(s, now))
+				}
+				continue
+			}
+			if s.alert != nil {
+				s.alert.Value = value
+				continue
+			}
+			if s.pendingSince.IsZero() {
+				s.pendingSince = now
+			}
+			if now.Sub(s.pendingSince) < rule.For {
+				continue
+			}
+			s.alert = &Alert{
+				Rule:      rule.Name,
+				Severity:  rule.Severity,
+				Target:    rule.Target,
+				Subject:   subject,
+				Condition: rule.Condition,
+				Value:     value,
+				FiredAt:   now,
+			}
+			changed = append(changed, *s.alert)
+		}
+	}
+
+	// Subjects that are gone (node removed, pod deleted) resolve
+	for key, s := range e.states {
+		if seen[key] || s.target != target {
+			continue
+		}
+		if s.alert != nil {
+			changed = append(changed, e.resolve(s, now))
+		}
+		delete(e.states, key)
+	}
+
+	notify := e.notify
+	e.mu.Unlock()
+
+	if notify != nil && len(changed) > 0 {
+		notify(changed)
+	}
+}
+
+// resolve moves the state's alert into history. The caller holds e.mu.
+func (e *Engine) resolve(s *state, now time.Time) Alert {
+	resolved := *s.alert
+	resolved.ResolvedAt = now
+	s.alert = nil
+	e.history = append(e.history, resolved)
+	if len(e.history) > maxHistory {
+		e.history = e.history[len(e.history)-maxHistory:]
+	}
+	return resolved
+}
+
+// increase records value and returns how much it grew within window. A
+// drop means the counter was reset (e.g. the pod was recreated) and
+// starts the window over.
+func (s *state) increase(now time.Time, value float64, window time.Duration) float64 {
+	if n := len(s.samples); n > 0 && value < s.samples[n-1].value {
+		s.samples = nil
+	}
+	s.samples = append(s.samples, sample{at: now, value: value})
+
+	cutoff := now.Add(-window)
+	i := 0
+	for i < len(s.samples)-1 && s.samples[i].at.Before(cutoff) {
+		i++
+	}
+	s.samples = s.samples[i:]
+	return value - s.samples[0].value
+}
+
+func nodeValue(node *model.NodeModel, metric string) (float64, bool) {
+	switch metric {
+	case "cpu":
+		return percent(node.UsageCpuQty, node.AllocatableCpuQty)
+	case "memory":
+		return percent(node.UsageMemQty, node.AllocatableMemQty)
+	case "restarts":
+		return float64(node.Restarts), true
+	case "notready":
+		return boolValue(node.Status != "Ready"), true
+	case "pressure":
+		return float64(len(node.Pressures)), true
+	}
+	return 0, false
+}
+
+func podValue(pod *model.PodModel, metric string, since time.Time) (float64, bool) {
+	switch metric {
+	case "cpu":
+		return percent(pod.PodUsageCpuQty, pod.PodRequestedCpuQty)
+	case "memory":
+		return percent(pod.PodUsageMemQty, pod.PodRequestedMemQty)
+	case "restarts":
+		return float64(pod.Restarts), true
+	case "oomkilled":
+		killed := 0
+		for _, at := range pod.OOMKills {
+			if at.After(since) {
+				killed++
+			}
+		}
+		return float64(killed), true
+	case "notready":
+		// Completed pods are expected to have no ready containers
+		if pod.Status == "Completed" || pod.Status == "Succeeded" {
+			return 0, true
+		}
+		return boolValue(pod.ReadyContainers < pod.TotalContainers), true
+	}
+	return 0, false
+}
+
+// percent returns used as a percentage of total, or false when either is
+// unknown or total is zero
+func percent(used, total *resource.Quantity) (float64, bool) {
+	if used == nil || total == nil || total.IsZero() {
+		return 0, false
+	}
+	return float64(used.MilliValue()) / float64(total.MilliValue()) * 100, true
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,245 @@
+package alerts
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vladimirvivien/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+func qty(s string) *resource.Quantity {
+	q := resource.MustParse(s)
+	return &q
+}
+
+func node(name, cpu string) model.NodeModel {
+	return model.NodeModel{
+		Name:              name,
+		Status:            "Ready",
+		UsageCpuQty:       qty(cpu),
+		AllocatableCpuQty: qty("1"),
+		UsageMemQty:       qty("1Gi"),
+		AllocatableMemQty: qty("4Gi"),
+	}
+}
+
+// newTestEngine returns an engine with a clock the test advances and a
+// record of every notification
+func newTestEngine(rules ...Rule) (*Engine, *time.Time, *[]Alert) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	e := NewEngine(rules)
+	e.now = func() time.Time { return now }
+	var notified []Alert
+	e.SetNotifyFunc(func(changed []Alert) {
+		notified = append(notified, changed...)
+	})
+	return e, &now, &notified
+}
+
+func TestEngine_ForDuration(t *testing.T) {
+	rule := DefaultRules()[0] // NodeCPUHigh: cpu > 90 for 2m
+	e, now, notified := newTestEngine(rule)
+
+	e.EvaluateNodes([]model.NodeModel{node("node-1", "950m"), node("node-2", "100m")})
+	if len(e.Firing()) != 0 {
+		t.Fatal("Expected no alert before the for duration elapsed")
+	}
+
+	*now = now.Add(time.Minute)
+	e.EvaluateNodes([]model.NodeModel{node("node-1", "950m"), node("node-2", "100m")})
+	if len(e.Firing()) != 0 {
+		t.Fatal("Expected no alert after 1m")
+	}
+
+	*now = now.Add(time.Minute)
+	e.EvaluateNodes([]model.NodeModel{node("node-1", "960m"), node("node-2", "100m")})
+	firing := e.Firing()
+	if len(firing) != 1 || firing[0].Subject != "node-1" || firing[0].Rule != "NodeCPUHigh" {
+		t.Fatalf("Expected NodeCPUHigh firing for node-1, got %+v", firing)
+	}
+	if got := firing[0].Message(); got != "node-1: cpu 96% (cpu > 90%)" {
+		t.Errorf("Unexpected message %q", got)
+	}
+	if len(*notified) != 1 || !(*notified)[0].Firing() {
+		t.Fatalf("Expected one firing notification, got %+v", *notified)
+	}
+
+	// Dropping below the threshold resolves into history
+	*now = now.Add(time.Minute)
+	e.EvaluateNodes([]model.NodeModel{node("node-1", "500m"), node("node-2", "100m")})
+	if len(e.Firing()) != 0 {
+		t.Error("Expected alert to resolve")
+	}
+	history := e.History()
+	if len(history) != 1 || history[0].Firing() || !history[0].ResolvedAt.Equal(*now) {
+		t.Fatalf("Expected one resolved alert in history, got %+v", history)
+	}
+	if len(*notified) != 2 || (*notified)[1].Firing() {
+		t.Errorf("Expected a resolved notification, got %+v", *notified)
+	}
+}
+
+func TestEngine_PendingResetsWhenConditionClears(t *testing.T) {
+	e, now, _ := newTestEngine(DefaultRules()[0])
+
+	e.EvaluateNodes([]model.NodeModel{node("node-1", "950m")})
+	*now = now.Add(90 * time.Second)
+	e.EvaluateNodes([]model.NodeModel{node("node-1", "100m")})
+	*now = now.Add(time.Minute)
+	e.EvaluateNodes([]model.NodeModel{node("node-1", "950m")})
+	*now = now.Add(time.Minute)
+	e.EvaluateNodes([]model.NodeModel{node("node-1", "950m")})
+	if len(e.Firing()) != 0 {
+		t.Error("Expected the for duration to restart after the condition cleared")
+	}
+}
+
+func TestEngine_RestartsWithinWindow(t *testing.T) {
+	rule := Rule{
+		Name:      "PodRestarting",
+		Target:    TargetPod,
+		Condition: Condition{Metric: "restarts", Op: ">", Threshold: 3},
+		Severity:  SeverityWarning,
+		Window:    10 * time.Minute,
+	}
+	e, now, _ := newTestEngine(rule)
+	pod := func(restarts int) []model.PodModel {
+		return []model.PodModel{{Namespace: "default", Name: "web", Restarts: restarts}}
+	}
+
+	// Restarts accumulated before ktop started don't count
+	e.EvaluatePods(pod(50))
+	if len(e.Firing()) != 0 {
+		t.Fatal("Expected no alert for restarts before the first sample")
+	}
+
+	*now = now.Add(5 * time.Minute)
+	e.EvaluatePods(pod(54))
+	firing := e.Firing()
+	if len(firing) != 1 || firing[0].Subject != "default/web" || firing[0].Value != 4 {
+		t.Fatalf("Expected alert for 4 restarts in window, got %+v", firing)
+	}
+
+	// Once the restarts age out of the window the alert resolves
+	*now = now.Add(11 * time.Minute)
+	e.EvaluatePods(pod(54))
+	if len(e.Firing()) != 0 {
+		t.Error("Expected alert to resolve once restarts left the window")
+	}
+
+	// A recreated pod resets the counter instead of going negative
+	*now = now.Add(time.Minute)
+	e.EvaluatePods(pod(0))
+	*now = now.Add(time.Minute)
+	e.EvaluatePods(pod(2))
+	if len(e.Firing()) != 0 {
+		t.Error("Expected no alert after counter reset")
+	}
+}
+
+func TestEngine_PodMetrics(t *testing.T) {
+	rules := []Rule{
+		{Name: "PodOOMKilled", Target: TargetPod, Severity: SeverityCritical,
+			Condition: Condition{Metric: "oomkilled", Op: ">", Threshold: 0}},
+		{Name: "PodMemoryHigh", Target: TargetPod, Severity: SeverityWarning,
+			Condition: Condition{Metric: "memory", Op: ">", Threshold: 90}},
+	}
+	e, clock, _ := newTestEngine(rules...)
+	now := *clock
+
+	e.EvaluatePods([]model.PodModel{
+		{Namespace: "default", Name: "oom", OOMKills: []time.Time{now.Add(-time.Minute)}},
+		{Namespace: "default", Name: "hungry", PodUsageMemQty: qty("950Mi"), PodRequestedMemQty: qty("1Gi")},
+		{Namespace: "default", Name: "no-requests", PodUsageMemQty: qty("4Gi")},
+	})
+
+	firing := e.Firing()
+	if len(firing) != 2 {
+		t.Fatalf("Expected 2 alerts, got %+v", firing)
+	}
+	if firing[0].Rule != "PodOOMKilled" || firing[0].Severity != SeverityCritical {
+		t.Errorf("Expected critical alert first, got %+v", firing[0])
+	}
+	if firing[1].Subject != "default/hungry" {
+		t.Errorf("Expected memory alert for default/hungry, got %+v", firing[1])
+	}
+}
+
+func TestEngine_OOMKillsWithinWindow(t *testing.T) {
+	rule := Rule{Name: "PodOOMKilled", Target: TargetPod, Severity: SeverityCritical,
+		Condition: Condition{Metric: "oomkilled", Op: ">", Threshold: 0}}
+	e, now, _ := newTestEngine(rule)
+	pod := func(killed ...time.Time) []model.PodModel {
+		return []model.PodModel{{Namespace: "default", Name: "web", OOMKills: killed}}
+	}
+
+	// A kill older than the window, kept as the last termination, is history
+	e.EvaluatePods(pod(now.Add(-time.Hour)))
+	if len(e.Firing()) != 0 {
+		t.Fatal("Expected no alert for an OOM kill before the window")
+	}
+
+	killed := *now
+	*now = now.Add(time.Minute)
+	e.EvaluatePods(pod(killed))
+	if firing := e.Firing(); len(firing) != 1 || firing[0].Value != 1 {
+		t.Fatalf("Expected alert for a recent OOM kill, got %+v", firing)
+	}
+
+	// The same termination stops counting once it leaves the window
+	*now = now.Add(eventWindow)
+	e.EvaluatePods(pod(killed))
+	if len(e.Firing()) != 0 {
+		t.Error("Expected alert to resolve once the OOM kill left the window")
+	}
+}
+
+func TestEngine_RemovedSubjectsResolve(t *testing.T) {
+	rule := Rule{Name: "NodeNotReady", Target: TargetNode, Severity: SeverityCritical,
+		Condition: Condition{Metric: "notready", Op: ">", Threshold: 0}}
+	podRule := Rule{Name: "PodOOMKilled", Target: TargetPod, Severity: SeverityCritical,
+		Condition: Condition{Metric: "oomkilled", Op: ">", Threshold: 0}}
+	e, now, notified := newTestEngine(rule, podRule)
+
+	down := node("node-1", "100m")
+	down.Status = "NotReady"
+	e.EvaluateNodes([]model.NodeModel{down})
+	e.EvaluatePods([]model.PodModel{{Namespace: "default", Name: "oom", OOMKills: []time.Time{*now}}})
+	if len(e.Firing()) != 2 {
+		t.Fatalf("Expected node and pod alerts, got %+v", e.Firing())
+	}
+
+	// A node refresh doesn't touch pod alerts
+	e.EvaluateNodes(nil)
+	firing := e.Firing()
+	if len(firing) != 1 || firing[0].Target != TargetPod {
+		t.Fatalf("Expected only the pod alert to remain, got %+v", firing)
+	}
+	if len(*notified) != 3 {
+		t.Errorf("Expected 2 firing and 1 resolved notifications, got %d", len(*notified))
+	}
+
+	e.Reset()
+	if len(e.Firing()) != 0 || len(e.History()) != 0 {
+		t.Error("Expected Reset to clear alerts and history")
+	}
+}
+
+func TestEngine_MissingMetricsKeepState(t *testing.T) {
+	rule := Rule{Name: "NodeCPUHigh", Target: TargetNode, Severity: SeverityWarning,
+		Condition: Condition{Metric: "cpu", Op: ">", Threshold: 90}}
+	e, _, _ := newTestEngine(rule)
+
+	e.EvaluateNodes([]model.NodeModel{node("node-1", "950m")})
+	if len(e.Firing()) != 1 {
+		t.Fatal("Expected alert to fire")
+	}
+
+	noMetrics := node("node-1", "0")
+	noMetrics.UsageCpuQty = nil
+	e.EvaluateNodes([]model.NodeModel{noMetrics})
+	if len(e.Firing()) != 1 {
+		t.Error("Expected alert to keep firing while metrics are unavailable")
+	}
+}
@@ -0,0 +1,224 @@
+// Package alerts evaluates user-defined rules against the node and pod
+// models on each refresh and tracks which alerts are firing. Rules are
+// written as simple conditions such as "cpu > 90", optionally held for a
+// duration before firing or measured over a window.
+package alerts
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vladimirvivien/ktop/theme"
+)
+
+// Target is the kind of object a rule is evaluated against
+type Target string
+
+const (
+	TargetNode Target = "node"
+	TargetPod  Target = "pod"
+)
+
+// Severity controls how a firing alert is announced
+type Severity string
+
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// metric describes a value a condition can test
+type metric struct {
+	targets []Target
+	percent bool // value is a percentage (0-100)
+	counter bool // only grows while the object exists; may use a window
+	event   bool // counts events within the window, eventWindow by default
+}
+
+// eventWindow is how long an event such as an OOM kill counts towards a
+// rule that sets no window
+const eventWindow = 10 * time.Minute
+
+// metrics lists the values rules can test. Node cpu and memory are
+// percentages of allocatable; pod cpu and memory are percentages of the
+// pod's requests and skip pods without requests.
+var metrics = map[string]metric{
+	"cpu":       {targets: []Target{TargetNode, TargetPod}, percent: true},
+	"memory":    {targets: []Target{TargetNode, TargetPod}, percent: true},
+	"restarts":  {targets: []Target{TargetNode, TargetPod}, counter: true},
+	"notready":  {targets: []Target{TargetNode, TargetPod}},
+	"pressure":  {targets: []Target{TargetNode}},
+	"oomkilled": {targets: []Target{TargetPod}, event: true},
+}
+
+// Condition compares a metric to a threshold, e.g. cpu > 90
+type Condition struct {
+	Metric    string
+	Op        string // one of > >= < <= == !=
+	Threshold float64
+}
+
+// ops are ordered so two-character operators match first
+var ops = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// ParseCondition parses "<metric> <op> <number>". A trailing % on the
+// number is accepted for percentage metrics.
+func ParseCondition(expr string) (Condition, error) {
+	for _, op := range ops {
+		i := strings.Index(expr, op)
+		if i < 0 {
+			continue
+		}
+		name := strings.ToLower(strings.TrimSpace(expr[:i]))
+		m, ok := metrics[name]
+		if !ok {
+			return Condition{}, fmt.Errorf("unknown metric %q in %q (valid: %s)", name, expr, metricNames())
+		}
+		number := strings.TrimSpace(expr[i+len(op):])
+		if m.percent {
+			number = strings.TrimSuffix(number, "%")
+		}
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+		if err != nil {
+			return Condition{}, fmt.Errorf("invalid threshold in %q: %w", expr, err)
+		}
+		return Condition{Metric: name, Op: op, Threshold: threshold}, nil
+	}
+	return Condition{}, fmt.Errorf("invalid condition %q: expected <metric> <op> <number>", expr)
+}
+
+// Matches reports whether value satisfies the condition
+func (c Condition) Matches(value float64) bool {
+	switch c.Op {
+	case ">":
+		return value > c.Threshold
+	case ">=":
+		return value >= c.Threshold
+	case "<":
+		return value < c.Threshold
+	case "<=":
+		return value <= c.Threshold
+	case "==":
+		return value == c.Threshold
+	case "!=":
+		return value != c.Threshold
+	}
+	return false
+}
+
+func (c Condition) String() string {
+	return fmt.Sprintf("%s %s %s", c.Metric, c.Op, c.FormatValue(c.Threshold))
+}
+
+// FormatValue formats value in the condition metric's unit
+func (c Condition) FormatValue(value float64) string {
+	s := strconv.FormatFloat(value, 'f', -1, 64)
+	if metrics[c.Metric].percent {
+		s = strconv.FormatFloat(value, 'f', 0, 64) + "%"
+	}
+	return s
+}
+
+// Rule is a named condition evaluated against every node or pod
+type Rule struct {
+	Name      string
+	Target    Target
+	Condition Condition
+	Severity  Severity
+
+	// For is how long the condition must hold before the alert fires
+	For time.Duration
+
+	// Window turns a counter metric (restarts) into its increase over the
+	// window, e.g. restarts > 3 within 10m. For an event metric (oomkilled)
+	// it is how long an event counts.
+	Window time.Duration
+}
+
+// Validate checks that the rule's metric applies to its target and that
+// durations are used where they make sense.
+func (r Rule) Validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("rule name is required")
+	}
+	switch r.Target {
+	case TargetNode, TargetPod:
+	default:
+		return fmt.Errorf("rule %s: invalid target %q (valid: node, pod)", r.Name, r.Target)
+	}
+	switch r.Severity {
+	case SeverityWarning, SeverityCritical:
+	default:
+		return fmt.Errorf("rule %s: invalid severity %q (valid: warning, critical)", r.Name, r.Severity)
+	}
+	m, ok := metrics[r.Condition.Metric]
+	if !ok {
+		return fmt.Errorf("rule %s: unknown metric %q", r.Name, r.Condition.Metric)
+	}
+	if !hasTarget(m.targets, r.Target) {
+		return fmt.Errorf("rule %s: metric %s does not apply to %ss", r.Name, r.Condition.Metric, r.Target)
+	}
+	if r.For < 0 || r.Window < 0 {
+		return fmt.Errorf("rule %s: durations must not be negative", r.Name)
+	}
+	if r.Window > 0 && !m.counter && !m.event {
+		return fmt.Errorf("rule %s: window only applies to restarts and oomkilled", r.Name)
+	}
+	return nil
+}
+
+// DefaultRules returns the rules used when the config file declares none.
+// The CPU and memory rules fire above theme.UsageHigh, where usage turns red.
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			Name:      "NodeCPUHigh",
+			Target:    TargetNode,
+			Condition: Condition{Metric: "cpu", Op: ">", Threshold: theme.UsageHigh},
+			Severity:  SeverityWarning,
+			For:       2 * time.Minute,
+		},
+		{
+			Name:      "NodeMemoryHigh",
+			Target:    TargetNode,
+			Condition: Condition{Metric: "memory", Op: ">", Threshold: theme.UsageHigh},
+			Severity:  SeverityWarning,
+			For:       2 * time.Minute,
+		},
+		{
+			Name:      "NodeNotReady",
+			Target:    TargetNode,
+			Condition: Condition{Metric: "notready", Op: ">", Threshold: 0},
+			Severity:  SeverityCritical,
+			For:       time.Minute,
+		},
+		{
+			Name:      "PodRestarting",
+			Target:    TargetPod,
+			Condition: Condition{Metric: "restarts", Op: ">", Threshold: 3},
+			Severity:  SeverityWarning,
+			Window:    10 * time.Minute,
+		},
+		{
+			Name:      "PodOOMKilled",
+			Target:    TargetPod,
+			Condition: Condition{Metric: "oomkilled", Op: ">", Threshold: 0},
+			Severity:  SeverityCritical,
+		},
+	}
+}
+
+func hasTarget(targets []Target, t Target) bool {
+	for _, target := range targets {
+		if target == t {
+			return true
+		}
+	}
+	return false
+}
+
+func metricNames() string {
+	return "cpu, memory, restarts, notready, pressure, oomkilled"
+}
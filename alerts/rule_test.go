@@ -0,0 +1,94 @@
+package alerts
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    Condition
+		wantErr string
+	}{
+		{expr: "cpu > 90", want: Condition{Metric: "cpu", Op: ">", Threshold: 90}},
+		{expr: "memory>=85%", want: Condition{Metric: "memory", Op: ">=", Threshold: 85}},
+		{expr: " Restarts > 3 ", want: Condition{Metric: "restarts", Op: ">", Threshold: 3}},
+		{expr: "notready != 0", want: Condition{Metric: "notready", Op: "!=", Threshold: 0}},
+		{expr: "oomkilled > 0", want: Condition{Metric: "oomkilled", Op: ">", Threshold: 0}},
+		{expr: "disk > 90", wantErr: "unknown metric"},
+		{expr: "restarts > 3%", wantErr: "invalid threshold"},
+		{expr: "cpu 90", wantErr: "expected <metric> <op> <number>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseCondition(tt.expr)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseCondition failed: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCondition_String(t *testing.T) {
+	if got := (Condition{Metric: "cpu", Op: ">", Threshold: 90}).String(); got != "cpu > 90%" {
+		t.Errorf("Expected %q, got %q", "cpu > 90%", got)
+	}
+	if got := (Condition{Metric: "restarts", Op: ">=", Threshold: 3}).String(); got != "restarts >= 3" {
+		t.Errorf("Expected %q, got %q", "restarts >= 3", got)
+	}
+}
+
+func TestRule_Validate(t *testing.T) {
+	valid := Rule{
+		Name:      "PodRestarting",
+		Target:    TargetPod,
+		Condition: Condition{Metric: "restarts", Op: ">", Threshold: 3},
+		Severity:  SeverityWarning,
+		Window:    10 * time.Minute,
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("Expected valid rule, got %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(r *Rule)
+		wantErr string
+	}{
+		{"missing name", func(r *Rule) { r.Name = "" }, "name is required"},
+		{"bad target", func(r *Rule) { r.Target = "deployment" }, "invalid target"},
+		{"bad severity", func(r *Rule) { r.Severity = "page" }, "invalid severity"},
+		{"metric not for target", func(r *Rule) { r.Condition.Metric = "pressure" }, "does not apply to pods"},
+		{"window on gauge", func(r *Rule) { r.Condition.Metric = "cpu" }, "window only applies"},
+		{"negative for", func(r *Rule) { r.For = -time.Second }, "must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid
+			tt.modify(&r)
+			err := r.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDefaultRules_Valid(t *testing.T) {
+	for _, r := range DefaultRules() {
+		if err := r.Validate(); err != nil {
+			t.Errorf("Default rule invalid: %v", err)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package application
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/vladimirvivien/ktop/actions"
+	"github.com/vladimirvivien/ktop/ui"
+)
+
+// Labels of the buttons of an action's confirmation
+const (
+	confirmLabel = "Confirm"
+	cancelLabel  = "Cancel"
+)
+
+const (
+	// accessCheckTimeout bounds the access reviews made before confirming
+	accessCheckTimeout = 10 * time.Second
+
+	// actionTimeout bounds an action; drainTimeout a drain, which waits
+	// on PodDisruptionBudgets and for the evicted pods to terminate
+	actionTimeout = 30 * time.Second
+	drainTimeout  = 5 * time.Minute
+
+	actionToastDuration = 5 * time.Second
+)
+
+// SetReadOnly disables the actions and container shells when readOnly is
+// true, as set by --read-only
+func (app *Application) SetReadOnly(readOnly bool) {
+	app.readOnly = readOnly
+}
+
+// IsReadOnly returns true when the actions and container shells are
+// disabled
+func (app *Application) IsReadOnly() bool {
+	return app.readOnly
+}
+
+// readOnlyHeader marks the header in read-only mode
+func (app *Application) readOnlyHeader() string {
+	if !app.readOnly {
+		return ""
+	}
+	return " [green]| [yellow]read-only"
+}
+
+// CanChange reports whether what, an action or shell, may change the
+// cluster, telling the user why not otherwise. Must be called on the UI
+// goroutine.
+func (app *Application) CanChange(what string) bool {
+	switch {
+	case app.player != nil:
+		app.ShowToast(what+" is not available in a replay", ui.ToastWarning, 3*time.Second)
+		return false
+	case app.readOnly:
+		app.ShowToast(what+" is disabled in read-only mode", ui.ToastWarning, 3*time.Second)
+		return false
+	}
+	return true
+}
+
+// ShowActions overlays a menu of the actions on a resource. The action
+// chosen is checked and confirmed before it runs. Must be called on the UI
+// goroutine.
+func (app *Application) ShowActions(title string, list []actions.Action) {
+	if !app.CanChange("Changing resources") {
+		return
+	}
+	labels := make([]string, len(list))
+	for i, a := range list {
+		labels[i] = a.Title()
+	}
+	app.ShowPicker(title, labels, func(index int) {
+		app.RunAction(list[index])
+	})
+}
+
+// RunAction checks with the API server that the user may run a, asks to
+// confirm it and then runs it in the background. Must be called on the UI
+// goroutine.
+func (app *Application) RunAction(a actions.Action) {
+	if !app.CanChange("Changing resources") {
+		return
+	}
+	source := app.cluster.Source()
+	go func() {
+		ctx, cancel := context.WithTimeout(app.rootCtx, accessCheckTimeout)
+		defer cancel()
+		err := actions.Check(ctx, source, a)
+		app.tviewApp.QueueUpdateDraw(func() {
+			if err != nil {
+				slog.Warn("action not allowed", "action", a.Title(), "target", a.Target(), "error", err)
+				app.ShowToast(err.Error(), ui.ToastError, actionToastDuration)
+				return
+			}
+			app.pendingAction = &a
+			app.ShowToastWithButtons(a.Confirmation(), ui.ToastWarning, 0, []string{confirmLabel, cancelLabel})
+		})
+	}()
+}
+
+// confirmAction runs the action waiting on its confirmation, or drops it
+// when it was not confirmed. Must be called on the UI goroutine.
+func (app *Application) confirmAction(confirmed bool) {
+	a := app.pendingAction
+	app.pendingAction = nil
+	if a == nil || !confirmed {
+		return
+	}
+
+	timeout := actionTimeout
+	if a.Kind == actions.Drain {
+		timeout = drainTimeout
+	}
+	source := app.cluster.Source()
+	app.ShowToast(a.Progress(), ui.ToastInfo, actionToastDuration)
+	slog.Info("running action", "action", a.Title(), "target", a.Target())
+
+	go func() {
+		ctx, cancel := context.WithTimeout(app.rootCtx, timeout)
+		defer cancel()
+		err := actions.Run(ctx, source, *a, func(msg string) {
+			app.tviewApp.QueueUpdateDraw(func() {
+				app.ShowToast(msg, ui.ToastInfo, actionToastDuration)
+			})
+		})
+		app.tviewApp.QueueUpdateDraw(func() {
+			if err != nil {
+				slog.Error("action failed", "action", a.Title(), "target", a.Target(), "error", err)
+				app.ShowToast(a.Title()+" failed: "+err.Error(), ui.ToastError, actionToastDuration)
+				return
+			}
+			app.ShowToast(a.Done(), ui.ToastSuccess, actionToastDuration)
+		})
+		app.Refresh()
+	}()
+}
@@ -0,0 +1,126 @@
+package application
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/vladimirvivien/ktop/alerts"
+	"github.com/vladimirvivien/ktop/ui"
+)
+
+// alertToastDuration is how long a firing or resolved alert toast stays up
+const alertToastDuration = 5 * time.Second
+
+// SetAlertRules enables alerting with rules. The pages evaluate the
+// engine on each refresh; changes are announced with a toast. It must be
+// called before Run.
+func (app *Application) SetAlertRules(rules []alerts.Rule) {
+	app.alerts = alerts.NewEngine(rules)
+	app.alerts.SetNotifyFunc(app.notifyAlerts)
+	slog.Info("alerts enabled", "rules", len(rules))
+}
+
+// GetAlertEngine returns the alert engine, or nil when alerting is disabled
+func (app *Application) GetAlertEngine() *alerts.Engine {
+	return app.alerts
+}
+
+// SetAlertsCallback sets the callback for showing the alerts page
+func (app *Application) SetAlertsCallback(callback func()) {
+	app.alertsCallback = callback
+}
+
+// NavigateToAlerts shows the firing alerts and resolved history
+func (app *Application) NavigateToAlerts() {
+	if app.alerts == nil {
+		app.ShowToast("Alerts are disabled (alerts.enabled in the config file)", ui.ToastInfo, 3*time.Second)
+		return
+	}
+	if current := app.navStack.Current(); current != nil && current.PageType == PageAlerts {
+		return
+	}
+
+	app.navStack.Push(PageState{PageType: PageAlerts})
+	if app.alertsCallback != nil {
+		app.alertsCallback()
+	}
+	app.updateFooterContext()
+}
+
+// notifyAlerts is called by the engine, off the UI goroutine, with the
+// alerts that fired or resolved in one evaluation. One toast summarizes
+// them; firing alerts take precedence over resolved ones.
+func (app *Application) notifyAlerts(changed []alerts.Alert) {
+	var firing, resolved []alerts.Alert
+	for _, a := range changed {
+		if a.Firing() {
+			slog.Warn("alert firing", "rule", a.Rule, "severity", a.Severity, "subject", a.Subject, "value", a.Value)
+			firing = append(firing, a)
+		} else {
+			slog.Info("alert resolved", "rule", a.Rule, "subject", a.Subject)
+			resolved = append(resolved, a)
+		}
+	}
+
+	var msg string
+	level := ui.ToastSuccess
+	switch {
+	case len(firing) == 1:
+		msg = fmt.Sprintf("%s\n%s", firing[0].Rule, firing[0].Message())
+		level = alertToastLevel(firing)
+	case len(firing) > 1:
+		msg = fmt.Sprintf("%d alerts firing: %s", len(firing), alertNames(firing))
+		level = alertToastLevel(firing)
+	case len(resolved) == 1:
+		msg = fmt.Sprintf("Resolved: %s %s", resolved[0].Rule, resolved[0].Subject)
+	default:
+		msg = fmt.Sprintf("%d alerts resolved", len(resolved))
+	}
+
+	app.tviewApp.QueueUpdateDraw(func() {
+		app.panel.DrawHeader(app.buildHeaderString(app.getNamespaceDisplay()))
+		// Connection problems matter more than alerts; don't replace their toast
+		if app.apiHealthToastID != "" {
+			return
+		}
+		app.ShowToast(msg, level, alertToastDuration)
+	})
+}
+
+// alertToastLevel is an error toast when any alert is critical
+func alertToastLevel(firing []alerts.Alert) ui.ToastLevel {
+	for _, a := range firing {
+		if a.Severity == alerts.SeverityCritical {
+			return ui.ToastError
+		}
+	}
+	return ui.ToastWarning
+}
+
+// alertNames lists rule and subject for up to three alerts
+func alertNames(list []alerts.Alert) string {
+	const max = 3
+	names := make([]string, 0, max)
+	for i, a := range list {
+		if i == max {
+			names = append(names, fmt.Sprintf("+%d more", len(list)-max))
+			break
+		}
+		names = append(names, a.Rule+" "+a.Subject)
+	}
+	return strings.Join(names, ", ")
+}
+
+// alertsHeader returns the header segment with the number of firing alerts
+func (app *Application) alertsHeader() string {
+	if app.alerts == nil {
+		return ""
+	}
+	n := len(app.alerts.Firing())
+	if n == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" [green]| Alerts: [red]%d", n)
+}
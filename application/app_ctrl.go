@@ -10,11 +10,14 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/actions"
+	"github.com/vladimirvivien/ktop/alerts"
 	"github.com/vladimirvivien/ktop/buildinfo"
 	"github.com/vladimirvivien/ktop/health"
 
 	"github.com/vladimirvivien/ktop/k8s"
 	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/portforward"
 	"github.com/vladimirvivien/ktop/ui"
 )
 
@@ -25,7 +28,7 @@ type AppPage struct {
 
 type Application struct {
 	namespace     string
-	k8sClient     *k8s.Client
+	cluster       k8s.Cluster
 	metricsSource metrics.MetricsSource
 	tviewApp      *tview.Application
 	pages         []AppPage
@@ -44,6 +47,16 @@ type Application struct {
 	nodeDetailCallback    func(nodeName string)
 	podDetailCallback     func(namespace, podName string)
 	containerLogsCallback func(namespace, podName, containerName string)
+	workloadPodsCallback  func(kind, namespace, name string) bool
+	alertsCallback        func()
+	controlPlaneCallback  func()
+	queryCallback         func()
+	pressureCallback      func()
+	rightsizingCallback   func()
+	capacityCallback      func()
+	eventsCallback        func()
+	logsCallback          func(kind, namespace, name string)
+	portForwardsCallback  func()
 
 	// Health state tracking for transitions
 	lastHealthyState      bool
@@ -62,29 +75,59 @@ type Application struct {
 	// Namespace filter callback for pod filtering
 	namespaceFilterCallback func(namespace string)
 
+	// Runtime context/namespace switching (see session.go)
+	connect    ConnectFunc
+	rootCtx    context.Context
+	endSession context.CancelFunc
+	switching  bool
+
+	// Playback controls when replaying a recording (see player.go)
+	player Player
+
+	// Alert rules evaluated on each refresh; nil when disabled (see alerts.go)
+	alerts *alerts.Engine
+
+	// Port forwards kept running across pages (see portforward.go)
+	forwards *portforward.Manager
+
+	// Resource actions (see actions.go): disabled by --read-only, and the
+	// action shown for confirmation
+	readOnly      bool
+	pendingAction *actions.Action
+
 	// Quit confirmation state (double-ESC to quit from Overview)
 	pendingQuit     bool
 	pendingQuitTime time.Time
 }
 
-func New(k8sC *k8s.Client, metricsSource metrics.MetricsSource) *Application {
+func New(cluster k8s.Cluster, metricsSource metrics.MetricsSource) *Application {
 	tapp := tview.NewApplication()
 	app := &Application{
-		k8sClient:     k8sC,
+		cluster:       cluster,
 		metricsSource: metricsSource,
-		namespace:     k8sC.Namespace(),
+		namespace:     cluster.Namespace(),
 		tviewApp:      tapp,
 		panel:         newPanel(tapp),
 		refreshQ:      make(chan struct{}, 1),
 		pageIdx:       -1,
 		tabIdx:        -1, // -1 = header (default focus), 0+ = children panels
 		navStack:      NewNavigationStack(),
+		forwards:      portforward.NewManager(),
 	}
 
-	// Initialize API health tracker with persistent toast callback
-	app.apiHealthTracker = health.NewAPIHealthTracker(func(state health.APIState, msg string) {
+	app.apiHealthTracker = app.newAPIHealthTracker()
+	app.forwards.SetNotifyFunc(app.notifyPortForward)
+
+	return app
+}
+
+// newAPIHealthTracker creates an API health tracker whose state changes are
+// surfaced as toasts and trigger a UI refresh.
+func (app *Application) newAPIHealthTracker() *health.APIHealthTracker {
+	// Persistent toast callback for connection state changes
+	tracker := health.NewAPIHealthTracker(func(state health.APIState, msg string) {
 		// Use QueueUpdateDraw to safely update UI from callback
-		tapp.QueueUpdateDraw(func() {
+		app.tviewApp.QueueUpdateDraw(func() {
 			switch state {
 			case health.APIHealthy:
 				// Connection restored - dismiss persistent toast and show brief success (no buttons)
@@ -114,19 +157,20 @@ func New(k8sC *k8s.Client, metricsSource metrics.MetricsSource) *Application {
 	})
 
 	// Set up callbacks for health state changes
-	app.apiHealthTracker.SetOnDisconnected(func() {
+	tracker.SetOnDisconnected(func() {
 		app.Refresh() // Trigger UI refresh to show zeroed values
 	})
 
-	app.apiHealthTracker.SetOnHealthy(func() {
+	tracker.SetOnHealthy(func() {
 		app.Refresh() // Trigger UI refresh when reconnected
 	})
 
-	return app
+	return tracker
 }
 
-func (app *Application) GetK8sClient() *k8s.Client {
-	return app.k8sClient
+// GetCluster returns the connection the application is currently showing
+func (app *Application) GetCluster() k8s.Cluster {
+	return app.cluster
 }
 
 func (app *Application) GetMetricsSource() metrics.MetricsSource {
@@ -220,6 +264,14 @@ func (app *Application) SetNamespaceFilterCallback(callback func(namespace strin
 	app.panel.setNamespaceFilterCallback(callback)
 }
 
+// SetNamespaceFilter sets the header's namespace filter as if it had been
+// typed, notifying the namespace filter callback. An empty namespace clears it.
+// Must be called from the main UI goroutine.
+func (app *Application) SetNamespaceFilter(namespace string) {
+	app.panel.setNamespaceFilter(namespace)
+	app.updateHeaderDirect()
+}
+
 // GetNamespaceFilter returns the current namespace filter text
 func (app *Application) GetNamespaceFilter() string {
 	return app.panel.getNamespaceFilter()
@@ -289,9 +341,14 @@ func (app *Application) WelcomeBanner() {
 }
 
 func (app *Application) setup(ctx context.Context) error {
+	// Pages run under a per-connection context so a context or namespace
+	// switch can stop their controllers without stopping the application.
+	app.rootCtx = ctx
+	sessionCtx := app.beginSession()
+
 	// setup each page panel
 	for _, page := range app.pages {
-		if err := page.Panel.Run(ctx); err != nil {
+		if err := page.Panel.Run(sessionCtx); err != nil {
 			return fmt.Errorf("init failed: page %s: %s", page.Title, err)
 		}
 	}
@@ -320,6 +377,11 @@ func (app *Application) setup(ctx context.Context) error {
 	// Set up focus restoration callback for toast dismissal
 	// This ensures proper focus is restored based on tabIdx after toast goes away
 	app.panel.setFocusRestorationCallback(func() {
+		// Detail pages keep their own focus; give it back
+		if app.IsInDetailView() && app.panel.returnFocus != nil {
+			app.tviewApp.SetFocus(app.panel.returnFocus)
+			return
+		}
 		views := app.pages[0].Panel.GetChildrenViews()
 		// Restore focus based on current tabIdx
 		if app.tabIdx == -1 {
@@ -336,55 +398,11 @@ func (app *Application) setup(ctx context.Context) error {
 		}
 	})
 
-	// Set up event-driven metrics health monitoring (replaces polling)
-	if app.metricsSource != nil {
-		sourceInfo := app.metricsSource.GetSourceInfo()
-		app.lastMetricsSource = sourceInfo.Type
-
-		// Register health callback for instant updates
-		app.metricsSource.SetHealthCallback(func(healthy bool, info metrics.SourceInfo) {
-			app.tviewApp.QueueUpdateDraw(func() {
-				app.handleMetricsHealthChange(healthy, info)
-			})
-		})
+	app.watchMetricsHealth()
+	app.watchPlayer()
 
-		// Check health state and update UI accordingly
-		// This handles the race where health changed between initial header draw
-		// and callback registration
-		if !app.metricsSource.IsHealthy() {
-			app.loadingToastStartTime = time.Now()
-			app.loadingToastID = app.ShowToast(
-				fmt.Sprintf("Waiting for metrics: %s...", sourceInfo.Type),
-				ui.ToastInfo,
-				0, // No timeout - dismiss when healthy or timeout
-			)
-			app.lastHealthyState = false
-
-			// One-shot timeout check (15 seconds) instead of polling
-			time.AfterFunc(15*time.Second, func() {
-				app.tviewApp.QueueUpdateDraw(func() {
-					if app.loadingToastID != "" && app.metricsSource != nil && !app.metricsSource.IsHealthy() {
-						app.DismissToast(app.loadingToastID)
-						app.loadingToastID = ""
-						sourceInfo := app.metricsSource.GetSourceInfo()
-						app.ShowToast(
-							fmt.Sprintf("%s metrics unavailable", sourceInfo.Type),
-							ui.ToastError,
-							5*time.Second,
-						)
-					}
-				})
-			})
-		} else {
-			app.lastHealthyState = true
-			// Redraw header to ensure it reflects the healthy state
-			// This handles the race where health transitioned before callback was registered
-			nsDisplay := app.getNamespaceDisplay()
-			app.panel.DrawHeader(app.buildHeaderString(nsDisplay))
-		}
-	}
-
-	// Set toast button callback to handle Retry and Quit buttons
+	// Set toast button callback to handle Retry and Quit buttons, and the
+	// confirmation of actions
 	app.panel.setToastButtonCallback(func(buttonLabel string) {
 		switch buttonLabel {
 		case "Quit":
@@ -393,6 +411,10 @@ func (app *Application) setup(ctx context.Context) error {
 			if app.IsAPIDisconnected() {
 				app.apiHealthTracker.TryReconnect()
 			}
+		case confirmLabel, cancelLabel, "":
+			app.tviewApp.QueueUpdateDraw(func() {
+				app.confirmAction(buttonLabel == confirmLabel)
+			})
 		}
 	})
 
@@ -408,6 +430,11 @@ func (app *Application) setup(ctx context.Context) error {
 			return event // Pass other keys through
 		}
 
+		// An open picker owns the keyboard, including ESC to close it
+		if app.panel.hasActivePicker() {
+			return event
+		}
+
 		// Reset pending quit state on any non-ESC key
 		if event.Key() != tcell.KeyEsc && app.pendingQuit {
 			app.pendingQuit = false
@@ -474,6 +501,12 @@ func (app *Application) setup(ctx context.Context) error {
 			return nil
 		}
 
+		// Replay playback keys work on every page
+		if app.handlePlayerKey(event) {
+			app.updateHeaderDirect()
+			return nil
+		}
+
 		// Handle 'R' key for reconnecting when API is disconnected
 		if event.Key() == tcell.KeyRune && (event.Rune() == 'R' || event.Rune() == 'r') {
 			if app.IsAPIDisconnected() {
@@ -484,13 +517,9 @@ func (app *Application) setup(ctx context.Context) error {
 
 		if event.Key() == tcell.KeyTAB || event.Key() == tcell.KeyBacktab {
 			// Check if we're on a detail page - if so, let the detail panel handle Tab
-			if frontPage, _ := app.panel.pages.GetFrontPage(); frontPage != "" {
-				// Detail pages are named "node_detail", "pod_detail", etc.
-				// Overview pages are named "Overview", etc.
-				if frontPage == "node_detail" || frontPage == "pod_detail" {
-					// Pass Tab through to the detail panel
-					return event
-				}
+			if frontPage, _ := app.panel.pages.GetFrontPage(); app.panel.tabbedPages[frontPage] {
+				// Pass Tab through to the detail panel
+				return event
 			}
 
 			views := app.pages[0].Panel.GetChildrenViews()
@@ -528,6 +557,46 @@ func (app *Application) setup(ctx context.Context) error {
 			return nil
 		}
 
+		// Context and namespace pickers and the cluster-wide pages, available from the Overview header
+		if app.tabIdx == -1 && !app.IsInDetailView() && !app.panel.isNamespaceFilterEditing() &&
+			event.Key() == tcell.KeyRune {
+			switch event.Rune() {
+			case 'c':
+				app.showContextPicker()
+				return nil
+			case 'n':
+				app.showNamespacePicker()
+				return nil
+			case 'a':
+				app.NavigateToAlerts()
+				return nil
+			case 'p':
+				app.NavigateToControlPlane()
+				return nil
+			case 'q':
+				app.NavigateToQuery()
+				return nil
+			case 't':
+				app.NavigateToPressure()
+				return nil
+			case 'r':
+				app.NavigateToRightsizing()
+				return nil
+			case 'b':
+				app.NavigateToCapacity()
+				return nil
+			case 'e':
+				app.NavigateToEvents()
+				return nil
+			case 'l':
+				app.NavigateToLogs("", "", "")
+				return nil
+			case 'f':
+				app.NavigateToPortForwards()
+				return nil
+			}
+		}
+
 		// Handle keyboard input when header is focused
 		if app.tabIdx == -1 {
 			if app.panel.handleHeaderKey(event) {
@@ -555,6 +624,64 @@ func (app *Application) setup(ctx context.Context) error {
 	return nil
 }
 
+// watchMetricsHealth registers for event-driven metrics health updates
+// (replaces polling) and shows a loading toast until the current source
+// reports healthy.
+func (app *Application) watchMetricsHealth() {
+	if app.metricsSource == nil {
+		return
+	}
+	sourceInfo := app.metricsSource.GetSourceInfo()
+	app.lastMetricsSource = sourceInfo.Type
+
+	// Register health callback for instant updates. Callbacks still queued
+	// from a source replaced by a context switch are dropped.
+	source := app.metricsSource
+	source.SetHealthCallback(func(healthy bool, info metrics.SourceInfo) {
+		app.tviewApp.QueueUpdateDraw(func() {
+			if app.metricsSource != source {
+				return
+			}
+			app.handleMetricsHealthChange(healthy, info)
+		})
+	})
+
+	// Check health state and update UI accordingly
+	// This handles the race where health changed between initial header draw
+	// and callback registration
+	if !app.metricsSource.IsHealthy() {
+		app.loadingToastStartTime = time.Now()
+		app.loadingToastID = app.ShowToast(
+			fmt.Sprintf("Waiting for metrics: %s...", sourceInfo.Type),
+			ui.ToastInfo,
+			0, // No timeout - dismiss when healthy or timeout
+		)
+		app.lastHealthyState = false
+
+		// One-shot timeout check (15 seconds) instead of polling
+		time.AfterFunc(15*time.Second, func() {
+			app.tviewApp.QueueUpdateDraw(func() {
+				if app.loadingToastID != "" && app.metricsSource != nil && !app.metricsSource.IsHealthy() {
+					app.DismissToast(app.loadingToastID)
+					app.loadingToastID = ""
+					sourceInfo := app.metricsSource.GetSourceInfo()
+					app.ShowToast(
+						fmt.Sprintf("%s metrics unavailable", sourceInfo.Type),
+						ui.ToastError,
+						5*time.Second,
+					)
+				}
+			})
+		})
+	} else {
+		app.lastHealthyState = true
+		// Redraw header to ensure it reflects the healthy state
+		// This handles the race where health transitioned before callback was registered
+		nsDisplay := app.getNamespaceDisplay()
+		app.panel.DrawHeader(app.buildHeaderString(nsDisplay))
+	}
+}
+
 func (app *Application) Run(ctx context.Context) error {
 
 	// setup application UI
@@ -571,6 +698,7 @@ func (app *Application) Run(ctx context.Context) error {
 	}()
 
 	slog.Info("tui started", "pages", app.getPageTitles())
+	defer app.forwards.Clear()
 	return app.tviewApp.Run()
 }
 
@@ -683,7 +811,7 @@ func (app *Application) buildHeaderString(namespaceDisplay string) string {
 		hdr.WriteString(" [red]not connected")
 	}
 
-	client := app.GetK8sClient()
+	client := app.GetCluster()
 
 	// Truncate long values to prevent header overflow
 	context := truncateString(client.ClusterName(), 25)
@@ -693,7 +821,7 @@ func (app *Application) buildHeaderString(namespaceDisplay string) string {
 	return fmt.Sprintf(
 		hdr.String(),
 		context, client.GetServerVersion(), user, ns,
-	)
+	) + app.alertsHeader() + app.playerHeader() + app.readOnlyHeader()
 }
 
 // truncateString truncates a string for header display
@@ -726,7 +854,7 @@ func (app *Application) getNamespaceDisplay() string {
 	}
 
 	// No filter - show actual namespace
-	namespace := app.k8sClient.Namespace()
+	namespace := app.cluster.Namespace()
 	if namespace == k8s.AllNamespaces {
 		return "[orange](all)[-]"
 	}
@@ -796,6 +924,167 @@ func (app *Application) NavigateToPodDetail(namespace, podName string) {
 	app.updateFooterContext()
 }
 
+// SetWorkloadPodsCallback sets the callback for navigating to a workload's
+// pods view. The callback returns false when the workload is not known.
+func (app *Application) SetWorkloadPodsCallback(callback func(kind, namespace, name string) bool) {
+	app.workloadPodsCallback = callback
+}
+
+// NavigateToWorkloadPods navigates to the pods owned by the given workload
+func (app *Application) NavigateToWorkloadPods(kind, namespace, name string) {
+	// Call the callback to show the workload view; the page is only
+	// pushed once it is displayed
+	if app.workloadPodsCallback == nil {
+		return
+	}
+	if !app.workloadPodsCallback(kind, namespace, name) {
+		app.ShowToast(fmt.Sprintf("%s %s/%s is not loaded yet", kind, namespace, name), ui.ToastWarning, 3*time.Second)
+		return
+	}
+
+	// Push current state to navigation stack
+	resourceID := kind + "/" + namespace + "/" + name
+	app.navStack.Push(PageState{
+		PageType:   PageWorkloadPods,
+		ResourceID: resourceID,
+	})
+
+	// Update footer context for detail page
+	app.updateFooterContext()
+}
+
+// SetControlPlaneCallback sets the callback for showing the control-plane page
+func (app *Application) SetControlPlaneCallback(callback func()) {
+	app.controlPlaneCallback = callback
+}
+
+// NavigateToControlPlane shows apiserver, etcd, scheduler and
+// controller-manager health
+func (app *Application) NavigateToControlPlane() {
+	if current := app.navStack.Current(); current != nil && current.PageType == PageControlPlane {
+		return
+	}
+
+	app.navStack.Push(PageState{PageType: PageControlPlane})
+	if app.controlPlaneCallback != nil {
+		app.controlPlaneCallback()
+	}
+	app.updateFooterContext()
+}
+
+// SetQueryCallback sets the callback for showing the query console
+func (app *Application) SetQueryCallback(callback func()) {
+	app.queryCallback = callback
+}
+
+// NavigateToQuery shows the PromQL query console
+func (app *Application) NavigateToQuery() {
+	if current := app.navStack.Current(); current != nil && current.PageType == PageQuery {
+		return
+	}
+
+	app.navStack.Push(PageState{PageType: PageQuery})
+	if app.queryCallback != nil {
+		app.queryCallback()
+	}
+	app.updateFooterContext()
+}
+
+// SetPressureCallback sets the callback for showing the pressure page
+func (app *Application) SetPressureCallback(callback func()) {
+	app.pressureCallback = callback
+}
+
+// NavigateToPressure shows the CPU throttled and OOM killed containers
+func (app *Application) NavigateToPressure() {
+	if current := app.navStack.Current(); current != nil && current.PageType == PagePressure {
+		return
+	}
+
+	app.navStack.Push(PageState{PageType: PagePressure})
+	if app.pressureCallback != nil {
+		app.pressureCallback()
+	}
+	app.updateFooterContext()
+}
+
+// SetRightsizingCallback sets the callback for showing the rightsizing page
+func (app *Application) SetRightsizingCallback(callback func()) {
+	app.rightsizingCallback = callback
+}
+
+// NavigateToRightsizing shows the request and limit recommendations
+func (app *Application) NavigateToRightsizing() {
+	if current := app.navStack.Current(); current != nil && current.PageType == PageRightsizing {
+		return
+	}
+
+	app.navStack.Push(PageState{PageType: PageRightsizing})
+	if app.rightsizingCallback != nil {
+		app.rightsizingCallback()
+	}
+	app.updateFooterContext()
+}
+
+// SetCapacityCallback sets the callback for showing the capacity page
+func (app *Application) SetCapacityCallback(callback func()) {
+	app.capacityCallback = callback
+}
+
+// NavigateToCapacity shows the capacity left on each node
+func (app *Application) NavigateToCapacity() {
+	if current := app.navStack.Current(); current != nil && current.PageType == PageCapacity {
+		return
+	}
+
+	app.navStack.Push(PageState{PageType: PageCapacity})
+	if app.capacityCallback != nil {
+		app.capacityCallback()
+	}
+	app.updateFooterContext()
+}
+
+// SetEventsCallback sets the callback for showing the events page
+func (app *Application) SetEventsCallback(callback func()) {
+	app.eventsCallback = callback
+}
+
+// NavigateToEvents shows the events of the cluster
+func (app *Application) NavigateToEvents() {
+	if current := app.navStack.Current(); current != nil && current.PageType == PageEvents {
+		return
+	}
+
+	app.navStack.Push(PageState{PageType: PageEvents})
+	if app.eventsCallback != nil {
+		app.eventsCallback()
+	}
+	app.updateFooterContext()
+}
+
+// SetLogsCallback sets the callback for showing the aggregated logs page
+func (app *Application) SetLogsCallback(callback func(kind, namespace, name string)) {
+	app.logsCallback = callback
+}
+
+// NavigateToLogs tails the logs of the pods of a workload, or of the pods
+// matching a label selector entered on the page when kind is empty
+func (app *Application) NavigateToLogs(kind, namespace, name string) {
+	if current := app.navStack.Current(); current != nil && current.PageType == PageLogs {
+		return
+	}
+
+	resourceID := ""
+	if kind != "" {
+		resourceID = kind + "/" + namespace + "/" + name
+	}
+	app.navStack.Push(PageState{PageType: PageLogs, ResourceID: resourceID})
+	if app.logsCallback != nil {
+		app.logsCallback(kind, namespace, name)
+	}
+	app.updateFooterContext()
+}
+
 // SetContainerLogsCallback sets the callback for navigating to container logs view
 func (app *Application) SetContainerLogsCallback(callback func(namespace, podName, containerName string)) {
 	app.containerLogsCallback = callback
@@ -854,6 +1143,37 @@ func (app *Application) NavigateBack() bool {
 		if len(parts) == 2 && app.podDetailCallback != nil {
 			app.podDetailCallback(parts[0], parts[1])
 		}
+	case PageWorkloadPods:
+		// Navigate back to the workload's pods (for nested navigation)
+		parts := strings.SplitN(current.ResourceID, "/", 3)
+		if len(parts) == 3 && app.workloadPodsCallback != nil {
+			app.workloadPodsCallback(parts[0], parts[1], parts[2])
+		}
+	case PageAlerts:
+		// Navigate back to the alerts page (e.g. from a node opened there)
+		if app.alertsCallback != nil {
+			app.alertsCallback()
+		}
+	case PagePressure:
+		// Back from a container opened on the pressure page
+		if app.pressureCallback != nil {
+			app.pressureCallback()
+		}
+	case PageRightsizing:
+		// Back from a workload opened on the rightsizing page
+		if app.rightsizingCallback != nil {
+			app.rightsizingCallback()
+		}
+	case PageEvents:
+		// Back from a pod or node opened on the events page
+		if app.eventsCallback != nil {
+			app.eventsCallback()
+		}
+	case PagePortForwards:
+		// Back from a pod opened on the port forwards page
+		if app.portForwardsCallback != nil {
+			app.portForwardsCallback()
+		}
 	}
 
 	// Update footer context for the page we navigated back to
@@ -886,7 +1206,14 @@ func (app *Application) getActiveDetailPanel() ui.EscapablePanel {
 
 // AddDetailPage adds a detail page to the application's pages widget
 func (app *Application) AddDetailPage(name string, page tview.Primitive) {
-	app.panel.addDetailPage(name, page)
+	app.panel.addDetailPage(name, page, false)
+}
+
+// AddTabbedDetailPage adds a detail page that moves the focus between its
+// own views with Tab and Backtab, which are passed to it instead of cycling
+// the overview panels
+func (app *Application) AddTabbedDetailPage(name string, page tview.Primitive) {
+	app.panel.addDetailPage(name, page, true)
 }
 
 // ShowDetailPage switches to a detail page
@@ -905,13 +1232,17 @@ func (app *Application) getFocusedPanelName() string {
 		return "header"
 	}
 	// Map tabIdx to panel names based on Overview page structure
-	// Order: summary (0), nodes (1), pods (2)
+	// Order: summary (0), nodes (1), namespaces (2), workloads (3), pods (4)
 	switch app.tabIdx {
 	case 0:
 		return "summary"
 	case 1:
 		return "nodes"
 	case 2:
+		return "namespaces"
+	case 3:
+		return "workloads"
+	case 4:
 		return "pods"
 	default:
 		return "summary"
@@ -936,6 +1267,26 @@ func (app *Application) updateFooterContext() {
 		ctx = ui.PodDetailContext{FocusedPanel: "events"}
 	case PageContainerLogs:
 		ctx = ui.ContainerDetailContext{FocusedPanel: "logs"}
+	case PageWorkloadPods:
+		ctx = ui.WorkloadPodsContext{}
+	case PageAlerts:
+		ctx = ui.AlertsContext{}
+	case PageControlPlane:
+		ctx = ui.ControlPlaneContext{}
+	case PageQuery:
+		ctx = ui.QueryContext{}
+	case PagePressure:
+		ctx = ui.PressureContext{}
+	case PageRightsizing:
+		ctx = ui.RightsizingContext{}
+	case PageCapacity:
+		ctx = ui.CapacityContext{}
+	case PageEvents:
+		ctx = ui.EventsContext{}
+	case PageLogs:
+		ctx = ui.LogsContext{}
+	case PagePortForwards:
+		ctx = ui.PortForwardsContext{}
 	default:
 		ctx = ui.OverviewContext{FocusedPanel: app.getFocusedPanelName()}
 	}
@@ -31,6 +31,7 @@ type appPanel struct {
 
 	// Toast tracking
 	currentToastID      string
+	currentToastEsc     string // label ESC stands for on the current toast
 	toastMutex          sync.Mutex
 	toastButtonCallback ui.ToastCallback // Callback for toast button presses
 
@@ -41,6 +42,13 @@ type appPanel struct {
 
 	// Focus restoration callback - called after toast is dismissed
 	focusRestorationCallback func()
+
+	// Primitive focused before a toast or picker took the focus, given back
+	// on detail pages where focusRestorationCallback has no panel to focus
+	returnFocus tview.Primitive
+
+	// Detail pages that handle Tab and Backtab themselves
+	tabbedPages map[string]bool
 }
 
 func newPanel(app *tview.Application) *appPanel {
@@ -48,6 +56,7 @@ func newPanel(app *tview.Application) *appPanel {
 		title:           "ktop",
 		tviewApp:        app,
 		namespaceFilter: &ui.FilterState{},
+		tabbedPages:     make(map[string]bool),
 	}
 	return p
 }
@@ -165,9 +174,13 @@ func (p *appPanel) switchToPage(title string) {
 	p.pages.SwitchToPage(title)
 }
 
-// addDetailPage adds a detail page that can be shown when navigating to resources
-func (p *appPanel) addDetailPage(name string, page tview.Primitive) {
+// addDetailPage adds a detail page that can be shown when navigating to resources.
+// A tabbed page gets the Tab and Backtab keys instead of the overview panels.
+func (p *appPanel) addDetailPage(name string, page tview.Primitive, tabbed bool) {
 	p.pages.AddPage(name, page, true, false)
+	if tabbed {
+		p.tabbedPages[name] = true
+	}
 }
 
 // showDetailPage switches to a detail page
@@ -205,6 +218,9 @@ func (p *appPanel) showToastWithButtons(message string, level ui.ToastLevel, dur
 	}
 
 	toastID := fmt.Sprintf("toast-%d", time.Now().UnixNano())
+	if p.currentToastID == "" && !p.hasActivePicker() {
+		p.returnFocus = p.tviewApp.GetFocus()
+	}
 
 	// Create callback that dismisses toast and calls user callback
 	// Run in goroutine to avoid blocking the modal's event handler
@@ -233,6 +249,7 @@ func (p *appPanel) showToastWithButtons(message string, level ui.ToastLevel, dur
 	// Add to pages with unique ID
 	p.root.AddPage(toastID, toast, true, true)
 	p.currentToastID = toastID
+	p.currentToastEsc = escLabel(buttons)
 
 	// Ensure the modal has focus so it can receive key input
 	p.tviewApp.SetFocus(toast)
@@ -250,6 +267,21 @@ func (p *appPanel) showToastWithButtons(message string, level ui.ToastLevel, dur
 	return toastID
 }
 
+// escLabel returns the label ESC stands for on a toast with buttons, as
+// handled by ui.NewToastWithButtons: "Quit" unless buttons lack it, when
+// ESC only dismisses the toast
+func escLabel(buttons []string) string {
+	if len(buttons) == 0 {
+		return "Quit"
+	}
+	for _, b := range buttons {
+		if b == "Quit" {
+			return "Quit"
+		}
+	}
+	return ""
+}
+
 // dismissToast removes a toast notification by ID (public, acquires lock)
 func (p *appPanel) dismissToast(toastID string) {
 	p.toastMutex.Lock()
@@ -295,6 +327,7 @@ func (p *appPanel) hasActiveToast() bool {
 func (p *appPanel) handleToastEsc() {
 	p.toastMutex.Lock()
 	toastID := p.currentToastID
+	label := p.currentToastEsc
 	callback := p.toastButtonCallback
 	p.toastMutex.Unlock()
 
@@ -306,9 +339,9 @@ func (p *appPanel) handleToastEsc() {
 			p.tviewApp.QueueUpdateDraw(func() {
 				p.dismissToastInternal(toastID)
 			})
-			// Then call the user callback with "Quit"
+			// Then call the user callback with what ESC stands for
 			if callback != nil {
-				callback("Quit")
+				callback(label)
 			}
 		}()
 	}
@@ -393,6 +426,25 @@ func (p *appPanel) handleHeaderKey(event *tcell.EventKey) bool {
 	return false
 }
 
+// setNamespaceFilter replaces the namespace filter with an active filter on
+// namespace (or clears it when empty) and notifies the callback.
+func (p *appPanel) setNamespaceFilter(namespace string) {
+	p.namespaceFilter.Clear()
+	if namespace != "" {
+		p.namespaceFilter.Text = namespace
+		p.namespaceFilter.Confirm()
+	}
+	if p.namespaceFilterCallback != nil {
+		p.namespaceFilterCallback(p.namespaceFilter.Text)
+	}
+}
+
+// clearNamespaceFilter drops the namespace filter without notifying the
+// callback; used when the pods it filtered belong to a replaced connection.
+func (p *appPanel) clearNamespaceFilter() {
+	p.namespaceFilter.Clear()
+}
+
 // hasEscapableHeaderState returns true if header has state that ESC should clear
 func (p *appPanel) hasEscapableHeaderState() bool {
 	return p.namespaceFilter.HasEscapableState()
@@ -0,0 +1,108 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/vladimirvivien/ktop/k8s"
+	"github.com/vladimirvivien/ktop/ui"
+	"golang.org/x/term"
+)
+
+// shellCommand starts bash when the container has it, sh otherwise
+var shellCommand = []string{"/bin/sh", "-c", "command -v bash >/dev/null 2>&1 && exec bash || exec sh"}
+
+// resizePollInterval is how often the terminal size is checked while a
+// shell runs, so the container's terminal follows window resizes
+const resizePollInterval = 250 * time.Millisecond
+
+// ExecShell suspends the UI and opens an interactive shell in a container
+// on the terminal. The UI comes back when the shell exits. Must be called
+// on the UI goroutine.
+func (app *Application) ExecShell(namespace, podName, containerName string) {
+	if !app.CanChange("Exec") {
+		return
+	}
+
+	source := app.cluster.Source()
+	var err error
+	suspended := app.tviewApp.Suspend(func() {
+		err = runShell(app.rootCtx, source, namespace, podName, containerName)
+	})
+	if !suspended {
+		app.ShowToast("Cannot suspend the terminal for a shell", ui.ToastError, 5*time.Second)
+		return
+	}
+	if err != nil {
+		slog.Error("exec failed", "namespace", namespace, "pod", podName, "container", containerName, "error", err)
+		app.ShowToast(fmt.Sprintf("Exec into %s/%s failed: %v", podName, containerName, err), ui.ToastError, 5*time.Second)
+	}
+}
+
+// runShell runs shellCommand in the container attached to the terminal,
+// in raw mode, until it exits
+func runShell(ctx context.Context, source k8s.ClusterSource, namespace, podName, containerName string) error {
+	fmt.Fprintf(os.Stdout, "Connecting to %s/%s [%s], exit the shell to return to ktop...\n", namespace, podName, containerName)
+
+	// Read keys from a terminal of our own: closing it when the shell exits
+	// stops the copy to the container, which would otherwise take the next
+	// key pressed from the UI.
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return fmt.Errorf("open terminal: %w", err)
+	}
+	defer tty.Close()
+
+	state, err := term.MakeRaw(int(os.Stdin.Fd()))
+	if err != nil {
+		return fmt.Errorf("set terminal raw mode: %w", err)
+	}
+	defer term.Restore(int(os.Stdin.Fd()), state)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	return source.Exec(ctx, namespace, podName, k8s.ExecOptions{
+		Container: containerName,
+		Command:   shellCommand,
+		Stdin:     tty,
+		Stdout:    os.Stdout,
+		TTY:       true,
+		Resize:    watchTerminalSize(ctx),
+	})
+}
+
+// watchTerminalSize sends the size of the terminal and then each change of
+// it, until ctx is done
+func watchTerminalSize(ctx context.Context) <-chan k8s.TerminalSize {
+	sizes := make(chan k8s.TerminalSize, 1)
+	go func() {
+		defer close(sizes)
+		ticker := time.NewTicker(resizePollInterval)
+		defer ticker.Stop()
+
+		var last k8s.TerminalSize
+		for {
+			if width, height, err := term.GetSize(int(os.Stdout.Fd())); err == nil {
+				size := k8s.TerminalSize{Width: uint16(width), Height: uint16(height)}
+				if size != last {
+					select {
+					case sizes <- size:
+						last = size
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return sizes
+}
@@ -8,12 +8,22 @@ const (
 	PageNodeDetail    PageType = "node_detail"
 	PagePodDetail     PageType = "pod_detail"
 	PageContainerLogs PageType = "container_logs"
+	PageWorkloadPods  PageType = "workload_pods"
+	PageAlerts        PageType = "alerts"
+	PageControlPlane  PageType = "control_plane"
+	PageQuery         PageType = "query"
+	PagePressure      PageType = "pressure"
+	PageRightsizing   PageType = "rightsizing"
+	PageCapacity      PageType = "capacity"
+	PageEvents        PageType = "events"
+	PageLogs          PageType = "logs"
+	PagePortForwards  PageType = "port_forwards"
 )
 
 // PageState represents a page in the navigation stack
 type PageState struct {
 	PageType   PageType
-	ResourceID string // e.g., "minikube" for node, "kube-system/coredns-xyz" for pod
+	ResourceID string // e.g., "minikube" for node, "kube-system/coredns-xyz" for pod, "Deployment/kube-system/coredns" for workload
 	ScrollPos  int    // Scroll position to restore when navigating back
 }
 
@@ -0,0 +1,75 @@
+package application
+
+import (
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/ui"
+)
+
+const (
+	pickerPageName  = "picker"
+	pickerWidth     = 50
+	pickerMaxHeight = 20
+)
+
+// showPicker overlays a centered selection list on the main page.
+// onSelect receives the index of the chosen label; ESC closes the picker
+// without a selection. Focus is restored the same way as after a toast.
+func (p *appPanel) showPicker(title string, labels []string, current int, onSelect func(index int)) {
+	p.returnFocus = p.tviewApp.GetFocus()
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true)
+	list.SetTitle(" " + title + " ")
+	list.SetBorderColor(ui.FocusBorderColor())
+	list.SetSelectedBackgroundColor(ui.FocusBorderColor())
+	list.SetSelectedTextColor(tcell.ColorWhite)
+
+	for _, label := range labels {
+		list.AddItem(label, "", 0, nil)
+	}
+	if current >= 0 && current < len(labels) {
+		list.SetCurrentItem(current)
+	}
+
+	list.SetSelectedFunc(func(index int, _, _ string, _ rune) {
+		p.dismissPicker()
+		if onSelect != nil {
+			onSelect(index)
+		}
+	})
+	list.SetDoneFunc(p.dismissPicker)
+
+	height := len(labels) + 2
+	if height > pickerMaxHeight {
+		height = pickerMaxHeight
+	}
+
+	column := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(nil, 0, 1, false).
+		AddItem(list, height, 0, true).
+		AddItem(nil, 0, 1, false)
+	overlay := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(column, pickerWidth, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	p.root.AddPage(pickerPageName, overlay, true, true)
+	p.tviewApp.SetFocus(list)
+}
+
+// dismissPicker removes the picker overlay if one is shown.
+func (p *appPanel) dismissPicker() {
+	if !p.hasActivePicker() {
+		return
+	}
+	p.root.RemovePage(pickerPageName)
+	p.root.SwitchToPage("main")
+	if p.focusRestorationCallback != nil {
+		p.focusRestorationCallback()
+	}
+}
+
+// hasActivePicker returns true while a picker overlay is displayed.
+func (p *appPanel) hasActivePicker() bool {
+	return p.root != nil && p.root.HasPage(pickerPageName)
+}
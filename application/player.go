@@ -0,0 +1,80 @@
+package application
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// seekStep is how far Shift-Left/Shift-Right move replay playback
+const seekStep = 10 * time.Second
+
+// Player controls playback when the application runs against a recording
+// (see `ktop replay`).
+type Player interface {
+	Status() string
+	TogglePause()
+	Seek(d time.Duration)
+	ChangeSpeed(faster bool)
+}
+
+// SetPlayer enables the replay header status and playback keys. It must be
+// called before Run.
+func (app *Application) SetPlayer(player Player) {
+	app.player = player
+}
+
+// watchPlayer redraws the header every second so the playback position
+// keeps moving while no new batch is delivered.
+func (app *Application) watchPlayer() {
+	if app.player == nil {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-app.rootCtx.Done():
+				return
+			case <-ticker.C:
+				app.updateHeader()
+			}
+		}
+	}()
+}
+
+// handlePlayerKey applies the playback keys: Ctrl-P pauses and resumes,
+// Shift-Left/Shift-Right seek and Shift-Up/Shift-Down change speed. It
+// reports whether the key was consumed.
+func (app *Application) handlePlayerKey(event *tcell.EventKey) bool {
+	if app.player == nil {
+		return false
+	}
+
+	shift := event.Modifiers()&tcell.ModShift != 0
+	switch {
+	case event.Key() == tcell.KeyCtrlP:
+		app.player.TogglePause()
+	case shift && event.Key() == tcell.KeyRight:
+		app.player.Seek(seekStep)
+	case shift && event.Key() == tcell.KeyLeft:
+		app.player.Seek(-seekStep)
+	case shift && event.Key() == tcell.KeyUp:
+		app.player.ChangeSpeed(true)
+	case shift && event.Key() == tcell.KeyDown:
+		app.player.ChangeSpeed(false)
+	default:
+		return false
+	}
+	return true
+}
+
+// playerHeader returns the header segment for the replay status
+func (app *Application) playerHeader() string {
+	if app.player == nil {
+		return ""
+	}
+	return fmt.Sprintf(" [green]| Replay: [yellow]%s", app.player.Status())
+}
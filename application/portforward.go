@@ -0,0 +1,72 @@
+package application
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/vladimirvivien/ktop/portforward"
+	"github.com/vladimirvivien/ktop/ui"
+)
+
+// portForwardToastDuration is how long a forward started or failed toast
+// stays up
+const portForwardToastDuration = 5 * time.Second
+
+// GetPortForwards returns the manager of the port forwards
+func (app *Application) GetPortForwards() *portforward.Manager {
+	return app.forwards
+}
+
+// SetPortForwardsCallback sets the callback for showing the port forwards page
+func (app *Application) SetPortForwardsCallback(callback func()) {
+	app.portForwardsCallback = callback
+}
+
+// NavigateToPortForwards shows the port forwards and their traffic
+func (app *Application) NavigateToPortForwards() {
+	if current := app.navStack.Current(); current != nil && current.PageType == PagePortForwards {
+		return
+	}
+
+	app.navStack.Push(PageState{PageType: PagePortForwards})
+	if app.portForwardsCallback != nil {
+		app.portForwardsCallback()
+	}
+	app.updateFooterContext()
+}
+
+// StartPortForward forwards a local port to a pod port in the background
+// until it is stopped on the port forwards page or the connection is
+// switched. Must be called on the UI goroutine.
+func (app *Application) StartPortForward(spec portforward.Spec) {
+	if app.player != nil {
+		app.ShowToast("Port forwarding is not available in a replay", ui.ToastWarning, 3*time.Second)
+		return
+	}
+	slog.Info("starting port forward", "target", spec.Target(), "local_port", spec.LocalPort)
+	app.forwards.Start(spec, app.cluster.Source().PortForward)
+}
+
+// ShowPicker overlays a selection list on the current page. onSelect
+// receives the index of the label chosen; ESC closes it without one. Must
+// be called on the UI goroutine.
+func (app *Application) ShowPicker(title string, labels []string, onSelect func(index int)) {
+	app.panel.showPicker(title, labels, 0, onSelect)
+}
+
+// notifyPortForward is called by the manager, off the UI goroutine, when a
+// forward becomes active or fails
+func (app *Application) notifyPortForward(f portforward.Forward) {
+	msg := fmt.Sprintf("Forwarding localhost:%d to %s", f.LocalPort, f.Target())
+	level := ui.ToastSuccess
+	if f.State == portforward.StateFailed {
+		slog.Warn("port forward failed", "target", f.Target(), "local_port", f.LocalPort, "error", f.Err)
+		msg = fmt.Sprintf("Port forward to %s failed: %v", f.Target(), f.Err)
+		level = ui.ToastError
+	}
+
+	app.tviewApp.QueueUpdateDraw(func() {
+		app.ShowToast(msg, level, portForwardToastDuration)
+	})
+}
@@ -0,0 +1,247 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/vladimirvivien/ktop/config"
+	"github.com/vladimirvivien/ktop/k8s"
+	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/ui"
+)
+
+// ConnectFunc builds a client and metrics source for a kubeconfig context
+// and namespace (k8s.AllNamespaces for all), and returns them with the
+// configuration resolved for that context. It is called off the UI
+// goroutine. Anything it starts in the background should be bound to ctx,
+// which is cancelled when the connection is replaced or the app exits.
+type ConnectFunc func(ctx context.Context, contextName, namespace string) (*k8s.Client, metrics.MetricsSource, *config.Config, error)
+
+// ColumnsPanel is implemented by pages whose table columns come from the
+// configuration. A context's profile may set other columns, so they are
+// applied again on each switch, before the page is rebound.
+type ColumnsPanel interface {
+	SetColumns(columns config.ColumnsConfig)
+}
+
+// SetConnectFunc enables the in-app context and namespace pickers.
+// endSession releases whatever the caller started for the connection the
+// application was created with; it is called when that connection is
+// replaced.
+func (app *Application) SetConnectFunc(connect ConnectFunc, endSession context.CancelFunc) {
+	app.connect = connect
+	app.endSession = endSession
+}
+
+// beginSession returns the context the initial connection's pages run under.
+// Ending the session cancels it along with the caller's endSession.
+func (app *Application) beginSession() context.Context {
+	ctx, cancel := context.WithCancel(app.rootCtx)
+	initial := app.endSession
+	app.endSession = func() {
+		cancel()
+		if initial != nil {
+			initial()
+		}
+	}
+	return ctx
+}
+
+// showContextPicker lists the kubeconfig contexts and switches to the one
+// selected, keeping the current namespace.
+func (app *Application) showContextPicker() {
+	if app.connect == nil {
+		app.ShowToast("Context switching is not available", ui.ToastWarning, 3*time.Second)
+		return
+	}
+
+	current := app.cluster.ClusterContext()
+	contexts := app.cluster.Contexts()
+	labels := make([]string, len(contexts))
+	selected := -1
+	for i, name := range contexts {
+		labels[i] = name
+		if name == current {
+			labels[i] = name + " (current)"
+			selected = i
+		}
+	}
+
+	app.panel.showPicker("Context", labels, selected, func(index int) {
+		if contexts[index] == current {
+			return
+		}
+		app.switchSession(contexts[index], app.cluster.Namespace())
+	})
+}
+
+// showNamespacePicker lists the namespaces known to the current connection
+// and reconnects the same context scoped to the one selected.
+func (app *Application) showNamespacePicker() {
+	if app.connect == nil {
+		app.ShowToast("Namespace switching is not available", ui.ToastWarning, 3*time.Second)
+		return
+	}
+
+	list, err := app.cluster.Source().GetNamespaceList(app.rootCtx)
+	if err != nil {
+		slog.Error("namespace list failed", "error", err)
+		app.ShowToast(fmt.Sprintf("Cannot list namespaces: %v", err), ui.ToastError, 5*time.Second)
+		return
+	}
+
+	namespaces := make([]string, 0, len(list))
+	for _, ns := range list {
+		namespaces = append(namespaces, ns.Name)
+	}
+	sort.Strings(namespaces)
+	namespaces = append([]string{k8s.AllNamespaces}, namespaces...)
+
+	current := app.cluster.Namespace()
+	labels := make([]string, len(namespaces))
+	selected := -1
+	for i, ns := range namespaces {
+		labels[i] = namespaceLabel(ns)
+		if ns == current {
+			labels[i] += " (current)"
+			selected = i
+		}
+	}
+
+	app.panel.showPicker("Namespace", labels, selected, func(index int) {
+		if namespaces[index] == current {
+			return
+		}
+		app.switchSession(app.cluster.ClusterContext(), namespaces[index])
+	})
+}
+
+// switchSession connects to contextName/namespace in the background. The
+// current connection keeps running until the new one is ready, so a failed
+// switch leaves the UI where it was.
+func (app *Application) switchSession(contextName, namespace string) {
+	if app.switching {
+		app.ShowToast("A switch is already in progress", ui.ToastWarning, 3*time.Second)
+		return
+	}
+	app.switching = true
+
+	target := fmt.Sprintf("%s/%s", contextName, namespaceLabel(namespace))
+	toastID := app.ShowToast(fmt.Sprintf("Connecting to %s...", target), ui.ToastInfo, 0)
+	slog.Info("switching connection", "context", contextName, "namespace", namespace)
+
+	ctx, cancel := context.WithCancel(app.rootCtx)
+	go func() {
+		client, source, cfg, err := app.connect(ctx, contextName, namespace)
+		app.tviewApp.QueueUpdateDraw(func() {
+			app.switching = false
+			app.DismissToast(toastID)
+			if err != nil {
+				cancel()
+				slog.Error("connection switch failed", "context", contextName, "namespace", namespace, "error", err)
+				app.ShowToast(fmt.Sprintf("Cannot switch to %s: %v", target, err), ui.ToastError, 5*time.Second)
+				return
+			}
+			app.replaceSession(ctx, cancel, client, source, cfg)
+		})
+	}()
+}
+
+// replaceSession tears down the current connection (informers, metrics
+// source, health tracker) and rebinds the pages to the new one. The
+// application shell and navigation stack are kept; navigation returns to the
+// Overview because detail pages refer to resources of the old connection.
+// Must be called on the UI goroutine.
+func (app *Application) replaceSession(ctx context.Context, cancel context.CancelFunc, client *k8s.Client, source metrics.MetricsSource, cfg *config.Config) {
+	if app.endSession != nil {
+		app.endSession()
+	}
+	stopMetricsSource(app.metricsSource)
+	app.apiHealthTracker.Stop()
+	if app.apiHealthToastID != "" {
+		app.DismissToast(app.apiHealthToastID)
+		app.apiHealthToastID = ""
+	}
+	if app.loadingToastID != "" {
+		app.DismissToast(app.loadingToastID)
+		app.loadingToastID = ""
+	}
+
+	app.endSession = cancel
+	app.cluster = client
+	app.namespace = client.Namespace()
+	app.metricsSource = source
+	app.lastHealthyState = false
+	app.metricsConsecOK = 0
+	app.metricsLastErrorTime = time.Time{}
+	app.apiHealthTracker = app.newAPIHealthTracker()
+	client.Controller().SetHealthTracker(app.apiHealthTracker)
+	if app.alerts != nil {
+		app.alerts.Reset() // Alerts refer to nodes and pods of the old connection
+	}
+	app.forwards.Clear()    // Forwards go to pods of the old connection
+	app.pendingAction = nil // So would an action left unconfirmed
+
+	// Back to the Overview with the header focused
+	app.navStack.Clear()
+	app.panel.clearNamespaceFilter()
+	app.tabIdx = -1
+	if len(app.pages) > 0 {
+		app.panel.pages.SwitchToPage(app.pages[0].Title)
+	}
+
+	for _, page := range app.pages {
+		if panel, ok := page.Panel.(ColumnsPanel); ok && cfg != nil {
+			panel.SetColumns(cfg.Columns)
+		}
+		if panel, ok := page.Panel.(ui.SessionPanel); ok {
+			if err := panel.Rebind(ctx); err != nil {
+				slog.Error("page rebind failed", "page", page.Title, "error", err)
+			}
+		}
+	}
+
+	if app.panel.focusRestorationCallback != nil {
+		app.panel.focusRestorationCallback()
+	}
+	app.updateFooterContext()
+	app.updateHeaderDirect()
+
+	// Shown first so a "waiting for metrics" toast can replace it
+	app.ShowToast(
+		fmt.Sprintf("Connected to %s/%s", client.ClusterContext(), namespaceLabel(client.Namespace())),
+		ui.ToastSuccess,
+		3*time.Second,
+	)
+	app.watchMetricsHealth()
+
+	slog.Info("connection switched",
+		"context", client.ClusterContext(),
+		"namespace", client.Namespace(),
+		"host", client.RESTConfig().Host,
+	)
+}
+
+// stopMetricsSource detaches the health callback and stops sources that
+// collect in the background; metrics-server needs no cleanup.
+func stopMetricsSource(source metrics.MetricsSource) {
+	if source == nil {
+		return
+	}
+	source.SetHealthCallback(nil)
+	if s, ok := source.(interface{ Stop() error }); ok {
+		if err := s.Stop(); err != nil {
+			slog.Debug("metrics source stop", "error", err)
+		}
+	}
+}
+
+func namespaceLabel(namespace string) string {
+	if namespace == k8s.AllNamespaces {
+		return "(all namespaces)"
+	}
+	return namespace
+}
@@ -2,12 +2,16 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"log/slog"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
@@ -15,11 +19,15 @@ import (
 	"github.com/vladimirvivien/ktop/application"
 	"github.com/vladimirvivien/ktop/buildinfo"
 	"github.com/vladimirvivien/ktop/config"
+	"github.com/vladimirvivien/ktop/headless"
 	"github.com/vladimirvivien/ktop/internal/logging"
+	"github.com/vladimirvivien/ktop/internal/userdir"
 	"github.com/vladimirvivien/ktop/k8s"
 	"github.com/vladimirvivien/ktop/metrics"
 	k8sMetrics "github.com/vladimirvivien/ktop/metrics/k8s"
 	promMetrics "github.com/vladimirvivien/ktop/metrics/prom"
+	"github.com/vladimirvivien/ktop/metrics/promapi"
+	"github.com/vladimirvivien/ktop/theme"
 	"github.com/vladimirvivien/ktop/ui"
 	"github.com/vladimirvivien/ktop/views/overview"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
@@ -39,6 +47,15 @@ var (
 
 # Start ktop for a specific namespace and context
 %[1]s --namespace <namespace> --context <context>
+
+# Read metrics from an existing Prometheus or Thanos server instead of scraping
+%[1]s --metrics-source=prometheus-api --prometheus-url=http://localhost:9090
+
+# Stream node, pod, and summary snapshots as NDJSON without the terminal UI
+%[1]s --noui --log=stderr | jq 'select(.kind == "summary")'
+
+# Disable the actions that change the cluster, e.g. on a production context
+%[1]s --read-only
 `
 )
 
@@ -59,10 +76,23 @@ type ktopCmdOptions struct {
 	prometheusRetention      string
 	prometheusMaxSamples     int
 	prometheusComponents     []string
+	prometheusPersist        bool
+	prometheusURL            string
+
+	// Config file and display theme
+	configFile string
+	theme      string
 
 	// Logging configuration
 	logLevel  string
 	logFormat string
+	logDest   string
+
+	// Headless mode
+	noUI bool
+
+	// Disables the resource actions and container shells
+	readOnly bool
 }
 
 // NewKtopCmd returns a command for ktop
@@ -85,31 +115,50 @@ func NewKtopCmd() *cobra.Command {
 			return o.runKtop(c, args)
 		},
 	}
-	cmd.Flags().BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "If true, display metrics for all accessible namespaces")
-	cmd.Flags().StringVar(&o.nodeColumns, "node-columns", "", "Comma-separated list of node columns to display (e.g. 'NAME,CPU,MEM')")
-	cmd.Flags().StringVar(&o.podColumns, "pod-columns", "", "Comma-separated list of pod columns to display (e.g. 'NAMESPACE,POD,STATUS')")
-	cmd.Flags().BoolVar(&o.showAllColumns, "show-all-columns", true, "If true, show all columns (default)")
+	// Flags shared with the record and replay subcommands
+	flags := cmd.PersistentFlags()
+	flags.BoolVarP(&o.allNamespaces, "all-namespaces", "A", false, "If true, display metrics for all accessible namespaces")
+	flags.StringVar(&o.nodeColumns, "node-columns", "", "Comma-separated list of node columns to display (e.g. 'NAME,CPU,MEM')")
+	flags.StringVar(&o.podColumns, "pod-columns", "", "Comma-separated list of pod columns to display (e.g. 'NAMESPACE,POD,STATUS')")
+	flags.BoolVar(&o.showAllColumns, "show-all-columns", true, "If true, show all columns (default)")
+	flags.StringVar(&o.configFile, "config", "", "Path to the ktop config file (default ~/.ktop/config.yaml)")
+	flags.StringVar(&o.theme, "theme", theme.Default,
+		fmt.Sprintf("Color theme: %s", strings.Join(theme.Names(), ", ")))
 
 	// Metrics source flags
-	cmd.Flags().StringVar(&o.metricsSource, "metrics-source", "prometheus",
-		"Metrics source: 'prom'/'prometheus' (default), 'metrics-server', 'none'")
-	cmd.Flags().StringVar(&o.prometheusScrapeInterval, "prometheus-scrape-interval", "5s",
+	flags.StringVar(&o.metricsSource, "metrics-source", "prometheus",
+		"Metrics source: 'prom'/'prometheus' (default), 'prometheus-api', 'metrics-server', 'none'")
+	flags.StringVar(&o.prometheusScrapeInterval, "prometheus-scrape-interval", "5s",
 		"Prometheus scrape interval (e.g., 10s, 30s, 1m)")
-	cmd.Flags().StringVar(&o.prometheusRetention, "prometheus-retention", "1h",
+	flags.StringVar(&o.prometheusRetention, "prometheus-retention", "1h",
 		"Prometheus metrics retention time (e.g., 30m, 1h, 2h)")
-	cmd.Flags().IntVar(&o.prometheusMaxSamples, "prometheus-max-samples", 10000,
+	flags.IntVar(&o.prometheusMaxSamples, "prometheus-max-samples", 10000,
 		"Maximum samples per time series")
-	cmd.Flags().StringSliceVar(&o.prometheusComponents, "prometheus-components",
+	flags.StringSliceVar(&o.prometheusComponents, "prometheus-components",
 		[]string{"kubelet", "cadvisor"},
 		"Kubernetes components to scrape (comma-separated: kubelet,cadvisor,apiserver,etcd,scheduler,controller-manager,kube-proxy)")
+	flags.BoolVar(&o.prometheusPersist, "prometheus-persist", false,
+		"If true, keep scraped metrics history on disk (~/.ktop/data/<context>) across restarts")
+	flags.StringVar(&o.prometheusURL, "prometheus-url", "",
+		"Prometheus or Thanos Query URL used by --metrics-source=prometheus-api (e.g., http://localhost:9090)")
 
 	// Logging flags
-	cmd.Flags().StringVar(&o.logLevel, "log-level", "info",
+	flags.StringVar(&o.logLevel, "log-level", "info",
 		"Log verbosity: debug, info, warn, error")
-	cmd.Flags().StringVar(&o.logFormat, "log-format", "text",
+	flags.StringVar(&o.logFormat, "log-format", "text",
 		"Log record format: text or json")
+	flags.StringVar(&o.logDest, "log", string(logging.DestFile),
+		"Log destination: file (~/.ktop/ktop.log) or stderr (requires --noui or record)")
 
-	o.kubeFlags.AddFlags(cmd.Flags())
+	// Headless flags
+	cmd.Flags().BoolVar(&o.noUI, "noui", false,
+		"If true, skip the terminal UI and stream node, pod, and summary snapshots to stdout as NDJSON")
+	cmd.Flags().BoolVar(&o.readOnly, "read-only", false,
+		"If true, disable the actions that change the cluster (delete, evict, cordon, drain, scale, restart) and container shells")
+
+	o.kubeFlags.AddFlags(flags)
+
+	cmd.AddCommand(newRecordCmd(o), newReplayCmd(o))
 	return cmd
 }
 
@@ -133,6 +182,19 @@ func tryPrometheus(ctx context.Context, restConfig *rest.Config, cfg *promMetric
 	return source, nil
 }
 
+// tryPrometheusAPI creates a source backed by an existing Prometheus server
+// and verifies that the server answers queries.
+func tryPrometheusAPI(ctx context.Context, cfg *promapi.PromAPIConfig) (*promapi.PromAPISource, error) {
+	source, err := promapi.NewPromAPISource(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := source.TestConnection(ctx); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
 // selectMetricsSource selects and initializes the metrics source.
 // When enableFallback is true and prometheus fails, it falls back to metrics-server.
 func selectMetricsSource(
@@ -140,6 +202,7 @@ func selectMetricsSource(
 	sourceType string,
 	k8sC *k8s.Client,
 	promConfig *promMetrics.PromConfig,
+	apiConfig *promapi.PromAPIConfig,
 	enableFallback bool,
 ) (metrics.MetricsSource, *promMetrics.PromMetricsSource, error) {
 	switch sourceType {
@@ -159,6 +222,22 @@ func selectMetricsSource(
 		slog.Info("metrics source ready", "source", "metrics-server", "reason", "prometheus-fallback")
 		return source, nil, nil
 
+	case "prometheus-api":
+		slog.Info("connecting to metrics source", "source", "prometheus-api", "url", apiConfig.URL)
+		apiSource, err := tryPrometheusAPI(ctx, apiConfig)
+		if err == nil {
+			slog.Info("metrics source ready", "source", "prometheus-api")
+			return apiSource, nil, nil
+		}
+		if !enableFallback {
+			slog.Error("prometheus api required but unreachable", "url", apiConfig.URL, "error", err)
+			return nil, nil, fmt.Errorf("prometheus api not available: %v", err)
+		}
+		slog.Warn("prometheus api unavailable, falling back to metrics-server", "error", err)
+		source := k8sMetrics.NewMetricsServerSource(k8sC.Controller())
+		slog.Info("metrics source ready", "source", "metrics-server", "reason", "prometheus-api-fallback")
+		return source, nil, nil
+
 	case "metrics-server":
 		slog.Info("connecting to metrics source", "source", "metrics-server")
 		source := k8sMetrics.NewMetricsServerSource(k8sC.Controller())
@@ -178,83 +257,188 @@ func (o *ktopCmdOptions) runKtop(c *cobra.Command, args []string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	_, logCloser, err := o.prepare(c, o.noUI)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = logCloser.Close() }()
+
+	// The initial connection's background work (prometheus collection) is
+	// bound to sessionCtx so the application can end it on a context or
+	// namespace switch.
+	sessionCtx, endSession := context.WithCancel(ctx)
+	defer endSession()
+
+	sess, err := o.connect(sessionCtx, c)
+	if err != nil {
+		return err
+	}
+	if sess.promSource != nil {
+		defer sess.promSource.Stop()
+	}
+	cfg, k8sC, metricsSource := sess.cfg, sess.client, sess.metrics
+
+	if o.noUI {
+		return runHeadless(ctx, k8sC, metricsSource)
+	}
+
+	app := application.New(k8sC, metricsSource)
+	app.SetConnectFunc(o.switchFunc(c), endSession)
+	app.SetReadOnly(o.readOnly)
+	if cfg.Alerts.Enabled {
+		app.SetAlertRules(cfg.Alerts.Rules)
+	}
+
+	// Connect API health tracker to the k8s controller
+	k8sC.Controller().SetHealthTracker(app.GetAPIHealthTracker())
+
+	app.WelcomeBanner()
+	o.addOverviewPage(app, cfg)
+
+	if err := k8sC.AssertCoreAuthz(ctx); err != nil {
+		slog.Error("kubernetes authorization check failed", "error", err)
+		return fmt.Errorf("ktop: %s", err)
+	}
+	slog.Info("kubernetes authorization checks passed")
+
+	return runApp(ctx, app)
+}
+
+// prepare resolves the configuration, initializes logging, and applies the
+// namespace and theme settings shared by every command. allowStderr permits
+// --log=stderr for commands that don't draw the terminal UI. The returned
+// closer flushes the log and must be closed by the caller.
+func (o *ktopCmdOptions) prepare(c *cobra.Command, allowStderr bool) (*config.Config, io.Closer, error) {
+	logDest := logging.Destination(o.logDest)
+	switch logDest {
+	case logging.DestFile:
+	case logging.DestStderr:
+		if !allowStderr {
+			return nil, nil, fmt.Errorf("--log=stderr requires --noui or the record command")
+		}
+	default:
+		return nil, nil, fmt.Errorf("invalid --log destination: %s (valid: file, stderr)", o.logDest)
+	}
+
+	// Resolve configuration first: the log level may come from the config
+	// file or environment. Errors are returned directly since logging is
+	// not set up yet.
+	cfg, cfgPath, err := o.loadConfig(c, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
 	// Initialize structured logging before any other work so subsequent
 	// diagnostics land in ~/.ktop/ktop.log. A failure here is non-fatal:
 	// ktop continues with logging silenced rather than letting slog
 	// fall back to stderr (which would corrupt the TUI).
 	logCloser, err := logging.Init(logging.Config{
-		Level:  o.logLevel,
+		Level:  cfg.LogLevel,
 		Format: o.logFormat,
+		Dest:   logDest,
 	})
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "ktop: logging disabled: %v\n", err)
 		slog.SetDefault(slog.New(slog.NewTextHandler(io.Discard, nil)))
 	}
-	defer func() { _ = logCloser.Close() }()
 
 	slog.Info("ktop starting",
 		"version", buildinfo.Version,
-		"log_level", o.logLevel,
-		"metrics_source", o.metricsSource,
+		"command", c.Name(),
+		"log_level", cfg.LogLevel,
+		"metrics_source", cfg.Source.Type,
+		"config_file", cfgPath,
+		"noui", o.noUI,
 	)
 
 	if o.allNamespaces {
 		o.namespace = k8s.AllNamespaces
+	} else if cfg.Namespace != "" && !c.Flags().Changed("namespace") {
+		*o.kubeFlags.Namespace = cfg.Namespace
 	}
 
-	// Load configuration with defaults
-	cfg := config.DefaultConfig()
-
-	// Override with CLI flags
-	cfg.Source.Type = o.metricsSource
-
-	if c.Flags().Changed("prometheus-scrape-interval") {
-		interval, err := time.ParseDuration(o.prometheusScrapeInterval)
-		if err != nil {
-			slog.Error("invalid prometheus-scrape-interval", "value", o.prometheusScrapeInterval, "error", err)
-			return fmt.Errorf("invalid prometheus-scrape-interval: %w", err)
-		}
-		cfg.Prometheus.ScrapeInterval = interval
+	if err := ui.ApplyTheme(cfg.Theme); err != nil {
+		_ = logCloser.Close()
+		return nil, nil, fmt.Errorf("invalid configuration: %w", err)
 	}
+	return cfg, logCloser, nil
+}
 
-	if c.Flags().Changed("prometheus-retention") {
-		retention, err := time.ParseDuration(o.prometheusRetention)
-		if err != nil {
-			slog.Error("invalid prometheus-retention", "value", o.prometheusRetention, "error", err)
-			return fmt.Errorf("invalid prometheus-retention: %w", err)
+// addOverviewPage adds the overview page with the configured columns
+func (o *ktopCmdOptions) addOverviewPage(app *application.Application, cfg *config.Config) {
+	page := overview.NewWithColumnOptions(app, "Overview", o.showAllColumns, nil, nil)
+	page.SetColumns(cfg.Columns)
+	page.SetStructuredLogs(cfg.Logs.Structured, cfg.Logs.Fields)
+	app.AddPage(page)
+}
+
+// runApp checks the terminal size and runs app until it exits or ctx is
+// cancelled.
+func runApp(ctx context.Context, app *application.Application) error {
+	// Check terminal height before starting TUI
+	screen, err := tcell.NewScreen()
+	if err == nil {
+		if err := screen.Init(); err == nil {
+			_, height := screen.Size()
+			screen.Fini()
+			if height < ui.MinTerminalHeight {
+				slog.Error("terminal too small", "height", height, "min", ui.MinTerminalHeight)
+				return fmt.Errorf("terminal height too small (%d rows). Minimum required: %d rows", height, ui.MinTerminalHeight)
+			}
 		}
-		cfg.Prometheus.RetentionTime = retention
 	}
 
-	if c.Flags().Changed("prometheus-max-samples") {
-		cfg.Prometheus.MaxSamples = o.prometheusMaxSamples
-	}
+	// launch application
+	appErr := make(chan error)
+	go func() {
+		appErr <- app.Run(ctx)
+	}()
 
-	if c.Flags().Changed("prometheus-components") {
-		components, err := config.ParseComponents(o.prometheusComponents)
+	select {
+	case err := <-appErr:
 		if err != nil {
-			slog.Error("invalid prometheus-components", "value", o.prometheusComponents, "error", err)
-			return fmt.Errorf("invalid prometheus-components: %w", err)
+			slog.Error("application exited with error", "error", err)
+			os.Exit(1)
 		}
-		cfg.Prometheus.Components = components
+	case <-ctx.Done():
 	}
 
-	// Validate configuration
-	if err := cfg.Validate(); err != nil {
-		slog.Error("invalid configuration", "error", err)
-		return fmt.Errorf("invalid configuration: %w", err)
-	}
+	return nil
+}
 
+// session is a connection to one kubeconfig context and namespace: the
+// client, the metrics source started for it, and the configuration resolved
+// with that context's profile.
+type session struct {
+	client     *k8s.Client
+	metrics    metrics.MetricsSource
+	promSource *promMetrics.PromMetricsSource // nil unless prometheus is in use
+	cfg        *config.Config
+}
+
+// connect creates a client from the context and namespace currently set in
+// o.kubeFlags, re-resolves the configuration with that context's profile,
+// and selects the metrics source. Prometheus collection runs until ctx is
+// cancelled.
+func (o *ktopCmdOptions) connect(ctx context.Context, c *cobra.Command) (*session, error) {
 	k8sC, err := k8s.New(o.kubeFlags)
 	if err != nil {
 		slog.Error("kubernetes client creation failed", "error", err)
-		return fmt.Errorf("ktop: failed to create Kubernetes client: %s", err)
+		return nil, fmt.Errorf("ktop: failed to create Kubernetes client: %s", err)
 	}
-	slog.Info("cluster connected", "host", k8sC.RESTConfig().Host)
+	slog.Info("cluster connected", "host", k8sC.RESTConfig().Host, "context", k8sC.ClusterContext())
 
-	// Initialize metrics source based on configuration
-	// Fallback is enabled only when using default (not explicitly set)
-	enableFallback := !c.Flags().Changed("metrics-source")
+	// Now that the context is known, resolve the configuration with its
+	// profile layered between the config file and env/flags.
+	clusterContext := k8sC.ClusterContext()
+	cfg, _, err := o.loadConfig(c, clusterContext)
+	if err != nil {
+		slog.Error("invalid configuration", "context", clusterContext, "error", err)
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+	if cfg.HasProfile(clusterContext) {
+		slog.Info("config profile applied", "context", clusterContext, "metrics_source", cfg.Source.Type)
+	}
 
 	promConfig := &promMetrics.PromConfig{
 		Enabled:        true,
@@ -262,72 +446,159 @@ func (o *ktopCmdOptions) runKtop(c *cobra.Command, args []string) error {
 		RetentionTime:  cfg.Prometheus.RetentionTime,
 		MaxSamples:     cfg.Prometheus.MaxSamples,
 		Components:     cfg.Prometheus.Components,
+		ExtraMetrics:   cfg.Prometheus.ExtraMetrics,
+	}
+	if cfg.Prometheus.Persist {
+		dataDir, err := userdir.DataDir(clusterContext)
+		if err != nil {
+			slog.Warn("metrics history will not be persisted", "error", err)
+		} else {
+			promConfig.DataDir = dataDir
+		}
 	}
 
-	metricsSource, promSource, err := selectMetricsSource(ctx, cfg.Source.Type, k8sC, promConfig, enableFallback)
+	apiConfig := promapi.DefaultPromAPIConfig()
+	apiConfig.URL = cfg.Prometheus.URL
+
+	// Fallback is enabled only when the source was not set explicitly
+	metricsSource, promSource, err := selectMetricsSource(ctx, cfg.Source.Type, k8sC, promConfig, apiConfig, cfg.Source.Fallback)
 	if err != nil {
-		return err
-	}
-	if promSource != nil {
-		defer promSource.Stop()
+		return nil, err
 	}
 
-	app := application.New(k8sC, metricsSource)
+	return &session{client: k8sC, metrics: metricsSource, promSource: promSource, cfg: cfg}, nil
+}
 
-	// Connect API health tracker to the k8s controller
-	k8sC.Controller().SetHealthTracker(app.GetAPIHealthTracker())
+// switchFunc returns the application's ConnectFunc: it points o.kubeFlags at
+// the requested context and namespace, connects, and verifies read access.
+// On failure the flags are restored so the next attempt starts from the
+// connection still in use.
+func (o *ktopCmdOptions) switchFunc(c *cobra.Command) application.ConnectFunc {
+	return func(ctx context.Context, contextName, namespace string) (*k8s.Client, metrics.MetricsSource, *config.Config, error) {
+		prevContext, prevNamespace := *o.kubeFlags.Context, *o.kubeFlags.Namespace
+		*o.kubeFlags.Context, *o.kubeFlags.Namespace = contextName, namespace
 
-	app.WelcomeBanner()
+		sess, err := o.connect(ctx, c)
+		if err == nil {
+			if err = sess.client.AssertCoreAuthz(ctx); err != nil {
+				slog.Error("kubernetes authorization check failed", "context", contextName, "error", err)
+				if sess.promSource != nil {
+					sess.promSource.Stop()
+				}
+			}
+		}
+		if err != nil {
+			*o.kubeFlags.Context, *o.kubeFlags.Namespace = prevContext, prevNamespace
+			return nil, nil, nil, err
+		}
+		return sess.client, sess.metrics, sess.cfg, nil
+	}
+}
 
-	// Process column options
-	nodeColumns := []string{}
-	if o.nodeColumns != "" {
-		nodeColumns = strings.Split(o.nodeColumns, ",")
-		o.showAllColumns = false
+// loadConfig builds the effective configuration and returns it with the
+// config file path that was read (empty if none). Precedence, lowest to
+// highest: built-in defaults, config file, the profile for clusterContext
+// (if any), KTOP_* environment, flags.
+func (o *ktopCmdOptions) loadConfig(c *cobra.Command, clusterContext string) (*config.Config, string, error) {
+	cfg := config.DefaultConfig()
+
+	// The default location is optional; a file named by --config or
+	// $KTOP_CONFIG must exist.
+	explicit := c.Flags().Changed("config") || os.Getenv(config.EnvConfig) != ""
+	path := o.configFile
+	if !c.Flags().Changed("config") {
+		defaultPath, err := config.DefaultPath()
+		if err != nil {
+			return nil, "", err
+		}
+		path = defaultPath
+	}
+	if err := cfg.LoadFile(path); err != nil {
+		if explicit || !errors.Is(err, fs.ErrNotExist) {
+			return nil, "", err
+		}
+		path = ""
 	}
 
-	podColumns := []string{}
-	if o.podColumns != "" {
-		podColumns = strings.Split(o.podColumns, ",")
-		o.showAllColumns = false
+	if clusterContext != "" {
+		cfg.ApplyProfile(clusterContext)
 	}
 
-	// Create a new overview page with column options
-	app.AddPage(overview.NewWithColumnOptions(app, "Overview", o.showAllColumns, nodeColumns, podColumns))
+	if err := cfg.LoadEnv(os.LookupEnv); err != nil {
+		return nil, "", err
+	}
 
-	if err := k8sC.AssertCoreAuthz(ctx); err != nil {
-		slog.Error("kubernetes authorization check failed", "error", err)
-		return fmt.Errorf("ktop: %s", err)
+	flags := c.Flags()
+	if flags.Changed("metrics-source") {
+		cfg.SetSource(o.metricsSource)
 	}
-	slog.Info("kubernetes authorization checks passed")
 
-	// Check terminal height before starting TUI
-	screen, err := tcell.NewScreen()
-	if err == nil {
-		if err := screen.Init(); err == nil {
-			_, height := screen.Size()
-			screen.Fini()
-			if height < ui.MinTerminalHeight {
-				slog.Error("terminal too small", "height", height, "min", ui.MinTerminalHeight)
-				return fmt.Errorf("terminal height too small (%d rows). Minimum required: %d rows", height, ui.MinTerminalHeight)
-			}
+	if flags.Changed("prometheus-scrape-interval") {
+		interval, err := time.ParseDuration(o.prometheusScrapeInterval)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid prometheus-scrape-interval: %w", err)
 		}
+		cfg.Prometheus.ScrapeInterval = interval
 	}
 
-	// launch application
-	appErr := make(chan error)
-	go func() {
-		appErr <- app.Run(ctx)
-	}()
+	if flags.Changed("prometheus-retention") {
+		retention, err := time.ParseDuration(o.prometheusRetention)
+		if err != nil {
+			return nil, "", fmt.Errorf("invalid prometheus-retention: %w", err)
+		}
+		cfg.Prometheus.RetentionTime = retention
+	}
 
-	select {
-	case err := <-appErr:
+	if flags.Changed("prometheus-max-samples") {
+		cfg.Prometheus.MaxSamples = o.prometheusMaxSamples
+	}
+
+	if flags.Changed("prometheus-components") {
+		components, err := config.ParseComponents(o.prometheusComponents)
 		if err != nil {
-			slog.Error("application exited with error", "error", err)
-			os.Exit(1)
+			return nil, "", fmt.Errorf("invalid prometheus-components: %w", err)
 		}
-	case <-ctx.Done():
+		cfg.Prometheus.Components = components
 	}
 
-	return nil
+	if flags.Changed("prometheus-persist") {
+		cfg.Prometheus.Persist = o.prometheusPersist
+	}
+
+	if flags.Changed("prometheus-url") {
+		cfg.Prometheus.URL = o.prometheusURL
+	}
+
+	if flags.Changed("node-columns") {
+		cfg.Columns.Node = config.SplitList(o.nodeColumns)
+	}
+	if flags.Changed("pod-columns") {
+		cfg.Columns.Pod = config.SplitList(o.podColumns)
+	}
+	if flags.Changed("theme") {
+		cfg.Theme = o.theme
+	}
+	if flags.Changed("log-level") {
+		cfg.LogLevel = o.logLevel
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, "", err
+	}
+	return cfg, path, nil
+}
+
+// runHeadless streams controller refreshes to stdout as NDJSON until the
+// process receives SIGINT or SIGTERM.
+func runHeadless(ctx context.Context, k8sC *k8s.Client, metricsSource metrics.MetricsSource) error {
+	if err := k8sC.AssertCoreAuthz(ctx); err != nil {
+		slog.Error("kubernetes authorization check failed", "error", err)
+		return fmt.Errorf("ktop: %s", err)
+	}
+	slog.Info("kubernetes authorization checks passed")
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return headless.New(os.Stdout, metricsSource).Run(ctx, k8sC.Controller())
 }
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/vladimirvivien/ktop/buildinfo"
+	"github.com/vladimirvivien/ktop/headless"
+)
+
+var recordExamples = `
+# Record the current context until Ctrl-C
+%[1]s record -o incident.ndjson
+
+# Record all namespaces of a context for ten minutes using metrics-server
+%[1]s record -A --context prod --metrics-source=metrics-server -o prod.ndjson --duration 10m
+`
+
+type recordCmdOptions struct {
+	*ktopCmdOptions
+	output   string
+	duration time.Duration
+}
+
+func newRecordCmd(parent *ktopCmdOptions) *cobra.Command {
+	o := &recordCmdOptions{ktopCmdOptions: parent}
+	cmd := &cobra.Command{
+		Use:          "record -o FILE",
+		Short:        "Record node, pod, and summary snapshots with metrics samples to a file for replay",
+		Example:      fmt.Sprintf(recordExamples, commandName()),
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			return o.runRecord(c)
+		},
+	}
+	cmd.Flags().StringVarP(&o.output, "output", "o", "", "File to write the recording to")
+	cmd.Flags().DurationVar(&o.duration, "duration", 0, "Stop recording after this long (default: until interrupted)")
+	_ = cmd.MarkFlagRequired("output")
+	return cmd
+}
+
+func (o *recordCmdOptions) runRecord(c *cobra.Command) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, logCloser, err := o.prepare(c, true)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = logCloser.Close() }()
+
+	sess, err := o.connect(ctx, c)
+	if err != nil {
+		return err
+	}
+	if sess.promSource != nil {
+		defer sess.promSource.Stop()
+	}
+	k8sC := sess.client
+	if err := k8sC.AssertCoreAuthz(ctx); err != nil {
+		slog.Error("kubernetes authorization check failed", "error", err)
+		return fmt.Errorf("ktop: %s", err)
+	}
+
+	f, err := os.Create(o.output)
+	if err != nil {
+		return fmt.Errorf("ktop: create recording: %w", err)
+	}
+
+	info := headless.SessionInfo{
+		Context:       k8sC.ClusterContext(),
+		Cluster:       k8sC.ClusterName(),
+		User:          k8sC.Username(),
+		ServerVersion: k8sC.GetServerVersion(),
+		Namespace:     k8sC.Namespace(),
+		KtopVersion:   buildinfo.Version,
+	}
+	if sess.metrics != nil {
+		info.MetricsSource = sess.metrics.GetSourceInfo().Type
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if o.duration > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeout(ctx, o.duration)
+		defer cancelTimeout()
+	}
+
+	slog.Info("recording started", "file", o.output, "context", info.Context, "duration", o.duration)
+	fmt.Fprintf(os.Stderr, "Recording %s to %s, press Ctrl-C to stop\n", info.Context, o.output)
+	start := time.Now()
+
+	runErr := headless.NewRecorder(f, sess.metrics, info).Run(ctx, k8sC.Controller())
+	if err := f.Close(); err != nil && runErr == nil {
+		runErr = fmt.Errorf("ktop: write recording: %w", err)
+	}
+	if runErr != nil {
+		return runErr
+	}
+
+	elapsed := time.Since(start).Round(time.Second)
+	slog.Info("recording finished", "file", o.output, "elapsed", elapsed)
+	fmt.Fprintf(os.Stderr, "Recorded %s to %s\n", elapsed, o.output)
+	return nil
+}
+
+// commandName is the program name as invoked, used in examples
+func commandName() string {
+	return filepath.Base(os.Args[0])
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/spf13/cobra"
+	"github.com/vladimirvivien/ktop/application"
+	"github.com/vladimirvivien/ktop/replay"
+)
+
+var replayExamples = `
+# Replay a recording made with "%[1]s record"
+%[1]s replay incident.ndjson
+
+# Playback keys: Ctrl-P pause/resume, Shift-Left/Right seek 10s,
+# Shift-Up/Down change speed
+`
+
+func newReplayCmd(parent *ktopCmdOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:          "replay FILE",
+		Short:        "Replay a session captured with the record command",
+		Example:      fmt.Sprintf(replayExamples, commandName()),
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(c *cobra.Command, args []string) error {
+			return parent.runReplay(c, args[0])
+		},
+	}
+}
+
+// runReplay plays a recording through the overview and detail views. No
+// cluster connection is made; kubeconfig and metrics flags are ignored.
+func (o *ktopCmdOptions) runReplay(c *cobra.Command, path string) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cfg, logCloser, err := o.prepare(c, false)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = logCloser.Close() }()
+
+	rec, err := replay.LoadFile(path)
+	if err != nil {
+		slog.Error("recording load failed", "file", path, "error", err)
+		return fmt.Errorf("ktop: %w", err)
+	}
+	slog.Info("replaying recording",
+		"file", path,
+		"context", rec.Session.Context,
+		"start", rec.Start,
+		"duration", rec.Duration(),
+		"metrics_source", rec.Session.MetricsSource,
+	)
+
+	player := replay.NewPlayer(rec)
+	app := application.New(player, player.MetricsSource())
+	app.SetPlayer(player)
+	if cfg.Alerts.Enabled {
+		app.SetAlertRules(cfg.Alerts.Rules)
+	}
+	o.addOverviewPage(app, cfg)
+
+	return runApp(ctx, app)
+}
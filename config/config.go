@@ -5,13 +5,17 @@ import (
 	"strings"
 	"time"
 
+	"github.com/vladimirvivien/ktop/alerts"
+	"github.com/vladimirvivien/ktop/metrics"
 	"github.com/vladimirvivien/ktop/prom"
+	"github.com/vladimirvivien/ktop/theme"
 )
 
 // ValidMetricsSources maps input values to canonical names
 var ValidMetricsSources = map[string]string{
 	"prom":           "prometheus",
 	"prometheus":     "prometheus",
+	"prometheus-api": "prometheus-api",
 	"metrics-server": "metrics-server",
 	"none":           "none",
 }
@@ -20,7 +24,7 @@ var ValidMetricsSources = map[string]string{
 func NormalizeMetricsSource(source string) (string, error) {
 	normalized, ok := ValidMetricsSources[strings.ToLower(source)]
 	if !ok {
-		return "", fmt.Errorf("invalid metrics source: %s (valid: prom, prometheus, metrics-server, none)", source)
+		return "", fmt.Errorf("invalid metrics source: %s (valid: prom, prometheus, prometheus-api, metrics-server, none)", source)
 	}
 	return normalized, nil
 }
@@ -29,11 +33,46 @@ func NormalizeMetricsSource(source string) (string, error) {
 type Config struct {
 	Source     SourceConfig
 	Prometheus PrometheusConfig
+	Columns    ColumnsConfig
+	Alerts     AlertsConfig
+	Logs       LogsConfig
+	Namespace  string // empty uses the kubeconfig context's namespace
+	Theme      string // see theme.Names; empty means default
+	LogLevel   string // "debug" | "info" | "warn" | "error"
+
+	// Profiles maps kubeconfig context names to per-context overrides.
+	Profiles map[string]Profile
 }
 
 // SourceConfig defines which metrics source to use
 type SourceConfig struct {
-	Type string // "metrics-server" | "prometheus" | "none"
+	Type string // "metrics-server" | "prometheus" | "prometheus-api" | "none"
+
+	// Fallback allows falling back to metrics-server when prometheus is
+	// unreachable. It is on for the built-in default and turned off once a
+	// source is chosen explicitly (file, env, or flag).
+	Fallback bool
+}
+
+// ColumnsConfig lists the overview columns to display. Empty shows all.
+type ColumnsConfig struct {
+	Node []string
+	Pod  []string
+
+	// Custom columns are added to the node or pod table they are grouped by
+	Custom []metrics.CustomColumn
+}
+
+// AlertsConfig holds the alert rules evaluated on each refresh
+type AlertsConfig struct {
+	Enabled bool
+	Rules   []alerts.Rule
+}
+
+// LogsConfig sets how the log views render JSON and logfmt lines
+type LogsConfig struct {
+	Structured bool     // render them as columns from the start
+	Fields     []string // keys shown next to the message, in this order
 }
 
 // PrometheusConfig holds Prometheus-specific settings
@@ -42,6 +81,12 @@ type PrometheusConfig struct {
 	RetentionTime  time.Duration
 	MaxSamples     int
 	Components     []prom.ComponentType
+	Persist        bool   // keep scraped history under ~/.ktop/data/<context> across restarts
+	URL            string // server queried by the prometheus-api source
+
+	// ExtraMetrics lists metric names or regexes scraped per component in
+	// addition to the ones ktop uses itself
+	ExtraMetrics map[prom.ComponentType][]string
 }
 
 // DefaultConfig returns the default configuration
@@ -49,7 +94,8 @@ type PrometheusConfig struct {
 func DefaultConfig() *Config {
 	return &Config{
 		Source: SourceConfig{
-			Type: "prometheus",
+			Type:     "prometheus",
+			Fallback: true,
 		},
 		Prometheus: PrometheusConfig{
 			ScrapeInterval: 5 * time.Second,
@@ -60,9 +106,21 @@ func DefaultConfig() *Config {
 				prom.ComponentCAdvisor,
 			},
 		},
+		Alerts: AlertsConfig{
+			Enabled: true,
+			Rules:   alerts.DefaultRules(),
+		},
+		Theme:    theme.Default,
+		LogLevel: "info",
 	}
 }
 
+// SetSource selects the metrics source explicitly, disabling fallback.
+func (c *Config) SetSource(source string) {
+	c.Source.Type = source
+	c.Source.Fallback = false
+}
+
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
 	// Validate and normalize source type
@@ -91,6 +149,48 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	if c.Source.Type == "prometheus-api" && c.Prometheus.URL == "" {
+		return fmt.Errorf("prometheus-url is required for the prometheus-api metrics source")
+	}
+
+	for name, p := range c.Profiles {
+		if p.Source == "" {
+			continue
+		}
+		if _, err := NormalizeMetricsSource(p.Source); err != nil {
+			return fmt.Errorf("profile %s: %w", name, err)
+		}
+	}
+
+	names := make(map[string]bool, len(c.Alerts.Rules))
+	for _, rule := range c.Alerts.Rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("alerts: %w", err)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("alerts: duplicate rule name %s", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+
+	columns := make(map[string]bool, len(c.Columns.Custom))
+	for _, col := range c.Columns.Custom {
+		if columns[col.Name] {
+			return fmt.Errorf("columns: duplicate custom column %s", col.Name)
+		}
+		columns[col.Name] = true
+	}
+
+	if !theme.IsValid(c.Theme) {
+		return fmt.Errorf("invalid theme: %s (valid: %s)", c.Theme, strings.Join(theme.Names(), ", "))
+	}
+
+	switch strings.ToLower(c.LogLevel) {
+	case "", "debug", "info", "warn", "warning", "error":
+	default:
+		return fmt.Errorf("invalid log-level: %s (valid: debug, info, warn, error)", c.LogLevel)
+	}
+
 	return nil
 }
 
@@ -96,7 +96,7 @@ func TestValidate_InvalidSource(t *testing.T) {
 		t.Error("Expected error for invalid source type")
 	}
 
-	expectedMsg := "invalid metrics source: invalid-source (valid: prom, prometheus, metrics-server, none)"
+	expectedMsg := "invalid metrics source: invalid-source (valid: prom, prometheus, prometheus-api, metrics-server, none)"
 	if err != nil && err.Error() != expectedMsg {
 		t.Errorf("Expected error message '%s', got '%s'", expectedMsg, err.Error())
 	}
@@ -378,6 +378,7 @@ func TestNormalizeMetricsSource(t *testing.T) {
 		{name: "prom alias", input: "prom", expected: "prometheus", wantErr: false},
 		{name: "PROM uppercase", input: "PROM", expected: "prometheus", wantErr: false},
 		{name: "Prometheus mixed case", input: "Prometheus", expected: "prometheus", wantErr: false},
+		{name: "prometheus-api", input: "prometheus-api", expected: "prometheus-api", wantErr: false},
 		{name: "metrics-server", input: "metrics-server", expected: "metrics-server", wantErr: false},
 		{name: "METRICS-SERVER uppercase", input: "METRICS-SERVER", expected: "metrics-server", wantErr: false},
 		{name: "none", input: "none", expected: "none", wantErr: false},
@@ -409,6 +410,19 @@ func TestNormalizeMetricsSource(t *testing.T) {
 	}
 }
 
+func TestValidate_PrometheusAPIRequiresURL(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.SetSource("prometheus-api")
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for prometheus-api without a url")
+	}
+
+	cfg.Prometheus.URL = "http://prometheus.monitoring:9090"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error: %v", err)
+	}
+}
+
 func TestValidate_NormalizesSource(t *testing.T) {
 	// Test that Validate normalizes the source type
 	cfg := &Config{
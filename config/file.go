@@ -0,0 +1,414 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vladimirvivien/ktop/alerts"
+	"github.com/vladimirvivien/ktop/internal/userdir"
+	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/prom"
+	"sigs.k8s.io/yaml"
+)
+
+// FileName is the config file name inside the ktop directory.
+const FileName = "config.yaml"
+
+// Environment variables that override values from the config file.
+// Flags, when set, take precedence over all of these.
+const (
+	EnvConfig                   = "KTOP_CONFIG"
+	EnvMetricsSource            = "KTOP_METRICS_SOURCE"
+	EnvPrometheusScrapeInterval = "KTOP_PROMETHEUS_SCRAPE_INTERVAL"
+	EnvPrometheusRetention      = "KTOP_PROMETHEUS_RETENTION"
+	EnvPrometheusMaxSamples     = "KTOP_PROMETHEUS_MAX_SAMPLES"
+	EnvPrometheusComponents     = "KTOP_PROMETHEUS_COMPONENTS"
+	EnvPrometheusPersist        = "KTOP_PROMETHEUS_PERSIST"
+	EnvPrometheusURL            = "KTOP_PROMETHEUS_URL"
+	EnvNamespace                = "KTOP_NAMESPACE"
+	EnvNodeColumns              = "KTOP_NODE_COLUMNS"
+	EnvPodColumns               = "KTOP_POD_COLUMNS"
+	EnvAlerts                   = "KTOP_ALERTS"
+	EnvTheme                    = "KTOP_THEME"
+	EnvLogLevel                 = "KTOP_LOG_LEVEL"
+)
+
+// fileConfig mirrors the on-disk YAML layout. Durations are kept as strings
+// so they can be written the same way as on the command line ("30s", "2h").
+// Pointer and nil-able fields distinguish "unset" from a zero value.
+type fileConfig struct {
+	Source *struct {
+		Type string `json:"type"`
+	} `json:"source"`
+	Prometheus *struct {
+		ScrapeInterval string   `json:"scrapeInterval"`
+		Retention      string   `json:"retention"`
+		MaxSamples     *int     `json:"maxSamples"`
+		Components     []string `json:"components"`
+		Persist        *bool    `json:"persist"`
+		URL            string   `json:"url"`
+
+		// ExtraMetrics maps component names to metric names or regexes
+		ExtraMetrics map[string][]string `json:"extraMetrics"`
+	} `json:"prometheus"`
+	Columns   *fileColumns           `json:"columns"`
+	Alerts    *fileAlerts            `json:"alerts"`
+	Logs      *fileLogs              `json:"logs"`
+	Namespace string                 `json:"namespace"`
+	Theme     string                 `json:"theme"`
+	LogLevel  string                 `json:"logLevel"`
+	Profiles  map[string]fileProfile `json:"profiles"`
+}
+
+type fileColumns struct {
+	Node   []string `json:"node"`
+	Pod    []string `json:"pod"`
+	Custom []string `json:"custom"` // "NAME = aggregation", see metrics.ParseCustomColumn
+}
+
+type fileLogs struct {
+	Structured *bool    `json:"structured"`
+	Fields     []string `json:"fields"`
+}
+
+// fileAlerts is the alerts key. Rules, when present, replace the
+// built-in rules rather than adding to them.
+type fileAlerts struct {
+	Enabled *bool       `json:"enabled"`
+	Rules   []fileAlert `json:"rules"`
+}
+
+type fileAlert struct {
+	Name     string `json:"name"`
+	Target   string `json:"target"`
+	Expr     string `json:"expr"`
+	For      string `json:"for"`
+	Window   string `json:"window"`
+	Severity string `json:"severity"`
+}
+
+// fileProfile is a per-context entry under the top-level profiles key.
+type fileProfile struct {
+	Source         string       `json:"source"`
+	PrometheusURL  string       `json:"prometheusURL"`
+	ScrapeInterval string       `json:"scrapeInterval"`
+	Components     []string     `json:"components"`
+	Columns        *fileColumns `json:"columns"`
+}
+
+// DefaultPath returns the config file location: $KTOP_CONFIG if set,
+// otherwise config.yaml inside the ktop directory.
+func DefaultPath() (string, error) {
+	if path := os.Getenv(EnvConfig); path != "" {
+		return path, nil
+	}
+	dir, err := userdir.Path()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// LoadFile reads the YAML file at path and merges every key it sets into c.
+// Keys absent from the file leave c unchanged. A missing file is reported
+// with an error wrapping fs.ErrNotExist so callers can treat it as optional.
+func (c *Config) LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config %s: %w", path, err)
+	}
+
+	var fc fileConfig
+	if err := yaml.UnmarshalStrict(data, &fc); err != nil {
+		return fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	if err := c.mergeFile(&fc); err != nil {
+		return fmt.Errorf("config %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) mergeFile(fc *fileConfig) error {
+	if fc.Source != nil && fc.Source.Type != "" {
+		c.SetSource(fc.Source.Type)
+	}
+
+	if p := fc.Prometheus; p != nil {
+		if p.ScrapeInterval != "" {
+			d, err := time.ParseDuration(p.ScrapeInterval)
+			if err != nil {
+				return fmt.Errorf("prometheus.scrapeInterval: %w", err)
+			}
+			c.Prometheus.ScrapeInterval = d
+		}
+		if p.Retention != "" {
+			d, err := time.ParseDuration(p.Retention)
+			if err != nil {
+				return fmt.Errorf("prometheus.retention: %w", err)
+			}
+			c.Prometheus.RetentionTime = d
+		}
+		if p.MaxSamples != nil {
+			c.Prometheus.MaxSamples = *p.MaxSamples
+		}
+		if p.Components != nil {
+			components, err := ParseComponents(p.Components)
+			if err != nil {
+				return fmt.Errorf("prometheus.components: %w", err)
+			}
+			c.Prometheus.Components = components
+		}
+		if p.Persist != nil {
+			c.Prometheus.Persist = *p.Persist
+		}
+		if p.URL != "" {
+			c.Prometheus.URL = p.URL
+		}
+		if p.ExtraMetrics != nil {
+			extra, err := parseExtraMetrics(p.ExtraMetrics)
+			if err != nil {
+				return fmt.Errorf("prometheus.extraMetrics: %w", err)
+			}
+			c.Prometheus.ExtraMetrics = extra
+		}
+	}
+
+	if fc.Columns != nil {
+		if err := c.Columns.merge(fc.Columns); err != nil {
+			return fmt.Errorf("columns: %w", err)
+		}
+	}
+
+	if a := fc.Alerts; a != nil {
+		if a.Enabled != nil {
+			c.Alerts.Enabled = *a.Enabled
+		}
+		if a.Rules != nil {
+			rules := make([]alerts.Rule, 0, len(a.Rules))
+			for i, fr := range a.Rules {
+				rule, err := fr.parse()
+				if err != nil {
+					return fmt.Errorf("alerts.rules[%d]: %w", i, err)
+				}
+				rules = append(rules, rule)
+			}
+			c.Alerts.Rules = rules
+		}
+	}
+
+	if l := fc.Logs; l != nil {
+		if l.Structured != nil {
+			c.Logs.Structured = *l.Structured
+		}
+		if l.Fields != nil {
+			c.Logs.Fields = l.Fields
+		}
+	}
+
+	if fc.Namespace != "" {
+		c.Namespace = fc.Namespace
+	}
+	if fc.Theme != "" {
+		c.Theme = fc.Theme
+	}
+	if fc.LogLevel != "" {
+		c.LogLevel = fc.LogLevel
+	}
+
+	for name, fp := range fc.Profiles {
+		profile, err := fp.parse()
+		if err != nil {
+			return fmt.Errorf("profiles.%s: %w", name, err)
+		}
+		if c.Profiles == nil {
+			c.Profiles = make(map[string]Profile, len(fc.Profiles))
+		}
+		c.Profiles[name] = profile
+	}
+	return nil
+}
+
+func (cc *ColumnsConfig) merge(fc *fileColumns) error {
+	if fc.Node != nil {
+		cc.Node = fc.Node
+	}
+	if fc.Pod != nil {
+		cc.Pod = fc.Pod
+	}
+	if fc.Custom != nil {
+		custom := make([]metrics.CustomColumn, 0, len(fc.Custom))
+		for i, def := range fc.Custom {
+			col, err := metrics.ParseCustomColumn(def)
+			if err != nil {
+				return fmt.Errorf("custom[%d]: %w", i, err)
+			}
+			custom = append(custom, col)
+		}
+		cc.Custom = custom
+	}
+	return nil
+}
+
+// parseExtraMetrics validates the component names and metric patterns of
+// the prometheus.extraMetrics key
+func parseExtraMetrics(fileExtra map[string][]string) (map[prom.ComponentType][]string, error) {
+	extra := make(map[prom.ComponentType][]string, len(fileExtra))
+	for name, patterns := range fileExtra {
+		components, err := ParseComponents([]string{name})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := prom.CompileMetricPatterns(patterns); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		extra[components[0]] = patterns
+	}
+	return extra, nil
+}
+
+func (fa fileAlert) parse() (alerts.Rule, error) {
+	rule := alerts.Rule{
+		Name:     fa.Name,
+		Target:   alerts.Target(fa.Target),
+		Severity: alerts.Severity(fa.Severity),
+	}
+	if rule.Severity == "" {
+		rule.Severity = alerts.SeverityWarning
+	}
+	cond, err := alerts.ParseCondition(fa.Expr)
+	if err != nil {
+		return rule, fmt.Errorf("expr: %w", err)
+	}
+	rule.Condition = cond
+	if fa.For != "" {
+		d, err := time.ParseDuration(fa.For)
+		if err != nil {
+			return rule, fmt.Errorf("for: %w", err)
+		}
+		rule.For = d
+	}
+	if fa.Window != "" {
+		d, err := time.ParseDuration(fa.Window)
+		if err != nil {
+			return rule, fmt.Errorf("window: %w", err)
+		}
+		rule.Window = d
+	}
+	return rule, nil
+}
+
+func (fp fileProfile) parse() (Profile, error) {
+	p := Profile{Source: fp.Source, PrometheusURL: fp.PrometheusURL}
+	if fp.ScrapeInterval != "" {
+		d, err := time.ParseDuration(fp.ScrapeInterval)
+		if err != nil {
+			return p, fmt.Errorf("scrapeInterval: %w", err)
+		}
+		p.ScrapeInterval = d
+	}
+	if fp.Components != nil {
+		components, err := ParseComponents(fp.Components)
+		if err != nil {
+			return p, fmt.Errorf("components: %w", err)
+		}
+		p.Components = components
+	}
+	if fp.Columns != nil {
+		if err := p.Columns.merge(fp.Columns); err != nil {
+			return p, fmt.Errorf("columns: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// LoadEnv merges KTOP_* environment variables into c using lookup
+// (typically os.LookupEnv). Variables that are unset or empty are ignored.
+func (c *Config) LoadEnv(lookup func(string) (string, bool)) error {
+	get := func(key string) (string, bool) {
+		v, ok := lookup(key)
+		v = strings.TrimSpace(v)
+		return v, ok && v != ""
+	}
+
+	if v, ok := get(EnvMetricsSource); ok {
+		c.SetSource(v)
+	}
+	if v, ok := get(EnvPrometheusScrapeInterval); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", EnvPrometheusScrapeInterval, err)
+		}
+		c.Prometheus.ScrapeInterval = d
+	}
+	if v, ok := get(EnvPrometheusRetention); ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", EnvPrometheusRetention, err)
+		}
+		c.Prometheus.RetentionTime = d
+	}
+	if v, ok := get(EnvPrometheusMaxSamples); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", EnvPrometheusMaxSamples, err)
+		}
+		c.Prometheus.MaxSamples = n
+	}
+	if v, ok := get(EnvPrometheusComponents); ok {
+		components, err := ParseComponents(SplitList(v))
+		if err != nil {
+			return fmt.Errorf("%s: %w", EnvPrometheusComponents, err)
+		}
+		c.Prometheus.Components = components
+	}
+	if v, ok := get(EnvPrometheusPersist); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", EnvPrometheusPersist, err)
+		}
+		c.Prometheus.Persist = b
+	}
+	if v, ok := get(EnvPrometheusURL); ok {
+		c.Prometheus.URL = v
+	}
+	if v, ok := get(EnvNamespace); ok {
+		c.Namespace = v
+	}
+	if v, ok := get(EnvNodeColumns); ok {
+		c.Columns.Node = SplitList(v)
+	}
+	if v, ok := get(EnvPodColumns); ok {
+		c.Columns.Pod = SplitList(v)
+	}
+	if v, ok := get(EnvAlerts); ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%s: %w", EnvAlerts, err)
+		}
+		c.Alerts.Enabled = b
+	}
+	if v, ok := get(EnvTheme); ok {
+		c.Theme = v
+	}
+	if v, ok := get(EnvLogLevel); ok {
+		c.LogLevel = v
+	}
+	return nil
+}
+
+// SplitList splits a comma-separated value, trimming blanks and dropping
+// empty entries.
+func SplitList(s string) []string {
+	var result []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
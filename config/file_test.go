@@ -0,0 +1,478 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/vladimirvivien/ktop/alerts"
+	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/prom"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func envLookup(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestLoadFile_AllFields(t *testing.T) {
+	path := writeConfigFile(t, `
+source:
+  type: metrics-server
+prometheus:
+  scrapeInterval: 30s
+  retention: 2h
+  maxSamples: 5000
+  components: [kubelet, etcd]
+  persist: true
+columns:
+  node: [NAME, CPU, MEM]
+  pod: [NAMESPACE, POD]
+namespace: production
+theme: light
+logLevel: debug
+`)
+
+	cfg := DefaultConfig()
+	if err := cfg.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+
+	if cfg.Source.Type != "metrics-server" {
+		t.Errorf("Source.Type = %q, want metrics-server", cfg.Source.Type)
+	}
+	if cfg.Source.Fallback {
+		t.Error("Source.Fallback should be disabled when the file sets a source")
+	}
+	if cfg.Prometheus.ScrapeInterval != 30*time.Second {
+		t.Errorf("ScrapeInterval = %v, want 30s", cfg.Prometheus.ScrapeInterval)
+	}
+	if cfg.Prometheus.RetentionTime != 2*time.Hour {
+		t.Errorf("RetentionTime = %v, want 2h", cfg.Prometheus.RetentionTime)
+	}
+	if cfg.Prometheus.MaxSamples != 5000 {
+		t.Errorf("MaxSamples = %d, want 5000", cfg.Prometheus.MaxSamples)
+	}
+	if !cfg.Prometheus.Persist {
+		t.Error("Prometheus.Persist = false, want true")
+	}
+	wantComponents := []prom.ComponentType{prom.ComponentKubelet, prom.ComponentEtcd}
+	if len(cfg.Prometheus.Components) != len(wantComponents) {
+		t.Fatalf("Components = %v, want %v", cfg.Prometheus.Components, wantComponents)
+	}
+	for i := range wantComponents {
+		if cfg.Prometheus.Components[i] != wantComponents[i] {
+			t.Errorf("Components[%d] = %v, want %v", i, cfg.Prometheus.Components[i], wantComponents[i])
+		}
+	}
+	if len(cfg.Columns.Node) != 3 || cfg.Columns.Node[2] != "MEM" {
+		t.Errorf("Columns.Node = %v, want [NAME CPU MEM]", cfg.Columns.Node)
+	}
+	if len(cfg.Columns.Pod) != 2 || cfg.Columns.Pod[1] != "POD" {
+		t.Errorf("Columns.Pod = %v, want [NAMESPACE POD]", cfg.Columns.Pod)
+	}
+	if cfg.Namespace != "production" {
+		t.Errorf("Namespace = %q, want production", cfg.Namespace)
+	}
+	if cfg.Theme != "light" {
+		t.Errorf("Theme = %q, want light", cfg.Theme)
+	}
+	if cfg.LogLevel != "debug" {
+		t.Errorf("LogLevel = %q, want debug", cfg.LogLevel)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error: %v", err)
+	}
+}
+
+func TestLoadFile_PartialKeepsDefaults(t *testing.T) {
+	path := writeConfigFile(t, "prometheus:\n  retention: 30m\n")
+
+	cfg := DefaultConfig()
+	if err := cfg.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+
+	if cfg.Prometheus.RetentionTime != 30*time.Minute {
+		t.Errorf("RetentionTime = %v, want 30m", cfg.Prometheus.RetentionTime)
+	}
+	if cfg.Prometheus.ScrapeInterval != 5*time.Second {
+		t.Errorf("ScrapeInterval = %v, want default 5s", cfg.Prometheus.ScrapeInterval)
+	}
+	if cfg.Source.Type != "prometheus" || !cfg.Source.Fallback {
+		t.Errorf("Source = %+v, want default prometheus with fallback", cfg.Source)
+	}
+}
+
+func TestLoadFile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"unknown key", "sorce:\n  type: none\n"},
+		{"bad duration", "prometheus:\n  scrapeInterval: soon\n"},
+		{"bad component", "prometheus:\n  components: [kubelet, nope]\n"},
+		{"not yaml", "source: [unterminated\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			if err := cfg.LoadFile(writeConfigFile(t, tt.content)); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestLoadFile_Missing(t *testing.T) {
+	cfg := DefaultConfig()
+	err := cfg.LoadFile(filepath.Join(t.TempDir(), "absent.yaml"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("LoadFile() error = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestLoadEnv_OverridesFile(t *testing.T) {
+	path := writeConfigFile(t, `
+source:
+  type: metrics-server
+namespace: production
+logLevel: warn
+`)
+	cfg := DefaultConfig()
+	if err := cfg.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+
+	err := cfg.LoadEnv(envLookup(map[string]string{
+		EnvMetricsSource:            "prom",
+		EnvPrometheusScrapeInterval: "15s",
+		EnvPrometheusMaxSamples:     "2000",
+		EnvPrometheusComponents:     "kubelet, cadvisor, apiserver",
+		EnvNodeColumns:              "NAME,CPU",
+		EnvNamespace:                "",
+		EnvLogLevel:                 "error",
+	}))
+	if err != nil {
+		t.Fatalf("LoadEnv() error: %v", err)
+	}
+
+	if cfg.Source.Type != "prom" {
+		t.Errorf("Source.Type = %q, want prom", cfg.Source.Type)
+	}
+	if cfg.Prometheus.ScrapeInterval != 15*time.Second {
+		t.Errorf("ScrapeInterval = %v, want 15s", cfg.Prometheus.ScrapeInterval)
+	}
+	if cfg.Prometheus.MaxSamples != 2000 {
+		t.Errorf("MaxSamples = %d, want 2000", cfg.Prometheus.MaxSamples)
+	}
+	if len(cfg.Prometheus.Components) != 3 {
+		t.Errorf("Components = %v, want 3 entries", cfg.Prometheus.Components)
+	}
+	if len(cfg.Columns.Node) != 2 {
+		t.Errorf("Columns.Node = %v, want [NAME CPU]", cfg.Columns.Node)
+	}
+	// Empty env values do not clear what the file set
+	if cfg.Namespace != "production" {
+		t.Errorf("Namespace = %q, want production from file", cfg.Namespace)
+	}
+	if cfg.LogLevel != "error" {
+		t.Errorf("LogLevel = %q, want error", cfg.LogLevel)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	if cfg.Source.Type != "prometheus" {
+		t.Errorf("Validate should normalize source to prometheus, got %q", cfg.Source.Type)
+	}
+}
+
+func TestLoadEnv_InvalidValue(t *testing.T) {
+	cfg := DefaultConfig()
+	err := cfg.LoadEnv(envLookup(map[string]string{EnvPrometheusMaxSamples: "lots"}))
+	if err == nil {
+		t.Error("expected error for non-numeric max samples")
+	}
+}
+
+func TestLoadEnv_Persist(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Prometheus.Persist {
+		t.Fatal("Prometheus.Persist should default to false")
+	}
+	if err := cfg.LoadEnv(envLookup(map[string]string{EnvPrometheusPersist: "true"})); err != nil {
+		t.Fatalf("LoadEnv() error: %v", err)
+	}
+	if !cfg.Prometheus.Persist {
+		t.Error("Prometheus.Persist = false, want true")
+	}
+	if err := cfg.LoadEnv(envLookup(map[string]string{EnvPrometheusPersist: "sometimes"})); err == nil {
+		t.Error("expected error for non-boolean persist")
+	}
+}
+
+func TestLoadEnv_PrometheusURL(t *testing.T) {
+	cfg := DefaultConfig()
+	err := cfg.LoadEnv(envLookup(map[string]string{
+		EnvMetricsSource: "prometheus-api",
+		EnvPrometheusURL: "http://localhost:9090",
+	}))
+	if err != nil {
+		t.Fatalf("LoadEnv() error: %v", err)
+	}
+	if cfg.Prometheus.URL != "http://localhost:9090" {
+		t.Errorf("Prometheus.URL = %q, want http://localhost:9090", cfg.Prometheus.URL)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error: %v", err)
+	}
+}
+
+func TestValidate_ThemeAndLogLevel(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Theme = "neon"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown theme")
+	}
+
+	cfg = DefaultConfig()
+	cfg.LogLevel = "verbose"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for unknown log level")
+	}
+}
+
+func TestDefaultPath_EnvOverride(t *testing.T) {
+	want := filepath.Join(t.TempDir(), "ktop.yaml")
+	t.Setenv(EnvConfig, want)
+
+	got, err := DefaultPath()
+	if err != nil {
+		t.Fatalf("DefaultPath() error: %v", err)
+	}
+	if got != want {
+		t.Errorf("DefaultPath() = %q, want %q", got, want)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := SplitList(" NAME, CPU ,,MEM ")
+	want := []string{"NAME", "CPU", "MEM"}
+	if len(got) != len(want) {
+		t.Fatalf("SplitList() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("SplitList()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLoadFile_Alerts(t *testing.T) {
+	path := writeConfigFile(t, `
+alerts:
+  rules:
+    - name: NodeCPUCritical
+      target: node
+      expr: cpu > 95%
+      for: 5m
+      severity: critical
+    - name: PodRestarting
+      target: pod
+      expr: restarts > 5
+      window: 15m
+`)
+
+	cfg := DefaultConfig()
+	if err := cfg.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+	if !cfg.Alerts.Enabled {
+		t.Error("Alerts.Enabled should stay on when the file only sets rules")
+	}
+	if len(cfg.Alerts.Rules) != 2 {
+		t.Fatalf("Alerts.Rules = %+v, want the 2 file rules replacing the defaults", cfg.Alerts.Rules)
+	}
+
+	cpu := cfg.Alerts.Rules[0]
+	if cpu.Target != alerts.TargetNode || cpu.Severity != alerts.SeverityCritical || cpu.For != 5*time.Minute {
+		t.Errorf("Rules[0] = %+v, want critical node rule held 5m", cpu)
+	}
+	if want := (alerts.Condition{Metric: "cpu", Op: ">", Threshold: 95}); cpu.Condition != want {
+		t.Errorf("Rules[0].Condition = %+v, want %+v", cpu.Condition, want)
+	}
+	restarts := cfg.Alerts.Rules[1]
+	if restarts.Severity != alerts.SeverityWarning || restarts.Window != 15*time.Minute {
+		t.Errorf("Rules[1] = %+v, want warning severity by default and a 15m window", restarts)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error: %v", err)
+	}
+
+	cfg = DefaultConfig()
+	if err := cfg.LoadFile(writeConfigFile(t, "alerts:\n  enabled: false\n")); err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+	if cfg.Alerts.Enabled || len(cfg.Alerts.Rules) != len(alerts.DefaultRules()) {
+		t.Errorf("Alerts = %+v, want disabled with default rules kept", cfg.Alerts)
+	}
+}
+
+func TestLoadFile_AlertErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"bad expr", "alerts:\n  rules:\n    - {name: A, target: node, expr: disk > 1}\n"},
+		{"bad for", "alerts:\n  rules:\n    - {name: A, target: node, expr: cpu > 90, for: later}\n"},
+		{"unknown field", "alerts:\n  rules:\n    - {name: A, target: node, expr: cpu > 90, when: 2m}\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			if err := cfg.LoadFile(writeConfigFile(t, tt.content)); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestValidate_Alerts(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Alerts.Rules = append(cfg.Alerts.Rules, cfg.Alerts.Rules[0])
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for duplicate rule names")
+	}
+
+	path := writeConfigFile(t, "alerts:\n  rules:\n    - {name: A, target: node, expr: oomkilled > 0}\n")
+	cfg = DefaultConfig()
+	if err := cfg.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for a pod metric on a node rule")
+	}
+}
+
+func TestLoadEnv_Alerts(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.LoadEnv(envLookup(map[string]string{EnvAlerts: "false"})); err != nil {
+		t.Fatalf("LoadEnv() error: %v", err)
+	}
+	if cfg.Alerts.Enabled {
+		t.Error("Alerts.Enabled = true, want false")
+	}
+	if err := cfg.LoadEnv(envLookup(map[string]string{EnvAlerts: "maybe"})); err == nil {
+		t.Error("expected error for non-boolean alerts")
+	}
+}
+
+func TestLoadFile_ExtraMetricsAndCustomColumns(t *testing.T) {
+	path := writeConfigFile(t, `
+prometheus:
+  extraMetrics:
+    cadvisor: [container_cpu_cfs_throttled_periods_total]
+    kubelet: ["kubelet_volume_stats_.*"]
+columns:
+  custom:
+    - GPU_MEM = sum(DCGM_FI_DEV_FB_USED) by (namespace, pod)
+    - "iowait = avg by (node) (rate(node_cpu_seconds_total{mode=\"iowait\"}[2m]))"
+`)
+
+	cfg := DefaultConfig()
+	if err := cfg.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+
+	extra := cfg.Prometheus.ExtraMetrics
+	if len(extra[prom.ComponentCAdvisor]) != 1 || extra[prom.ComponentKubelet][0] != "kubelet_volume_stats_.*" {
+		t.Errorf("ExtraMetrics = %v, want cadvisor and kubelet entries", extra)
+	}
+
+	custom := cfg.Columns.Custom
+	if len(custom) != 2 {
+		t.Fatalf("Columns.Custom = %+v, want 2 columns", custom)
+	}
+	if custom[0].Name != "GPU_MEM" || custom[0].Table != metrics.ColumnTablePod || custom[0].RowKey("ml", "trainer-0") != "ml/trainer-0" {
+		t.Errorf("Custom[0] = %+v, want GPU_MEM pod column", custom[0])
+	}
+	iowait := custom[1]
+	if iowait.Name != "IOWAIT" || iowait.Table != metrics.ColumnTableNode {
+		t.Errorf("Custom[1] = %+v, want upper-cased IOWAIT node column", iowait)
+	}
+	if got, want := iowait.Query.String(), `avg by (node) (rate(node_cpu_seconds_total{mode="iowait"}[2m]))`; got != want {
+		t.Errorf("Custom[1].Query.String() = %s, want %s", got, want)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error: %v", err)
+	}
+
+	cfg.Columns.Custom = append(cfg.Columns.Custom, custom[0])
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for duplicate custom column names")
+	}
+}
+
+func TestLoadFile_Logs(t *testing.T) {
+	path := writeConfigFile(t, `
+logs:
+  structured: true
+  fields: [user, trace_id]
+`)
+
+	cfg := DefaultConfig()
+	if cfg.Logs.Structured {
+		t.Error("structured logs should be off by default")
+	}
+	if err := cfg.LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+	if !cfg.Logs.Structured {
+		t.Error("Logs.Structured = false, want true")
+	}
+	if len(cfg.Logs.Fields) != 2 || cfg.Logs.Fields[0] != "user" || cfg.Logs.Fields[1] != "trace_id" {
+		t.Errorf("Logs.Fields = %v, want [user trace_id]", cfg.Logs.Fields)
+	}
+}
+
+func TestLoadFile_ExtraMetricsAndCustomColumnErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"bad component", "prometheus:\n  extraMetrics:\n    nope: [up]\n"},
+		{"bad pattern", "prometheus:\n  extraMetrics:\n    kubelet: [\"kubelet_(\"]\n"},
+		{"no name", "columns:\n  custom: [\"sum(up) by (node)\"]\n"},
+		{"no grouping", "columns:\n  custom: [\"UP = sum(up)\"]\n"},
+		{"unsupported grouping", "columns:\n  custom: [\"UP = sum(up) by (job)\"]\n"},
+		{"not an aggregation", "columns:\n  custom: [\"UP = rate(up[1m])\"]\n"},
+		{"grouped without", "columns:\n  custom: [\"UP = sum without (job) (up)\"]\n"},
+		{"unterminated selector", "columns:\n  custom: [\"UP = sum(up{job=\\\"x\\\") by (node)\"]\n"},
+		{"bad range", "columns:\n  custom: [\"UP = sum(rate(up[soon])) by (node)\"]\n"},
+		{"trailing text", "columns:\n  custom: [\"UP = sum(up) by (node) * 2\"]\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			if err := cfg.LoadFile(writeConfigFile(t, tt.content)); err == nil {
+				t.Error("expected error, got nil")
+			}
+		})
+	}
+}
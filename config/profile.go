@@ -0,0 +1,58 @@
+package config
+
+import (
+	"time"
+
+	"github.com/vladimirvivien/ktop/prom"
+)
+
+// Profile holds settings applied automatically when ktop connects to a
+// specific kubeconfig context. Profiles are declared under the profiles key
+// of the config file, keyed by context name. Zero-valued fields leave the
+// base configuration unchanged.
+type Profile struct {
+	Source         string
+	PrometheusURL  string
+	ScrapeInterval time.Duration
+	Components     []prom.ComponentType
+	Columns        ColumnsConfig
+}
+
+// HasProfile reports whether a profile is declared for the named context.
+func (c *Config) HasProfile(context string) bool {
+	_, ok := c.Profiles[context]
+	return ok
+}
+
+// ApplyProfile merges the profile declared for context into c and reports
+// whether one was found. It sits between the config file and the
+// environment in precedence, so callers apply it right after LoadFile.
+func (c *Config) ApplyProfile(context string) bool {
+	p, ok := c.Profiles[context]
+	if !ok {
+		return false
+	}
+
+	if p.Source != "" {
+		c.SetSource(p.Source)
+	}
+	if p.PrometheusURL != "" {
+		c.Prometheus.URL = p.PrometheusURL
+	}
+	if p.ScrapeInterval != 0 {
+		c.Prometheus.ScrapeInterval = p.ScrapeInterval
+	}
+	if p.Components != nil {
+		c.Prometheus.Components = p.Components
+	}
+	if p.Columns.Node != nil {
+		c.Columns.Node = p.Columns.Node
+	}
+	if p.Columns.Pod != nil {
+		c.Columns.Pod = p.Columns.Pod
+	}
+	if p.Columns.Custom != nil {
+		c.Columns.Custom = p.Columns.Custom
+	}
+	return true
+}
@@ -0,0 +1,174 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vladimirvivien/ktop/prom"
+)
+
+const profilesYAML = `
+source:
+  type: prometheus
+prometheus:
+  scrapeInterval: 10s
+columns:
+  pod: [NAMESPACE, POD, STATUS]
+profiles:
+  prod-east:
+    source: metrics-server
+    columns:
+      node: [NAME, CPU, MEM]
+  kind-dev:
+    scrapeInterval: 30s
+    components: [kubelet, cadvisor, etcd]
+`
+
+func TestLoadFile_Profiles(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.LoadFile(writeConfigFile(t, profilesYAML)); err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+
+	if len(cfg.Profiles) != 2 {
+		t.Fatalf("got %d profiles, want 2", len(cfg.Profiles))
+	}
+	if !cfg.HasProfile("prod-east") || !cfg.HasProfile("kind-dev") {
+		t.Errorf("profiles = %v, want prod-east and kind-dev", cfg.Profiles)
+	}
+	if cfg.HasProfile("staging") {
+		t.Error("HasProfile(staging) = true, want false")
+	}
+	// Loading profiles must not change the base config
+	if cfg.Prometheus.ScrapeInterval != 10*time.Second {
+		t.Errorf("ScrapeInterval = %v, want 10s from top-level", cfg.Prometheus.ScrapeInterval)
+	}
+}
+
+func TestApplyProfile_MetricsServer(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.LoadFile(writeConfigFile(t, profilesYAML)); err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+
+	if !cfg.ApplyProfile("prod-east") {
+		t.Fatal("ApplyProfile(prod-east) = false, want true")
+	}
+	if cfg.Source.Type != "metrics-server" {
+		t.Errorf("Source.Type = %q, want metrics-server", cfg.Source.Type)
+	}
+	if cfg.Source.Fallback {
+		t.Error("Source.Fallback should be disabled by a profile source")
+	}
+	if len(cfg.Columns.Node) != 3 {
+		t.Errorf("Columns.Node = %v, want profile columns", cfg.Columns.Node)
+	}
+	if len(cfg.Columns.Pod) != 3 {
+		t.Errorf("Columns.Pod = %v, want top-level columns kept", cfg.Columns.Pod)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error: %v", err)
+	}
+}
+
+func TestApplyProfile_Prometheus(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.LoadFile(writeConfigFile(t, profilesYAML)); err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+
+	if !cfg.ApplyProfile("kind-dev") {
+		t.Fatal("ApplyProfile(kind-dev) = false, want true")
+	}
+	if cfg.Source.Type != "prometheus" {
+		t.Errorf("Source.Type = %q, want prometheus from top-level", cfg.Source.Type)
+	}
+	if cfg.Prometheus.ScrapeInterval != 30*time.Second {
+		t.Errorf("ScrapeInterval = %v, want 30s", cfg.Prometheus.ScrapeInterval)
+	}
+	want := []prom.ComponentType{prom.ComponentKubelet, prom.ComponentCAdvisor, prom.ComponentEtcd}
+	if len(cfg.Prometheus.Components) != len(want) {
+		t.Fatalf("Components = %v, want %v", cfg.Prometheus.Components, want)
+	}
+	for i := range want {
+		if cfg.Prometheus.Components[i] != want[i] {
+			t.Errorf("Components[%d] = %v, want %v", i, cfg.Prometheus.Components[i], want[i])
+		}
+	}
+}
+
+func TestApplyProfile_PrometheusURL(t *testing.T) {
+	cfg := DefaultConfig()
+	yaml := `
+prometheus:
+  url: http://prometheus.default:9090
+profiles:
+  prod-west:
+    source: prometheus-api
+    prometheusURL: https://thanos.prod-west.example.com
+`
+	if err := cfg.LoadFile(writeConfigFile(t, yaml)); err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+	if cfg.Prometheus.URL != "http://prometheus.default:9090" {
+		t.Errorf("Prometheus.URL = %q, want top-level url", cfg.Prometheus.URL)
+	}
+
+	if !cfg.ApplyProfile("prod-west") {
+		t.Fatal("ApplyProfile(prod-west) = false, want true")
+	}
+	if cfg.Source.Type != "prometheus-api" {
+		t.Errorf("Source.Type = %q, want prometheus-api", cfg.Source.Type)
+	}
+	if cfg.Prometheus.URL != "https://thanos.prod-west.example.com" {
+		t.Errorf("Prometheus.URL = %q, want profile url", cfg.Prometheus.URL)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error: %v", err)
+	}
+}
+
+func TestApplyProfile_UnknownContext(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.LoadFile(writeConfigFile(t, profilesYAML)); err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+	before := cfg.Prometheus.ScrapeInterval
+
+	if cfg.ApplyProfile("staging") {
+		t.Error("ApplyProfile(staging) = true, want false")
+	}
+	if cfg.Prometheus.ScrapeInterval != before {
+		t.Errorf("ScrapeInterval changed to %v for unknown context", cfg.Prometheus.ScrapeInterval)
+	}
+}
+
+func TestApplyProfile_EnvStillWins(t *testing.T) {
+	cfg := DefaultConfig()
+	if err := cfg.LoadFile(writeConfigFile(t, profilesYAML)); err != nil {
+		t.Fatalf("LoadFile() error: %v", err)
+	}
+	cfg.ApplyProfile("kind-dev")
+	if err := cfg.LoadEnv(envLookup(map[string]string{EnvPrometheusScrapeInterval: "20s"})); err != nil {
+		t.Fatalf("LoadEnv() error: %v", err)
+	}
+	if cfg.Prometheus.ScrapeInterval != 20*time.Second {
+		t.Errorf("ScrapeInterval = %v, want env value 20s", cfg.Prometheus.ScrapeInterval)
+	}
+}
+
+func TestLoadFile_InvalidProfile(t *testing.T) {
+	cfg := DefaultConfig()
+	err := cfg.LoadFile(writeConfigFile(t, "profiles:\n  dev:\n    scrapeInterval: often\n"))
+	if err == nil {
+		t.Error("expected error for invalid profile scrape interval")
+	}
+}
+
+func TestValidate_InvalidProfileSource(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.Profiles = map[string]Profile{"dev": {Source: "graphite"}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for invalid profile source")
+	}
+}
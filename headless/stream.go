@@ -0,0 +1,240 @@
+// Package headless runs ktop without the terminal UI. Each controller
+// refresh is written to an io.Writer (stdout by default) as newline-delimited
+// JSON so scripts and CI jobs can consume the same data the TUI displays.
+// The same format, with a few more record kinds, is used by `ktop record`.
+package headless
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/vladimirvivien/ktop/k8s"
+	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// RecordKind identifies the model carried by a Record.
+type RecordKind string
+
+const (
+	KindNode    RecordKind = "node"
+	KindPod     RecordKind = "pod"
+	KindSummary RecordKind = "summary"
+
+	// Written only by recordings (see NewRecorder)
+	KindSession     RecordKind = "session"
+	KindWorkload    RecordKind = "workload"
+	KindNamespace   RecordKind = "namespace"
+	KindNodeMetrics RecordKind = "nodeMetrics"
+	KindPodMetrics  RecordKind = "podMetrics"
+)
+
+// Record is a single NDJSON line. Exactly one of the payload fields is set,
+// matching Kind. Records produced by the same refresh share a Timestamp.
+type Record struct {
+	Kind        RecordKind            `json:"kind"`
+	Timestamp   time.Time             `json:"timestamp"`
+	Session     *SessionInfo          `json:"session,omitempty"`
+	Node        *model.NodeModel      `json:"node,omitempty"`
+	Pod         *model.PodModel       `json:"pod,omitempty"`
+	Summary     *model.ClusterSummary `json:"summary,omitempty"`
+	Workload    *model.WorkloadModel  `json:"workload,omitempty"`
+	Namespace   *model.NamespaceModel `json:"namespace,omitempty"`
+	NodeMetrics *metrics.NodeMetrics  `json:"nodeMetrics,omitempty"`
+	PodMetrics  *metrics.PodMetrics   `json:"podMetrics,omitempty"`
+}
+
+// SessionInfo describes the connection a recording was made from. It is
+// the first record of a recording and supplies the header shown on replay.
+type SessionInfo struct {
+	Context       string `json:"context"`
+	Cluster       string `json:"cluster"`
+	User          string `json:"user"`
+	ServerVersion string `json:"serverVersion"`
+	Namespace     string `json:"namespace"`
+	MetricsSource string `json:"metricsSource"` // empty when metrics were disabled
+	KtopVersion   string `json:"ktopVersion"`
+}
+
+// Streamer encodes controller refreshes as NDJSON records. Its Refresh*
+// methods match the k8s.Controller callback signatures and are safe to call
+// from the controller's refresh goroutines concurrently.
+type Streamer struct {
+	mu            sync.Mutex
+	enc           *json.Encoder
+	metricsSource metrics.MetricsSource
+	now           func() time.Time
+
+	// Set by NewRecorder
+	session *SessionInfo
+}
+
+// New returns a Streamer writing to w. When source is non-nil, node and pod
+// usage is refreshed from it before encoding, as the overview panels do.
+func New(w io.Writer, source metrics.MetricsSource) *Streamer {
+	return &Streamer{
+		enc:           json.NewEncoder(w),
+		metricsSource: source,
+		now:           time.Now,
+	}
+}
+
+// NewRecorder returns a Streamer that captures a session for `ktop replay`.
+// On top of what New writes, a recording starts with a session record and
+// carries workload and namespace models plus the metrics samples behind the
+// node and pod usage, one nodeMetrics or podMetrics record per sample.
+func NewRecorder(w io.Writer, source metrics.MetricsSource, session SessionInfo) *Streamer {
+	s := New(w, source)
+	s.session = &session
+	return s
+}
+
+// Run wires the streamer into ctrl, starts the controller, and blocks until
+// ctx is cancelled.
+func (s *Streamer) Run(ctx context.Context, ctrl *k8s.Controller) error {
+	ctrl.SetMetricsSource(s.metricsSource)
+	ctrl.SetClusterSummaryRefreshFunc(s.RefreshSummary)
+	ctrl.SetNodeRefreshFunc(s.RefreshNodes)
+	ctrl.SetPodRefreshFunc(s.RefreshPods)
+	if s.session != nil {
+		ctrl.SetWorkloadRefreshFunc(s.RefreshWorkloads)
+		ctrl.SetNamespaceRefreshFunc(s.RefreshNamespaces)
+		if err := s.write([]Record{{Kind: KindSession, Timestamp: s.now(), Session: s.session}}); err != nil {
+			return fmt.Errorf("headless: write session: %w", err)
+		}
+	}
+
+	if err := ctrl.Start(ctx, 10*time.Second); err != nil {
+		return fmt.Errorf("headless: controller start: %w", err)
+	}
+	slog.Info("headless streaming started", "recording", s.session != nil)
+
+	<-ctx.Done()
+	return nil
+}
+
+// RefreshNodes writes one node record per model.
+func (s *Streamer) RefreshNodes(ctx context.Context, items []model.NodeModel) error {
+	ts := s.now()
+	records := make([]Record, 0, len(items))
+	for i := range items {
+		node := items[i]
+		if s.metricsSource != nil {
+			if nm, err := s.metricsSource.GetNodeMetrics(ctx, node.Name); err == nil {
+				node.UsageCpuQty = nm.CPUUsage
+				node.UsageMemQty = nm.MemoryUsage
+				if s.session != nil {
+					records = append(records, Record{Kind: KindNodeMetrics, Timestamp: ts, NodeMetrics: nm})
+				}
+			}
+		}
+		records = append(records, Record{Kind: KindNode, Timestamp: ts, Node: &node})
+	}
+	return s.write(records)
+}
+
+// RefreshPods writes one pod record per model.
+func (s *Streamer) RefreshPods(ctx context.Context, items []model.PodModel) error {
+	usage, samples := s.podUsage(ctx)
+
+	ts := s.now()
+	records := make([]Record, 0, len(items)+len(samples))
+	if s.session != nil {
+		for _, pm := range samples {
+			records = append(records, Record{Kind: KindPodMetrics, Timestamp: ts, PodMetrics: pm})
+		}
+	}
+	for i := range items {
+		pod := items[i]
+		if pm, ok := usage[pod.Namespace+"/"+pod.Name]; ok {
+			pod.PodUsageCpuQty = pm.cpu
+			pod.PodUsageMemQty = pm.mem
+		}
+		records = append(records, Record{Kind: KindPod, Timestamp: ts, Pod: &pod})
+	}
+	return s.write(records)
+}
+
+// RefreshSummary writes a single summary record.
+func (s *Streamer) RefreshSummary(_ context.Context, summary model.ClusterSummary) error {
+	return s.write([]Record{{Kind: KindSummary, Timestamp: s.now(), Summary: &summary}})
+}
+
+// RefreshWorkloads writes one workload record per model.
+func (s *Streamer) RefreshWorkloads(_ context.Context, items []model.WorkloadModel) error {
+	ts := s.now()
+	records := make([]Record, 0, len(items))
+	for i := range items {
+		records = append(records, Record{Kind: KindWorkload, Timestamp: ts, Workload: &items[i]})
+	}
+	return s.write(records)
+}
+
+// RefreshNamespaces writes one namespace record per model.
+func (s *Streamer) RefreshNamespaces(_ context.Context, items []model.NamespaceModel) error {
+	ts := s.now()
+	records := make([]Record, 0, len(items))
+	for i := range items {
+		records = append(records, Record{Kind: KindNamespace, Timestamp: ts, Namespace: &items[i]})
+	}
+	return s.write(records)
+}
+
+type podUsage struct {
+	cpu *resource.Quantity
+	mem *resource.Quantity
+}
+
+// podUsage fetches all pod metrics in one call and sums container usage,
+// keyed by namespace/name. Pods without any reported usage are omitted so
+// their model values are left untouched. The fetched metrics are returned
+// as well for recordings.
+func (s *Streamer) podUsage(ctx context.Context) (map[string]podUsage, []*metrics.PodMetrics) {
+	if s.metricsSource == nil {
+		return nil, nil
+	}
+	all, err := s.metricsSource.GetAllPodMetrics(ctx)
+	if err != nil {
+		slog.Debug("headless: pod metrics unavailable", "error", err)
+		return nil, nil
+	}
+
+	result := make(map[string]podUsage, len(all))
+	for _, pm := range all {
+		cpu := resource.NewQuantity(0, resource.DecimalSI)
+		mem := resource.NewQuantity(0, resource.BinarySI)
+		for _, c := range pm.Containers {
+			if c.CPUUsage != nil {
+				cpu.Add(*c.CPUUsage)
+			}
+			if c.MemoryUsage != nil {
+				mem.Add(*c.MemoryUsage)
+			}
+		}
+		if cpu.IsZero() && mem.IsZero() {
+			continue
+		}
+		result[pm.Namespace+"/"+pm.PodName] = podUsage{cpu: cpu, mem: mem}
+	}
+	return result, all
+}
+
+// write encodes records under the lock so lines from concurrent refreshes
+// never interleave.
+func (s *Streamer) write(records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range records {
+		if err := s.enc.Encode(&records[i]); err != nil {
+			slog.Error("headless: write record failed", "kind", records[i].Kind, "error", err)
+			return err
+		}
+	}
+	return nil
+}
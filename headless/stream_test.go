@@ -0,0 +1,244 @@
+package headless
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// fakeSource implements only the MetricsSource methods the streamer calls.
+type fakeSource struct {
+	metrics.MetricsSource
+	nodes map[string]*metrics.NodeMetrics
+	pods  []*metrics.PodMetrics
+}
+
+func (f *fakeSource) GetNodeMetrics(_ context.Context, name string) (*metrics.NodeMetrics, error) {
+	if nm, ok := f.nodes[name]; ok {
+		return nm, nil
+	}
+	return nil, errors.New("not found")
+}
+
+func (f *fakeSource) GetAllPodMetrics(_ context.Context) ([]*metrics.PodMetrics, error) {
+	return f.pods, nil
+}
+
+func decodeRecords(t *testing.T, buf *bytes.Buffer) []Record {
+	t.Helper()
+	var records []Record
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("line %q is not valid JSON: %v", scanner.Text(), err)
+		}
+		records = append(records, r)
+	}
+	return records
+}
+
+func fixedNow() time.Time {
+	return time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func TestStreamer_RefreshNodes(t *testing.T) {
+	var buf bytes.Buffer
+	cpu := resource.MustParse("250m")
+	mem := resource.MustParse("1Gi")
+	s := New(&buf, &fakeSource{nodes: map[string]*metrics.NodeMetrics{
+		"node-a": {NodeName: "node-a", CPUUsage: &cpu, MemoryUsage: &mem},
+	}})
+	s.now = fixedNow
+
+	err := s.RefreshNodes(context.Background(), []model.NodeModel{
+		{Name: "node-a", Status: "Ready"},
+		{Name: "node-b", Status: "NotReady"},
+	})
+	if err != nil {
+		t.Fatalf("RefreshNodes() error: %v", err)
+	}
+
+	records := decodeRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	for _, r := range records {
+		if r.Kind != KindNode {
+			t.Errorf("Kind = %q, want %q", r.Kind, KindNode)
+		}
+		if !r.Timestamp.Equal(fixedNow()) {
+			t.Errorf("Timestamp = %v, want %v", r.Timestamp, fixedNow())
+		}
+		if r.Node == nil || r.Pod != nil || r.Summary != nil {
+			t.Fatalf("expected only Node set, got %+v", r)
+		}
+	}
+	if got := records[0].Node.UsageCpuQty; got == nil || got.MilliValue() != 250 {
+		t.Errorf("node-a CPU usage = %v, want 250m", got)
+	}
+	if records[1].Node.UsageCpuQty != nil {
+		t.Errorf("node-b CPU usage = %v, want nil when metrics are missing", records[1].Node.UsageCpuQty)
+	}
+}
+
+func TestStreamer_RefreshPods_MergesUsage(t *testing.T) {
+	var buf bytes.Buffer
+	c1, c2 := resource.MustParse("100m"), resource.MustParse("50m")
+	m1, m2 := resource.MustParse("64Mi"), resource.MustParse("32Mi")
+	s := New(&buf, &fakeSource{pods: []*metrics.PodMetrics{{
+		PodName:   "web",
+		Namespace: "default",
+		Containers: []metrics.ContainerMetrics{
+			{Name: "app", CPUUsage: &c1, MemoryUsage: &m1},
+			{Name: "sidecar", CPUUsage: &c2, MemoryUsage: &m2},
+		},
+	}}})
+
+	err := s.RefreshPods(context.Background(), []model.PodModel{
+		{Namespace: "default", Name: "web"},
+		{Namespace: "default", Name: "db"},
+	})
+	if err != nil {
+		t.Fatalf("RefreshPods() error: %v", err)
+	}
+
+	records := decodeRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	web := records[0].Pod
+	if web == nil || web.Name != "web" {
+		t.Fatalf("first record = %+v, want pod web", records[0])
+	}
+	if web.PodUsageCpuQty == nil || web.PodUsageCpuQty.MilliValue() != 150 {
+		t.Errorf("web CPU usage = %v, want 150m", web.PodUsageCpuQty)
+	}
+	if web.PodUsageMemQty == nil || web.PodUsageMemQty.Value() != 96*1024*1024 {
+		t.Errorf("web memory usage = %v, want 96Mi", web.PodUsageMemQty)
+	}
+	if records[1].Pod.PodUsageCpuQty != nil {
+		t.Errorf("db CPU usage = %v, want nil", records[1].Pod.PodUsageCpuQty)
+	}
+}
+
+func TestStreamer_RefreshSummary_NilSource(t *testing.T) {
+	var buf bytes.Buffer
+	s := New(&buf, nil)
+
+	if err := s.RefreshSummary(context.Background(), model.ClusterSummary{NodesCount: 3, PodsRunning: 12}); err != nil {
+		t.Fatalf("RefreshSummary() error: %v", err)
+	}
+	if err := s.RefreshPods(context.Background(), []model.PodModel{{Name: "p"}}); err != nil {
+		t.Fatalf("RefreshPods() with nil source error: %v", err)
+	}
+
+	records := decodeRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+	if records[0].Kind != KindSummary || records[0].Summary == nil {
+		t.Fatalf("first record = %+v, want summary", records[0])
+	}
+	if records[0].Summary.NodesCount != 3 || records[0].Summary.PodsRunning != 12 {
+		t.Errorf("summary = %+v, want NodesCount=3 PodsRunning=12", records[0].Summary)
+	}
+	if records[1].Kind != KindPod {
+		t.Errorf("second record kind = %q, want %q", records[1].Kind, KindPod)
+	}
+}
+
+func TestRecorder_RecordsMetricsSamples(t *testing.T) {
+	var buf bytes.Buffer
+	cpu, mem := resource.MustParse("250m"), resource.MustParse("1Gi")
+	c1, m1 := resource.MustParse("100m"), resource.MustParse("64Mi")
+	s := NewRecorder(&buf, &fakeSource{
+		nodes: map[string]*metrics.NodeMetrics{
+			"node-a": {NodeName: "node-a", CPUUsage: &cpu, MemoryUsage: &mem},
+		},
+		pods: []*metrics.PodMetrics{{
+			PodName:    "web",
+			Namespace:  "default",
+			Containers: []metrics.ContainerMetrics{{Name: "app", CPUUsage: &c1, MemoryUsage: &m1}},
+		}},
+	}, SessionInfo{Context: "kind-dev"})
+	s.now = fixedNow
+
+	ctx := context.Background()
+	if err := s.RefreshNodes(ctx, []model.NodeModel{{Name: "node-a"}}); err != nil {
+		t.Fatalf("RefreshNodes() error: %v", err)
+	}
+	if err := s.RefreshPods(ctx, []model.PodModel{{Namespace: "default", Name: "web"}}); err != nil {
+		t.Fatalf("RefreshPods() error: %v", err)
+	}
+
+	records := decodeRecords(t, &buf)
+	var kinds []RecordKind
+	for _, r := range records {
+		kinds = append(kinds, r.Kind)
+	}
+	want := []RecordKind{KindNodeMetrics, KindNode, KindPodMetrics, KindPod}
+	if len(kinds) != len(want) {
+		t.Fatalf("kinds = %v, want %v", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Fatalf("kinds = %v, want %v", kinds, want)
+		}
+	}
+	if nm := records[0].NodeMetrics; nm == nil || nm.CPUUsage.MilliValue() != 250 {
+		t.Errorf("node metrics = %+v, want 250m CPU", nm)
+	}
+	if pm := records[2].PodMetrics; pm == nil || pm.PodName != "web" || len(pm.Containers) != 1 {
+		t.Errorf("pod metrics = %+v, want web with one container", pm)
+	}
+}
+
+func TestRecorder_WorkloadsAndNamespaces(t *testing.T) {
+	var buf bytes.Buffer
+	s := NewRecorder(&buf, nil, SessionInfo{})
+	s.now = fixedNow
+
+	ctx := context.Background()
+	if err := s.RefreshWorkloads(ctx, []model.WorkloadModel{{Kind: "Deployment", Namespace: "default", Name: "web"}}); err != nil {
+		t.Fatalf("RefreshWorkloads() error: %v", err)
+	}
+	if err := s.RefreshNamespaces(ctx, []model.NamespaceModel{{Name: "default"}, {Name: "kube-system"}}); err != nil {
+		t.Fatalf("RefreshNamespaces() error: %v", err)
+	}
+
+	records := decodeRecords(t, &buf)
+	if len(records) != 3 {
+		t.Fatalf("got %d records, want 3", len(records))
+	}
+	if records[0].Kind != KindWorkload || records[0].Workload == nil || records[0].Workload.Name != "web" {
+		t.Errorf("first record = %+v, want workload web", records[0])
+	}
+	if records[2].Kind != KindNamespace || records[2].Namespace == nil || records[2].Namespace.Name != "kube-system" {
+		t.Errorf("last record = %+v, want namespace kube-system", records[2])
+	}
+}
+
+func TestStreamer_NoMetricsRecordsWhenNotRecording(t *testing.T) {
+	var buf bytes.Buffer
+	cpu, mem := resource.MustParse("250m"), resource.MustParse("1Gi")
+	s := New(&buf, &fakeSource{nodes: map[string]*metrics.NodeMetrics{
+		"node-a": {NodeName: "node-a", CPUUsage: &cpu, MemoryUsage: &mem},
+	}})
+
+	if err := s.RefreshNodes(context.Background(), []model.NodeModel{{Name: "node-a"}}); err != nil {
+		t.Fatalf("RefreshNodes() error: %v", err)
+	}
+	records := decodeRecords(t, &buf)
+	if len(records) != 1 || records[0].Kind != KindNode {
+		t.Errorf("records = %+v, want a single node record", records)
+	}
+}
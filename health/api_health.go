@@ -224,6 +224,23 @@ func (h *APIHealthTracker) TryReconnect() {
 	}
 }
 
+// Stop cancels any pending retry and drops all callbacks so a tracker
+// belonging to a replaced connection can no longer notify the UI.
+// Reports made after Stop still update state but are otherwise silent.
+func (h *APIHealthTracker) Stop() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.retryTimer != nil {
+		h.retryTimer.Stop()
+		h.retryTimer = nil
+	}
+	h.onStateChange = nil
+	h.onHealthy = nil
+	h.onDisconnected = nil
+	h.onTryReconnect = nil
+}
+
 // GetState returns the current API health state
 func (h *APIHealthTracker) GetState() APIState {
 	h.mu.RLock()
@@ -262,6 +262,38 @@ func TestAPIHealthTracker_MinUnhealthyTime(t *testing.T) {
 	}
 }
 
+func TestAPIHealthTracker_Stop(t *testing.T) {
+	var calls int
+	tracker := NewAPIHealthTracker(func(state APIState, msg string) {
+		calls++
+	})
+	reconnects := 0
+	tracker.SetOnTryReconnect(func() { reconnects++ })
+
+	tracker.ReportError(errors.New("connection refused"))
+	if calls != 1 {
+		t.Fatalf("expected 1 state change before Stop, got %d", calls)
+	}
+
+	tracker.Stop()
+
+	for i := 0; i < 10; i++ {
+		tracker.ReportError(errors.New("connection refused"))
+	}
+	if calls != 1 {
+		t.Errorf("expected no callbacks after Stop, got %d more", calls-1)
+	}
+	if !tracker.IsDisconnected() {
+		t.Errorf("expected state to keep tracking after Stop, got %v", tracker.GetState())
+	}
+
+	tracker.TryReconnect()
+	time.Sleep(10 * time.Millisecond)
+	if reconnects != 0 {
+		t.Error("expected reconnect callback to be dropped by Stop")
+	}
+}
+
 func TestAPIState_String(t *testing.T) {
 	tests := []struct {
 		state    APIState
@@ -1,12 +1,14 @@
 // Package userdir resolves and provisions the per-user ktop directory
-// (default ~/.ktop), where ktop stores its log file and, from v0.6.3,
-// its config file. Honor $KTOP_DIR to relocate the entire tree.
+// (default ~/.ktop), where ktop stores its log file, its config.yaml and
+// persisted metrics history.
+// Honor $KTOP_DIR to relocate the entire tree.
 package userdir
 
 import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 // EnvVar is the environment variable that overrides the default location.
@@ -43,3 +45,45 @@ func Ensure() (string, error) {
 	}
 	return dir, nil
 }
+
+// DataDir returns the directory holding persisted data for a kubeconfig
+// context: data/<context> inside the ktop directory. Characters that are
+// not safe in a file name (context names are often ARNs or URLs) are
+// replaced with '_'. Like Path, it does not create the directory.
+func DataDir(context string) (string, error) {
+	dir, err := Path()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "data", safeName(context)), nil
+}
+
+func safeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '-', r == '_', r == '.', r == '@':
+			return r
+		}
+		return '_'
+	}, name)
+	if name == "" || name == "." || name == ".." {
+		return "_" + name
+	}
+	return name
+}
+
+// ExportPath returns the path of an exported file named name: exports/<name>
+// inside the ktop directory, which it creates if missing. Characters of
+// name that are not safe in a file name are replaced with '_'.
+func ExportPath(name string) (string, error) {
+	dir, err := Path()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "exports")
+	if err := os.MkdirAll(dir, dirMode); err != nil {
+		return "", fmt.Errorf("create %s: %w", dir, err)
+	}
+	return filepath.Join(dir, safeName(name)), nil
+}
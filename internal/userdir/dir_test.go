@@ -72,3 +72,41 @@ func TestEnsure_IsIdempotent(t *testing.T) {
 		t.Fatalf("second Ensure() error: %v", err)
 	}
 }
+
+func TestDataDir_SanitizesContextName(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(EnvVar, root)
+
+	tests := map[string]string{
+		"kind-dev": "kind-dev",
+		"arn:aws:eks:us-east-1:123456789012:cluster/prod": "arn_aws_eks_us-east-1_123456789012_cluster_prod",
+		"admin@k8s.local": "admin@k8s.local",
+		"..":              "_..",
+		"":                "_",
+	}
+	for context, want := range tests {
+		got, err := DataDir(context)
+		if err != nil {
+			t.Fatalf("DataDir(%q) error: %v", context, err)
+		}
+		if want := filepath.Join(root, "data", want); got != want {
+			t.Errorf("DataDir(%q) = %q, want %q", context, got, want)
+		}
+	}
+}
+
+func TestExportPath_CreatesExportsDirectory(t *testing.T) {
+	root := t.TempDir()
+	t.Setenv(EnvVar, root)
+
+	got, err := ExportPath("rightsizing-arn:aws/prod.yaml")
+	if err != nil {
+		t.Fatalf("ExportPath() error: %v", err)
+	}
+	if want := filepath.Join(root, "exports", "rightsizing-arn_aws_prod.yaml"); got != want {
+		t.Errorf("ExportPath() = %q, want %q", got, want)
+	}
+	if info, err := os.Stat(filepath.Dir(got)); err != nil || !info.IsDir() {
+		t.Errorf("exports directory not created: %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/vladimirvivien/ktop/views/model"
+	authzV1 "k8s.io/api/authorization/v1"
+	coreV1 "k8s.io/api/core/v1"
+	policyV1 "k8s.io/api/policy/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// restartedAtAnnotation is set on a pod template to roll its pods, as
+// kubectl rollout restart does
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// Access is an API request checked with the API server before an action
+// makes it. An empty Namespace means all namespaces, an empty Name all
+// objects.
+type Access struct {
+	Verb        string
+	Group       string
+	Resource    string
+	Subresource string
+	Namespace   string
+	Name        string
+}
+
+// String describes the access, e.g. "create pods/eviction in default"
+func (a Access) String() string {
+	resource := a.Resource
+	if a.Group != "" {
+		resource += "." + a.Group
+	}
+	if a.Subresource != "" {
+		resource += "/" + a.Subresource
+	}
+	s := a.Verb + " " + resource
+	if a.Namespace != "" {
+		s += " in " + a.Namespace
+	}
+	return s
+}
+
+// CheckAccess asks the API server, with a SelfSubjectAccessReview, whether
+// the user may make the request. reason may explain a denial.
+func (c *Controller) CheckAccess(ctx context.Context, access Access) (bool, string, error) {
+	review := &authzV1.SelfSubjectAccessReview{
+		Spec: authzV1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authzV1.ResourceAttributes{
+				Verb:        access.Verb,
+				Group:       access.Group,
+				Resource:    access.Resource,
+				Subresource: access.Subresource,
+				Namespace:   access.Namespace,
+				Name:        access.Name,
+			},
+		},
+	}
+	result, err := c.client.kubeClient.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return false, "", err
+	}
+	return result.Status.Allowed, result.Status.Reason, nil
+}
+
+// GetPodsOnNode lists the pods of all namespaces scheduled on a node from
+// the API server, as the pod informer may only watch one namespace
+func (c *Controller) GetPodsOnNode(ctx context.Context, nodeName string) ([]coreV1.Pod, error) {
+	list, err := c.client.kubeClient.CoreV1().Pods(AllNamespaces).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", nodeName).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
+
+// DeletePod deletes a pod with its default grace period
+func (c *Controller) DeletePod(ctx context.Context, namespace, podName string) error {
+	return c.client.kubeClient.CoreV1().Pods(namespace).Delete(ctx, podName, metav1.DeleteOptions{})
+}
+
+// EvictPod evicts a pod through the eviction API. The API server refuses
+// with a TooManyRequests error while a PodDisruptionBudget of the pod does
+// not allow the disruption.
+func (c *Controller) EvictPod(ctx context.Context, namespace, podName string) error {
+	return c.client.kubeClient.CoreV1().Pods(namespace).EvictV1(ctx, &policyV1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: podName},
+	})
+}
+
+// CordonNode marks a node unschedulable, or schedulable again when cordon
+// is false
+func (c *Controller) CordonNode(ctx context.Context, nodeName string, cordon bool) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"unschedulable": cordon},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = c.client.kubeClient.CoreV1().Nodes().Patch(ctx, nodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// ScaleWorkload sets the replicas of a Deployment or StatefulSet through
+// its scale subresource
+func (c *Controller) ScaleWorkload(ctx context.Context, kind, namespace, name string, replicas int32) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{"replicas": replicas},
+	})
+	if err != nil {
+		return err
+	}
+	apps := c.client.kubeClient.AppsV1()
+	switch kind {
+	case model.WorkloadKindDeployment:
+		_, err = apps.Deployments(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}, "scale")
+	case model.WorkloadKindStatefulSet:
+		_, err = apps.StatefulSets(namespace).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}, "scale")
+	default:
+		return fmt.Errorf("cannot scale a %s", kind)
+	}
+	return err
+}
+
+// RestartWorkload rolls the pods of a Deployment, StatefulSet or DaemonSet
+// by stamping the restart time on its pod template
+func (c *Controller) RestartWorkload(ctx context.Context, kind, namespace, name string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"annotations": map[string]string{
+						restartedAtAnnotation: time.Now().Format(time.RFC3339),
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	apps := c.client.kubeClient.AppsV1()
+	switch kind {
+	case model.WorkloadKindDeployment:
+		_, err = apps.Deployments(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case model.WorkloadKindStatefulSet:
+		_, err = apps.StatefulSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case model.WorkloadKindDaemonSet:
+		_, err = apps.DaemonSets(namespace).Patch(ctx, name, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	default:
+		return fmt.Errorf("cannot restart a %s", kind)
+	}
+	return err
+}
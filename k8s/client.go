@@ -3,6 +3,7 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -35,6 +36,7 @@ var (
 		"pods":                   {Group: "", Version: "v1", Resource: "pods"},
 		"persistentvolumes":      {Group: "", Version: "v1", Resource: "persistentvolumes"},
 		"persistentvolumeclaims": {Group: "", Version: "v1", Resource: "persistentvolumeclaims"},
+		"resourcequotas":         {Group: "", Version: "v1", Resource: "resourcequotas"},
 		"deployments":            {Group: appsV1.GroupName, Version: "v1", Resource: "deployments"},
 		"daemonsets":             {Group: appsV1.GroupName, Version: "v1", Resource: "daemonsets"},
 		"replicasets":            {Group: appsV1.GroupName, Version: "v1", Resource: "replicasets"},
@@ -92,8 +94,15 @@ func New(flags *genericclioptions.ConfigFlags) (*Client, error) {
 		return nil, err
 	}
 
+	// RawConfig reports the kubeconfig's current-context; --context wins
+	// when set so ClusterContext names the context actually in use.
+	contextName := apiCfg.CurrentContext
+	if flags.Context != nil && *flags.Context != "" {
+		contextName = *flags.Context
+	}
+
 	username := "<empty>"
-	currCtx, ok := apiCfg.Contexts[apiCfg.CurrentContext]
+	currCtx, ok := apiCfg.Contexts[contextName]
 	if ok {
 		username = currCtx.AuthInfo
 	}
@@ -109,7 +118,7 @@ func New(flags *genericclioptions.ConfigFlags) (*Client, error) {
 		namespace:      namespace,
 		config:         config,
 		apiConfig:      apiCfg,
-		clusterContext: apiCfg.CurrentContext,
+		clusterContext: contextName,
 		username:       username,
 		kubeClient:     kubeClient,
 		discoClient:    disco,
@@ -131,6 +140,16 @@ func (k8s *Client) ClusterContext() string {
 	return k8s.clusterContext
 }
 
+// Contexts returns the context names defined in the kubeconfig, sorted.
+func (k8s *Client) Contexts() []string {
+	names := make([]string, 0, len(k8s.apiConfig.Contexts))
+	for name := range k8s.apiConfig.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // ClusterName returns the cluster name from kubeconfig (may differ from context name)
 func (k8s *Client) ClusterName() string {
 	if ctx, ok := k8s.apiConfig.Contexts[k8s.clusterContext]; ok {
@@ -193,7 +212,8 @@ func (k8s *Client) GetMetricsClient() *metricsclient.Clientset {
 }
 
 // AssertCoreAuthz verifies read access to core resources using lightweight
-// GET requests instead of SelfSubjectAccessReview, keeping ktop fully read-only.
+// GET requests instead of SelfSubjectAccessReview. The access reviews are
+// left to the actions that change resources (see CheckAccess).
 func (k8s *Client) AssertCoreAuthz(ctx context.Context) error {
 	listOpts := metav1.ListOptions{Limit: 1}
 
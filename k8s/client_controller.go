@@ -20,19 +20,22 @@ import (
 type RefreshNodesFunc func(ctx context.Context, items []model.NodeModel) error
 type RefreshPodsFunc func(ctx context.Context, items []model.PodModel) error
 type RefreshSummaryFunc func(ctx context.Context, items model.ClusterSummary) error
+type RefreshWorkloadsFunc func(ctx context.Context, items []model.WorkloadModel) error
+type RefreshNamespacesFunc func(ctx context.Context, items []model.NamespaceModel) error
 
 type Controller struct {
 	client        *Client
 	metricsSource metrics.MetricsSource // NEW: for cluster summary metrics
 
-	nodeMetricsInformer *NodeMetricsInformer // DEPRECATED: no longer initialized
-	podMetricsInformer  *PodMetricsInformer  // DEPRECATED: no longer initialized
-	namespaceInformer   coreV1Informers.NamespaceInformer
-	nodeInformer        coreV1Informers.NodeInformer
-	podInformer         coreV1Informers.PodInformer
-	pvInformer          coreV1Informers.PersistentVolumeInformer
-	pvcInformer         coreV1Informers.PersistentVolumeClaimInformer
-	eventInformer       coreV1Informers.EventInformer
+	nodeMetricsInformer   *NodeMetricsInformer // DEPRECATED: no longer initialized
+	podMetricsInformer    *PodMetricsInformer  // DEPRECATED: no longer initialized
+	namespaceInformer     coreV1Informers.NamespaceInformer
+	nodeInformer          coreV1Informers.NodeInformer
+	podInformer           coreV1Informers.PodInformer
+	pvInformer            coreV1Informers.PersistentVolumeInformer
+	pvcInformer           coreV1Informers.PersistentVolumeClaimInformer
+	eventInformer         coreV1Informers.EventInformer
+	resourceQuotaInformer coreV1Informers.ResourceQuotaInformer
 
 	jobInformer     batchV1Informers.JobInformer
 	cronJobInformer batchV1Informers.CronJobInformer
@@ -42,9 +45,11 @@ type Controller struct {
 	replicaSetInformer  appsV1Informers.ReplicaSetInformer
 	statefulSetInformer appsV1Informers.StatefulSetInformer
 
-	nodeRefreshFunc    RefreshNodesFunc
-	podRefreshFunc     RefreshPodsFunc
-	summaryRefreshFunc RefreshSummaryFunc
+	nodeRefreshFunc      RefreshNodesFunc
+	podRefreshFunc       RefreshPodsFunc
+	summaryRefreshFunc   RefreshSummaryFunc
+	workloadRefreshFunc  RefreshWorkloadsFunc
+	namespaceRefreshFunc RefreshNamespacesFunc
 
 	// API health tracking
 	healthTracker *health.APIHealthTracker
@@ -64,6 +69,16 @@ func (c *Controller) SetPodRefreshFunc(fn RefreshPodsFunc) *Controller {
 	return c
 }
 
+func (c *Controller) SetWorkloadRefreshFunc(fn RefreshWorkloadsFunc) *Controller {
+	c.workloadRefreshFunc = fn
+	return c
+}
+
+func (c *Controller) SetNamespaceRefreshFunc(fn RefreshNamespacesFunc) *Controller {
+	c.namespaceRefreshFunc = fn
+	return c
+}
+
 func (c *Controller) SetClusterSummaryRefreshFunc(fn RefreshSummaryFunc) *Controller {
 	c.summaryRefreshFunc = fn
 	return c
@@ -134,6 +149,10 @@ func (c *Controller) Start(ctx context.Context, resync time.Duration) error {
 	pvcHasSynced := c.pvcInformer.Informer().HasSynced
 	c.eventInformer = coreInformers.Events()
 	eventHasSynced := c.eventInformer.Informer().HasSynced
+	// ResourceQuotas only annotate the namespace panel and are not waited on,
+	// so users without access to them still get a working UI
+	c.resourceQuotaInformer = coreInformers.ResourceQuotas()
+	c.resourceQuotaInformer.Informer()
 
 	// Apps/v1 Informers
 	appsInformers := factory.Apps().V1()
@@ -184,13 +203,17 @@ func (c *Controller) Start(ctx context.Context, resync time.Duration) error {
 			cronJobHasSynced,
 		)
 		if !ok {
-			panic("resource failed to sync")
+			// Only happens when ctx is done, e.g. the session was replaced
+			// by a context or namespace switch before the caches filled
+			slog.Debug("informer cache sync stopped", "error", ctx.Err())
 		}
 	}()
 
 	c.setupSummaryHandler(ctx, c.summaryRefreshFunc)
 	c.setupNodeHandler(ctx, c.nodeRefreshFunc)
 	c.installPodsHandler(ctx, c.podRefreshFunc)
+	c.installWorkloadsHandler(ctx, c.workloadRefreshFunc)
+	c.installNamespacesHandler(ctx, c.namespaceRefreshFunc)
 
 	// Wire up reconnect callback to trigger immediate health check when user presses Retry
 	if c.healthTracker != nil {
@@ -120,6 +120,39 @@ func (c *Controller) GetPVCList(ctx context.Context) ([]*coreV1.PersistentVolume
 	return items, nil
 }
 
+// GetResourceQuotaList returns the cached ResourceQuotas. The informer is not
+// required to sync, so the list is empty for users who cannot watch quotas.
+func (c *Controller) GetResourceQuotaList(ctx context.Context) ([]*coreV1.ResourceQuota, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	items, err := c.resourceQuotaInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// GetEvents returns the events of all namespaces in scope, most recent first
+func (c *Controller) GetEvents(ctx context.Context) ([]coreV1.Event, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	allEvents, err := c.eventInformer.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]coreV1.Event, 0, len(allEvents))
+	for _, evt := range allEvents {
+		events = append(events, *evt)
+	}
+
+	sortEventsByTime(events)
+	return events, nil
+}
+
 // GetEventsForNode returns events related to a specific node
 func (c *Controller) GetEventsForNode(ctx context.Context, nodeName string) ([]coreV1.Event, error) {
 	if ctx.Err() != nil {
@@ -0,0 +1,93 @@
+package k8s
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/vladimirvivien/ktop/metrics"
+	coreV1 "k8s.io/api/core/v1"
+)
+
+// Cluster is a connection as the UI sees it: the identity shown in the header
+// and the ClusterSource feeding the views. *Client implements it for a live
+// cluster; a replayed recording implements it offline.
+type Cluster interface {
+	Namespace() string
+	ClusterContext() string
+	ClusterName() string
+	Username() string
+	GetServerVersion() string
+	Contexts() []string
+	Source() ClusterSource
+}
+
+// ClusterSource delivers models to the registered Refresh*Func callbacks once
+// started, and answers the lookups made by the detail views. *Controller,
+// through Client.Source, is the implementation backed by informers.
+type ClusterSource interface {
+	SetMetricsSource(source metrics.MetricsSource)
+	SetNodeRefreshFunc(fn RefreshNodesFunc)
+	SetPodRefreshFunc(fn RefreshPodsFunc)
+	SetWorkloadRefreshFunc(fn RefreshWorkloadsFunc)
+	SetNamespaceRefreshFunc(fn RefreshNamespacesFunc)
+	SetClusterSummaryRefreshFunc(fn RefreshSummaryFunc)
+	Start(ctx context.Context, resync time.Duration) error
+
+	GetNamespaceList(ctx context.Context) ([]*coreV1.Namespace, error)
+	GetNodeList(ctx context.Context) ([]*coreV1.Node, error)
+	GetPodList(ctx context.Context) ([]*coreV1.Pod, error)
+	GetNode(ctx context.Context, nodeName string) (*coreV1.Node, error)
+	GetPod(ctx context.Context, namespace, podName string) (*coreV1.Pod, error)
+	GetEvents(ctx context.Context) ([]coreV1.Event, error)
+	GetEventsForNode(ctx context.Context, nodeName string) ([]coreV1.Event, error)
+	GetEventsForPod(ctx context.Context, namespace, podName string) ([]coreV1.Event, error)
+	GetPodLogs(ctx context.Context, namespace, podName string, opts LogOptions) (io.ReadCloser, error)
+	WatchPods(ctx context.Context, sel PodSelector, onChange func(pod *coreV1.Pod, deleted bool)) error
+	Exec(ctx context.Context, namespace, podName string, opts ExecOptions) error
+	PortForward(ctx context.Context, namespace, podName string, opts PortForwardOptions) error
+
+	// Changes made by the actions on the detail pages
+	CheckAccess(ctx context.Context, access Access) (bool, string, error)
+	GetPodsOnNode(ctx context.Context, nodeName string) ([]coreV1.Pod, error)
+	DeletePod(ctx context.Context, namespace, podName string) error
+	EvictPod(ctx context.Context, namespace, podName string) error
+	CordonNode(ctx context.Context, nodeName string, cordon bool) error
+	ScaleWorkload(ctx context.Context, kind, namespace, name string, replicas int32) error
+	RestartWorkload(ctx context.Context, kind, namespace, name string) error
+}
+
+// Source returns the client's controller as a ClusterSource
+func (k8s *Client) Source() ClusterSource {
+	return controllerSource{k8s.controller}
+}
+
+// controllerSource adapts *Controller, whose setters return the controller
+// for chaining, to the setters of ClusterSource
+type controllerSource struct {
+	*Controller
+}
+
+func (s controllerSource) SetMetricsSource(source metrics.MetricsSource) {
+	s.Controller.SetMetricsSource(source)
+}
+
+func (s controllerSource) SetNodeRefreshFunc(fn RefreshNodesFunc) {
+	s.Controller.SetNodeRefreshFunc(fn)
+}
+
+func (s controllerSource) SetPodRefreshFunc(fn RefreshPodsFunc) {
+	s.Controller.SetPodRefreshFunc(fn)
+}
+
+func (s controllerSource) SetWorkloadRefreshFunc(fn RefreshWorkloadsFunc) {
+	s.Controller.SetWorkloadRefreshFunc(fn)
+}
+
+func (s controllerSource) SetNamespaceRefreshFunc(fn RefreshNamespacesFunc) {
+	s.Controller.SetNamespaceRefreshFunc(fn)
+}
+
+func (s controllerSource) SetClusterSummaryRefreshFunc(fn RefreshSummaryFunc) {
+	s.Controller.SetClusterSummaryRefreshFunc(fn)
+}
@@ -0,0 +1,106 @@
+package k8s
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/vladimirvivien/ktop/views/model"
+)
+
+// GetNamespaceModels returns a model for each namespace in scope with pod
+// counts, restarts, requests and limits against ResourceQuota, and usage
+// summed from the metrics source (left at zero when metrics are unavailable).
+func (c *Controller) GetNamespaceModels(ctx context.Context) ([]model.NamespaceModel, error) {
+	namespaces, err := c.GetNamespaceList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := c.GetPodList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	quotas, err := c.GetResourceQuotaList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*model.NamespaceModel, len(namespaces))
+	for _, ns := range namespaces {
+		// A namespace-scoped session only shows its own namespace
+		if c.client.namespace != AllNamespaces && ns.Name != c.client.namespace {
+			continue
+		}
+		byName[ns.Name] = model.NewNamespaceModel(ns)
+	}
+
+	for _, pod := range pods {
+		if m, ok := byName[pod.Namespace]; ok {
+			m.AddPod(pod)
+		}
+	}
+	for _, quota := range quotas {
+		if m, ok := byName[quota.Namespace]; ok {
+			m.AddQuota(quota)
+		}
+	}
+
+	if c.metricsSource != nil {
+		podMetrics, err := c.metricsSource.GetAllPodMetrics(ctx)
+		if err != nil {
+			slog.Debug("namespace usage unavailable", "error", err)
+		}
+		for _, pm := range podMetrics {
+			m, ok := byName[pm.Namespace]
+			if !ok {
+				continue
+			}
+			for _, cm := range pm.Containers {
+				m.AddUsage(cm.CPUUsage, cm.MemoryUsage)
+			}
+		}
+	}
+
+	models := make([]model.NamespaceModel, 0, len(byName))
+	for _, m := range byName {
+		models = append(models, *m)
+	}
+	return models, nil
+}
+
+func (c *Controller) installNamespacesHandler(ctx context.Context, refreshFunc RefreshNamespacesFunc) {
+	if refreshFunc == nil {
+		return
+	}
+	go func() {
+		c.refreshNamespaces(ctx, refreshFunc) // initial refresh
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := c.refreshNamespaces(ctx, refreshFunc); err != nil {
+					continue
+				}
+			}
+		}
+	}()
+}
+
+func (c *Controller) refreshNamespaces(ctx context.Context, refreshFunc RefreshNamespacesFunc) error {
+	// Skip refresh if API is disconnected - don't update UI with stale cached data
+	if c.healthTracker != nil && c.healthTracker.IsDisconnected() {
+		return nil
+	}
+
+	models, err := c.GetNamespaceModels(ctx)
+	if err != nil {
+		c.reportError(err)
+		return err
+	}
+	c.reportSuccess()
+	refreshFunc(ctx, models)
+	return nil
+}
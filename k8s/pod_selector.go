@@ -0,0 +1,136 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/vladimirvivien/ktop/views/model"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// PodSelector picks a set of pods in Namespace (all namespaces when empty):
+// those owned by the workload Kind/Name when Kind is set, otherwise those
+// matching Labels
+type PodSelector struct {
+	Namespace string
+	Labels    labels.Selector
+	Kind      string
+	Name      string
+}
+
+// workloadKinds maps the workload names ParsePodSelector accepts, as kubectl
+// spells them, to their kind
+var workloadKinds = map[string]string{
+	"deployment":   model.WorkloadKindDeployment,
+	"deployments":  model.WorkloadKindDeployment,
+	"deploy":       model.WorkloadKindDeployment,
+	"statefulset":  model.WorkloadKindStatefulSet,
+	"statefulsets": model.WorkloadKindStatefulSet,
+	"sts":          model.WorkloadKindStatefulSet,
+	"daemonset":    model.WorkloadKindDaemonSet,
+	"daemonsets":   model.WorkloadKindDaemonSet,
+	"ds":           model.WorkloadKindDaemonSet,
+}
+
+// ParsePodSelector reads a workload such as "deployment/web" or a label
+// selector such as "app=web,tier!=cache" for the pods in namespace
+func ParsePodSelector(s, namespace string) (PodSelector, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return PodSelector{}, fmt.Errorf("empty pod selector")
+	}
+	if kind, name, ok := strings.Cut(s, "/"); ok {
+		if k, known := workloadKinds[strings.ToLower(kind)]; known {
+			if name == "" {
+				return PodSelector{}, fmt.Errorf("%s needs a name", k)
+			}
+			return PodSelector{Namespace: namespace, Kind: k, Name: name}, nil
+		}
+	}
+	selector, err := labels.Parse(s)
+	if err != nil {
+		return PodSelector{}, fmt.Errorf("label selector %q: %w", s, err)
+	}
+	return PodSelector{Namespace: namespace, Labels: selector}, nil
+}
+
+// String writes the selector the way ParsePodSelector reads it
+func (s PodSelector) String() string {
+	if s.Kind != "" {
+		return s.Kind + "/" + s.Name
+	}
+	if s.Labels == nil || s.Labels.Empty() {
+		return "all pods"
+	}
+	return s.Labels.String()
+}
+
+// matchesPodSelector reports whether pod is one of the pods sel picks
+func (c *Controller) matchesPodSelector(sel PodSelector, pod *coreV1.Pod) bool {
+	if sel.Namespace != "" && pod.Namespace != sel.Namespace {
+		return false
+	}
+	if sel.Kind != "" {
+		kind, name := c.getPodWorkload(pod)
+		return kind == sel.Kind && name == sel.Name
+	}
+	return sel.Labels == nil || sel.Labels.Matches(labels.Set(pod.Labels))
+}
+
+// WatchPods calls onChange with a copy of each pod picked by sel as the pod
+// informer adds or updates it, starting with the pods already cached, and
+// with deleted set once the pod is gone or no longer picked. onChange runs
+// on the informer's goroutine. Watching stops when ctx is done.
+func (c *Controller) WatchPods(ctx context.Context, sel PodSelector, onChange func(pod *coreV1.Pod, deleted bool)) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	toPod := func(obj interface{}) (*coreV1.Pod, bool) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		pod, ok := obj.(*coreV1.Pod)
+		return pod, ok
+	}
+
+	informer := c.podInformer.Informer()
+	registration, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if pod, ok := toPod(obj); ok && c.matchesPodSelector(sel, pod) {
+				onChange(pod.DeepCopy(), false)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			pod, ok := toPod(newObj)
+			if !ok {
+				return
+			}
+			if c.matchesPodSelector(sel, pod) {
+				onChange(pod.DeepCopy(), false)
+			} else if old, ok := toPod(oldObj); ok && c.matchesPodSelector(sel, old) {
+				onChange(pod.DeepCopy(), true) // Relabeled out of the selection
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if pod, ok := toPod(obj); ok && c.matchesPodSelector(sel, pod) {
+				onChange(pod.DeepCopy(), true)
+			}
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("watch pods: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		if err := informer.RemoveEventHandler(registration); err != nil {
+			slog.Debug("pod watch: removing handler failed", "error", err)
+		}
+	}()
+	return nil
+}
@@ -8,6 +8,9 @@ import (
 	"github.com/vladimirvivien/ktop/views/model"
 	coreV1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
 	metricsV1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 )
 
@@ -64,6 +67,84 @@ func (c *Controller) GetPodLogs(ctx context.Context, namespace, podName string,
 	return req.Stream(ctx)
 }
 
+// ExecOptions configures a command run in a container. Streams left nil are
+// not attached; Stderr must be nil when TTY is set, the terminal carrying
+// both outputs.
+type ExecOptions struct {
+	Container string
+	Command   []string
+	Stdin     io.Reader
+	Stdout    io.Writer
+	Stderr    io.Writer
+	TTY       bool
+	Resize    <-chan TerminalSize // sizes of the terminal, closed when done
+}
+
+// TerminalSize is the size of a terminal in characters
+type TerminalSize struct {
+	Width  uint16
+	Height uint16
+}
+
+// Exec runs a command in a pod container, streaming until it exits or ctx
+// is cancelled
+func (c *Controller) Exec(ctx context.Context, namespace, podName string, opts ExecOptions) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	req := c.client.kubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("exec").
+		VersionedParams(&coreV1.PodExecOptions{
+			Container: opts.Container,
+			Command:   opts.Command,
+			Stdin:     opts.Stdin != nil,
+			Stdout:    opts.Stdout != nil,
+			Stderr:    opts.Stderr != nil,
+			TTY:       opts.TTY,
+		}, scheme.ParameterCodec)
+
+	// Websockets first, as kubectl does, and SPDY for API servers that
+	// can't upgrade the connection to them
+	websocket, err := remotecommand.NewWebSocketExecutor(c.client.config, "GET", req.URL().String())
+	if err != nil {
+		return err
+	}
+	spdy, err := remotecommand.NewSPDYExecutor(c.client.config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	executor, err := remotecommand.NewFallbackExecutor(websocket, spdy, httpstream.IsUpgradeFailure)
+	if err != nil {
+		return err
+	}
+
+	streamOpts := remotecommand.StreamOptions{
+		Stdin:  opts.Stdin,
+		Stdout: opts.Stdout,
+		Stderr: opts.Stderr,
+		Tty:    opts.TTY,
+	}
+	if opts.Resize != nil {
+		streamOpts.TerminalSizeQueue = terminalSizeQueue(opts.Resize)
+	}
+	return executor.StreamWithContext(ctx, streamOpts)
+}
+
+// terminalSizeQueue adapts a channel of sizes to remotecommand
+type terminalSizeQueue <-chan TerminalSize
+
+func (q terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q
+	if !ok {
+		return nil
+	}
+	return &remotecommand.TerminalSize{Width: size.Width, Height: size.Height}
+}
+
 func (c *Controller) GetPodModels(ctx context.Context) (models []model.PodModel, err error) {
 	pods, err := c.GetPodList(ctx)
 	if err != nil {
@@ -0,0 +1,135 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// PortForwardOptions configures a forward of a local port to a pod port
+type PortForwardOptions struct {
+	LocalPort  uint16 // 0 picks a free port
+	RemotePort uint16
+
+	// Ready is called with the local port once it accepts connections
+	Ready func(localPort uint16)
+
+	// Traffic counts the bytes forwarded, when set
+	Traffic *PortForwardTraffic
+}
+
+// PortForwardTraffic counts the bytes carried by a port forward
+type PortForwardTraffic struct {
+	BytesIn  atomic.Int64 // received from the pod
+	BytesOut atomic.Int64 // sent to the pod
+}
+
+// PortForward listens on a local port and forwards its connections to a
+// port of a pod until ctx is done or the connection to the pod is lost
+func (c *Controller) PortForward(ctx context.Context, namespace, podName string, opts PortForwardOptions) error {
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	transport, upgrader, err := spdy.RoundTripperFor(c.client.config)
+	if err != nil {
+		return err
+	}
+	req := c.client.kubeClient.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("portforward")
+
+	var dialer httpstream.Dialer = spdy.NewDialer(upgrader, &http.Client{Transport: transport}, http.MethodPost, req.URL())
+	if opts.Traffic != nil {
+		dialer = countingDialer{Dialer: dialer, traffic: opts.Traffic}
+	}
+
+	stopCh := make(chan struct{})
+	readyCh := make(chan struct{})
+	ports := []string{fmt.Sprintf("%d:%d", opts.LocalPort, opts.RemotePort)}
+	forwarder, err := portforward.NewOnAddresses(dialer, []string{"localhost"}, ports, stopCh, readyCh, io.Discard, io.Discard)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			close(stopCh)
+		case <-done:
+		}
+	}()
+	go func() {
+		select {
+		case <-readyCh:
+			if opts.Ready == nil {
+				return
+			}
+			if forwarded, err := forwarder.GetPorts(); err == nil && len(forwarded) > 0 {
+				opts.Ready(forwarded[0].Local)
+			}
+		case <-done:
+		}
+	}()
+
+	err = forwarder.ForwardPorts()
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// countingDialer counts the bytes of the data streams of its connections
+type countingDialer struct {
+	httpstream.Dialer
+	traffic *PortForwardTraffic
+}
+
+func (d countingDialer) Dial(protocols ...string) (httpstream.Connection, string, error) {
+	conn, protocol, err := d.Dialer.Dial(protocols...)
+	if err != nil {
+		return nil, "", err
+	}
+	return countingConnection{Connection: conn, traffic: d.traffic}, protocol, nil
+}
+
+type countingConnection struct {
+	httpstream.Connection
+	traffic *PortForwardTraffic
+}
+
+func (c countingConnection) CreateStream(headers http.Header) (httpstream.Stream, error) {
+	stream, err := c.Connection.CreateStream(headers)
+	if err != nil || headers.Get(coreV1.StreamType) != coreV1.StreamTypeData {
+		return stream, err
+	}
+	return countingStream{Stream: stream, traffic: c.traffic}, nil
+}
+
+type countingStream struct {
+	httpstream.Stream
+	traffic *PortForwardTraffic
+}
+
+func (s countingStream) Read(p []byte) (int, error) {
+	n, err := s.Stream.Read(p)
+	s.traffic.BytesIn.Add(int64(n))
+	return n, err
+}
+
+func (s countingStream) Write(p []byte) (int, error) {
+	n, err := s.Stream.Write(p)
+	s.traffic.BytesOut.Add(int64(n))
+	return n, err
+}
@@ -2,6 +2,7 @@ package k8s
 
 import (
 	"context"
+	"log/slog"
 	"time"
 
 	"github.com/vladimirvivien/ktop/metrics"
@@ -116,6 +117,11 @@ func (c *Controller) refreshSummary(ctx context.Context, handlerFunc RefreshSumm
 				summary.EvictedPods++
 			}
 		}
+		for _, cs := range pod.Status.ContainerStatuses {
+			if isOOMKilled(cs.State.Terminated) || isOOMKilled(cs.LastTerminationState.Terminated) {
+				summary.OOMKillCount++
+			}
+		}
 		containerSummary := model.GetPodContainerSummary(pod)
 		summary.RequestedPodMemTotal.Add(*containerSummary.RequestedMemQty)
 		summary.RequestedPodCpuTotal.Add(*containerSummary.RequestedCpuQty)
@@ -199,7 +205,7 @@ func (c *Controller) refreshSummary(ctx context.Context, handlerFunc RefreshSumm
 
 	// === Prometheus-Enhanced Metrics Collection ===
 	// When using Prometheus source, collect additional cluster-wide metrics
-	if c.metricsSource != nil && summary.MetricsSourceType == metrics.SourceTypePrometheus {
+	if c.metricsSource != nil && metrics.IsPrometheusSource(summary.MetricsSourceType) {
 		c.collectPrometheusEnhancedMetrics(ctx, &summary, nodes, pods)
 	}
 
@@ -249,4 +255,28 @@ func (c *Controller) collectPrometheusEnhancedMetrics(ctx context.Context, summa
 			summary.NodePressureCount++
 		}
 	}
+
+	// Average throttling of the containers hitting their CPU limit
+	if source, ok := c.metricsSource.(metrics.QuerySource); ok {
+		pressure, err := metrics.QueryContainerPressure(ctx, source)
+		if err != nil {
+			slog.Debug("summary: container pressure query failed", "error", err)
+			return
+		}
+		var throttled int
+		for _, cp := range pressure {
+			if cp.ThrottledPercent > 0 {
+				summary.CPUThrottledPercent += cp.ThrottledPercent
+				throttled++
+			}
+		}
+		if throttled > 0 {
+			summary.CPUThrottledPercent /= float64(throttled)
+		}
+	}
+}
+
+// isOOMKilled reports whether a container terminated for running out of memory
+func isOOMKilled(state *coreV1.ContainerStateTerminated) bool {
+	return state != nil && state.Reason == "OOMKilled"
 }
@@ -0,0 +1,120 @@
+package k8s
+
+import (
+	"context"
+	"time"
+
+	"github.com/vladimirvivien/ktop/views/model"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetWorkloadModels returns a model for every Deployment, StatefulSet and
+// DaemonSet, each listing the pods it owns. Resource totals are left for the
+// view layer, which aggregates them from pod models carrying fresh metrics.
+func (c *Controller) GetWorkloadModels(ctx context.Context) ([]model.WorkloadModel, error) {
+	deployments, err := c.GetDeploymentList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	statefulSets, err := c.GetStatefulSetList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	daemonSets, err := c.GetDaemonSetList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	pods, err := c.GetPodList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Index owned pods by workload key
+	podsByOwner := make(map[string][]string)
+	for _, pod := range pods {
+		kind, name := c.getPodWorkload(pod)
+		if kind == "" {
+			continue
+		}
+		key := model.WorkloadKey(kind, pod.Namespace, name)
+		podsByOwner[key] = append(podsByOwner[key], pod.Namespace+"/"+pod.Name)
+	}
+
+	models := make([]model.WorkloadModel, 0, len(deployments)+len(statefulSets)+len(daemonSets))
+	add := func(m *model.WorkloadModel) {
+		m.Pods = podsByOwner[m.Key()]
+		models = append(models, *m)
+	}
+	for _, d := range deployments {
+		add(model.NewDeploymentModel(d))
+	}
+	for _, s := range statefulSets {
+		add(model.NewStatefulSetModel(s))
+	}
+	for _, ds := range daemonSets {
+		add(model.NewDaemonSetModel(ds))
+	}
+	return models, nil
+}
+
+// getPodWorkload returns the kind and name of the workload controlling pod.
+// Pods owned by a ReplicaSet are attributed to the ReplicaSet's Deployment;
+// it returns empty strings for pods not owned by a supported workload.
+func (c *Controller) getPodWorkload(pod *coreV1.Pod) (kind, name string) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "", ""
+	}
+
+	switch owner.Kind {
+	case model.WorkloadKindStatefulSet, model.WorkloadKindDaemonSet:
+		return owner.Kind, owner.Name
+	case "ReplicaSet":
+		rs, err := c.replicaSetInformer.Lister().ReplicaSets(pod.Namespace).Get(owner.Name)
+		if err != nil {
+			return "", ""
+		}
+		if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil && rsOwner.Kind == model.WorkloadKindDeployment {
+			return model.WorkloadKindDeployment, rsOwner.Name
+		}
+	}
+	return "", ""
+}
+
+func (c *Controller) installWorkloadsHandler(ctx context.Context, refreshFunc RefreshWorkloadsFunc) {
+	if refreshFunc == nil {
+		return
+	}
+	go func() {
+		c.refreshWorkloads(ctx, refreshFunc) // initial refresh
+		ticker := time.NewTicker(5 * time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := c.refreshWorkloads(ctx, refreshFunc); err != nil {
+					continue
+				}
+			}
+		}
+	}()
+}
+
+func (c *Controller) refreshWorkloads(ctx context.Context, refreshFunc RefreshWorkloadsFunc) error {
+	// Skip refresh if API is disconnected - don't update UI with stale cached data
+	if c.healthTracker != nil && c.healthTracker.IsDisconnected() {
+		return nil
+	}
+
+	models, err := c.GetWorkloadModels(ctx)
+	if err != nil {
+		c.reportError(err)
+		return err
+	}
+	c.reportSuccess()
+	refreshFunc(ctx, models)
+	return nil
+}
@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"context"
+	"time"
+)
+
+// ControlPlaneSource is implemented by metrics sources that can report
+// control-plane health. Callers check for it with a type assertion:
+//
+//	if cp, ok := source.(metrics.ControlPlaneSource); ok { ... }
+type ControlPlaneSource interface {
+	// GetControlPlaneMetrics returns the latest control-plane metrics.
+	// Components that are not scraped are left nil.
+	GetControlPlaneMetrics(ctx context.Context) (*ControlPlaneMetrics, error)
+}
+
+// ControlPlaneMetrics summarizes the health of the Kubernetes control plane.
+type ControlPlaneMetrics struct {
+	// Timestamp when these metrics were computed
+	Timestamp time.Time
+
+	// Window is the time range rates and percentiles are computed over
+	Window time.Duration
+
+	APIServer         *APIServerMetrics
+	Etcd              *EtcdMetrics
+	Scheduler         *SchedulerMetrics
+	ControllerManager *ControllerManagerMetrics
+
+	// Kubelet is node-level, but slow pod starts are often where scheduling
+	// problems end up, so it is reported alongside the control plane
+	Kubelet *KubeletMetrics
+}
+
+// APIServerMetrics holds request throughput and latency for kube-apiserver.
+type APIServerMetrics struct {
+	// RequestRate is the number of requests per second
+	RequestRate float64
+
+	// ErrorRate is the number of requests per second answered with a 5xx code
+	ErrorRate float64
+
+	// Latency percentiles of non-streaming requests (WATCH and CONNECT are
+	// excluded). Zero when no requests completed during the window.
+	LatencyP50 time.Duration
+	LatencyP90 time.Duration
+	LatencyP99 time.Duration
+
+	// InflightReadOnly and InflightMutating are the requests currently being served
+	InflightReadOnly float64
+	InflightMutating float64
+}
+
+// EtcdMetrics holds the state of the etcd cluster backing the apiserver.
+type EtcdMetrics struct {
+	// Members is the number of etcd members reporting metrics
+	Members int
+
+	// HasLeader is false when any member reports no leader
+	HasLeader bool
+
+	// DBSizeBytes is the largest database size across members
+	DBSizeBytes float64
+
+	// LeaderChanges is the highest leader change count across members since they started
+	LeaderChanges float64
+
+	// RecentLeaderChanges is the number of leader changes in the last hour
+	RecentLeaderChanges float64
+}
+
+// SchedulerMetrics holds the kube-scheduler queues.
+type SchedulerMetrics struct {
+	// PendingPods is the number of pending pods per scheduling queue
+	// (active, backoff, unschedulable, gated)
+	PendingPods map[string]float64
+}
+
+// ControllerManagerMetrics holds the kube-controller-manager workqueues.
+type ControllerManagerMetrics struct {
+	// WorkqueueDepth is the number of queued items per controller workqueue
+	WorkqueueDepth map[string]float64
+}
+
+// KubeletMetrics holds pod start latency across all kubelets.
+type KubeletMetrics struct {
+	// Window is the time range the percentiles are computed over. Pods start
+	// far less often than requests are served, so it is longer than
+	// ControlPlaneMetrics.Window.
+	Window time.Duration
+
+	// Pod start latency percentiles, from a kubelet first seeing a pod until
+	// it runs, image pulls included. Zero when no pods started during Window.
+	PodStartP50 time.Duration
+	PodStartP99 time.Duration
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vladimirvivien/ktop/prom"
+)
+
+// Tables custom columns can be added to
+const (
+	ColumnTableNode = "node"
+	ColumnTablePod  = "pod"
+)
+
+// CustomColumn is a user-defined node or pod table column, such as
+//
+//	GPU_MEM = sum(DCGM_FI_DEV_FB_USED) by (namespace, pod)
+//
+// The query is PromQL in the subset prom.Query evaluates, and must be an
+// aggregation by labels. The table follows from the grouping: by node adds
+// a node column, by pod or by (namespace, pod) a pod column. Grouping by pod
+// alone adds up pods of the same name in different namespaces.
+type CustomColumn struct {
+	Name  string
+	Table string // ColumnTableNode or ColumnTablePod
+	Query *prom.Query
+	By    []string // grouping labels of the query
+}
+
+// ParseCustomColumn parses "<NAME> = <query>". Names are upper-cased like
+// the built-in columns.
+func ParseCustomColumn(def string) (CustomColumn, error) {
+	name, expr, ok := strings.Cut(def, "=")
+	name = strings.ToUpper(strings.TrimSpace(name))
+	if !ok || name == "" {
+		return CustomColumn{}, fmt.Errorf("custom column %q must be written NAME = expression", def)
+	}
+	if strings.ContainsAny(name, "{}(),\"") {
+		return CustomColumn{}, fmt.Errorf("invalid custom column name %q", name)
+	}
+
+	query, err := prom.ParseQuery(expr)
+	if err != nil {
+		return CustomColumn{}, fmt.Errorf("custom column %s: %w", name, err)
+	}
+	by, ok := query.Grouping()
+	if !ok {
+		return CustomColumn{}, fmt.Errorf("custom column %s: %q must be an aggregation by labels", name, query)
+	}
+
+	col := CustomColumn{Name: name, Query: query, By: by}
+	sorted := slices.Clone(by)
+	sort.Strings(sorted)
+	switch strings.Join(sorted, ",") {
+	case "node":
+		col.Table = ColumnTableNode
+	case "pod", "namespace,pod":
+		col.Table = ColumnTablePod
+	default:
+		return CustomColumn{}, fmt.Errorf("custom column %s must be grouped by node, pod or (namespace, pod), got (%s)", name, strings.Join(by, ", "))
+	}
+	return col, nil
+}
+
+// GroupKey returns the group a series belongs to: the values of the By
+// labels, in order, joined with "/". label looks up a label of the series.
+func (c CustomColumn) GroupKey(label func(name string) string) string {
+	values := make([]string, len(c.By))
+	for i, name := range c.By {
+		values[i] = label(name)
+	}
+	return strings.Join(values, "/")
+}
+
+// RowKey returns the group key of the node or pod shown on a table row;
+// namespace is ignored for nodes.
+func (c CustomColumn) RowKey(namespace, name string) string {
+	return c.GroupKey(func(label string) string {
+		if label == "namespace" {
+			return namespace
+		}
+		return name
+	})
+}
+
+// QueryCustomColumn evaluates col against source, returning the current
+// value of each group keyed by GroupKey
+func QueryCustomColumn(ctx context.Context, source QuerySource, col CustomColumn) (map[string]float64, error) {
+	now := time.Now()
+	series, err := source.QueryRange(ctx, col.Query.String(), now, now, time.Minute)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]float64, len(series))
+	for _, s := range series {
+		if len(s.DataPoints) == 0 {
+			continue
+		}
+		v := s.DataPoints[len(s.DataPoints)-1].Value
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		values[col.GroupKey(func(name string) string { return s.Labels[name] })] = v
+	}
+	return values, nil
+}
@@ -124,6 +124,17 @@ func (m *MetricsServerSource) GetPodMetrics(ctx context.Context, namespace, podN
 	m.recordHistory(key+":cpu", now, float64(totalCPU))
 	m.recordHistory(key+":memory", now, float64(totalMem))
 
+	// And per container, for container history queries
+	for _, c := range result.Containers {
+		containerKey := fmt.Sprintf("container:%s/%s/%s", namespace, podName, c.Name)
+		if c.CPUUsage != nil {
+			m.recordHistory(containerKey+":cpu", now, float64(c.CPUUsage.MilliValue()))
+		}
+		if c.MemoryUsage != nil {
+			m.recordHistory(containerKey+":memory", now, float64(c.MemoryUsage.Value()))
+		}
+	}
+
 	return result, nil
 }
 
@@ -366,6 +377,9 @@ func (m *MetricsServerSource) GetPodHistory(ctx context.Context, namespace, podN
 	}
 
 	key := fmt.Sprintf("pod:%s/%s:%s", namespace, podName, suffix)
+	if query.Container != "" {
+		key = fmt.Sprintf("container:%s/%s/%s:%s", namespace, podName, query.Container, suffix)
+	}
 	return m.getHistoryFromBuffer(key, query)
 }
 
@@ -0,0 +1,107 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Windows over which container pressure is measured
+const (
+	ThrottleWindow = 5 * time.Minute
+	OOMWindow      = time.Hour
+)
+
+// Workload containers only: cAdvisor also reports the pod cgroup
+// (container="") and the pause container
+const workloadContainers = `container!="", container!="POD"`
+
+var (
+	throttledQuery = fmt.Sprintf(
+		`100 * sum by (namespace, pod, container) (increase(container_cpu_cfs_throttled_periods_total{%[1]s}[%[2]s]))`+
+			` / sum by (namespace, pod, container) (increase(container_cpu_cfs_periods_total{%[1]s}[%[2]s]))`,
+		workloadContainers, formatRange(ThrottleWindow))
+	oomEventsQuery = fmt.Sprintf(
+		`sum by (namespace, pod, container) (increase(container_oom_events_total{%s}[%s]))`,
+		workloadContainers, formatRange(OOMWindow))
+)
+
+// ContainerPressure is the CPU throttling and OOM events of a container
+type ContainerPressure struct {
+	Namespace string
+	Pod       string
+	Container string
+
+	// ThrottledPercent is the share of CFS periods in ThrottleWindow in which
+	// the container was throttled (0.0 - 100.0). Only containers with a CPU
+	// limit have CFS periods.
+	ThrottledPercent float64
+
+	// OOMEvents counts the OOM events in the container's cgroup in OOMWindow
+	OOMEvents float64
+}
+
+// QueryContainerPressure returns the pressure of every container that was
+// throttled or hit OOM, evaluated with PromQL against source
+func QueryContainerPressure(ctx context.Context, source QuerySource) ([]ContainerPressure, error) {
+	now := time.Now()
+	byContainer := make(map[string]*ContainerPressure)
+	var order []string
+
+	record := func(expr string, set func(cp *ContainerPressure, v float64)) error {
+		series, err := source.QueryRange(ctx, expr, now, now, time.Minute)
+		if err != nil {
+			return err
+		}
+		for _, s := range series {
+			if len(s.DataPoints) == 0 {
+				continue
+			}
+			v := s.DataPoints[len(s.DataPoints)-1].Value
+			// Zero, or NaN for containers that ran no CFS periods
+			if v <= 0 || math.IsNaN(v) {
+				continue
+			}
+			key := s.Labels["namespace"] + "/" + s.Labels["pod"] + "/" + s.Labels["container"]
+			cp, ok := byContainer[key]
+			if !ok {
+				cp = &ContainerPressure{
+					Namespace: s.Labels["namespace"],
+					Pod:       s.Labels["pod"],
+					Container: s.Labels["container"],
+				}
+				byContainer[key] = cp
+				order = append(order, key)
+			}
+			set(cp, v)
+		}
+		return nil
+	}
+
+	if err := record(throttledQuery, func(cp *ContainerPressure, v float64) { cp.ThrottledPercent = v }); err != nil {
+		return nil, fmt.Errorf("query cpu throttling: %w", err)
+	}
+	if err := record(oomEventsQuery, func(cp *ContainerPressure, v float64) { cp.OOMEvents = v }); err != nil {
+		return nil, fmt.Errorf("query oom events: %w", err)
+	}
+
+	result := make([]ContainerPressure, len(order))
+	for i, key := range order {
+		result[i] = *byContainer[key]
+	}
+	return result, nil
+}
+
+// formatRange writes a duration the way PromQL ranges are written (2m, 90s)
+func formatRange(d time.Duration) string {
+	switch {
+	case d%time.Hour == 0:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d%time.Minute == 0:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	case d%time.Second == 0:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+	return fmt.Sprintf("%dms", d/time.Millisecond)
+}
@@ -0,0 +1,211 @@
+package prom
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/prom"
+)
+
+// leaderChangeWindow is how far back etcd leader changes count as recent
+const leaderChangeWindow = time.Hour
+
+// podStartWindow is the time range pod start latency is computed over
+const podStartWindow = 15 * time.Minute
+
+// GetControlPlaneMetrics implements metrics.ControlPlaneSource from the
+// apiserver, etcd, scheduler and controller-manager series in the store.
+// Only components listed in the scrape config have data.
+func (p *PromMetricsSource) GetControlPlaneMetrics(ctx context.Context) (*metrics.ControlPlaneMetrics, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.isHealthyLocked() {
+		return nil, fmt.Errorf("prometheus source is not healthy")
+	}
+
+	if p.store == nil {
+		return nil, fmt.Errorf("metrics store not initialized")
+	}
+
+	// Rates need a few samples per series; widen the window for slow scrapes
+	window := time.Minute
+	if w := 4 * p.config.ScrapeInterval; w > window {
+		window = w
+	}
+
+	return &metrics.ControlPlaneMetrics{
+		Timestamp:         time.Now(),
+		Window:            window,
+		APIServer:         p.apiServerMetrics(window),
+		Etcd:              p.etcdMetrics(),
+		Scheduler:         p.schedulerMetrics(),
+		ControllerManager: p.controllerManagerMetrics(),
+		Kubelet:           p.kubeletMetrics(),
+	}, nil
+}
+
+func (p *PromMetricsSource) apiServerMetrics(window time.Duration) *metrics.APIServerMetrics {
+	requestRate, err := p.calculateCPURate("apiserver_request_total", nil, window)
+	if err != nil {
+		return nil
+	}
+
+	api := &metrics.APIServerMetrics{RequestRate: requestRate}
+	api.ErrorRate, _ = p.calculateCPURateWithFilter("apiserver_request_total", nil, window, func(seriesKey string) bool {
+		return strings.HasPrefix(seriesLabel(seriesKey, "code"), "5")
+	})
+
+	api.LatencyP50 = p.latencyQuantile("apiserver_request_duration_seconds", 0.50, window)
+	api.LatencyP90 = p.latencyQuantile("apiserver_request_duration_seconds", 0.90, window)
+	api.LatencyP99 = p.latencyQuantile("apiserver_request_duration_seconds", 0.99, window)
+
+	api.InflightReadOnly, _ = p.store.QueryLatestSum("apiserver_current_inflight_requests", map[string]string{"request_kind": "readOnly"})
+	api.InflightMutating, _ = p.store.QueryLatestSum("apiserver_current_inflight_requests", map[string]string{"request_kind": "mutating"})
+	return api
+}
+
+func (p *PromMetricsSource) etcdMetrics() *metrics.EtcdMetrics {
+	dbSizes := p.latestPerSeries("etcd_mvcc_db_total_size_in_bytes")
+	if len(dbSizes) == 0 {
+		return nil
+	}
+
+	etcd := &metrics.EtcdMetrics{Members: len(dbSizes), HasLeader: true}
+	for _, size := range dbSizes {
+		etcd.DBSizeBytes = math.Max(etcd.DBSizeBytes, size)
+	}
+	for _, hasLeader := range p.latestPerSeries("etcd_server_has_leader") {
+		if hasLeader == 0 {
+			etcd.HasLeader = false
+		}
+	}
+	for _, changes := range p.latestPerSeries("etcd_server_leader_changes_seen_total") {
+		etcd.LeaderChanges = math.Max(etcd.LeaderChanges, changes)
+	}
+	if increases, err := p.increasePerSeries("etcd_server_leader_changes_seen_total", leaderChangeWindow); err == nil {
+		for _, increase := range increases {
+			etcd.RecentLeaderChanges = math.Max(etcd.RecentLeaderChanges, increase)
+		}
+	}
+	return etcd
+}
+
+func (p *PromMetricsSource) schedulerMetrics() *metrics.SchedulerMetrics {
+	pending := p.latestByLabel("scheduler_pending_pods", "queue")
+	if pending == nil {
+		return nil
+	}
+	return &metrics.SchedulerMetrics{PendingPods: pending}
+}
+
+func (p *PromMetricsSource) controllerManagerMetrics() *metrics.ControllerManagerMetrics {
+	depth := p.latestByLabel("workqueue_depth", "name")
+	if depth == nil {
+		return nil
+	}
+	return &metrics.ControllerManagerMetrics{WorkqueueDepth: depth}
+}
+
+func (p *PromMetricsSource) kubeletMetrics() *metrics.KubeletMetrics {
+	if len(p.latestPerSeries("kubelet_pod_start_duration_seconds_count")) == 0 {
+		return nil
+	}
+	return &metrics.KubeletMetrics{
+		Window:      podStartWindow,
+		PodStartP50: p.latencyQuantile("kubelet_pod_start_duration_seconds", 0.50, podStartWindow),
+		PodStartP99: p.latencyQuantile("kubelet_pod_start_duration_seconds", 0.99, podStartWindow),
+	}
+}
+
+// latestPerSeries returns the latest value of each series of metricName
+// seen in the last five minutes, keyed by series.
+func (p *PromMetricsSource) latestPerSeries(metricName string) map[string]float64 {
+	now := time.Now()
+	seriesSamples, err := p.store.QueryRangePerSeries(metricName, nil, now.Add(-5*time.Minute), now)
+	if err != nil {
+		return nil
+	}
+
+	latest := make(map[string]float64, len(seriesSamples))
+	for seriesKey, samples := range seriesSamples {
+		if len(samples) > 0 {
+			latest[seriesKey] = samples[len(samples)-1].Value
+		}
+	}
+	return latest
+}
+
+// latestByLabel sums the latest values of metricName by the value of label,
+// adding up the pods of a component. Returns nil when there are no series.
+func (p *PromMetricsSource) latestByLabel(metricName, label string) map[string]float64 {
+	latest := p.latestPerSeries(metricName)
+	if len(latest) == 0 {
+		return nil
+	}
+
+	byLabel := make(map[string]float64)
+	for seriesKey, value := range latest {
+		byLabel[seriesLabel(seriesKey, label)] += value
+	}
+	return byLabel
+}
+
+// increasePerSeries returns how much each series of the counter metricName
+// grew within window, evaluated as increase(metricName[window])
+func (p *PromMetricsSource) increasePerSeries(metricName string, window time.Duration) ([]float64, error) {
+	query, err := prom.ParseQuery(fmt.Sprintf("increase(%s[%s])", metricName, model.Duration(window)))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	results, err := query.Range(p.store, now, now, window)
+	if err != nil {
+		return nil, err
+	}
+
+	increases := make([]float64, 0, len(results))
+	for _, result := range results {
+		for _, point := range result.Points {
+			increases = append(increases, point.Value)
+		}
+	}
+	if len(increases) == 0 {
+		return nil, fmt.Errorf("insufficient samples for %s", metricName)
+	}
+	return increases, nil
+}
+
+// latencyQuantile returns the q-quantile of a histogram of durations in
+// seconds, or zero when nothing was observed during window.
+func (p *PromMetricsSource) latencyQuantile(metricName string, q float64, window time.Duration) time.Duration {
+	seconds, err := p.store.QueryQuantile(metricName, nil, q, window)
+	if err != nil {
+		return 0
+	}
+	return secondsToDuration(seconds)
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if math.IsNaN(seconds) {
+		return 0
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// seriesLabel returns the value of label name in a series key, which is
+// written by labels.Labels.String(): {__name__="metric", code="200", ...}
+func seriesLabel(seriesKey, name string) string {
+	for _, prefix := range []string{"{", " "} {
+		if _, rest, ok := strings.Cut(seriesKey, prefix+name+`="`); ok {
+			value, _, _ := strings.Cut(rest, `"`)
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package prom
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/vladimirvivien/ktop/prom"
+	"k8s.io/client-go/rest"
+)
+
+// addSeries stores one sample per value for a series, spaced 10s apart
+// and ending now
+func addSeries(t *testing.T, store prom.MetricsStore, lbls labels.Labels, values ...float64) {
+	t.Helper()
+	addSeriesAt(t, store, time.Now(), lbls, values...)
+}
+
+// addSeriesAt is addSeries ending at end, so that several series can share
+// timestamps
+func addSeriesAt(t *testing.T, store prom.MetricsStore, end time.Time, lbls labels.Labels, values ...float64) {
+	t.Helper()
+	name := lbls.Get("__name__")
+	for i, v := range values {
+		samples := prom.NewRingBuffer[prom.MetricSample](1)
+		samples.Add(prom.MetricSample{
+			Timestamp: end.Add(-time.Duration(len(values)-1-i) * 10 * time.Second).UnixMilli(),
+			Value:     v,
+		})
+		err := store.AddMetrics(&prom.ScrapedMetrics{
+			Families: map[string]*prom.MetricFamily{
+				name: {Name: name, TimeSeries: []*prom.TimeSeries{{Labels: lbls, Samples: samples}}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("AddMetrics failed: %v", err)
+		}
+	}
+}
+
+func TestGetControlPlaneMetrics(t *testing.T) {
+	source, _ := NewPromMetricsSource(&rest.Config{}, nil)
+	store := prom.NewInMemoryStore(prom.DefaultScrapeConfig())
+	source.store = store
+	source.setHealthyForTesting(true)
+
+	// 10 requests/s, of which 1/s failed
+	addSeries(t, store, labels.FromStrings("__name__", "apiserver_request_total", "code", "200"), 1000, 1090, 1180)
+	addSeries(t, store, labels.FromStrings("__name__", "apiserver_request_total", "code", "500"), 10, 20, 30)
+	// Half the requests took up to 100ms, all of them up to 1s
+	addSeries(t, store, labels.FromStrings("__name__", "apiserver_request_duration_seconds_bucket", "le", "0.1"), 0, 50)
+	addSeries(t, store, labels.FromStrings("__name__", "apiserver_request_duration_seconds_bucket", "le", "1"), 0, 100)
+	addSeries(t, store, labels.FromStrings("__name__", "apiserver_request_duration_seconds_bucket", "le", "+Inf"), 0, 100)
+	addSeries(t, store, labels.FromStrings("__name__", "apiserver_current_inflight_requests", "request_kind", "readOnly"), 7)
+	addSeries(t, store, labels.FromStrings("__name__", "apiserver_current_inflight_requests", "request_kind", "mutating"), 3)
+
+	addSeries(t, store, labels.FromStrings("__name__", "etcd_mvcc_db_total_size_in_bytes", "pod", "etcd-a"), 100)
+	addSeries(t, store, labels.FromStrings("__name__", "etcd_mvcc_db_total_size_in_bytes", "pod", "etcd-b"), 120)
+	addSeries(t, store, labels.FromStrings("__name__", "etcd_server_has_leader", "pod", "etcd-a"), 1)
+	addSeries(t, store, labels.FromStrings("__name__", "etcd_server_has_leader", "pod", "etcd-b"), 1)
+	addSeries(t, store, labels.FromStrings("__name__", "etcd_server_leader_changes_seen_total", "pod", "etcd-a"), 4, 5)
+
+	addSeries(t, store, labels.FromStrings("__name__", "scheduler_pending_pods", "queue", "active", "pod", "sched-a"), 2)
+	addSeries(t, store, labels.FromStrings("__name__", "scheduler_pending_pods", "queue", "active", "pod", "sched-b"), 0)
+	addSeries(t, store, labels.FromStrings("__name__", "scheduler_pending_pods", "queue", "unschedulable", "pod", "sched-a"), 5)
+
+	// Pod starts on two nodes: 2 within 1s, 2 more within 5s
+	addSeries(t, store, labels.FromStrings("__name__", "kubelet_pod_start_duration_seconds_bucket", "le", "1", "node", "a"), 0, 1)
+	addSeries(t, store, labels.FromStrings("__name__", "kubelet_pod_start_duration_seconds_bucket", "le", "1", "node", "b"), 5, 6)
+	addSeries(t, store, labels.FromStrings("__name__", "kubelet_pod_start_duration_seconds_bucket", "le", "5", "node", "a"), 0, 3)
+	addSeries(t, store, labels.FromStrings("__name__", "kubelet_pod_start_duration_seconds_bucket", "le", "5", "node", "b"), 5, 6)
+	addSeries(t, store, labels.FromStrings("__name__", "kubelet_pod_start_duration_seconds_bucket", "le", "+Inf", "node", "a"), 0, 3)
+	addSeries(t, store, labels.FromStrings("__name__", "kubelet_pod_start_duration_seconds_bucket", "le", "+Inf", "node", "b"), 5, 6)
+	addSeries(t, store, labels.FromStrings("__name__", "kubelet_pod_start_duration_seconds_count", "node", "a"), 0, 3)
+
+	cp, err := source.GetControlPlaneMetrics(context.Background())
+	if err != nil {
+		t.Fatalf("GetControlPlaneMetrics failed: %v", err)
+	}
+
+	api := cp.APIServer
+	if api == nil {
+		t.Fatal("Expected apiserver metrics")
+	}
+	if math.Abs(api.RequestRate-10) > 0.01 || math.Abs(api.ErrorRate-1) > 0.01 {
+		t.Errorf("Expected 10 req/s with 1 error/s, got %.2f and %.2f", api.RequestRate, api.ErrorRate)
+	}
+	if api.LatencyP50 != 100*time.Millisecond {
+		t.Errorf("Expected p50 of 100ms, got %v", api.LatencyP50)
+	}
+	if api.LatencyP99 < 900*time.Millisecond || api.LatencyP99 > time.Second {
+		t.Errorf("Expected p99 between 900ms and 1s, got %v", api.LatencyP99)
+	}
+	if api.InflightReadOnly != 7 || api.InflightMutating != 3 {
+		t.Errorf("Expected 7 read-only and 3 mutating inflight, got %v and %v", api.InflightReadOnly, api.InflightMutating)
+	}
+
+	etcd := cp.Etcd
+	if etcd == nil || etcd.Members != 2 || !etcd.HasLeader || etcd.DBSizeBytes != 120 {
+		t.Fatalf("Unexpected etcd metrics %+v", etcd)
+	}
+	if etcd.LeaderChanges != 5 || etcd.RecentLeaderChanges != 1 {
+		t.Errorf("Expected 5 leader changes, 1 recent, got %v and %v", etcd.LeaderChanges, etcd.RecentLeaderChanges)
+	}
+
+	if cp.Scheduler == nil || cp.Scheduler.PendingPods["active"] != 2 || cp.Scheduler.PendingPods["unschedulable"] != 5 {
+		t.Errorf("Unexpected scheduler metrics %+v", cp.Scheduler)
+	}
+	if cp.Kubelet == nil || cp.Kubelet.PodStartP50 != time.Second || cp.Kubelet.PodStartP99 < 4*time.Second {
+		t.Errorf("Unexpected kubelet metrics %+v", cp.Kubelet)
+	}
+	if cp.ControllerManager != nil {
+		t.Errorf("Expected no controller-manager metrics when it isn't scraped, got %+v", cp.ControllerManager)
+	}
+}
+
+func TestGetControlPlaneMetrics_NotHealthy(t *testing.T) {
+	source, _ := NewPromMetricsSource(&rest.Config{}, nil)
+	source.setHealthyForTesting(false)
+
+	if _, err := source.GetControlPlaneMetrics(context.Background()); err == nil {
+		t.Error("Expected error when source is not healthy")
+	}
+}
+
+func TestSeriesLabel(t *testing.T) {
+	key := labels.FromStrings("__name__", "workqueue_depth", "name", "deployment", "pod", "kcm-a").String()
+	if got := seriesLabel(key, "name"); got != "deployment" {
+		t.Errorf("Expected name=deployment, got %q", got)
+	}
+	if got := seriesLabel(key, "le"); got != "" {
+		t.Errorf("Expected missing label to be empty, got %q", got)
+	}
+}
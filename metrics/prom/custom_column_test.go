@@ -0,0 +1,87 @@
+package prom
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/prom"
+	"k8s.io/client-go/rest"
+)
+
+func TestQueryCustomColumn(t *testing.T) {
+	source, _ := NewPromMetricsSource(&rest.Config{}, nil)
+	store := prom.NewInMemoryStore(prom.DefaultScrapeConfig())
+	source.store = store
+	source.setHealthyForTesting(true)
+
+	// Two GPUs on one pod, one on another
+	addSeries(t, store, labels.FromStrings("__name__", "DCGM_FI_DEV_FB_USED", "gpu", "0", "namespace", "ml", "pod", "trainer-0", "node", "gpu-a"), 1000)
+	addSeries(t, store, labels.FromStrings("__name__", "DCGM_FI_DEV_FB_USED", "gpu", "1", "namespace", "ml", "pod", "trainer-0", "node", "gpu-a"), 3000)
+	addSeries(t, store, labels.FromStrings("__name__", "DCGM_FI_DEV_FB_USED", "gpu", "0", "namespace", "ml", "pod", "notebook", "node", "gpu-b"), 500)
+
+	tests := []struct {
+		def  string
+		want map[string]float64
+	}{
+		{"GPU_MEM = sum(DCGM_FI_DEV_FB_USED) by (namespace, pod)", map[string]float64{"ml/trainer-0": 4000, "ml/notebook": 500}},
+		{"GPU_MAX = max by (node) (DCGM_FI_DEV_FB_USED)", map[string]float64{"gpu-a": 3000, "gpu-b": 500}},
+		{`GPU0 = avg(DCGM_FI_DEV_FB_USED{gpu="0"}) by (pod)`, map[string]float64{"trainer-0": 1000, "notebook": 500}},
+		{"GPUS = count(DCGM_FI_DEV_FB_USED) by (node)", map[string]float64{"gpu-a": 2, "gpu-b": 1}},
+	}
+	for _, tt := range tests {
+		col, err := metrics.ParseCustomColumn(tt.def)
+		if err != nil {
+			t.Fatalf("ParseCustomColumn(%q) failed: %v", tt.def, err)
+		}
+		got, err := metrics.QueryCustomColumn(context.Background(), source, col)
+		if err != nil {
+			t.Fatalf("%s: QueryCustomColumn failed: %v", col.Name, err)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %v", col.Name, tt.want, got)
+		}
+		for key, want := range tt.want {
+			if got[key] != want {
+				t.Errorf("%s: expected %s = %v, got %v", col.Name, key, want, got[key])
+			}
+		}
+	}
+}
+
+func TestQueryCustomColumn_Rate(t *testing.T) {
+	source, _ := NewPromMetricsSource(&rest.Config{}, nil)
+	store := prom.NewInMemoryStore(prom.DefaultScrapeConfig())
+	source.store = store
+	source.setHealthyForTesting(true)
+
+	// 5 throttled periods/s, with a counter reset between the last two samples
+	addSeries(t, store, labels.FromStrings("__name__", "container_cpu_cfs_throttled_periods_total", "namespace", "web", "pod", "api", "container", "app"), 100, 150, 50)
+
+	col, err := metrics.ParseCustomColumn("THROTTLED = sum(rate(container_cpu_cfs_throttled_periods_total[1m])) by (namespace, pod)")
+	if err != nil {
+		t.Fatalf("ParseCustomColumn failed: %v", err)
+	}
+	got, err := metrics.QueryCustomColumn(context.Background(), source, col)
+	if err != nil {
+		t.Fatalf("QueryCustomColumn failed: %v", err)
+	}
+	if rate := got[col.RowKey("web", "api")]; math.Abs(rate-5) > 0.01 {
+		t.Errorf("Expected 5/s, got %v", rate)
+	}
+}
+
+func TestQueryCustomColumn_NotHealthy(t *testing.T) {
+	source, _ := NewPromMetricsSource(&rest.Config{}, nil)
+	source.setHealthyForTesting(false)
+
+	col, err := metrics.ParseCustomColumn("X = sum(x) by (pod)")
+	if err != nil {
+		t.Fatalf("ParseCustomColumn failed: %v", err)
+	}
+	if _, err := metrics.QueryCustomColumn(context.Background(), source, col); err == nil {
+		t.Error("Expected error when source is not healthy")
+	}
+}
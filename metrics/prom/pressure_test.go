@@ -0,0 +1,67 @@
+package prom
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/prom"
+	"k8s.io/client-go/rest"
+)
+
+func TestQueryContainerPressure(t *testing.T) {
+	source, _ := NewPromMetricsSource(&rest.Config{}, nil)
+	store := prom.NewInMemoryStore(prom.DefaultScrapeConfig())
+	source.store = store
+	source.setHealthyForTesting(true)
+
+	container := func(name, namespace, pod, container string) labels.Labels {
+		return labels.FromStrings("__name__", name, "namespace", namespace, "pod", pod, "container", container)
+	}
+
+	// Throttled in 60 of 200 periods
+	addSeries(t, store, container("container_cpu_cfs_periods_total", "web", "api", "app"), 0, 100, 200)
+	addSeries(t, store, container("container_cpu_cfs_throttled_periods_total", "web", "api", "app"), 0, 30, 60)
+	// Never throttled
+	addSeries(t, store, container("container_cpu_cfs_periods_total", "web", "idle", "app"), 0, 100)
+	addSeries(t, store, container("container_cpu_cfs_throttled_periods_total", "web", "idle", "app"), 0, 0)
+	// The pod cgroup and pause container are left out
+	addSeries(t, store, container("container_cpu_cfs_periods_total", "web", "api", ""), 0, 100)
+	addSeries(t, store, container("container_cpu_cfs_throttled_periods_total", "web", "api", ""), 0, 90)
+	addSeries(t, store, container("container_oom_events_total", "web", "api", "POD"), 0, 1)
+	// An OOM event in two containers, one of them also throttled
+	addSeries(t, store, container("container_oom_events_total", "db", "pg", "postgres"), 3, 4)
+	addSeries(t, store, container("container_oom_events_total", "web", "api", "app"), 0, 1)
+
+	got, err := metrics.QueryContainerPressure(context.Background(), source)
+	if err != nil {
+		t.Fatalf("QueryContainerPressure failed: %v", err)
+	}
+
+	want := map[string]metrics.ContainerPressure{
+		"web/api/app":    {Namespace: "web", Pod: "api", Container: "app", ThrottledPercent: 30, OOMEvents: 1},
+		"db/pg/postgres": {Namespace: "db", Pod: "pg", Container: "postgres", OOMEvents: 1},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d containers, got %v", len(want), got)
+	}
+	for _, cp := range got {
+		w, ok := want[cp.Namespace+"/"+cp.Pod+"/"+cp.Container]
+		if !ok {
+			t.Errorf("Unexpected container %+v", cp)
+			continue
+		}
+		if math.Abs(cp.ThrottledPercent-w.ThrottledPercent) > 0.01 || cp.OOMEvents != w.OOMEvents {
+			t.Errorf("Expected %+v, got %+v", w, cp)
+		}
+	}
+}
+
+func TestQueryContainerPressure_NotHealthy(t *testing.T) {
+	source, _ := NewPromMetricsSource(&rest.Config{}, nil)
+	if _, err := metrics.QueryContainerPressure(context.Background(), source); err == nil {
+		t.Error("Expected error from an unhealthy source")
+	}
+}
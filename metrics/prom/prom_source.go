@@ -38,6 +38,11 @@ type PromConfig struct {
 	RetentionTime  time.Duration
 	MaxSamples     int
 	Components     []prom.ComponentType
+	DataDir        string // Persist history here across restarts; empty keeps it in memory only
+
+	// ExtraMetrics lists metric names or regexes, per component, scraped
+	// beyond the built-in allowlists (see prom.ScrapeConfig)
+	ExtraMetrics map[prom.ComponentType][]string
 }
 
 // DefaultPromConfig returns a default Prometheus configuration
@@ -69,6 +74,8 @@ func NewPromMetricsSource(kubeConfig *rest.Config, config *PromConfig) (*PromMet
 		RetentionTime: config.RetentionTime,
 		InsecureTLS:   false,
 		Components:    config.Components,
+		DataDir:       config.DataDir,
+		ExtraMetrics:  config.ExtraMetrics,
 	}
 
 	// Create the collector controller
@@ -821,6 +828,9 @@ func (p *PromMetricsSource) GetPodHistory(ctx context.Context, namespace, podNam
 		"pod":       podName,
 		"namespace": namespace,
 	}
+	if query.Container != "" {
+		labelMatchers["container"] = query.Container
+	}
 
 	switch query.Resource {
 	case metrics.ResourceCPU:
@@ -854,7 +864,7 @@ func (p *PromMetricsSource) GetPodHistory(ctx context.Context, namespace, podNam
 	// For memory, determine the right filter based on available data
 	// Some pods (static pods) only have aggregate metrics (container="")
 	memoryFilter := isWorkloadContainerMemory
-	if query.Resource == metrics.ResourceMemory {
+	if query.Resource == metrics.ResourceMemory && query.Container == "" {
 		hasIndividualContainers := false
 		for seriesKey := range seriesSamples {
 			if isWorkloadContainerMemory(seriesKey) {
@@ -957,6 +967,12 @@ func (p *PromMetricsSource) SupportsHistory() bool {
 	return true
 }
 
+// Retention implements metrics.RetentionSource with the retention of the
+// scraped samples
+func (p *PromMetricsSource) Retention() time.Duration {
+	return p.config.RetentionTime
+}
+
 // downsampleDataPoints reduces the number of data points by averaging
 func downsampleDataPoints(points []metrics.HistoryDataPoint, maxPoints int) []metrics.HistoryDataPoint {
 	if len(points) <= maxPoints {
@@ -6,6 +6,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/vladimirvivien/ktop/metrics"
 	"github.com/vladimirvivien/ktop/prom"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -143,6 +145,10 @@ func (m *MockMetricsStore) QueryRangePerSeries(metricName string, labelMatchers
 	return nil, fmt.Errorf("metric %s not found", metricName)
 }
 
+func (m *MockMetricsStore) QueryQuantile(metricName string, labelMatchers map[string]string, q float64, window time.Duration) (float64, error) {
+	return 0, fmt.Errorf("metric %s not found", metricName)
+}
+
 func (m *MockMetricsStore) GetMetricNames() []string {
 	names := make([]string, 0, len(m.metrics))
 	for name := range m.metrics {
@@ -155,6 +161,10 @@ func (m *MockMetricsStore) GetLabelValues(labelName string) []string {
 	return []string{}
 }
 
+func (m *MockMetricsStore) GetLabelNames() []string {
+	return []string{}
+}
+
 func (m *MockMetricsStore) Cleanup() error {
 	return nil
 }
@@ -708,3 +718,35 @@ func TestGetMetricsForPod_NotImplemented(t *testing.T) {
 		t.Errorf("Expected pod name 'test-pod', got '%s'", metrics.PodName)
 	}
 }
+
+func TestGetPodHistory_Container(t *testing.T) {
+	source, _ := NewPromMetricsSource(&rest.Config{}, nil)
+	store := prom.NewInMemoryStore(prom.DefaultScrapeConfig())
+	source.store = store
+	source.setHealthyForTesting(true)
+
+	memory := func(container string) labels.Labels {
+		return labels.FromStrings("__name__", "container_memory_working_set_bytes", "namespace", "web", "pod", "api", "container", container)
+	}
+	now := time.Now()
+	addSeriesAt(t, store, now, memory("app"), 100, 300)
+	addSeriesAt(t, store, now, memory("sidecar"), 10, 20)
+
+	query := metrics.HistoryQuery{Resource: metrics.ResourceMemory, Duration: time.Minute}
+	pod, err := source.GetPodHistory(context.Background(), "web", "api", query)
+	if err != nil {
+		t.Fatalf("GetPodHistory failed: %v", err)
+	}
+	if pod.MaxValue != 320 {
+		t.Errorf("Expected the pod's containers summed to 320, got %v", pod.MaxValue)
+	}
+
+	query.Container = "app"
+	app, err := source.GetPodHistory(context.Background(), "web", "api", query)
+	if err != nil {
+		t.Fatalf("GetPodHistory failed: %v", err)
+	}
+	if len(app.DataPoints) != 2 || app.MinValue != 100 || app.MaxValue != 300 {
+		t.Errorf("Expected the app container alone, got %+v", app)
+	}
+}
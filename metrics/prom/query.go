@@ -0,0 +1,75 @@
+package prom
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/prom"
+)
+
+// QueryRange implements metrics.QuerySource by evaluating expr against the
+// scraped series in the store. Only the subset of PromQL described by
+// prom.Query is supported.
+func (p *PromMetricsSource) QueryRange(ctx context.Context, expr string, start, end time.Time, step time.Duration) ([]metrics.QuerySeries, error) {
+	query, err := prom.ParseQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	store, err := p.queryStore()
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := query.Range(store, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	series := make([]metrics.QuerySeries, len(results))
+	for i, result := range results {
+		series[i].Labels = result.Labels.Map()
+		series[i].DataPoints = make([]metrics.HistoryDataPoint, len(result.Points))
+		for j, point := range result.Points {
+			series[i].DataPoints[j] = metrics.HistoryDataPoint{
+				Timestamp: time.UnixMilli(point.Timestamp),
+				Value:     point.Value,
+			}
+		}
+	}
+	return series, nil
+}
+
+// MetricNames implements metrics.QuerySource with the scraped metrics
+func (p *PromMetricsSource) MetricNames(ctx context.Context) ([]string, error) {
+	store, err := p.queryStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.GetMetricNames(), nil
+}
+
+// LabelNames implements metrics.QuerySource with the labels of the scraped
+// metrics
+func (p *PromMetricsSource) LabelNames(ctx context.Context) ([]string, error) {
+	store, err := p.queryStore()
+	if err != nil {
+		return nil, err
+	}
+	return store.GetLabelNames(), nil
+}
+
+// queryStore returns the store once the source is healthy. The store has
+// its own lock, so queries run without holding p.mu.
+func (p *PromMetricsSource) queryStore() (prom.MetricsStore, error) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if !p.isHealthyLocked() {
+		return nil, fmt.Errorf("prometheus source is not healthy")
+	}
+	if p.store == nil {
+		return nil, fmt.Errorf("metrics store not initialized")
+	}
+	return p.store, nil
+}
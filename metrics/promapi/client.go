@@ -0,0 +1,222 @@
+package promapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// apiClient issues PromQL queries against the Prometheus HTTP API
+// (/api/v1/query and /api/v1/query_range) and lists metric and label names.
+// Thanos Query and other servers that implement the same API work as well.
+type apiClient struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+}
+
+// apiResponse is the envelope shared by all Prometheus API responses
+type apiResponse struct {
+	Status    string          `json:"status"`
+	Data      json.RawMessage `json:"data"`
+	ErrorType string          `json:"errorType"`
+	Error     string          `json:"error"`
+}
+
+type queryData struct {
+	ResultType string          `json:"resultType"`
+	Result     json.RawMessage `json:"result"`
+}
+
+// sample is one element of an instant vector
+type sample struct {
+	Metric map[string]string `json:"metric"`
+	Value  samplePair        `json:"value"`
+}
+
+// series is one element of a range matrix
+type series struct {
+	Metric map[string]string `json:"metric"`
+	Values []samplePair      `json:"values"`
+}
+
+// samplePair decodes the API's [<unix seconds>, "<value>"] pairs
+type samplePair struct {
+	Timestamp time.Time
+	Value     float64
+}
+
+func (s *samplePair) UnmarshalJSON(b []byte) error {
+	var raw [2]json.RawMessage
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	var ts float64
+	if err := json.Unmarshal(raw[0], &ts); err != nil {
+		return fmt.Errorf("sample timestamp: %w", err)
+	}
+	var value string
+	if err := json.Unmarshal(raw[1], &value); err != nil {
+		return fmt.Errorf("sample value: %w", err)
+	}
+	v, err := strconv.ParseFloat(value, 64) // also accepts NaN and ±Inf
+	if err != nil {
+		return fmt.Errorf("sample value: %w", err)
+	}
+	s.Timestamp = time.UnixMilli(int64(ts * 1000))
+	s.Value = v
+	return nil
+}
+
+func newAPIClient(rawURL string, timeout time.Duration) (*apiClient, error) {
+	u, err := url.Parse(strings.TrimSuffix(rawURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid prometheus url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("invalid prometheus url %q: scheme must be http or https", rawURL)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid prometheus url %q: missing host", rawURL)
+	}
+	return &apiClient{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// query evaluates an instant query at time at. Scalar results are returned
+// as a single sample without labels.
+func (c *apiClient) query(ctx context.Context, q string, at time.Time) ([]sample, error) {
+	params := url.Values{}
+	params.Set("query", q)
+	params.Set("time", formatTime(at))
+
+	data, err := c.doQuery(ctx, "/api/v1/query", params)
+	if err != nil {
+		return nil, err
+	}
+
+	switch data.ResultType {
+	case "vector":
+		var result []sample
+		if err := json.Unmarshal(data.Result, &result); err != nil {
+			return nil, fmt.Errorf("decode vector: %w", err)
+		}
+		return result, nil
+	case "scalar":
+		var value samplePair
+		if err := json.Unmarshal(data.Result, &value); err != nil {
+			return nil, fmt.Errorf("decode scalar: %w", err)
+		}
+		return []sample{{Value: value}}, nil
+	default:
+		return nil, fmt.Errorf("unexpected result type %q for instant query", data.ResultType)
+	}
+}
+
+// queryRange evaluates a range query from start to end at the given step
+func (c *apiClient) queryRange(ctx context.Context, q string, start, end time.Time, step time.Duration) ([]series, error) {
+	params := url.Values{}
+	params.Set("query", q)
+	params.Set("start", formatTime(start))
+	params.Set("end", formatTime(end))
+	params.Set("step", strconv.FormatFloat(step.Seconds(), 'f', -1, 64))
+
+	data, err := c.doQuery(ctx, "/api/v1/query_range", params)
+	if err != nil {
+		return nil, err
+	}
+	if data.ResultType != "matrix" {
+		return nil, fmt.Errorf("unexpected result type %q for range query", data.ResultType)
+	}
+	var result []series
+	if err := json.Unmarshal(data.Result, &result); err != nil {
+		return nil, fmt.Errorf("decode matrix: %w", err)
+	}
+	return result, nil
+}
+
+// labelValues lists the values of label name; __name__ lists the metric names
+func (c *apiClient) labelValues(ctx context.Context, name string) ([]string, error) {
+	return c.doStrings(ctx, "/api/v1/label/"+url.PathEscape(name)+"/values")
+}
+
+// labelNames lists the label names of all series
+func (c *apiClient) labelNames(ctx context.Context) ([]string, error) {
+	return c.doStrings(ctx, "/api/v1/labels")
+}
+
+func (c *apiClient) doStrings(ctx context.Context, path string) ([]string, error) {
+	raw, err := c.do(ctx, path, url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	var values []string
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, fmt.Errorf("decode prometheus response: %w", err)
+	}
+	return values, nil
+}
+
+func (c *apiClient) doQuery(ctx context.Context, path string, params url.Values) (*queryData, error) {
+	raw, err := c.do(ctx, path, params)
+	if err != nil {
+		return nil, err
+	}
+	var data queryData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("decode prometheus response: %w", err)
+	}
+	return &data, nil
+}
+
+// do issues a GET request and returns the data of a successful response
+func (c *apiClient) do(ctx context.Context, path string, params url.Values) (json.RawMessage, error) {
+	endpoint := c.baseURL.JoinPath(path)
+	endpoint.User = nil
+	endpoint.RawQuery = params.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if user := c.baseURL.User; user != nil {
+		password, _ := user.Password()
+		req.SetBasicAuth(user.Username(), password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("prometheus request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read prometheus response: %w", err)
+	}
+
+	// Query errors come back as 4xx/5xx with the usual envelope, so decode
+	// the body before looking at the status code
+	var envelope apiResponse
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("prometheus returned %s", resp.Status)
+		}
+		return nil, fmt.Errorf("decode prometheus response: %w", err)
+	}
+	if envelope.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s: %s", envelope.ErrorType, envelope.Error)
+	}
+	return envelope.Data, nil
+}
+
+func formatTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixMilli())/1000, 'f', -1, 64)
+}
@@ -0,0 +1,598 @@
+package promapi
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vladimirvivien/ktop/metrics"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// workloadContainers selects cAdvisor series for individual containers,
+// excluding the pod-level aggregate (container="") and the pause container.
+// Per-CPU breakdowns (cpu="0", cpu="1", ...) are excluded so CPU usage is
+// not double counted; series without a cpu label still match.
+const workloadContainers = `container!="",container!="POD",cpu!~"[0-9]+"`
+
+// defaultHistoryPoints is the resolution used when a history query sets no MaxPoints
+const defaultHistoryPoints = 120
+
+// PromAPIConfig holds configuration for the Prometheus HTTP API source
+type PromAPIConfig struct {
+	URL        string        // Base URL of the Prometheus or Thanos Query server; userinfo is sent as basic auth
+	Timeout    time.Duration // Per-request timeout
+	RateWindow time.Duration // Range used in rate(); must cover at least two of the server's scrapes
+	CacheTTL   time.Duration // How long cluster-wide node and pod results are reused
+}
+
+// DefaultPromAPIConfig returns a default Prometheus API configuration
+func DefaultPromAPIConfig() *PromAPIConfig {
+	return &PromAPIConfig{
+		Timeout:    10 * time.Second,
+		RateWindow: 2 * time.Minute,
+		CacheTTL:   4 * time.Second,
+	}
+}
+
+// PromAPISource implements metrics.MetricsSource by querying an existing
+// Prometheus (or Thanos) server with PromQL instead of scraping the cluster
+// itself. Metrics are expected to carry the labels used by the common
+// kubelet/cAdvisor scrape configurations (node, namespace, pod, container).
+//
+// Node and pod metrics are fetched cluster-wide, a handful of queries per
+// refresh, and cached for CacheTTL so that per-row lookups don't each hit the
+// server. History is read with range queries, so it is available immediately
+// and reaches as far back as the server retains data.
+type PromAPISource struct {
+	client *apiClient
+	config *PromAPIConfig
+
+	snapshotMu sync.Mutex // serializes snapshot refreshes
+	nodes      map[string]*metrics.NodeMetrics
+	nodesAt    time.Time
+	pods       map[string]*metrics.PodMetrics // keyed by namespace/name
+	podsAt     time.Time
+
+	// Health tracking
+	mu             sync.RWMutex
+	healthy        bool
+	lastError      error
+	errorCount     int
+	lastQuery      time.Time
+	healthCallback func(healthy bool, info metrics.SourceInfo)
+}
+
+// NewPromAPISource creates a source that queries the server at config.URL
+func NewPromAPISource(config *PromAPIConfig) (*PromAPISource, error) {
+	if config == nil || config.URL == "" {
+		return nil, fmt.Errorf("prometheus url is required")
+	}
+	defaults := DefaultPromAPIConfig()
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.RateWindow <= 0 {
+		config.RateWindow = defaults.RateWindow
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaults.CacheTTL
+	}
+
+	client, err := newAPIClient(config.URL, config.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &PromAPISource{
+		client: client,
+		config: config,
+	}, nil
+}
+
+// TestConnection evaluates a trivial query to verify the server is reachable
+// and speaks the Prometheus API.
+func (s *PromAPISource) TestConnection(ctx context.Context) error {
+	if _, err := s.client.query(ctx, "1", time.Now()); err != nil {
+		s.recordError(err)
+		return fmt.Errorf("prometheus api at %s: %w", s.config.URL, err)
+	}
+	s.recordSuccess()
+	return nil
+}
+
+// GetNodeMetrics retrieves metrics for a specific node
+func (s *PromAPISource) GetNodeMetrics(ctx context.Context, nodeName string) (*metrics.NodeMetrics, error) {
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+
+	if err := s.refreshNodesLocked(ctx); err != nil {
+		return nil, err
+	}
+	nm, ok := s.nodes[nodeName]
+	if !ok {
+		return nil, fmt.Errorf("no metrics for node %s", nodeName)
+	}
+	result := *nm
+	return &result, nil
+}
+
+// GetPodMetrics retrieves per-container metrics for a specific pod
+func (s *PromAPISource) GetPodMetrics(ctx context.Context, namespace, podName string) (*metrics.PodMetrics, error) {
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+
+	if err := s.refreshPodsLocked(ctx); err != nil {
+		return nil, err
+	}
+	pm, ok := s.pods[namespace+"/"+podName]
+	if !ok {
+		return nil, fmt.Errorf("no metrics for pod %s/%s", namespace, podName)
+	}
+	return copyPodMetrics(pm), nil
+}
+
+// GetMetricsForPod retrieves metrics for a specific pod object
+func (s *PromAPISource) GetMetricsForPod(ctx context.Context, pod metav1.Object) (*metrics.PodMetrics, error) {
+	return s.GetPodMetrics(ctx, pod.GetNamespace(), pod.GetName())
+}
+
+// GetAllPodMetrics retrieves metrics for every pod known to the server
+func (s *PromAPISource) GetAllPodMetrics(ctx context.Context) ([]*metrics.PodMetrics, error) {
+	s.snapshotMu.Lock()
+	defer s.snapshotMu.Unlock()
+
+	if err := s.refreshPodsLocked(ctx); err != nil {
+		return nil, err
+	}
+	result := make([]*metrics.PodMetrics, 0, len(s.pods))
+	for _, pm := range s.pods {
+		result = append(result, copyPodMetrics(pm))
+	}
+	return result, nil
+}
+
+// GetPodNetworkDiskMetrics retrieves network and disk I/O rates for a pod.
+// Network series are reported on the pod sandbox, so they are summed over
+// the whole pod; disk I/O is summed over the workload containers.
+func (s *PromAPISource) GetPodNetworkDiskMetrics(ctx context.Context, namespace, podName string) (netRx, netTx, diskRead, diskWrite float64, err error) {
+	pod := fmt.Sprintf(`namespace=%s,pod=%s`, strconv.Quote(namespace), strconv.Quote(podName))
+	w := formatDuration(s.config.RateWindow)
+
+	queries := []struct {
+		query string
+		value *float64
+	}{
+		{fmt.Sprintf(`sum(rate(container_network_receive_bytes_total{%s}[%s]))`, pod, w), &netRx},
+		{fmt.Sprintf(`sum(rate(container_network_transmit_bytes_total{%s}[%s]))`, pod, w), &netTx},
+		{fmt.Sprintf(`sum(rate(container_fs_reads_bytes_total{%s,%s}[%s]))`, pod, workloadContainers, w), &diskRead},
+		{fmt.Sprintf(`sum(rate(container_fs_writes_bytes_total{%s,%s}[%s]))`, pod, workloadContainers, w), &diskWrite},
+	}
+
+	now := time.Now()
+	for _, q := range queries {
+		samples, qerr := s.query(ctx, q.query, now)
+		if qerr != nil {
+			return 0, 0, 0, 0, qerr
+		}
+		// An empty result means the series doesn't exist; leave the rate at zero
+		if len(samples) > 0 && isFinite(samples[0].Value.Value) {
+			*q.value = samples[0].Value.Value
+		}
+	}
+	return netRx, netTx, diskRead, diskWrite, nil
+}
+
+// GetAvailableMetrics returns the list of metrics available from the server
+func (s *PromAPISource) GetAvailableMetrics() []string {
+	return []string{
+		"cpu",
+		"memory",
+		"network_rx",
+		"network_tx",
+		"disk_read",
+		"disk_write",
+		"pod_count",
+		"container_count",
+	}
+}
+
+// IsHealthy returns true if the last query against the server succeeded
+func (s *PromAPISource) IsHealthy() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.healthy
+}
+
+// GetSourceInfo returns metadata about the Prometheus API source
+func (s *PromAPISource) GetSourceInfo() metrics.SourceInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.buildSourceInfoLocked(s.healthy)
+}
+
+// SetHealthCallback registers a callback for health state changes.
+// The callback is NOT invoked immediately - callers should check IsHealthy()
+// for the initial state and use the callback only for subsequent changes.
+func (s *PromAPISource) SetHealthCallback(callback func(healthy bool, info metrics.SourceInfo)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.healthCallback = callback
+}
+
+// GetNodeHistory retrieves CPU (millicores) or memory (bytes) history for a node
+func (s *PromAPISource) GetNodeHistory(ctx context.Context, nodeName string, query metrics.HistoryQuery) (*metrics.ResourceHistory, error) {
+	selector := fmt.Sprintf(`id="/",node=%s`, strconv.Quote(nodeName))
+	return s.queryHistory(ctx, selector, query)
+}
+
+// GetPodHistory retrieves CPU (millicores) or memory (bytes) history for a pod,
+// summed over its workload containers, or for one of its containers
+func (s *PromAPISource) GetPodHistory(ctx context.Context, namespace, podName string, query metrics.HistoryQuery) (*metrics.ResourceHistory, error) {
+	selector := fmt.Sprintf(`namespace=%s,pod=%s,%s`, strconv.Quote(namespace), strconv.Quote(podName), workloadContainers)
+	if query.Container != "" {
+		selector += ",container=" + strconv.Quote(query.Container)
+	}
+	return s.queryHistory(ctx, selector, query)
+}
+
+// SupportsHistory returns true since history is served by the Prometheus server
+func (s *PromAPISource) SupportsHistory() bool {
+	return true
+}
+
+// QueryRange implements metrics.QuerySource by running expr as a range
+// query. Console queries are typed by hand, so their errors don't count
+// against the health of the source.
+func (s *PromAPISource) QueryRange(ctx context.Context, expr string, start, end time.Time, step time.Duration) ([]metrics.QuerySeries, error) {
+	matrix, err := s.client.queryRange(ctx, expr, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]metrics.QuerySeries, len(matrix))
+	for i, series := range matrix {
+		result[i].Labels = series.Metric
+		for _, v := range series.Values {
+			result[i].DataPoints = append(result[i].DataPoints, metrics.HistoryDataPoint{Timestamp: v.Timestamp, Value: v.Value})
+		}
+	}
+	return result, nil
+}
+
+// MetricNames implements metrics.QuerySource with the metric names known
+// to the server
+func (s *PromAPISource) MetricNames(ctx context.Context) ([]string, error) {
+	return s.client.labelValues(ctx, "__name__")
+}
+
+// LabelNames implements metrics.QuerySource with the label names known to
+// the server
+func (s *PromAPISource) LabelNames(ctx context.Context) ([]string, error) {
+	names, err := s.client.labelNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(names, func(name string) bool { return name == "__name__" }), nil
+}
+
+// refreshNodesLocked re-queries node metrics once the cached snapshot is older
+// than CacheTTL. Must be called with snapshotMu held.
+func (s *PromAPISource) refreshNodesLocked(ctx context.Context) error {
+	if s.nodes != nil && time.Since(s.nodesAt) < s.config.CacheTTL {
+		return nil
+	}
+
+	w := formatDuration(s.config.RateWindow)
+	queries := []struct {
+		query string
+		set   func(nm *metrics.NodeMetrics, v float64)
+	}{
+		{
+			fmt.Sprintf(`sum by (node) (rate(container_cpu_usage_seconds_total{id="/"}[%s]))`, w),
+			func(nm *metrics.NodeMetrics, v float64) { nm.CPUUsage = cpuQuantity(v) },
+		},
+		{
+			`sum by (node) (container_memory_working_set_bytes{id="/"})`,
+			func(nm *metrics.NodeMetrics, v float64) {
+				nm.MemoryUsage = resource.NewQuantity(int64(v), resource.BinarySI)
+			},
+		},
+		{
+			fmt.Sprintf(`sum by (node) (rate(container_network_receive_bytes_total{id="/"}[%s]))`, w),
+			func(nm *metrics.NodeMetrics, v float64) {
+				nm.NetworkRxRate = v
+				nm.NetworkRxBytes = resource.NewQuantity(int64(v), resource.BinarySI)
+			},
+		},
+		{
+			fmt.Sprintf(`sum by (node) (rate(container_network_transmit_bytes_total{id="/"}[%s]))`, w),
+			func(nm *metrics.NodeMetrics, v float64) {
+				nm.NetworkTxRate = v
+				nm.NetworkTxBytes = resource.NewQuantity(int64(v), resource.BinarySI)
+			},
+		},
+		{
+			fmt.Sprintf(`sum by (node) (rate(container_fs_reads_bytes_total{id="/"}[%s]))`, w),
+			func(nm *metrics.NodeMetrics, v float64) { nm.DiskReadRate = v },
+		},
+		{
+			fmt.Sprintf(`sum by (node) (rate(container_fs_writes_bytes_total{id="/"}[%s]))`, w),
+			func(nm *metrics.NodeMetrics, v float64) { nm.DiskWriteRate = v },
+		},
+		{
+			`sum by (node) (kubelet_running_pods)`,
+			func(nm *metrics.NodeMetrics, v float64) { nm.PodCount = int(v) },
+		},
+		{
+			`sum by (node) (kubelet_running_containers{container_state="running"})`,
+			func(nm *metrics.NodeMetrics, v float64) { nm.ContainerCount = int(v) },
+		},
+	}
+
+	now := time.Now()
+	nodes := make(map[string]*metrics.NodeMetrics)
+	for _, q := range queries {
+		samples, err := s.query(ctx, q.query, now)
+		if err != nil {
+			return err
+		}
+		for _, smp := range samples {
+			name := smp.Metric["node"]
+			if name == "" || !isFinite(smp.Value.Value) {
+				continue
+			}
+			nm, ok := nodes[name]
+			if !ok {
+				nm = &metrics.NodeMetrics{NodeName: name, Timestamp: now}
+				nodes[name] = nm
+			}
+			q.set(nm, smp.Value.Value)
+		}
+	}
+
+	s.nodes, s.nodesAt = nodes, now
+	return nil
+}
+
+// refreshPodsLocked re-queries per-container pod metrics once the cached
+// snapshot is older than CacheTTL. Must be called with snapshotMu held.
+func (s *PromAPISource) refreshPodsLocked(ctx context.Context) error {
+	if s.pods != nil && time.Since(s.podsAt) < s.config.CacheTTL {
+		return nil
+	}
+
+	now := time.Now()
+	cpu, err := s.query(ctx, fmt.Sprintf(`sum by (namespace, pod, container) (rate(container_cpu_usage_seconds_total{%s}[%s]))`,
+		workloadContainers, formatDuration(s.config.RateWindow)), now)
+	if err != nil {
+		return err
+	}
+	mem, err := s.query(ctx, fmt.Sprintf(`sum by (namespace, pod, container) (container_memory_working_set_bytes{%s})`,
+		workloadContainers), now)
+	if err != nil {
+		return err
+	}
+
+	containers := make(map[string]map[string]*metrics.ContainerMetrics)
+	container := func(smp sample) *metrics.ContainerMetrics {
+		key := smp.Metric["namespace"] + "/" + smp.Metric["pod"]
+		byName, ok := containers[key]
+		if !ok {
+			byName = make(map[string]*metrics.ContainerMetrics)
+			containers[key] = byName
+		}
+		name := smp.Metric["container"]
+		cm, ok := byName[name]
+		if !ok {
+			cm = &metrics.ContainerMetrics{Name: name}
+			byName[name] = cm
+		}
+		return cm
+	}
+	for _, smp := range cpu {
+		if smp.Metric["pod"] != "" && isFinite(smp.Value.Value) {
+			container(smp).CPUUsage = cpuQuantity(smp.Value.Value)
+		}
+	}
+	for _, smp := range mem {
+		if smp.Metric["pod"] != "" && isFinite(smp.Value.Value) {
+			container(smp).MemoryUsage = resource.NewQuantity(int64(smp.Value.Value), resource.BinarySI)
+		}
+	}
+
+	pods := make(map[string]*metrics.PodMetrics, len(containers))
+	for key, byName := range containers {
+		namespace, name, _ := strings.Cut(key, "/")
+		pm := &metrics.PodMetrics{PodName: name, Namespace: namespace, Timestamp: now}
+		for _, cm := range byName {
+			pm.Containers = append(pm.Containers, *cm)
+		}
+		sort.Slice(pm.Containers, func(i, j int) bool {
+			return pm.Containers[i].Name < pm.Containers[j].Name
+		})
+		pods[key] = pm
+	}
+
+	s.pods, s.podsAt = pods, now
+	return nil
+}
+
+// queryHistory runs a range query over the requested duration for the
+// series matched by selector
+func (s *PromAPISource) queryHistory(ctx context.Context, selector string, query metrics.HistoryQuery) (*metrics.ResourceHistory, error) {
+	points := query.MaxPoints
+	if points <= 0 {
+		points = defaultHistoryPoints
+	}
+	step := (query.Duration / time.Duration(points)).Truncate(time.Second)
+	if step < time.Second {
+		step = time.Second
+	}
+
+	var promql string
+	switch query.Resource {
+	case metrics.ResourceCPU:
+		// A window shorter than the step would skip samples between points
+		window := s.config.RateWindow
+		if window < step {
+			window = step
+		}
+		promql = fmt.Sprintf(`sum(rate(container_cpu_usage_seconds_total{%s}[%s])) * 1000`, selector, formatDuration(window))
+	case metrics.ResourceMemory:
+		promql = fmt.Sprintf(`sum(container_memory_working_set_bytes{%s})`, selector)
+	default:
+		return nil, fmt.Errorf("unsupported resource type: %s", query.Resource)
+	}
+
+	end := time.Now()
+	start := end.Add(-query.Duration)
+	result, err := s.rangeQuery(ctx, promql, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+
+	history := &metrics.ResourceHistory{
+		Resource:   query.Resource,
+		DataPoints: []metrics.HistoryDataPoint{},
+	}
+	if len(result) == 0 {
+		return history, nil
+	}
+
+	history.MinValue = math.MaxFloat64
+	history.MaxValue = -math.MaxFloat64
+	for _, v := range result[0].Values {
+		if !isFinite(v.Value) {
+			continue
+		}
+		history.DataPoints = append(history.DataPoints, metrics.HistoryDataPoint{
+			Timestamp: v.Timestamp,
+			Value:     v.Value,
+		})
+		history.MinValue = math.Min(history.MinValue, v.Value)
+		history.MaxValue = math.Max(history.MaxValue, v.Value)
+	}
+
+	// A range includes both endpoints, which can be one point over the limit
+	if query.MaxPoints > 0 && len(history.DataPoints) > query.MaxPoints {
+		history.DataPoints = history.DataPoints[len(history.DataPoints)-query.MaxPoints:]
+	}
+	if len(history.DataPoints) == 0 {
+		history.MinValue = 0
+		history.MaxValue = 0
+	}
+	return history, nil
+}
+
+// query runs an instant query and records the outcome for health tracking
+func (s *PromAPISource) query(ctx context.Context, q string, at time.Time) ([]sample, error) {
+	result, err := s.client.query(ctx, q, at)
+	if err != nil {
+		s.recordError(err)
+		slog.Debug("prometheus api query failed", "query", q, "error", err)
+		return nil, err
+	}
+	s.recordSuccess()
+	return result, nil
+}
+
+// rangeQuery runs a range query and records the outcome for health tracking
+func (s *PromAPISource) rangeQuery(ctx context.Context, q string, start, end time.Time, step time.Duration) ([]series, error) {
+	result, err := s.client.queryRange(ctx, q, start, end, step)
+	if err != nil {
+		s.recordError(err)
+		slog.Debug("prometheus api range query failed", "query", q, "error", err)
+		return nil, err
+	}
+	s.recordSuccess()
+	return result, nil
+}
+
+// buildSourceInfoLocked builds SourceInfo while holding the lock.
+// Must be called with s.mu held.
+func (s *PromAPISource) buildSourceInfoLocked(healthy bool) metrics.SourceInfo {
+	return metrics.SourceInfo{
+		Type:         metrics.SourceTypePrometheusAPI,
+		Version:      "v1",
+		LastScrape:   s.lastQuery,
+		MetricsCount: len(s.GetAvailableMetrics()),
+		ErrorCount:   s.errorCount,
+		Healthy:      healthy,
+	}
+}
+
+// recordError updates health status after a failed query
+func (s *PromAPISource) recordError(err error) {
+	var cb func(bool, metrics.SourceInfo)
+	var info metrics.SourceInfo
+
+	s.mu.Lock()
+	s.lastError = err
+	s.errorCount++
+	wasHealthy := s.healthy
+	s.healthy = false
+	if wasHealthy && s.healthCallback != nil {
+		cb = s.healthCallback
+		info = s.buildSourceInfoLocked(false)
+	}
+	s.mu.Unlock()
+
+	// Notify outside the lock to avoid deadlock
+	if cb != nil {
+		cb(false, info)
+	}
+}
+
+// recordSuccess updates health status after a successful query
+func (s *PromAPISource) recordSuccess() {
+	var cb func(bool, metrics.SourceInfo)
+	var info metrics.SourceInfo
+
+	s.mu.Lock()
+	s.lastError = nil
+	s.lastQuery = time.Now()
+	wasHealthy := s.healthy
+	s.healthy = true
+	if !wasHealthy && s.healthCallback != nil {
+		cb = s.healthCallback
+		info = s.buildSourceInfoLocked(true)
+	}
+	s.mu.Unlock()
+
+	// Notify outside the lock to avoid deadlock
+	if cb != nil {
+		cb(true, info)
+	}
+}
+
+// cpuQuantity converts CPU cores to a millicore quantity. Ceil keeps
+// sub-millicore usage from showing as 0.
+func cpuQuantity(cores float64) *resource.Quantity {
+	return resource.NewMilliQuantity(int64(math.Ceil(cores*1000)), resource.DecimalSI)
+}
+
+func copyPodMetrics(pm *metrics.PodMetrics) *metrics.PodMetrics {
+	result := *pm
+	result.Containers = append([]metrics.ContainerMetrics(nil), pm.Containers...)
+	return &result
+}
+
+// formatDuration renders d as a PromQL duration in whole seconds
+func formatDuration(d time.Duration) string {
+	secs := int64(d / time.Second)
+	if secs < 1 {
+		secs = 1
+	}
+	return strconv.FormatInt(secs, 10) + "s"
+}
+
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
@@ -0,0 +1,396 @@
+package promapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vladimirvivien/ktop/metrics"
+)
+
+// fakePrometheus is an httptest stand-in for the Prometheus HTTP API. It
+// answers instant queries by matching a substring of the PromQL expression
+// and range queries with a fixed series, and records every query it sees.
+type fakePrometheus struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	vectors map[string][]sample // query substring -> result
+	matrix  []series
+	queries []string
+	fail    bool
+}
+
+func newFakePrometheus(t *testing.T) *fakePrometheus {
+	t.Helper()
+	f := &fakePrometheus{vectors: make(map[string][]sample)}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *fakePrometheus) serve(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	q := r.URL.Query().Get("query")
+	f.queries = append(f.queries, q)
+	if f.fail {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"status":"error","errorType":"unavailable","error":"storage is down"}`)
+		return
+	}
+
+	var data any
+	switch r.URL.Path {
+	case "/api/v1/query":
+		if q == "1" {
+			data = map[string]any{"resultType": "scalar", "result": []any{float64(time.Now().Unix()), "1"}}
+			break
+		}
+		result := []map[string]any{}
+		for substr, samples := range f.vectors {
+			if !strings.Contains(q, substr) {
+				continue
+			}
+			for _, s := range samples {
+				result = append(result, map[string]any{
+					"metric": s.Metric,
+					"value":  []any{float64(s.Value.Timestamp.Unix()), strconv.FormatFloat(s.Value.Value, 'f', -1, 64)},
+				})
+			}
+		}
+		data = map[string]any{"resultType": "vector", "result": result}
+	case "/api/v1/query_range":
+		result := []map[string]any{}
+		for _, s := range f.matrix {
+			var values [][]any
+			for _, v := range s.Values {
+				values = append(values, []any{float64(v.Timestamp.Unix()), strconv.FormatFloat(v.Value, 'f', -1, 64)})
+			}
+			result = append(result, map[string]any{"metric": s.Metric, "values": values})
+		}
+		data = map[string]any{"resultType": "matrix", "result": result}
+	case "/api/v1/labels":
+		data = []string{"__name__", "namespace", "pod"}
+	case "/api/v1/label/__name__/values":
+		data = []string{"container_cpu_usage_seconds_total", "up"}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]any{"status": "success", "data": data})
+}
+
+func (f *fakePrometheus) setVector(substr string, samples ...sample) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.vectors[substr] = samples
+}
+
+func (f *fakePrometheus) setFail(fail bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fail = fail
+}
+
+func (f *fakePrometheus) queryLog() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.queries...)
+}
+
+func newTestSource(t *testing.T, url string) *PromAPISource {
+	t.Helper()
+	source, err := NewPromAPISource(&PromAPIConfig{URL: url, CacheTTL: time.Minute})
+	if err != nil {
+		t.Fatalf("NewPromAPISource failed: %v", err)
+	}
+	return source
+}
+
+func vec(value float64, labels ...string) sample {
+	metric := make(map[string]string)
+	for i := 0; i+1 < len(labels); i += 2 {
+		metric[labels[i]] = labels[i+1]
+	}
+	return sample{Metric: metric, Value: samplePair{Timestamp: time.Now(), Value: value}}
+}
+
+func TestNewPromAPISource_InvalidURL(t *testing.T) {
+	for _, url := range []string{"", "localhost:9090", "ftp://prometheus", "http://"} {
+		if _, err := NewPromAPISource(&PromAPIConfig{URL: url}); err == nil {
+			t.Errorf("Expected error for url %q", url)
+		}
+	}
+}
+
+func TestPromAPISource_TestConnection(t *testing.T) {
+	fake := newFakePrometheus(t)
+	source := newTestSource(t, fake.URL)
+
+	if err := source.TestConnection(context.Background()); err != nil {
+		t.Fatalf("TestConnection failed: %v", err)
+	}
+	if !source.IsHealthy() {
+		t.Error("Expected source to be healthy after a successful query")
+	}
+
+	fake.setFail(true)
+	err := source.TestConnection(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "storage is down") {
+		t.Errorf("Expected the server's error message, got %v", err)
+	}
+	if source.IsHealthy() {
+		t.Error("Expected source to be unhealthy after a failed query")
+	}
+}
+
+func TestPromAPISource_GetNodeMetrics(t *testing.T) {
+	fake := newFakePrometheus(t)
+	fake.setVector("container_cpu_usage_seconds_total", vec(0.25, "node", "node-1"), vec(1.5, "node", "node-2"))
+	fake.setVector("container_memory_working_set_bytes", vec(2<<30, "node", "node-1"))
+	fake.setVector("container_network_receive_bytes_total", vec(1024, "node", "node-1"))
+	fake.setVector("kubelet_running_pods", vec(12, "node", "node-1"))
+	source := newTestSource(t, fake.URL)
+
+	nm, err := source.GetNodeMetrics(context.Background(), "node-1")
+	if err != nil {
+		t.Fatalf("GetNodeMetrics failed: %v", err)
+	}
+	if got := nm.CPUUsage.MilliValue(); got != 250 {
+		t.Errorf("Expected 250m CPU, got %dm", got)
+	}
+	if got := nm.MemoryUsage.Value(); got != 2<<30 {
+		t.Errorf("Expected 2Gi memory, got %d", got)
+	}
+	if nm.NetworkRxRate != 1024 {
+		t.Errorf("Expected 1024 B/s network rx, got %v", nm.NetworkRxRate)
+	}
+	if nm.PodCount != 12 {
+		t.Errorf("Expected 12 pods, got %d", nm.PodCount)
+	}
+
+	// The second node comes from the cached snapshot without new queries
+	before := len(fake.queryLog())
+	if nm, err := source.GetNodeMetrics(context.Background(), "node-2"); err != nil || nm.CPUUsage.MilliValue() != 1500 {
+		t.Errorf("Expected 1500m CPU for node-2, got %v (err %v)", nm, err)
+	}
+	if after := len(fake.queryLog()); after != before {
+		t.Errorf("Expected cached node metrics, saw %d new queries", after-before)
+	}
+
+	if _, err := source.GetNodeMetrics(context.Background(), "node-3"); err == nil {
+		t.Error("Expected error for a node without metrics")
+	}
+}
+
+func TestPromAPISource_GetPodMetrics(t *testing.T) {
+	fake := newFakePrometheus(t)
+	fake.setVector("container_cpu_usage_seconds_total",
+		vec(0.1, "namespace", "default", "pod", "web-0", "container", "nginx"),
+		vec(0.0002, "namespace", "default", "pod", "web-0", "container", "sidecar"),
+		vec(0.5, "namespace", "kube-system", "pod", "coredns", "container", "coredns"),
+	)
+	fake.setVector("container_memory_working_set_bytes",
+		vec(64<<20, "namespace", "default", "pod", "web-0", "container", "nginx"),
+	)
+	source := newTestSource(t, fake.URL)
+
+	pm, err := source.GetPodMetrics(context.Background(), "default", "web-0")
+	if err != nil {
+		t.Fatalf("GetPodMetrics failed: %v", err)
+	}
+	if len(pm.Containers) != 2 {
+		t.Fatalf("Expected 2 containers, got %d", len(pm.Containers))
+	}
+	nginx, sidecar := pm.Containers[0], pm.Containers[1]
+	if nginx.Name != "nginx" || sidecar.Name != "sidecar" {
+		t.Errorf("Expected containers sorted by name, got %s, %s", nginx.Name, sidecar.Name)
+	}
+	if nginx.CPUUsage.MilliValue() != 100 || nginx.MemoryUsage.Value() != 64<<20 {
+		t.Errorf("Unexpected nginx usage: cpu %v, memory %v", nginx.CPUUsage, nginx.MemoryUsage)
+	}
+	if sidecar.CPUUsage.MilliValue() != 1 {
+		t.Errorf("Expected sub-millicore usage to round up to 1m, got %dm", sidecar.CPUUsage.MilliValue())
+	}
+
+	all, err := source.GetAllPodMetrics(context.Background())
+	if err != nil {
+		t.Fatalf("GetAllPodMetrics failed: %v", err)
+	}
+	if len(all) != 2 {
+		t.Errorf("Expected 2 pods, got %d", len(all))
+	}
+
+	for _, q := range fake.queryLog() {
+		if strings.Contains(q, "container_cpu_usage_seconds_total") && !strings.Contains(q, `container!="POD"`) {
+			t.Errorf("Expected pod CPU query to exclude pause containers: %s", q)
+		}
+	}
+}
+
+func TestPromAPISource_GetPodHistory(t *testing.T) {
+	fake := newFakePrometheus(t)
+	now := time.Now().Truncate(time.Second)
+	fake.matrix = []series{{
+		Metric: map[string]string{},
+		Values: []samplePair{
+			{Timestamp: now.Add(-2 * time.Minute), Value: 100},
+			{Timestamp: now.Add(-time.Minute), Value: 300},
+			{Timestamp: now, Value: 200},
+		},
+	}}
+	source := newTestSource(t, fake.URL)
+
+	history, err := source.GetPodHistory(context.Background(), "default", "web-0", metrics.HistoryQuery{
+		Resource:  metrics.ResourceCPU,
+		Duration:  5 * time.Minute,
+		MaxPoints: 2,
+	})
+	if err != nil {
+		t.Fatalf("GetPodHistory failed: %v", err)
+	}
+	if len(history.DataPoints) != 2 {
+		t.Fatalf("Expected MaxPoints to limit history to 2 points, got %d", len(history.DataPoints))
+	}
+	if history.DataPoints[1].Value != 200 || !history.DataPoints[1].Timestamp.Equal(now) {
+		t.Errorf("Expected newest point last, got %+v", history.DataPoints[1])
+	}
+	if history.MinValue != 100 || history.MaxValue != 300 {
+		t.Errorf("Expected min 100 and max 300, got %v and %v", history.MinValue, history.MaxValue)
+	}
+
+	log := fake.queryLog()
+	if q := log[len(log)-1]; !strings.Contains(q, `pod="web-0"`) || !strings.HasSuffix(q, "* 1000") {
+		t.Errorf("Expected a millicore CPU query for the pod, got %s", q)
+	}
+
+	if _, err := source.GetPodHistory(context.Background(), "default", "web-0", metrics.HistoryQuery{
+		Resource:  metrics.ResourceMemory,
+		Duration:  5 * time.Minute,
+		Container: "app",
+	}); err != nil {
+		t.Fatalf("GetPodHistory failed: %v", err)
+	}
+	log = fake.queryLog()
+	if q := log[len(log)-1]; !strings.Contains(q, `pod="web-0"`) || !strings.Contains(q, `container="app"`) {
+		t.Errorf("Expected a memory query for the app container, got %s", q)
+	}
+}
+
+func TestPromAPISource_CustomColumn(t *testing.T) {
+	fake := newFakePrometheus(t)
+	now := time.Now().Truncate(time.Second)
+	fake.matrix = []series{
+		{Metric: map[string]string{"namespace": "ml", "pod": "trainer-0"}, Values: []samplePair{{Timestamp: now, Value: 2048}}},
+		{Metric: map[string]string{"namespace": "ml", "pod": "notebook"}, Values: []samplePair{{Timestamp: now, Value: 512}}},
+	}
+	source := newTestSource(t, fake.URL)
+
+	col, err := metrics.ParseCustomColumn(`GPU_MEM = sum(DCGM_FI_DEV_FB_USED{gpu="0"}) by (namespace, pod)`)
+	if err != nil {
+		t.Fatalf("ParseCustomColumn failed: %v", err)
+	}
+	values, err := metrics.QueryCustomColumn(context.Background(), source, col)
+	if err != nil {
+		t.Fatalf("QueryCustomColumn failed: %v", err)
+	}
+	if values[col.RowKey("ml", "trainer-0")] != 2048 || values[col.RowKey("ml", "notebook")] != 512 {
+		t.Errorf("Unexpected values %v", values)
+	}
+
+	// The expression is sent to the server as written
+	queries := fake.queryLog()
+	want := `sum(DCGM_FI_DEV_FB_USED{gpu="0"}) by (namespace, pod)`
+	if len(queries) != 1 || queries[0] != want {
+		t.Errorf("Expected query %q, got %v", want, queries)
+	}
+}
+
+func TestPromAPISource_Query(t *testing.T) {
+	fake := newFakePrometheus(t)
+	now := time.Now().Truncate(time.Second)
+	fake.matrix = []series{{
+		Metric: map[string]string{"namespace": "web"},
+		Values: []samplePair{{Timestamp: now.Add(-time.Minute), Value: 1.5}, {Timestamp: now, Value: 2.5}},
+	}}
+	source := newTestSource(t, fake.URL)
+	ctx := context.Background()
+
+	result, err := source.QueryRange(ctx, `sum by (namespace) (rate(container_cpu_usage_seconds_total[5m]))`, now.Add(-time.Minute), now, time.Minute)
+	if err != nil {
+		t.Fatalf("QueryRange failed: %v", err)
+	}
+	if len(result) != 1 || result[0].Labels["namespace"] != "web" || len(result[0].DataPoints) != 2 || result[0].DataPoints[1].Value != 2.5 {
+		t.Errorf("Unexpected result %+v", result)
+	}
+
+	metricNames, err := source.MetricNames(ctx)
+	if err != nil || len(metricNames) != 2 || metricNames[1] != "up" {
+		t.Errorf("Unexpected metric names %v (%v)", metricNames, err)
+	}
+	labelNames, err := source.LabelNames(ctx)
+	if err != nil || len(labelNames) != 2 || labelNames[0] != "namespace" {
+		t.Errorf("Expected label names without __name__, got %v (%v)", labelNames, err)
+	}
+
+	// A bad console query is the user's mistake, not an unhealthy server
+	source.recordSuccess()
+	fake.setFail(true)
+	if _, err := source.QueryRange(ctx, "up", now, now, time.Minute); err == nil {
+		t.Error("Expected error from failing server")
+	}
+	if !source.IsHealthy() {
+		t.Error("Expected console query errors to leave the source healthy")
+	}
+}
+
+func TestPromAPISource_HealthCallback(t *testing.T) {
+	fake := newFakePrometheus(t)
+	source := newTestSource(t, fake.URL)
+
+	var transitions []bool
+	source.SetHealthCallback(func(healthy bool, info metrics.SourceInfo) {
+		if info.Type != metrics.SourceTypePrometheusAPI {
+			t.Errorf("Expected source type %s, got %s", metrics.SourceTypePrometheusAPI, info.Type)
+		}
+		transitions = append(transitions, healthy)
+	})
+
+	source.TestConnection(context.Background())
+	source.TestConnection(context.Background())
+	fake.setFail(true)
+	source.TestConnection(context.Background())
+
+	if len(transitions) != 2 || !transitions[0] || transitions[1] {
+		t.Errorf("Expected transitions [true false], got %v", transitions)
+	}
+	if info := source.GetSourceInfo(); info.ErrorCount != 1 || info.Healthy {
+		t.Errorf("Unexpected source info: %+v", info)
+	}
+}
+
+func TestPromAPISource_BasicAuthFromURL(t *testing.T) {
+	var user, password string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, _ = r.BasicAuth()
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"scalar","result":[0,"1"]}}`)
+	}))
+	defer server.Close()
+
+	source := newTestSource(t, strings.Replace(server.URL, "http://", "http://ktop:s3cret@", 1))
+	if err := source.TestConnection(context.Background()); err != nil {
+		t.Fatalf("TestConnection failed: %v", err)
+	}
+	if user != "ktop" || password != "s3cret" {
+		t.Errorf("Expected basic auth ktop/s3cret, got %q/%q", user, password)
+	}
+}
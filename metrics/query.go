@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"context"
+	"time"
+)
+
+// QuerySource is implemented by metrics sources that can evaluate PromQL
+// for the query console. Callers check for it with a type assertion, like
+// ControlPlaneSource.
+type QuerySource interface {
+	// QueryRange evaluates expr at each step from start to end
+	QueryRange(ctx context.Context, expr string, start, end time.Time, step time.Duration) ([]QuerySeries, error)
+
+	// MetricNames returns the names of the metrics that can be queried
+	MetricNames(ctx context.Context) ([]string, error)
+
+	// LabelNames returns the label names used by those metrics
+	LabelNames(ctx context.Context) ([]string, error)
+}
+
+// QuerySeries is one series of a query result
+type QuerySeries struct {
+	// Labels identify the series; __name__ holds the metric name when the
+	// query kept it
+	Labels map[string]string
+	// DataPoints are the values of the series, oldest first. Steps where the
+	// series had no value are left out.
+	DataPoints []HistoryDataPoint
+}
@@ -193,9 +193,17 @@ type SourceInfo struct {
 // SourceType constants for common metrics sources
 const (
 	SourceTypePrometheus    = "prometheus"
+	SourceTypePrometheusAPI = "prometheus-api"
 	SourceTypeMetricsServer = "metrics-server"
 )
 
+// IsPrometheusSource reports whether sourceType is backed by Prometheus
+// metrics, either scraped by ktop or queried from a Prometheus server.
+// These sources provide the enhanced metrics (network, disk, history).
+func IsPrometheusSource(sourceType string) bool {
+	return sourceType == SourceTypePrometheus || sourceType == SourceTypePrometheusAPI
+}
+
 // ResourceType identifies the type of resource for history queries
 type ResourceType string
 
@@ -232,4 +240,15 @@ type HistoryQuery struct {
 	Duration time.Duration
 	// MaxPoints limits the number of data points returned (0 = no limit)
 	MaxPoints int
+	// Container limits pod history to one container; empty sums the pod's
+	// containers. Ignored for node history.
+	Container string
+}
+
+// RetentionSource is implemented by metrics sources that keep history for a
+// known period, such as ktop's own scraper. Prometheus servers and the
+// metrics-server buffer don't report theirs.
+type RetentionSource interface {
+	// Retention returns how far back history reaches at most
+	Retention() time.Duration
 }
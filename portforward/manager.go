@@ -0,0 +1,288 @@
+// Package portforward keeps the port forwards started from the UI running
+// in the background, whatever page is shown.
+package portforward
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/vladimirvivien/ktop/k8s"
+	v1 "k8s.io/api/core/v1"
+)
+
+// ForwardFunc forwards a local port to a pod port until ctx is done, like
+// k8s.ClusterSource.PortForward
+type ForwardFunc func(ctx context.Context, namespace, podName string, opts k8s.PortForwardOptions) error
+
+// State is the state of a port forward
+type State int
+
+const (
+	StateStarting State = iota
+	StateActive
+	StateStopped
+	StateFailed
+)
+
+func (s State) String() string {
+	switch s {
+	case StateStarting:
+		return "Starting"
+	case StateActive:
+		return "Active"
+	case StateStopped:
+		return "Stopped"
+	default:
+		return "Failed"
+	}
+}
+
+// Spec is a pod port to forward
+type Spec struct {
+	Namespace  string
+	Pod        string
+	Container  string
+	PortName   string // name of the container port, may be empty
+	RemotePort uint16
+	LocalPort  uint16 // 0 picks a free port
+}
+
+// Target describes the pod port, e.g. "default/web-0:8080 (http)"
+func (s Spec) Target() string {
+	target := fmt.Sprintf("%s/%s:%d", s.Namespace, s.Pod, s.RemotePort)
+	if s.PortName != "" {
+		target += " (" + s.PortName + ")"
+	}
+	return target
+}
+
+// PodSpecs returns a spec for each TCP port declared by the containers of
+// pod, forwarded from the same local port when it is not privileged and
+// from a free port otherwise
+func PodSpecs(pod *v1.Pod) []Spec {
+	var specs []Spec
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.Protocol != "" && port.Protocol != v1.ProtocolTCP {
+				continue
+			}
+			spec := Spec{
+				Namespace:  pod.Namespace,
+				Pod:        pod.Name,
+				Container:  container.Name,
+				PortName:   port.Name,
+				RemotePort: uint16(port.ContainerPort),
+			}
+			if port.ContainerPort >= 1024 {
+				spec.LocalPort = spec.RemotePort
+			}
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// Forward is a snapshot of a port forward
+type Forward struct {
+	ID int
+	Spec
+	State     State
+	Err       error     // why the forward failed
+	StartedAt time.Time // when it was last started
+	BytesIn   int64     // received from the pod, over all runs
+	BytesOut  int64     // sent to the pod, over all runs
+}
+
+// forward is a port forward and the run that serves it
+type forward struct {
+	Forward
+	fn      ForwardFunc
+	traffic *k8s.PortForwardTraffic
+	run     int // incremented on each start so a stale run can't update state
+	cancel  context.CancelFunc
+}
+
+// Manager runs port forwards in the background until they are stopped. It
+// is safe for concurrent use.
+type Manager struct {
+	mu       sync.Mutex
+	forwards []*forward
+	nextID   int
+	notify   func(Forward)
+	now      func() time.Time
+}
+
+// NewManager returns a manager without forwards
+func NewManager() *Manager {
+	return &Manager{nextID: 1, now: time.Now}
+}
+
+// SetNotifyFunc registers fn to receive a forward when it becomes active or
+// fails. It is called off the caller's goroutine, outside the manager lock.
+func (m *Manager) SetNotifyFunc(fn func(Forward)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.notify = fn
+}
+
+// Start forwards spec with fn and returns the ID of the forward
+func (m *Manager) Start(spec Spec, fn ForwardFunc) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f := &forward{
+		Forward: Forward{ID: m.nextID, Spec: spec},
+		fn:      fn,
+		traffic: &k8s.PortForwardTraffic{},
+	}
+	m.nextID++
+	m.forwards = append(m.forwards, f)
+	m.startLocked(f)
+	return f.ID
+}
+
+// Resume starts a stopped or failed forward again, on the local port it
+// had. It reports whether the forward was restarted.
+func (m *Manager) Resume(id int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f := m.findLocked(id)
+	if f == nil || f.cancel != nil {
+		return false
+	}
+	m.startLocked(f)
+	return true
+}
+
+// Stop stops a forward, keeping it listed so it can be resumed
+func (m *Manager) Stop(id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if f := m.findLocked(id); f != nil {
+		m.stopLocked(f)
+	}
+}
+
+// Remove stops a forward and drops it from the list
+func (m *Manager) Remove(id int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, f := range m.forwards {
+		if f.ID == id {
+			m.stopLocked(f)
+			m.forwards = append(m.forwards[:i], m.forwards[i+1:]...)
+			return
+		}
+	}
+}
+
+// Clear stops and drops all forwards, e.g. before switching to another
+// cluster or exiting
+func (m *Manager) Clear() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, f := range m.forwards {
+		m.stopLocked(f)
+	}
+	m.forwards = nil
+}
+
+// List returns the forwards in the order they were started
+func (m *Manager) List() []Forward {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	list := make([]Forward, len(m.forwards))
+	for i, f := range m.forwards {
+		list[i] = f.Forward
+		list[i].BytesIn = f.traffic.BytesIn.Load()
+		list[i].BytesOut = f.traffic.BytesOut.Load()
+	}
+	return list
+}
+
+// Active returns the number of forwards starting or active
+func (m *Manager) Active() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	active := 0
+	for _, f := range m.forwards {
+		if f.cancel != nil {
+			active++
+		}
+	}
+	return active
+}
+
+func (m *Manager) findLocked(id int) *forward {
+	for _, f := range m.forwards {
+		if f.ID == id {
+			return f
+		}
+	}
+	return nil
+}
+
+func (m *Manager) startLocked(f *forward) {
+	ctx, cancel := context.WithCancel(context.Background())
+	f.run++
+	f.cancel = cancel
+	f.State = StateStarting
+	f.Err = nil
+	f.StartedAt = m.now()
+
+	run, spec := f.run, f.Spec
+	go func() {
+		err := f.fn(ctx, spec.Namespace, spec.Pod, k8s.PortForwardOptions{
+			LocalPort:  spec.LocalPort,
+			RemotePort: spec.RemotePort,
+			Traffic:    f.traffic,
+			Ready: func(localPort uint16) {
+				m.update(f, run, func() {
+					f.LocalPort = localPort // kept when resumed
+					f.State = StateActive
+				})
+			},
+		})
+		cancel()
+		// A forward stopped through the manager has moved on to another
+		// run; one still on this run ended on its own
+		m.update(f, run, func() {
+			if err == nil {
+				err = errors.New("forward ended")
+			}
+			f.cancel = nil
+			f.State = StateFailed
+			f.Err = err
+		})
+	}()
+}
+
+func (m *Manager) stopLocked(f *forward) {
+	if f.cancel == nil {
+		return
+	}
+	f.cancel()
+	f.cancel = nil
+	f.run++ // the run ending now no longer updates the forward
+	f.State = StateStopped
+}
+
+// update applies change to f if run is still the forward's current run,
+// and notifies when that made it active or failed
+func (m *Manager) update(f *forward, run int, change func()) {
+	m.mu.Lock()
+	if f.run != run {
+		m.mu.Unlock()
+		return
+	}
+	change()
+	snapshot := f.Forward
+	notify := m.notify
+	m.mu.Unlock()
+
+	if notify != nil && (snapshot.State == StateActive || snapshot.State == StateFailed) {
+		notify(snapshot)
+	}
+}
@@ -0,0 +1,173 @@
+package portforward
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vladimirvivien/ktop/k8s"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// fakeForward is a ForwardFunc that becomes ready on localPort, or 40000
+// when a free port is asked for, moves bytes and then waits to be stopped
+// or fail
+type fakeForward struct {
+	fail  chan error
+	calls chan k8s.PortForwardOptions
+}
+
+func newFakeForward() *fakeForward {
+	return &fakeForward{fail: make(chan error, 1), calls: make(chan k8s.PortForwardOptions, 4)}
+}
+
+func (f *fakeForward) forward(ctx context.Context, _, _ string, opts k8s.PortForwardOptions) error {
+	f.calls <- opts
+	local := opts.LocalPort
+	if local == 0 {
+		local = 40000
+	}
+	opts.Traffic.BytesOut.Add(10)
+	opts.Traffic.BytesIn.Add(100)
+	opts.Ready(local)
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-f.fail:
+		return err
+	}
+}
+
+// waitFor polls m until the forward id is in state
+func waitFor(t *testing.T, m *Manager, id int, state State) Forward {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		for _, f := range m.List() {
+			if f.ID == id && f.State == state {
+				return f
+			}
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Forward %d did not become %s: %+v", id, state, m.List())
+	return Forward{}
+}
+
+func TestManager_StartStopResume(t *testing.T) {
+	m := NewManager()
+	notified := make(chan Forward, 4)
+	m.SetNotifyFunc(func(f Forward) { notified <- f })
+	fake := newFakeForward()
+
+	id := m.Start(Spec{Namespace: "default", Pod: "web-0", RemotePort: 80}, fake.forward)
+	f := waitFor(t, m, id, StateActive)
+	if f.LocalPort != 40000 {
+		t.Errorf("Expected the free port picked to be kept, got %d", f.LocalPort)
+	}
+	if f.BytesIn != 100 || f.BytesOut != 10 {
+		t.Errorf("Expected 100 bytes in and 10 out, got %d and %d", f.BytesIn, f.BytesOut)
+	}
+	if got := <-notified; got.ID != id || got.State != StateActive {
+		t.Errorf("Expected an active notification, got %+v", got)
+	}
+	if m.Active() != 1 {
+		t.Errorf("Expected 1 active forward, got %d", m.Active())
+	}
+
+	m.Stop(id)
+	waitFor(t, m, id, StateStopped)
+	if m.Active() != 0 {
+		t.Errorf("Expected no active forward, got %d", m.Active())
+	}
+	<-fake.calls
+
+	if !m.Resume(id) {
+		t.Fatal("Expected a stopped forward to resume")
+	}
+	if opts := <-fake.calls; opts.LocalPort != 40000 {
+		t.Errorf("Expected the resumed forward on its local port, got %d", opts.LocalPort)
+	}
+	f = waitFor(t, m, id, StateActive)
+	if f.BytesIn != 200 {
+		t.Errorf("Expected bytes counted over both runs, got %d", f.BytesIn)
+	}
+	if m.Resume(id) {
+		t.Error("Expected an active forward not to resume")
+	}
+}
+
+func TestManager_Failure(t *testing.T) {
+	m := NewManager()
+	notified := make(chan Forward, 4)
+	m.SetNotifyFunc(func(f Forward) { notified <- f })
+	fake := newFakeForward()
+
+	id := m.Start(Spec{Namespace: "default", Pod: "web-0", RemotePort: 8080, LocalPort: 8080}, fake.forward)
+	waitFor(t, m, id, StateActive)
+	<-notified
+
+	lost := errors.New("lost connection to pod")
+	fake.fail <- lost
+	f := waitFor(t, m, id, StateFailed)
+	if !errors.Is(f.Err, lost) {
+		t.Errorf("Expected the forward error, got %v", f.Err)
+	}
+	if got := <-notified; got.State != StateFailed {
+		t.Errorf("Expected a failed notification, got %+v", got)
+	}
+	if !m.Resume(id) {
+		t.Error("Expected a failed forward to resume")
+	}
+	waitFor(t, m, id, StateActive)
+}
+
+func TestManager_RemoveAndClear(t *testing.T) {
+	m := NewManager()
+	fake := newFakeForward()
+
+	first := m.Start(Spec{Pod: "a", RemotePort: 80}, fake.forward)
+	second := m.Start(Spec{Pod: "b", RemotePort: 80}, fake.forward)
+	waitFor(t, m, first, StateActive)
+	waitFor(t, m, second, StateActive)
+
+	m.Remove(first)
+	if list := m.List(); len(list) != 1 || list[0].ID != second {
+		t.Fatalf("Expected only the second forward left, got %+v", list)
+	}
+	m.Clear()
+	if len(m.List()) != 0 || m.Active() != 0 {
+		t.Fatalf("Expected no forwards after Clear, got %+v", m.List())
+	}
+}
+
+func TestPodSpecs(t *testing.T) {
+	pod := &v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web-0"},
+		Spec: v1.PodSpec{Containers: []v1.Container{
+			{Name: "nginx", Ports: []v1.ContainerPort{
+				{Name: "http", ContainerPort: 80},
+				{Name: "dns", ContainerPort: 5353, Protocol: v1.ProtocolUDP},
+			}},
+			{Name: "app", Ports: []v1.ContainerPort{
+				{ContainerPort: 8080, Protocol: v1.ProtocolTCP},
+			}},
+		}},
+	}
+
+	specs := PodSpecs(pod)
+	if len(specs) != 2 {
+		t.Fatalf("Expected the 2 TCP ports, got %+v", specs)
+	}
+	if specs[0].Container != "nginx" || specs[0].RemotePort != 80 || specs[0].LocalPort != 0 {
+		t.Errorf("Expected privileged port 80 forwarded from a free port, got %+v", specs[0])
+	}
+	if specs[1].Container != "app" || specs[1].LocalPort != 8080 {
+		t.Errorf("Expected port 8080 forwarded from 8080, got %+v", specs[1])
+	}
+	if got := specs[0].Target(); got != "default/web-0:80 (http)" {
+		t.Errorf("Unexpected target %q", got)
+	}
+}
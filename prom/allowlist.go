@@ -0,0 +1,62 @@
+package prom
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// CompileMetricPatterns compiles metric names or regular expressions into a
+// single expression that matches a whole metric name against any of them.
+// Plain names are valid expressions that match only themselves.
+func CompileMetricPatterns(patterns []string) (*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	alternatives := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return nil, fmt.Errorf("metric pattern %q: %w", pattern, err)
+		}
+		alternatives = append(alternatives, "(?:"+pattern+")")
+	}
+	return regexp.Compile("^(?:" + strings.Join(alternatives, "|") + ")$")
+}
+
+// compileExtraMetrics compiles ScrapeConfig.ExtraMetrics per component
+func compileExtraMetrics(extra map[ComponentType][]string) (map[ComponentType]*regexp.Regexp, error) {
+	compiled := make(map[ComponentType]*regexp.Regexp, len(extra))
+	for component, patterns := range extra {
+		re, err := CompileMetricPatterns(patterns)
+		if err != nil {
+			return nil, fmt.Errorf("extra metrics for %s: %w", component, err)
+		}
+		if re != nil {
+			compiled[component] = re
+		}
+	}
+	return compiled, nil
+}
+
+// allowedFamily returns family as it should be stored for component, or
+// false when it is dropped. Control-plane components use
+// ControlPlaneMetricAllowlist, node components DefaultMetricAllowlist.
+// Metrics matched only by the configured extra metrics are stored with all
+// of their labels, so patterns that match high-cardinality metrics cost
+// memory accordingly.
+func (ks *KubernetesScraper) allowedFamily(component ComponentType, name string, family *dto.MetricFamily) (*dto.MetricFamily, bool) {
+	if rules, ok := ControlPlaneMetricAllowlist[component]; ok {
+		if rule, ok := rules[name]; ok {
+			return rule.reduce(family), true
+		}
+	} else if DefaultMetricAllowlist[name] {
+		return family, true
+	}
+
+	if re := ks.extraMetrics[component]; re != nil && re.MatchString(name) {
+		return family, true
+	}
+	return nil, false
+}
@@ -0,0 +1,72 @@
+package prom
+
+import (
+	"testing"
+)
+
+func TestCompileMetricPatterns(t *testing.T) {
+	re, err := CompileMetricPatterns([]string{"DCGM_FI_DEV_FB_USED", "kubelet_volume_stats_.*"})
+	if err != nil {
+		t.Fatalf("CompileMetricPatterns failed: %v", err)
+	}
+
+	tests := map[string]bool{
+		"DCGM_FI_DEV_FB_USED":                 true,
+		"DCGM_FI_DEV_FB_USED_total":           false, // names match whole
+		"kubelet_volume_stats_used_bytes":     true,
+		"kubelet_volume_stats_capacity_bytes": true,
+		"x_kubelet_volume_stats_used_bytes":   false,
+	}
+	for name, want := range tests {
+		if got := re.MatchString(name); got != want {
+			t.Errorf("%s: expected match %v, got %v", name, want, got)
+		}
+	}
+
+	if re, err := CompileMetricPatterns(nil); re != nil || err != nil {
+		t.Errorf("Expected nil for no patterns, got %v, %v", re, err)
+	}
+	if _, err := CompileMetricPatterns([]string{"container_("}); err == nil {
+		t.Error("Expected error for invalid pattern")
+	}
+}
+
+func TestAllowedFamily_ExtraMetrics(t *testing.T) {
+	extra, err := compileExtraMetrics(map[ComponentType][]string{
+		ComponentKubelet:   {"kubelet_volume_stats_.*"},
+		ComponentAPIServer: {"apiserver_request_total", "apiserver_storage_objects"},
+	})
+	if err != nil {
+		t.Fatalf("compileExtraMetrics failed: %v", err)
+	}
+	scraper := &KubernetesScraper{extraMetrics: extra}
+	families, err := scraper.parseMetricsBody([]byte(apiserverMetrics + `# TYPE apiserver_storage_objects gauge
+apiserver_storage_objects{resource="pods"} 40
+apiserver_storage_objects{resource="nodes"} 3
+# TYPE kubelet_volume_stats_used_bytes gauge
+kubelet_volume_stats_used_bytes{namespace="db",persistentvolumeclaim="data"} 1024
+`))
+	if err != nil {
+		t.Fatalf("parseMetricsBody failed: %v", err)
+	}
+
+	// Extra metrics keep all their series
+	objects, ok := scraper.allowedFamily(ComponentAPIServer, "apiserver_storage_objects", families["apiserver_storage_objects"])
+	if !ok || len(objects.Metric) != 2 {
+		t.Errorf("Expected apiserver_storage_objects with 2 series, got %v, %v", objects, ok)
+	}
+	if _, ok := scraper.allowedFamily(ComponentKubelet, "kubelet_volume_stats_used_bytes", families["kubelet_volume_stats_used_bytes"]); !ok {
+		t.Error("Expected kubelet_volume_stats_used_bytes to be allowed for the kubelet")
+	}
+
+	// Patterns are per component
+	if _, ok := scraper.allowedFamily(ComponentCAdvisor, "kubelet_volume_stats_used_bytes", families["kubelet_volume_stats_used_bytes"]); ok {
+		t.Error("Expected kubelet patterns not to apply to cadvisor")
+	}
+
+	// Built-in reductions still apply to metrics that are also listed as extra
+	requests, ok := scraper.allowedFamily(ComponentAPIServer, "apiserver_request_total", families["apiserver_request_total"])
+	if !ok || len(requests.Metric) != 2 {
+		t.Errorf("Expected apiserver_request_total reduced to 2 series, got %v, %v", requests, ok)
+	}
+}
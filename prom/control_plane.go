@@ -0,0 +1,171 @@
+package prom
+
+import (
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// seriesRule describes how an allowlisted control-plane metric is reduced
+// before it is stored. Control-plane components label their metrics by verb,
+// resource, scope and so on, which adds up to thousands of series; ktop only
+// needs a handful of them.
+type seriesRule struct {
+	// keep lists the labels that survive; series left with the same labels
+	// are summed. Nil keeps no labels at all.
+	keep []string
+	// exclude drops series whose label has one of the listed values before
+	// they are summed.
+	exclude map[string][]string
+}
+
+// ControlPlaneMetricAllowlist contains the control-plane metrics shown on the
+// control-plane page, per component. Metrics from components not listed here
+// go through DefaultMetricAllowlist; see allowedFamily.
+var ControlPlaneMetricAllowlist = map[ComponentType]map[string]seriesRule{
+	ComponentAPIServer: {
+		"apiserver_request_total": {keep: []string{"code"}},
+		// Long-running requests would swamp the latency percentiles
+		"apiserver_request_duration_seconds":  {exclude: map[string][]string{"verb": {"WATCH", "CONNECT"}}},
+		"apiserver_current_inflight_requests": {keep: []string{"request_kind"}},
+	},
+	ComponentEtcd: {
+		"etcd_server_has_leader":                {},
+		"etcd_server_leader_changes_seen_total": {},
+		"etcd_mvcc_db_total_size_in_bytes":      {},
+	},
+	ComponentScheduler: {
+		"scheduler_pending_pods": {keep: []string{"queue"}},
+	},
+	ComponentControllerManager: {
+		"workqueue_depth": {keep: []string{"name"}},
+	},
+}
+
+// reduce applies the rule to family, returning a family with one metric per
+// distinct set of kept labels.
+func (r seriesRule) reduce(family *dto.MetricFamily) *dto.MetricFamily {
+	reduced := &dto.MetricFamily{Name: family.Name, Help: family.Help, Type: family.Type}
+	byKey := make(map[string]*dto.Metric)
+	var keys []string
+
+	for _, metric := range family.Metric {
+		if r.excluded(metric) {
+			continue
+		}
+
+		var kept []*dto.LabelPair
+		var key strings.Builder
+		for _, lp := range metric.Label {
+			if slices.Contains(r.keep, lp.GetName()) {
+				kept = append(kept, lp)
+				key.WriteString(lp.GetName() + "=" + lp.GetValue() + ",")
+			}
+		}
+
+		existing, ok := byKey[key.String()]
+		if !ok {
+			byKey[key.String()] = copyMetric(metric, kept)
+			keys = append(keys, key.String())
+			continue
+		}
+		addMetric(existing, metric)
+	}
+
+	sort.Strings(keys)
+	for _, key := range keys {
+		reduced.Metric = append(reduced.Metric, byKey[key])
+	}
+	return reduced
+}
+
+func (r seriesRule) excluded(metric *dto.Metric) bool {
+	for _, lp := range metric.Label {
+		if slices.Contains(r.exclude[lp.GetName()], lp.GetValue()) {
+			return true
+		}
+	}
+	return false
+}
+
+// copyMetric returns a copy of the value in metric with the given labels
+func copyMetric(metric *dto.Metric, lbls []*dto.LabelPair) *dto.Metric {
+	m := &dto.Metric{Label: lbls}
+	switch {
+	case metric.Counter != nil:
+		m.Counter = &dto.Counter{Value: ptr(metric.Counter.GetValue())}
+	case metric.Gauge != nil:
+		m.Gauge = &dto.Gauge{Value: ptr(metric.Gauge.GetValue())}
+	case metric.Untyped != nil:
+		m.Untyped = &dto.Untyped{Value: ptr(metric.Untyped.GetValue())}
+	case metric.Histogram != nil:
+		h := &dto.Histogram{
+			SampleCount: ptr(metric.Histogram.GetSampleCount()),
+			SampleSum:   ptr(metric.Histogram.GetSampleSum()),
+		}
+		for _, b := range metric.Histogram.Bucket {
+			h.Bucket = append(h.Bucket, &dto.Bucket{
+				UpperBound:      ptr(b.GetUpperBound()),
+				CumulativeCount: ptr(b.GetCumulativeCount()),
+			})
+		}
+		m.Histogram = h
+	case metric.Summary != nil:
+		s := &dto.Summary{
+			SampleCount: ptr(metric.Summary.GetSampleCount()),
+			SampleSum:   ptr(metric.Summary.GetSampleSum()),
+		}
+		for _, q := range metric.Summary.Quantile {
+			s.Quantile = append(s.Quantile, &dto.Quantile{
+				Quantile: ptr(q.GetQuantile()),
+				Value:    ptr(q.GetValue()),
+			})
+		}
+		m.Summary = s
+	}
+	return m
+}
+
+// addMetric adds the value of metric into sum. Histogram buckets are matched
+// by upper bound; all series of a histogram share the same buckets. Summary
+// quantiles can't be added up, so the highest value of each is kept, as
+// QueryQuantile does across series.
+func addMetric(sum, metric *dto.Metric) {
+	switch {
+	case sum.Counter != nil:
+		*sum.Counter.Value += metric.GetCounter().GetValue()
+	case sum.Gauge != nil:
+		*sum.Gauge.Value += metric.GetGauge().GetValue()
+	case sum.Untyped != nil:
+		*sum.Untyped.Value += metric.GetUntyped().GetValue()
+	case sum.Histogram != nil:
+		h := metric.GetHistogram()
+		*sum.Histogram.SampleCount += h.GetSampleCount()
+		*sum.Histogram.SampleSum += h.GetSampleSum()
+		for _, b := range h.Bucket {
+			for _, sb := range sum.Histogram.Bucket {
+				if sb.GetUpperBound() == b.GetUpperBound() {
+					*sb.CumulativeCount += b.GetCumulativeCount()
+					break
+				}
+			}
+		}
+	case sum.Summary != nil:
+		s := metric.GetSummary()
+		*sum.Summary.SampleCount += s.GetSampleCount()
+		*sum.Summary.SampleSum += s.GetSampleSum()
+		for _, q := range s.Quantile {
+			for _, sq := range sum.Summary.Quantile {
+				if sq.GetQuantile() == q.GetQuantile() {
+					*sq.Value = math.Max(sq.GetValue(), q.GetValue())
+					break
+				}
+			}
+		}
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
@@ -0,0 +1,126 @@
+package prom
+
+import (
+	"math"
+	"testing"
+)
+
+const apiserverMetrics = `# TYPE apiserver_request_total counter
+apiserver_request_total{code="200",resource="pods",verb="GET"} 10
+apiserver_request_total{code="200",resource="nodes",verb="LIST"} 5
+apiserver_request_total{code="500",resource="pods",verb="GET"} 1
+# TYPE apiserver_request_duration_seconds histogram
+apiserver_request_duration_seconds_bucket{resource="pods",verb="GET",le="0.1"} 3
+apiserver_request_duration_seconds_bucket{resource="pods",verb="GET",le="1"} 4
+apiserver_request_duration_seconds_bucket{resource="pods",verb="GET",le="+Inf"} 4
+apiserver_request_duration_seconds_sum{resource="pods",verb="GET"} 0.9
+apiserver_request_duration_seconds_count{resource="pods",verb="GET"} 4
+apiserver_request_duration_seconds_bucket{resource="pods",verb="WATCH",le="0.1"} 0
+apiserver_request_duration_seconds_bucket{resource="pods",verb="WATCH",le="1"} 0
+apiserver_request_duration_seconds_bucket{resource="pods",verb="WATCH",le="+Inf"} 7
+apiserver_request_duration_seconds_sum{resource="pods",verb="WATCH"} 3000
+apiserver_request_duration_seconds_count{resource="pods",verb="WATCH"} 7
+apiserver_request_duration_seconds_bucket{resource="nodes",verb="LIST",le="0.1"} 1
+apiserver_request_duration_seconds_bucket{resource="nodes",verb="LIST",le="1"} 2
+apiserver_request_duration_seconds_bucket{resource="nodes",verb="LIST",le="+Inf"} 2
+apiserver_request_duration_seconds_sum{resource="nodes",verb="LIST"} 0.6
+apiserver_request_duration_seconds_count{resource="nodes",verb="LIST"} 2
+# TYPE workqueue_depth gauge
+workqueue_depth{name="APIServiceRegistrationController"} 0
+`
+
+const controllerSummaryMetrics = `# TYPE sync_duration_seconds summary
+sync_duration_seconds{controller="a",quantile="0.5"} 0.1
+sync_duration_seconds{controller="a",quantile="0.99"} 3
+sync_duration_seconds_sum{controller="a"} 10
+sync_duration_seconds_count{controller="a"} 20
+sync_duration_seconds{controller="b",quantile="0.5"} 0.2
+sync_duration_seconds{controller="b",quantile="0.99"} 0.5
+sync_duration_seconds_sum{controller="b"} 2
+sync_duration_seconds_count{controller="b"} 10
+`
+
+func TestAllowedFamily_ControlPlane(t *testing.T) {
+	scraper := &KubernetesScraper{}
+	families, err := scraper.parseMetricsBody([]byte(apiserverMetrics))
+	if err != nil {
+		t.Fatalf("parseMetricsBody failed: %v", err)
+	}
+
+	// Labels other than code are dropped and the series summed
+	requests, ok := scraper.allowedFamily(ComponentAPIServer, "apiserver_request_total", families["apiserver_request_total"])
+	if !ok {
+		t.Fatal("Expected apiserver_request_total to be allowed")
+	}
+	if len(requests.Metric) != 2 {
+		t.Fatalf("Expected 2 series after reduction, got %d", len(requests.Metric))
+	}
+	for _, m := range requests.Metric {
+		if len(m.Label) != 1 || m.Label[0].GetName() != "code" {
+			t.Errorf("Expected only the code label, got %v", m.Label)
+		}
+		want := map[string]float64{"200": 15, "500": 1}[m.Label[0].GetValue()]
+		if m.Counter.GetValue() != want {
+			t.Errorf("Expected code %s to sum to %v, got %v", m.Label[0].GetValue(), want, m.Counter.GetValue())
+		}
+	}
+
+	// workqueue_depth from the apiserver isn't the controller-manager's
+	if _, ok := scraper.allowedFamily(ComponentAPIServer, "workqueue_depth", families["workqueue_depth"]); ok {
+		t.Error("Expected workqueue_depth to be dropped for the apiserver")
+	}
+	if _, ok := scraper.allowedFamily(ComponentControllerManager, "workqueue_depth", families["workqueue_depth"]); !ok {
+		t.Error("Expected workqueue_depth to be allowed for the controller-manager")
+	}
+
+	// Node components keep using the default allowlist
+	if _, ok := scraper.allowedFamily(ComponentKubelet, "apiserver_request_total", families["apiserver_request_total"]); ok {
+		t.Error("Expected control-plane metrics to be dropped for the kubelet")
+	}
+}
+
+func TestSeriesRule_ReduceHistogramAndSummary(t *testing.T) {
+	scraper := &KubernetesScraper{}
+	families, err := scraper.parseMetricsBody([]byte(apiserverMetrics + controllerSummaryMetrics))
+	if err != nil {
+		t.Fatalf("parseMetricsBody failed: %v", err)
+	}
+
+	name := "apiserver_request_duration_seconds"
+	reduced, ok := scraper.allowedFamily(ComponentAPIServer, name, families[name])
+	if !ok {
+		t.Fatalf("Expected %s to be allowed", name)
+	}
+	if len(reduced.Metric) != 1 {
+		t.Fatalf("Expected one histogram after reduction, got %d", len(reduced.Metric))
+	}
+	// WATCH is excluded; GET and LIST are summed into one histogram
+	h := reduced.Metric[0].GetHistogram()
+	want := map[float64]uint64{0.1: 4, 1: 6, math.Inf(1): 6}
+	for _, b := range h.Bucket {
+		if b.GetCumulativeCount() != want[b.GetUpperBound()] {
+			t.Errorf("Expected bucket le=%v to be %v, got %v", b.GetUpperBound(), want[b.GetUpperBound()], b.GetCumulativeCount())
+		}
+	}
+	if h.GetSampleCount() != 6 || h.GetSampleSum() != 1.5 {
+		t.Errorf("Expected count 6 and sum 1.5, got %v and %v", h.GetSampleCount(), h.GetSampleSum())
+	}
+
+	// Summaries keep their quantiles: the highest of each, as they can't be added
+	summary := seriesRule{}.reduce(families["sync_duration_seconds"])
+	if len(summary.Metric) != 1 {
+		t.Fatalf("Expected one summary after reduction, got %d", len(summary.Metric))
+	}
+	s := summary.Metric[0].GetSummary()
+	if s == nil || len(s.Quantile) != 2 {
+		t.Fatalf("Expected the summary's 2 quantiles, got %+v", s)
+	}
+	for _, q := range s.Quantile {
+		if want := map[float64]float64{0.5: 0.2, 0.99: 3}[q.GetQuantile()]; q.GetValue() != want {
+			t.Errorf("Expected quantile %v to be %v, got %v", q.GetQuantile(), want, q.GetValue())
+		}
+	}
+	if s.GetSampleCount() != 30 || s.GetSampleSum() != 12 {
+		t.Errorf("Expected count 30 and sum 12, got %v and %v", s.GetSampleCount(), s.GetSampleSum())
+	}
+}
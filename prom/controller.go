@@ -3,6 +3,7 @@ package prom
 import (
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
 	"sync"
 	"time"
@@ -25,6 +26,8 @@ func (cc *CollectorController) Start(ctx context.Context) error {
 		cc.mutex.Unlock()
 		return fmt.Errorf("initializing controller: %w", err)
 	}
+	ctx, cancel := context.WithCancel(ctx)
+	cc.cancel = cancel
 	cc.running = true
 	cc.mutex.Unlock()
 
@@ -36,14 +39,21 @@ func (cc *CollectorController) Start(ctx context.Context) error {
 	cc.discoverAvailableComponents(discoveryCtx)
 	discoveryCancel()
 
-	// Start the metrics collector
-	go cc.runCollector(ctx)
-
-	// Start periodic cleanup
-	go cc.runPeriodicCleanup(ctx)
-
-	// Start component discovery (for periodic re-discovery)
-	go cc.runComponentDiscovery(ctx)
+	// Start the metrics collector, periodic cleanup and component
+	// discovery (for periodic re-discovery); Stop waits for them
+	cc.wg.Add(3)
+	go func() {
+		defer cc.wg.Done()
+		cc.runCollector(ctx)
+	}()
+	go func() {
+		defer cc.wg.Done()
+		cc.runPeriodicCleanup(ctx)
+	}()
+	go func() {
+		defer cc.wg.Done()
+		cc.runComponentDiscovery(ctx)
+	}()
 
 	slog.Info("prometheus collector started",
 		"scrape_interval", cc.config.Interval,
@@ -54,19 +64,29 @@ func (cc *CollectorController) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop gracefully stops the metrics collection controller
+// Stop gracefully stops the metrics collection controller: it ends the
+// collection goroutines, waits for them, then closes the store.
 func (cc *CollectorController) Stop() error {
 	cc.mutex.Lock()
-	defer cc.mutex.Unlock()
-
 	if !cc.running {
+		cc.mutex.Unlock()
 		return fmt.Errorf("controller is not running")
 	}
-
 	cc.running = false
+	cc.cancel()
+	cc.mutex.Unlock()
+
+	// The goroutines take the lock to record errors, so wait without it
+	cc.wg.Wait()
+
+	// Persist history; the store stays queryable
+	if closer, ok := cc.store.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			slog.Warn("saving metrics history failed", "error", err)
+		}
+	}
 
 	slog.Info("prometheus collector stopped")
-	// Cleanup would be handled by context cancellation
 	return nil
 }
 
@@ -110,8 +130,16 @@ func (cc *CollectorController) TestScrape(ctx context.Context) error {
 
 // initialize sets up the collector and store components
 func (cc *CollectorController) initialize() error {
-	// Create metrics store
+	// Create metrics store, persisted to disk when a data directory is set
 	cc.store = NewInMemoryStore(cc.config)
+	if cc.config.DataDir != "" {
+		diskStore, err := NewDiskStore(cc.config.DataDir, cc.config)
+		if err != nil {
+			slog.Warn("metrics history will not be persisted", "dir", cc.config.DataDir, "error", err)
+		} else {
+			cc.store = diskStore
+		}
+	}
 
 	// Create metrics collector
 	scraper, err := NewKubernetesScraper(cc.kubeConfig, cc.config)
@@ -132,7 +160,9 @@ func (cc *CollectorController) runCollector(ctx context.Context) {
 
 	// Run first collection NON-BLOCKING with timeout
 	// This prevents startup hangs - UI will show loading state while metrics populate
+	cc.wg.Add(1)
 	go func() {
+		defer cc.wg.Done()
 		firstCtx, cancel := context.WithTimeout(ctx, cc.config.Timeout)
 		defer cancel()
 		cc.collectFromAllComponents(firstCtx)
@@ -334,10 +364,8 @@ func (cc *CollectorController) GetStats() map[string]interface{} {
 		stats["last_error"] = lastError.Error()
 	}
 
-	if cc.store != nil {
-		if memStore, ok := cc.store.(*InMemoryStore); ok {
-			stats["store"] = memStore.GetStats()
-		}
+	if memStore := cc.memoryStore(); memStore != nil {
+		stats["store"] = memStore.GetStats()
 	}
 
 	return stats
@@ -355,17 +383,24 @@ func (cc *CollectorController) ForceCollection(ctx context.Context) error {
 
 // GetComponentMetrics returns metrics for a specific component
 func (cc *CollectorController) GetComponentMetrics(component ComponentType) map[string]*TimeSeries {
-	if cc.store == nil {
-		return nil
-	}
-
-	if memStore, ok := cc.store.(*InMemoryStore); ok {
+	if memStore := cc.memoryStore(); memStore != nil {
 		return memStore.QueryByComponent(component)
 	}
 
 	return nil
 }
 
+// memoryStore returns the in-memory store backing cc.store, or nil
+func (cc *CollectorController) memoryStore() *InMemoryStore {
+	switch store := cc.store.(type) {
+	case *InMemoryStore:
+		return store
+	case *DiskStore:
+		return store.InMemoryStore
+	}
+	return nil
+}
+
 // QueryMetric provides a simple interface to query the latest value of a metric
 func (cc *CollectorController) QueryMetric(metricName string, labelMatchers map[string]string) (float64, error) {
 	if cc.store == nil {
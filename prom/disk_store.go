@@ -0,0 +1,542 @@
+package prom
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// On-disk layout of a DiskStore directory. Both files hold the same record
+// format: the snapshot is the compacted store, the WAL holds samples added
+// since the snapshot was written.
+const (
+	snapshotFile = "snapshot.dat"
+	walFile      = "wal.dat"
+	lockFile     = "lock"
+
+	diskMagic    = "ktopms02" // file header, bumped on incompatible format changes
+	maxRecordLen = 64 << 20   // guards against reading a corrupt length
+
+	// maxWALSize is the log size past which AddMetrics compacts the store
+	maxWALSize = 32 << 20
+)
+
+// Record kinds. A series record gives a series its id within the file; the
+// samples records that follow refer to the series by that id.
+const (
+	seriesRecord  byte = 1
+	samplesRecord byte = 2
+)
+
+// ErrStoreLocked is returned by NewDiskStore when another process has the
+// directory open
+var ErrStoreLocked = errors.New("metrics history is in use by another ktop")
+
+// openStores holds the store open on each directory in this process
+var (
+	openStoresMu sync.Mutex
+	openStores   = make(map[string]*DiskStore)
+)
+
+// DiskStore is a MetricsStore that keeps the in-memory ring buffers and
+// persists them to a directory so history survives a restart.
+//
+// Every AddMetrics call appends the new samples to a write-ahead log, where
+// a series' labels are written once and its samples refer to it by id. When
+// the log grows past maxWALSize, and on Close, the store is compacted: the
+// retained samples are written to a snapshot and the log is truncated.
+// Cleanup only prunes memory; samples it drops that are still in the log
+// are skipped on reload once they are older than RetentionTime, and are
+// gone after the next compaction. NewDiskStore reloads the snapshot and the log, dropping samples
+// older than RetentionTime; the ring buffers keep at most MaxSamples per
+// series as usual.
+//
+// A directory is used by one store at a time: the store holds an exclusive
+// lock on it until Close.
+type DiskStore struct {
+	*InMemoryStore
+
+	fileMu    sync.Mutex // serializes log appends and compaction
+	dir       string
+	lock      *os.File
+	wal       *os.File
+	walSeries *seriesWriter // series described in the log so far
+	walSize   int64
+	walLimit  int64
+	closed    bool
+}
+
+// NewDiskStore opens (creating if needed) the store in dir and loads the
+// history persisted there. A store this process has open on dir, as when
+// switching namespace in the same context, is closed first so its samples
+// are loaded; ErrStoreLocked is returned if another process has dir open.
+func NewDiskStore(dir string, config *ScrapeConfig) (*DiskStore, error) {
+	dir = filepath.Clean(dir)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create %s: %w", dir, err)
+	}
+
+	openStoresMu.Lock()
+	prev := openStores[dir]
+	openStoresMu.Unlock()
+	if prev != nil {
+		if err := prev.Close(); err != nil {
+			slog.Warn("saving metrics history failed", "dir", dir, "error", err)
+		}
+	}
+
+	lock, err := lockDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	store := &DiskStore{
+		InMemoryStore: NewInMemoryStore(config),
+		dir:           dir,
+		lock:          lock,
+		walLimit:      maxWALSize,
+	}
+
+	cutoff := time.Now().Add(-config.RetentionTime).UnixMilli()
+	for _, name := range []string{snapshotFile, walFile} {
+		if err := store.load(filepath.Join(dir, name), cutoff); err != nil {
+			lock.Close()
+			return nil, err
+		}
+	}
+
+	wal, err := os.OpenFile(filepath.Join(dir, walFile), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		lock.Close()
+		return nil, fmt.Errorf("open wal: %w", err)
+	}
+	store.wal = wal
+
+	// Fold the replayed log into a fresh snapshot so the files don't carry
+	// expired samples from one run to the next
+	store.fileMu.Lock()
+	defer store.fileMu.Unlock()
+	if err := store.compact(); err != nil {
+		wal.Close()
+		lock.Close()
+		return nil, err
+	}
+
+	openStoresMu.Lock()
+	openStores[dir] = store
+	openStoresMu.Unlock()
+
+	slog.Info("metrics history loaded",
+		"dir", dir,
+		"series", store.totalSeries,
+		"samples", store.totalSamples,
+	)
+	return store, nil
+}
+
+// AddMetrics stores scraped metrics in memory and appends them to the log,
+// compacting the store once the log outgrows its limit. After Close only the
+// in-memory store is updated.
+func (store *DiskStore) AddMetrics(metrics *ScrapedMetrics) error {
+	store.fileMu.Lock()
+	defer store.fileMu.Unlock()
+
+	if err := store.InMemoryStore.AddMetrics(metrics); err != nil {
+		return err
+	}
+	if store.closed {
+		return nil
+	}
+
+	var buf []byte
+	for metricName, family := range metrics.Families {
+		for _, ts := range family.TimeSeries {
+			if ts.Samples.IsEmpty() {
+				continue
+			}
+			buf = store.walSeries.append(buf, metricName, ts.Labels, ts.Samples.Slice())
+		}
+	}
+	if len(buf) == 0 {
+		return nil
+	}
+	n, err := store.wal.Write(buf)
+	store.walSize += int64(n)
+	if err != nil {
+		// Series described by the failed write are unknown to the log;
+		// compacting starts a new one
+		if cerr := store.compact(); cerr != nil {
+			slog.Warn("compacting metrics history failed", "dir", store.dir, "error", cerr)
+		}
+		return fmt.Errorf("append wal: %w", err)
+	}
+	if store.walSize > store.walLimit {
+		return store.compact()
+	}
+	return nil
+}
+
+// Close compacts the store, closes the log and releases the directory. The
+// in-memory data remains queryable.
+func (store *DiskStore) Close() error {
+	store.fileMu.Lock()
+	defer store.fileMu.Unlock()
+
+	if store.closed {
+		return nil
+	}
+	store.closed = true
+
+	err := store.compact()
+	if cerr := store.wal.Close(); err == nil && cerr != nil {
+		err = fmt.Errorf("close wal: %w", cerr)
+	}
+	store.unlock()
+	return err
+}
+
+// unlock releases the directory (must be called with fileMu held)
+func (store *DiskStore) unlock() {
+	openStoresMu.Lock()
+	if openStores[store.dir] == store {
+		delete(openStores, store.dir)
+	}
+	openStoresMu.Unlock()
+	store.lock.Close() // closing the file drops the lock
+}
+
+// lockDir takes an exclusive lock on dir, held until the returned file is
+// closed. The lock is taken by the platform's lockExclusive.
+func lockDir(dir string) (*os.File, error) {
+	f, err := os.OpenFile(filepath.Join(dir, lockFile), os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open lock: %w", err)
+	}
+	if err := lockExclusive(f); err != nil {
+		f.Close()
+		if errors.Is(err, ErrStoreLocked) {
+			return nil, fmt.Errorf("%w: %s", ErrStoreLocked, dir)
+		}
+		return nil, fmt.Errorf("lock %s: %w", dir, err)
+	}
+	return f, nil
+}
+
+// compactSeries is a series copied out of the in-memory store for compact
+type compactSeries struct {
+	metricName string
+	labels     labels.Labels
+	samples    []MetricSample
+}
+
+// compact writes every retained series to a new snapshot, replaces the old
+// one, then truncates the log (must be called with fileMu held). The series
+// are copied under the store's lock and written without it, so queries
+// aren't held up by the disk.
+func (store *DiskStore) compact() error {
+	store.mutex.RLock()
+	retained := make([]compactSeries, 0, store.totalSeries)
+	for metricName, seriesMap := range store.series {
+		for _, ts := range seriesMap {
+			retained = append(retained, compactSeries{metricName, ts.Labels, ts.Samples.Slice()})
+		}
+	}
+	store.mutex.RUnlock()
+
+	path := filepath.Join(store.dir, snapshotFile)
+	tmp, err := os.CreateTemp(store.dir, snapshotFile+".*")
+	if err != nil {
+		return fmt.Errorf("create snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // no-op once renamed
+
+	w := bufio.NewWriter(tmp)
+	w.WriteString(diskMagic)
+
+	var buf []byte
+	series := newSeriesWriter()
+	for _, cs := range retained {
+		buf = series.append(buf[:0], cs.metricName, cs.labels, cs.samples)
+		w.Write(buf)
+	}
+
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close snapshot: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("replace snapshot: %w", err)
+	}
+
+	// Everything in the log is now in the snapshot
+	if err := store.wal.Truncate(0); err != nil {
+		return fmt.Errorf("truncate wal: %w", err)
+	}
+	if _, err := store.wal.WriteString(diskMagic); err != nil {
+		return fmt.Errorf("truncate wal: %w", err)
+	}
+	store.walSeries = newSeriesWriter()
+	store.walSize = int64(len(diskMagic))
+	return nil
+}
+
+// load replays the records in path into the in-memory store, skipping
+// samples older than cutoff. A missing file is not an error. Reading stops
+// at the first damaged record, which is what an interrupted append leaves
+// behind; the records before it are kept.
+func (store *DiskStore) load(path string, cutoff int64) error {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	header := make([]byte, len(diskMagic))
+	if _, err := io.ReadFull(r, header); err != nil || string(header) != diskMagic {
+		slog.Warn("ignoring metrics history file with unknown format", "path", path)
+		return nil
+	}
+
+	series := make(map[uint64]diskSeries)
+	for {
+		rec, err := readRecord(r)
+		if err == io.EOF {
+			return nil
+		}
+		if err == nil && rec.kind == samplesRecord {
+			if _, ok := series[rec.id]; !ok {
+				err = fmt.Errorf("samples of unknown series %d", rec.id)
+			}
+		}
+		if err != nil {
+			slog.Warn("metrics history truncated at damaged record", "path", path, "error", err)
+			return nil
+		}
+		if rec.kind == seriesRecord {
+			series[rec.id] = rec.series
+			continue
+		}
+
+		metricName := series[rec.id].metricName
+		ts := &TimeSeries{
+			Labels:  series[rec.id].labels,
+			Samples: NewRingBuffer[MetricSample](len(rec.samples)),
+		}
+		for _, sample := range rec.samples {
+			if sample.Timestamp >= cutoff {
+				ts.Samples.Add(sample)
+			}
+		}
+		if ts.Samples.IsEmpty() {
+			continue
+		}
+
+		err = store.InMemoryStore.AddMetrics(&ScrapedMetrics{
+			Families: map[string]*MetricFamily{
+				metricName: {Name: metricName, TimeSeries: []*TimeSeries{ts}},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("load %s: %w", path, err)
+		}
+	}
+}
+
+// diskSeries is what a series record describes
+type diskSeries struct {
+	metricName string
+	labels     labels.Labels
+}
+
+// seriesWriter encodes the records of one file, describing each series the
+// first time its samples are written.
+type seriesWriter struct {
+	ids map[string]uint64
+}
+
+func newSeriesWriter() *seriesWriter {
+	return &seriesWriter{ids: make(map[string]uint64)}
+}
+
+// append appends the samples of a series to buf, preceded by the series
+// record if the file doesn't describe the series yet.
+func (w *seriesWriter) append(buf []byte, metricName string, lbls labels.Labels, samples []MetricSample) []byte {
+	key := metricName + "\xff" + lbls.String()
+	id, ok := w.ids[key]
+	if !ok {
+		id = uint64(len(w.ids))
+		w.ids[key] = id
+		payload := []byte{seriesRecord}
+		payload = binary.AppendUvarint(payload, id)
+		payload = appendString(payload, metricName)
+		payload = binary.AppendUvarint(payload, uint64(len(lbls)))
+		for _, l := range lbls {
+			payload = appendString(payload, l.Name)
+			payload = appendString(payload, l.Value)
+		}
+		buf = appendRecord(buf, payload)
+	}
+
+	payload := []byte{samplesRecord}
+	payload = binary.AppendUvarint(payload, id)
+	payload = binary.AppendUvarint(payload, uint64(len(samples)))
+	for _, s := range samples {
+		payload = binary.AppendVarint(payload, s.Timestamp)
+		payload = binary.LittleEndian.AppendUint64(payload, math.Float64bits(s.Value))
+	}
+	return appendRecord(buf, payload)
+}
+
+// appendRecord appends one record to buf. A record is the uvarint length of
+// its payload, the payload and a CRC-32 of the payload. The payload starts
+// with the record kind:
+//
+//	series:  kind, uvarint id, metric name, label count, (name, value)...
+//	samples: kind, uvarint id, sample count, (varint timestamp, float64 bits)...
+//
+// Strings are a uvarint length followed by the bytes.
+func appendRecord(buf, payload []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(payload)))
+	buf = append(buf, payload...)
+	return binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(payload))
+}
+
+func appendString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+// record is a decoded record; series is set for series records, samples
+// for samples records
+type record struct {
+	kind    byte
+	id      uint64
+	series  diskSeries
+	samples []MetricSample
+}
+
+// readRecord reads one record written by appendRecord. It returns io.EOF
+// only when r is exhausted at a record boundary.
+func readRecord(r *bufio.Reader) (record, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil {
+		return record{}, err
+	}
+	if n > maxRecordLen {
+		return record{}, fmt.Errorf("record length %d exceeds limit", n)
+	}
+
+	payload := make([]byte, n+4)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return record{}, fmt.Errorf("short record: %w", err)
+	}
+	payload, sum := payload[:n], binary.LittleEndian.Uint32(payload[n:])
+	if crc32.ChecksumIEEE(payload) != sum {
+		return record{}, fmt.Errorf("record checksum mismatch")
+	}
+	if len(payload) == 0 {
+		return record{}, fmt.Errorf("empty record")
+	}
+
+	rec := record{kind: payload[0]}
+	d := &recordDecoder{buf: payload[1:]}
+	rec.id = d.uvarint()
+	switch rec.kind {
+	case seriesRecord:
+		rec.series.metricName = d.string()
+		lbls := make([]labels.Label, d.uvarint())
+		for i := range lbls {
+			lbls[i] = labels.Label{Name: d.string(), Value: d.string()}
+		}
+		rec.series.labels = labels.New(lbls...)
+	case samplesRecord:
+		rec.samples = make([]MetricSample, d.uvarint())
+		for i := range rec.samples {
+			rec.samples[i].Timestamp = d.varint()
+			rec.samples[i].Value = math.Float64frombits(d.uint64())
+		}
+	default:
+		return record{}, fmt.Errorf("unknown record kind %d", rec.kind)
+	}
+	if d.err != nil {
+		return record{}, d.err
+	}
+	return rec, nil
+}
+
+// recordDecoder reads the fields of a record payload, remembering the first
+// error so callers can check once at the end.
+type recordDecoder struct {
+	buf []byte
+	err error
+}
+
+func (d *recordDecoder) fail() {
+	if d.err == nil {
+		d.err = fmt.Errorf("malformed record")
+	}
+	d.buf = nil
+}
+
+func (d *recordDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 || v > uint64(len(d.buf)) { // every count and length is bounded by the payload size
+		d.fail()
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *recordDecoder) varint() int64 {
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+func (d *recordDecoder) uint64() uint64 {
+	if len(d.buf) < 8 {
+		d.fail()
+		return 0
+	}
+	v := binary.LittleEndian.Uint64(d.buf)
+	d.buf = d.buf[8:]
+	return v
+}
+
+func (d *recordDecoder) string() string {
+	n := d.uvarint()
+	if d.err != nil || n > uint64(len(d.buf)) {
+		d.fail()
+		return ""
+	}
+	s := string(d.buf[:n])
+	d.buf = d.buf[n:]
+	return s
+}
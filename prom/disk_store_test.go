@@ -0,0 +1,289 @@
+package prom
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+func diskTestMetrics(value float64, at time.Time) *ScrapedMetrics {
+	return &ScrapedMetrics{
+		Families: map[string]*MetricFamily{
+			"test_gauge": {
+				Name: "test_gauge",
+				TimeSeries: []*TimeSeries{
+					createTestTimeSeries(
+						labels.Labels{
+							{Name: "__name__", Value: "test_gauge"},
+							{Name: "pod", Value: "web-0"},
+						},
+						MetricSample{Timestamp: at.UnixMilli(), Value: value},
+					),
+				},
+			},
+		},
+	}
+}
+
+func queryDiskTestSeries(t *testing.T, store MetricsStore) []*MetricSample {
+	t.Helper()
+	now := time.Now()
+	samples, err := store.QueryRange("test_gauge", map[string]string{"pod": "web-0"}, now.Add(-24*time.Hour), now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("QueryRange failed: %v", err)
+	}
+	return samples
+}
+
+func TestDiskStore_ReloadsHistory(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultScrapeConfig()
+
+	store, err := NewDiskStore(dir, config)
+	if err != nil {
+		t.Fatalf("NewDiskStore failed: %v", err)
+	}
+	now := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := store.AddMetrics(diskTestMetrics(float64(i), now.Add(time.Duration(i)*time.Second))); err != nil {
+			t.Fatalf("AddMetrics failed: %v", err)
+		}
+	}
+	if err := store.AddMetrics(diskTestMetrics(math.NaN(), now.Add(3*time.Second))); err != nil {
+		t.Fatalf("AddMetrics failed: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	reopened, err := NewDiskStore(dir, config)
+	if err != nil {
+		t.Fatalf("reopening store failed: %v", err)
+	}
+	defer reopened.Close()
+
+	samples := queryDiskTestSeries(t, reopened)
+	if len(samples) != 4 {
+		t.Fatalf("Expected 4 samples after reload, got %d", len(samples))
+	}
+	for i := 0; i < 3; i++ {
+		if samples[i].Value != float64(i) {
+			t.Errorf("Sample %d: expected value %d, got %v", i, i, samples[i].Value)
+		}
+	}
+	if !math.IsNaN(samples[3].Value) {
+		t.Errorf("Expected NaN to survive reload, got %v", samples[3].Value)
+	}
+	if names := reopened.GetLabelValues("pod"); len(names) != 1 || names[0] != "web-0" {
+		t.Errorf("Expected label index to be rebuilt, got %v", names)
+	}
+}
+
+// crash releases the store's files without compacting, as a killed process
+// would
+func crash(store *DiskStore) {
+	store.fileMu.Lock()
+	defer store.fileMu.Unlock()
+	store.closed = true
+	store.wal.Close()
+	store.unlock()
+}
+
+func TestDiskStore_ReplaysLogWithoutClose(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultScrapeConfig()
+
+	store, err := NewDiskStore(dir, config)
+	if err != nil {
+		t.Fatalf("NewDiskStore failed: %v", err)
+	}
+	now := time.Now()
+	store.AddMetrics(diskTestMetrics(1, now))
+	store.AddMetrics(diskTestMetrics(2, now.Add(time.Second)))
+
+	// Simulate a crash during an append: a partial record at the end of the log
+	f, err := os.OpenFile(filepath.Join(dir, walFile), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatalf("open wal: %v", err)
+	}
+	f.Write([]byte{0x20, 0x01, 0x02})
+	f.Close()
+	crash(store)
+
+	reopened, err := NewDiskStore(dir, config)
+	if err != nil {
+		t.Fatalf("reopening store failed: %v", err)
+	}
+	defer reopened.Close()
+
+	if samples := queryDiskTestSeries(t, reopened); len(samples) != 2 {
+		t.Errorf("Expected the 2 complete samples to be replayed, got %d", len(samples))
+	}
+}
+
+func TestDiskStore_HonorsRetentionAndMaxSamples(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultScrapeConfig()
+	config.RetentionTime = time.Hour
+	config.MaxSamples = 3
+
+	store, err := NewDiskStore(dir, config)
+	if err != nil {
+		t.Fatalf("NewDiskStore failed: %v", err)
+	}
+	now := time.Now()
+	store.AddMetrics(diskTestMetrics(-1, now.Add(-2*time.Hour))) // expired by reload
+	for i := 0; i < 5; i++ {
+		store.AddMetrics(diskTestMetrics(float64(i), now.Add(time.Duration(i)*time.Second)))
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	config.MaxSamples = 2 // a smaller limit applies to reloaded history too
+	reopened, err := NewDiskStore(dir, config)
+	if err != nil {
+		t.Fatalf("reopening store failed: %v", err)
+	}
+	defer reopened.Close()
+
+	samples := queryDiskTestSeries(t, reopened)
+	if len(samples) != 2 {
+		t.Fatalf("Expected 2 samples, got %d", len(samples))
+	}
+	if samples[0].Value != 3 || samples[1].Value != 4 {
+		t.Errorf("Expected the newest samples (3, 4), got (%v, %v)", samples[0].Value, samples[1].Value)
+	}
+}
+
+func TestDiskStore_CleanupKeepsLog(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDiskStore(dir, DefaultScrapeConfig())
+	if err != nil {
+		t.Fatalf("NewDiskStore failed: %v", err)
+	}
+	defer store.Close()
+
+	store.AddMetrics(diskTestMetrics(1, time.Now()))
+	walPath := filepath.Join(dir, walFile)
+	before, err := os.Stat(walPath)
+	if err != nil || before.Size() <= int64(len(diskMagic)) {
+		t.Fatalf("Expected samples in the log before cleanup, err %v", err)
+	}
+
+	// Compaction is driven by the log's size, not by cleanup
+	if err := store.Cleanup(); err != nil {
+		t.Fatalf("Cleanup failed: %v", err)
+	}
+	if after, _ := os.Stat(walPath); after.Size() != before.Size() {
+		t.Errorf("Expected cleanup to leave the log alone, size %d, was %d", after.Size(), before.Size())
+	}
+	if samples := queryDiskTestSeries(t, store); len(samples) != 1 {
+		t.Errorf("Expected the sample to survive cleanup, got %d", len(samples))
+	}
+}
+
+func TestDiskStore_LogDescribesSeriesOnce(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewDiskStore(dir, DefaultScrapeConfig())
+	if err != nil {
+		t.Fatalf("NewDiskStore failed: %v", err)
+	}
+	defer store.Close()
+
+	walPath := filepath.Join(dir, walFile)
+	walSize := func() int64 {
+		info, err := os.Stat(walPath)
+		if err != nil {
+			t.Fatalf("stat wal: %v", err)
+		}
+		return info.Size()
+	}
+
+	now := time.Now()
+	store.AddMetrics(diskTestMetrics(1, now))
+	first := walSize() - int64(len(diskMagic))
+	store.AddMetrics(diskTestMetrics(2, now.Add(time.Second)))
+	second := walSize() - int64(len(diskMagic)) - first
+	if second >= first {
+		t.Errorf("Expected later appends to leave out the labels, first %d bytes, second %d", first, second)
+	}
+
+	// Past the limit, the log is folded into the snapshot
+	store.walLimit = walSize()
+	store.AddMetrics(diskTestMetrics(3, now.Add(2*time.Second)))
+	if size := walSize(); size != int64(len(diskMagic)) {
+		t.Errorf("Expected the log compacted past its limit, size %d", size)
+	}
+	store.AddMetrics(diskTestMetrics(4, now.Add(3*time.Second)))
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	reopened, err := NewDiskStore(dir, DefaultScrapeConfig())
+	if err != nil {
+		t.Fatalf("reopening store failed: %v", err)
+	}
+	defer reopened.Close()
+	if samples := queryDiskTestSeries(t, reopened); len(samples) != 4 {
+		t.Errorf("Expected 4 samples after reload, got %d", len(samples))
+	}
+}
+
+func TestDiskStore_OneStorePerDirectory(t *testing.T) {
+	dir := t.TempDir()
+	config := DefaultScrapeConfig()
+
+	first, err := NewDiskStore(dir, config)
+	if err != nil {
+		t.Fatalf("NewDiskStore failed: %v", err)
+	}
+	first.AddMetrics(diskTestMetrics(1, time.Now()))
+
+	// A second store in this process takes over, with the first one's samples
+	second, err := NewDiskStore(dir, config)
+	if err != nil {
+		t.Fatalf("opening a second store failed: %v", err)
+	}
+	if !first.closed {
+		t.Error("Expected the first store closed")
+	}
+	if samples := queryDiskTestSeries(t, second); len(samples) != 1 {
+		t.Errorf("Expected the first store's sample, got %d", len(samples))
+	}
+	if err := second.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Another process holding the lock keeps the directory
+	lock, err := lockDir(dir)
+	if err != nil {
+		t.Fatalf("lockDir failed: %v", err)
+	}
+	defer lock.Close()
+	if _, err := NewDiskStore(dir, config); !errors.Is(err, ErrStoreLocked) {
+		t.Errorf("Expected ErrStoreLocked, got %v", err)
+	}
+}
+
+func TestDiskStore_IgnoresUnknownFormat(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, snapshotFile), []byte("not a snapshot"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewDiskStore(dir, DefaultScrapeConfig())
+	if err != nil {
+		t.Fatalf("NewDiskStore failed: %v", err)
+	}
+	defer store.Close()
+
+	if names := store.GetMetricNames(); len(names) != 0 {
+		t.Errorf("Expected empty store, got metrics %v", names)
+	}
+}
@@ -0,0 +1,251 @@
+package prom
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// convertHistogramFamily converts a histogram into the series Prometheus
+// itself exposes for one: <name>_bucket with an le label per bucket,
+// <name>_sum and <name>_count.
+func (ks *KubernetesScraper) convertHistogramFamily(name string, family *dto.MetricFamily) map[string]*MetricFamily {
+	now := time.Now()
+	newFamily := func(suffix string) *MetricFamily {
+		return &MetricFamily{
+			Name:        name + suffix,
+			Type:        dto.MetricType_HISTOGRAM,
+			Help:        family.GetHelp(),
+			LastUpdated: now,
+		}
+	}
+	buckets, sum, count := newFamily("_bucket"), newFamily("_sum"), newFamily("_count")
+	timestamp := now.UnixMilli()
+
+	for _, metric := range family.Metric {
+		h := metric.GetHistogram()
+		if h == nil {
+			continue
+		}
+
+		seriesLabels := func(metricName string, extra ...labels.Label) labels.Labels {
+			lbls := make(labels.Labels, 0, len(metric.Label)+2)
+			lbls = append(lbls, labels.Label{Name: "__name__", Value: metricName})
+			for _, label := range metric.Label {
+				lbls = append(lbls, labels.Label{Name: label.GetName(), Value: label.GetValue()})
+			}
+			return append(lbls, extra...)
+		}
+
+		sawInf := false
+		for _, b := range h.Bucket {
+			sawInf = sawInf || math.IsInf(b.GetUpperBound(), 1)
+			buckets.TimeSeries = append(buckets.TimeSeries, newSeries(
+				seriesLabels(buckets.Name, labels.Label{Name: "le", Value: formatBound(b.GetUpperBound())}),
+				timestamp, float64(b.GetCumulativeCount())))
+		}
+		// The +Inf bucket is implied when the exposition leaves it out
+		if !sawInf {
+			buckets.TimeSeries = append(buckets.TimeSeries, newSeries(
+				seriesLabels(buckets.Name, labels.Label{Name: "le", Value: "+Inf"}),
+				timestamp, float64(h.GetSampleCount())))
+		}
+		sum.TimeSeries = append(sum.TimeSeries, newSeries(seriesLabels(sum.Name), timestamp, h.GetSampleSum()))
+		count.TimeSeries = append(count.TimeSeries, newSeries(seriesLabels(count.Name), timestamp, float64(h.GetSampleCount())))
+	}
+
+	return map[string]*MetricFamily{buckets.Name: buckets, sum.Name: sum, count.Name: count}
+}
+
+// convertSummaryFamily converts a summary into the series Prometheus itself
+// exposes for one: <name> with a quantile label per quantile, <name>_sum
+// and <name>_count.
+func (ks *KubernetesScraper) convertSummaryFamily(name string, family *dto.MetricFamily) map[string]*MetricFamily {
+	now := time.Now()
+	newFamily := func(suffix string) *MetricFamily {
+		return &MetricFamily{
+			Name:        name + suffix,
+			Type:        dto.MetricType_SUMMARY,
+			Help:        family.GetHelp(),
+			LastUpdated: now,
+		}
+	}
+	quantiles, sum, count := newFamily(""), newFamily("_sum"), newFamily("_count")
+	timestamp := now.UnixMilli()
+
+	for _, metric := range family.Metric {
+		s := metric.GetSummary()
+		if s == nil {
+			continue
+		}
+
+		seriesLabels := func(metricName string, extra ...labels.Label) labels.Labels {
+			lbls := make(labels.Labels, 0, len(metric.Label)+2)
+			lbls = append(lbls, labels.Label{Name: "__name__", Value: metricName})
+			for _, label := range metric.Label {
+				lbls = append(lbls, labels.Label{Name: label.GetName(), Value: label.GetValue()})
+			}
+			return append(lbls, extra...)
+		}
+
+		for _, q := range s.Quantile {
+			quantiles.TimeSeries = append(quantiles.TimeSeries, newSeries(
+				seriesLabels(quantiles.Name, labels.Label{Name: "quantile", Value: formatBound(q.GetQuantile())}),
+				timestamp, q.GetValue()))
+		}
+		sum.TimeSeries = append(sum.TimeSeries, newSeries(seriesLabels(sum.Name), timestamp, s.GetSampleSum()))
+		count.TimeSeries = append(count.TimeSeries, newSeries(seriesLabels(count.Name), timestamp, float64(s.GetSampleCount())))
+	}
+
+	return map[string]*MetricFamily{quantiles.Name: quantiles, sum.Name: sum, count.Name: count}
+}
+
+func newSeries(lbls labels.Labels, timestamp int64, value float64) *TimeSeries {
+	samples := NewRingBuffer[MetricSample](1)
+	samples.Add(MetricSample{Timestamp: timestamp, Value: value})
+	return &TimeSeries{Labels: lbls, Samples: samples}
+}
+
+// formatBound formats a bucket bound the way the le label is written
+func formatBound(bound float64) string {
+	if math.IsInf(bound, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(bound, 'g', -1, 64)
+}
+
+// QueryQuantile estimates the q-quantile (0 <= q <= 1) of a histogram or
+// summary metric over the last window, across the series matching
+// labelMatchers. metricName is the base name, without _bucket.
+//
+// For a histogram the buckets of all matching series are summed and the
+// quantile is interpolated the way PromQL's histogram_quantile does over
+// their increase in window, so it needs two scrapes within window. NaN
+// means nothing was observed. For a summary, whose quantiles cannot be
+// combined, the highest recent value of the exposed quantile q is returned.
+func (store *InMemoryStore) QueryQuantile(metricName string, labelMatchers map[string]string, q float64, window time.Duration) (float64, error) {
+	if q < 0 || q > 1 {
+		return 0, fmt.Errorf("quantile %v out of range [0, 1]", q)
+	}
+
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	startMs := time.Now().Add(-window).UnixMilli()
+	if seriesMap, ok := store.series[metricName+"_bucket"]; ok {
+		return store.histogramQuantileLocked(metricName, seriesMap, labelMatchers, q, startMs)
+	}
+	if seriesMap, ok := store.series[metricName]; ok {
+		return store.summaryQuantileLocked(metricName, seriesMap, labelMatchers, q, startMs)
+	}
+	return 0, fmt.Errorf("metric %s not found", metricName)
+}
+
+func (store *InMemoryStore) histogramQuantileLocked(metricName string, seriesMap map[string]*TimeSeries, labelMatchers map[string]string, q float64, startMs int64) (float64, error) {
+	buckets := make(map[float64]float64)
+	for _, ts := range seriesMap {
+		if !store.matchesLabels(ts.Labels, labelMatchers) {
+			continue
+		}
+		upper, err := strconv.ParseFloat(ts.Labels.Get("le"), 64)
+		if err != nil {
+			continue
+		}
+		var window []*MetricSample
+		ts.Samples.Range(func(_ int, sample MetricSample) bool {
+			if sample.Timestamp >= startMs {
+				window = append(window, &sample)
+			}
+			return true
+		})
+		if increase, ok := counterIncrease(window); ok {
+			buckets[upper] += increase
+		}
+	}
+	if len(buckets) == 0 {
+		return 0, fmt.Errorf("insufficient samples for %s", metricName)
+	}
+	return histogramQuantile(q, buckets), nil
+}
+
+func (store *InMemoryStore) summaryQuantileLocked(metricName string, seriesMap map[string]*TimeSeries, labelMatchers map[string]string, q float64, startMs int64) (float64, error) {
+	value, found := math.Inf(-1), false
+	for _, ts := range seriesMap {
+		if !store.matchesLabels(ts.Labels, labelMatchers) {
+			continue
+		}
+		quantile, err := strconv.ParseFloat(ts.Labels.Get("quantile"), 64)
+		if err != nil || quantile != q {
+			continue
+		}
+		sample, ok := ts.Samples.Last()
+		if !ok || sample.Timestamp < startMs || math.IsNaN(sample.Value) {
+			continue
+		}
+		value, found = math.Max(value, sample.Value), true
+	}
+	if !found {
+		return 0, fmt.Errorf("no recent %v quantile for %s", q, metricName)
+	}
+	return value, nil
+}
+
+// counterIncrease returns how much a counter grew over samples, oldest
+// first. A counter that went down was reset, and only its growth after the
+// reset counts. Needs at least two samples.
+func counterIncrease(samples []*MetricSample) (float64, bool) {
+	if len(samples) < 2 {
+		return 0, false
+	}
+	var increase float64
+	for i := 1; i < len(samples); i++ {
+		delta := samples[i].Value - samples[i-1].Value
+		if delta < 0 {
+			delta = samples[i].Value
+		}
+		increase += delta
+	}
+	return increase, true
+}
+
+// histogramQuantile estimates the q-quantile from cumulative bucket counts
+// keyed by upper bound, interpolating linearly within the bucket the way
+// PromQL's histogram_quantile does. Returns NaN for an empty histogram.
+func histogramQuantile(q float64, buckets map[float64]float64) float64 {
+	bounds := make([]float64, 0, len(buckets))
+	for upper := range buckets {
+		bounds = append(bounds, upper)
+	}
+	sort.Float64s(bounds)
+
+	if len(bounds) == 0 || !math.IsInf(bounds[len(bounds)-1], 1) {
+		return math.NaN()
+	}
+	total := buckets[bounds[len(bounds)-1]]
+	if total == 0 {
+		return math.NaN()
+	}
+
+	rank := q * total
+	lower, lowerCount := 0.0, 0.0
+	for _, upper := range bounds {
+		count := buckets[upper]
+		if count >= rank {
+			if math.IsInf(upper, 1) {
+				// Nothing to interpolate against; the highest finite bound is the best estimate
+				return lower
+			}
+			if count == lowerCount {
+				return upper
+			}
+			return lower + (upper-lower)*(rank-lowerCount)/(count-lowerCount)
+		}
+		lower, lowerCount = upper, count
+	}
+	return lower
+}
@@ -0,0 +1,173 @@
+package prom
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+const kubeletMetrics = `# TYPE kubelet_pleg_relist_interval_seconds summary
+kubelet_pleg_relist_interval_seconds{quantile="0.5"} 1.01
+kubelet_pleg_relist_interval_seconds{quantile="0.99"} 1.2
+kubelet_pleg_relist_interval_seconds_sum 300
+kubelet_pleg_relist_interval_seconds_count 290
+`
+
+const histogramMetricsText = `# TYPE request_duration_seconds histogram
+request_duration_seconds_bucket{verb="GET",le="0.1"} 3
+request_duration_seconds_bucket{verb="GET",le="1"} 4
+request_duration_seconds_sum{verb="GET"} 0.9
+request_duration_seconds_count{verb="GET"} 5
+`
+
+func TestConvertHistogramFamily(t *testing.T) {
+	scraper := &KubernetesScraper{}
+	families, err := scraper.parseMetricsBody([]byte(histogramMetricsText))
+	if err != nil {
+		t.Fatalf("parseMetricsBody failed: %v", err)
+	}
+	name := "request_duration_seconds"
+	flat := scraper.convertHistogramFamily(name, families[name])
+
+	buckets := flat[name+"_bucket"]
+	if buckets == nil || len(buckets.TimeSeries) != 3 {
+		t.Fatalf("Expected 3 bucket series, got %+v", buckets)
+	}
+	// The +Inf bucket left out of the exposition holds the count
+	want := map[string]float64{"0.1": 3, "1": 4, "+Inf": 5}
+	for _, ts := range buckets.TimeSeries {
+		le := ts.Labels.Get("le")
+		sample, _ := ts.Samples.Last()
+		if sample.Value != want[le] {
+			t.Errorf("Expected bucket le=%s to be %v, got %v", le, want[le], sample.Value)
+		}
+		if ts.Labels.Get("__name__") != name+"_bucket" || ts.Labels.Get("verb") != "GET" {
+			t.Errorf("Expected %s_bucket with the verb label, got %s", name, ts.Labels)
+		}
+	}
+
+	count, _ := flat[name+"_count"].TimeSeries[0].Samples.Last()
+	sum, _ := flat[name+"_sum"].TimeSeries[0].Samples.Last()
+	if count.Value != 5 || sum.Value != 0.9 {
+		t.Errorf("Expected count 5 and sum 0.9, got %v and %v", count.Value, sum.Value)
+	}
+}
+
+// histogramMetrics returns the given histogram series, each with one sample
+// per value spaced 10s apart and ending now
+func histogramMetrics(name string, series map[string][]float64, extra ...string) *ScrapedMetrics {
+	now := time.Now()
+	family := &MetricFamily{Name: name}
+	for le, values := range series {
+		var samples []MetricSample
+		for i, v := range values {
+			samples = append(samples, MetricSample{
+				Timestamp: now.Add(-time.Duration(len(values)-1-i) * 10 * time.Second).UnixMilli(),
+				Value:     v,
+			})
+		}
+		lbls := labels.FromStrings(append([]string{"__name__", name, "le", le}, extra...)...)
+		family.TimeSeries = append(family.TimeSeries, createTestTimeSeries(lbls, samples...))
+	}
+	return &ScrapedMetrics{Families: map[string]*MetricFamily{name: family}}
+}
+
+func TestQueryQuantile_Histogram(t *testing.T) {
+	store := NewInMemoryStore(DefaultScrapeConfig())
+
+	// 100 observations during the window: 50 up to 100ms, all up to 1s.
+	// The le="1" bucket was reset along the way.
+	if err := store.AddMetrics(histogramMetrics("request_duration_seconds_bucket", map[string][]float64{
+		"0.1":  {1000, 1050},
+		"1":    {5000, 20, 100},
+		"+Inf": {5000, 5100},
+	}, "verb", "GET")); err != nil {
+		t.Fatalf("AddMetrics failed: %v", err)
+	}
+	// Slow requests of another verb
+	if err := store.AddMetrics(histogramMetrics("request_duration_seconds_bucket", map[string][]float64{
+		"0.1":  {0, 0},
+		"1":    {0, 0},
+		"+Inf": {0, 100},
+	}, "verb", "LIST")); err != nil {
+		t.Fatalf("AddMetrics failed: %v", err)
+	}
+
+	get := map[string]string{"verb": "GET"}
+	if got, err := store.QueryQuantile("request_duration_seconds", get, 0.5, time.Minute); err != nil || math.Abs(got-0.1) > 1e-9 {
+		t.Errorf("Expected p50 of 0.1, got %v (%v)", got, err)
+	}
+	if got, err := store.QueryQuantile("request_duration_seconds", get, 0.75, time.Minute); err != nil || math.Abs(got-0.55) > 1e-9 {
+		t.Errorf("Expected p75 of 0.55, got %v (%v)", got, err)
+	}
+	// Across both verbs half the requests exceed the highest finite bucket
+	if got, err := store.QueryQuantile("request_duration_seconds", nil, 0.99, time.Minute); err != nil || got != 1 {
+		t.Errorf("Expected p99 of 1, got %v (%v)", got, err)
+	}
+
+	if _, err := store.QueryQuantile("request_duration_seconds", get, 0.5, time.Second); err == nil {
+		t.Error("Expected error when the window holds a single scrape")
+	}
+	if _, err := store.QueryQuantile("missing_seconds", nil, 0.5, time.Minute); err == nil {
+		t.Error("Expected error for unknown metric")
+	}
+	if _, err := store.QueryQuantile("request_duration_seconds", nil, 1.5, time.Minute); err == nil {
+		t.Error("Expected error for quantile out of range")
+	}
+}
+
+func TestQueryQuantile_Summary(t *testing.T) {
+	scraper := &KubernetesScraper{}
+	families, err := scraper.parseMetricsBody([]byte(kubeletMetrics))
+	if err != nil {
+		t.Fatalf("parseMetricsBody failed: %v", err)
+	}
+	name := "kubelet_pleg_relist_interval_seconds"
+	flat := scraper.convertSummaryFamily(name, families[name])
+
+	if quantiles := flat[name]; quantiles == nil || len(quantiles.TimeSeries) != 2 {
+		t.Fatalf("Expected 2 quantile series, got %+v", flat[name])
+	}
+	if count, _ := flat[name+"_count"].TimeSeries[0].Samples.Last(); count.Value != 290 {
+		t.Errorf("Expected count 290, got %v", count.Value)
+	}
+
+	store := NewInMemoryStore(DefaultScrapeConfig())
+	if err := store.AddMetrics(&ScrapedMetrics{Families: flat}); err != nil {
+		t.Fatalf("AddMetrics failed: %v", err)
+	}
+	if got, err := store.QueryQuantile(name, nil, 0.99, time.Minute); err != nil || got != 1.2 {
+		t.Errorf("Expected p99 of 1.2, got %v (%v)", got, err)
+	}
+	if _, err := store.QueryQuantile(name, nil, 0.9, time.Minute); err == nil {
+		t.Error("Expected error for a quantile the summary doesn't expose")
+	}
+}
+
+func TestHistogramQuantile(t *testing.T) {
+	buckets := map[float64]float64{0.1: 50, 0.5: 90, 1: 100, math.Inf(1): 100}
+	tests := []struct {
+		q    float64
+		want float64
+	}{
+		{0.25, 0.05},
+		{0.5, 0.1},
+		{0.7, 0.3},
+		{0.95, 0.75},
+	}
+	for _, tt := range tests {
+		if got := histogramQuantile(tt.q, buckets); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("q=%v: expected %v, got %v", tt.q, tt.want, got)
+		}
+	}
+
+	// Observations above the highest finite bucket report that bound
+	if got := histogramQuantile(0.99, map[float64]float64{1: 10, math.Inf(1): 100}); got != 1 {
+		t.Errorf("Expected highest finite bound 1, got %v", got)
+	}
+	if got := histogramQuantile(0.5, map[float64]float64{1: 0, math.Inf(1): 0}); !math.IsNaN(got) {
+		t.Errorf("Expected NaN for empty histogram, got %v", got)
+	}
+}
@@ -0,0 +1,19 @@
+//go:build unix
+
+package prom
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lockExclusive takes an flock on f without waiting, returning
+// ErrStoreLocked if another open file holds it
+func lockExclusive(f *os.File) error {
+	err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+	if errors.Is(err, syscall.EWOULDBLOCK) {
+		return ErrStoreLocked
+	}
+	return err
+}
@@ -0,0 +1,811 @@
+package prom
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// queryLookback is how far back an instant selector looks for a sample,
+// as in Prometheus
+const queryLookback = 5 * time.Minute
+
+// QueryResult is one series of an evaluated query: its labels and its value
+// at each step where it had one
+type QueryResult struct {
+	Labels labels.Labels
+	Points []MetricSample
+}
+
+// Query is a parsed expression in the subset of PromQL the store can
+// evaluate:
+//
+//   - selectors with =, !=, =~ and !~ matchers: container_memory_working_set_bytes{namespace="web"}
+//   - rate, irate and increase over a range: rate(container_cpu_usage_seconds_total[5m])
+//   - sum, avg, min, max and count, grouped by or without labels
+//   - topk and bottomk
+//   - +, -, *, / and % between numbers and series; series are matched on
+//     all their labels except the metric name
+//
+// Unlike Prometheus, rate and increase do not extrapolate to the edges of
+// the range.
+type Query struct {
+	expr string
+	root queryNode
+}
+
+// ParseQuery parses expr
+func ParseQuery(expr string) (*Query, error) {
+	tokens, err := lexQuery(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return &Query{expr: strings.TrimSpace(expr), root: root}, nil
+}
+
+// String returns the expression the query was parsed from
+func (q *Query) String() string {
+	return q.expr
+}
+
+// Grouping returns the labels the query's outermost aggregation groups by,
+// or false when the query isn't an aggregation by labels
+func (q *Query) Grouping() ([]string, bool) {
+	agg, ok := q.root.(*aggregateNode)
+	if !ok || agg.without || len(agg.grouping) == 0 {
+		return nil, false
+	}
+	return slices.Clone(agg.grouping), true
+}
+
+// Range evaluates the query at each step from start to end. Results are
+// sorted by labels. A number evaluates to a single series without labels.
+func (q *Query) Range(store MetricsStore, start, end time.Time, step time.Duration) ([]QueryResult, error) {
+	if end.Before(start) {
+		return nil, fmt.Errorf("query end %s is before start %s", end, start)
+	}
+	if step <= 0 {
+		return nil, fmt.Errorf("query step must be positive")
+	}
+
+	ev := &queryEvaluator{series: make(map[*selectorNode][]storedSeries)}
+	ev.fetch(store, q.root, start, end)
+
+	byKey := make(map[string]*QueryResult)
+	for t := start; !t.After(end); t = t.Add(step) {
+		ts := t.UnixMilli()
+		v, err := ev.eval(q.root, ts)
+		if err != nil {
+			return nil, err
+		}
+		if v.isScalar {
+			v.vector = []vectorSample{{value: v.scalar}}
+		}
+		for _, s := range v.vector {
+			key := s.labels.String()
+			result, ok := byKey[key]
+			if !ok {
+				result = &QueryResult{Labels: s.labels}
+				byKey[key] = result
+			}
+			result.Points = append(result.Points, MetricSample{Timestamp: ts, Value: s.value})
+		}
+	}
+
+	results := make([]QueryResult, 0, len(byKey))
+	for _, result := range byKey {
+		results = append(results, *result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Labels.String() < results[j].Labels.String()
+	})
+	return results, nil
+}
+
+// Query AST
+
+type queryNode interface{}
+
+type numberNode struct {
+	value float64
+}
+
+type selectorNode struct {
+	metric   string
+	matchers []*labels.Matcher
+}
+
+// rangeFuncNode applies rate, irate or increase to the samples of a
+// selector within window
+type rangeFuncNode struct {
+	fn     string
+	sel    *selectorNode
+	window time.Duration
+}
+
+type aggregateNode struct {
+	op       string
+	grouping []string
+	without  bool
+	param    float64 // k of topk and bottomk
+	expr     queryNode
+}
+
+type binaryNode struct {
+	op       string
+	lhs, rhs queryNode
+}
+
+var (
+	queryAggregations = []string{"sum", "avg", "min", "max", "count", "topk", "bottomk"}
+	queryRangeFuncs   = []string{"rate", "irate", "increase"}
+)
+
+// QueryKeywords returns the aggregation and function names queries may use
+func QueryKeywords() []string {
+	return append(slices.Clone(queryAggregations), queryRangeFuncs...)
+}
+
+// Lexer
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokRange // the contents of [...]
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func lexQuery(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := rune(expr[i])
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case isIdentStart(c):
+			start := i
+			for i < len(expr) && (isIdentStart(rune(expr[i])) || unicode.IsDigit(rune(expr[i]))) {
+				i++
+			}
+			tokens = append(tokens, token{tokIdent, expr[start:i], start})
+		case unicode.IsDigit(c) || (c == '.' && i+1 < len(expr) && unicode.IsDigit(rune(expr[i+1]))):
+			start := i
+			for i < len(expr) && (unicode.IsDigit(rune(expr[i])) || expr[i] == '.') {
+				i++
+			}
+			if i < len(expr) && (expr[i] == 'e' || expr[i] == 'E') {
+				i++
+				if i < len(expr) && (expr[i] == '+' || expr[i] == '-') {
+					i++
+				}
+				for i < len(expr) && unicode.IsDigit(rune(expr[i])) {
+					i++
+				}
+			}
+			tokens = append(tokens, token{tokNumber, expr[start:i], start})
+		case c == '"' || c == '\'':
+			quoted, ok := quotedPrefix(expr[i:])
+			if !ok {
+				return nil, fmt.Errorf("unterminated string at position %d", i)
+			}
+			tokens = append(tokens, token{tokString, quoted, i})
+			i += len(quoted)
+		case c == '[':
+			end := strings.IndexByte(expr[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated range at position %d", i)
+			}
+			tokens = append(tokens, token{tokRange, strings.TrimSpace(expr[i+1 : i+end]), i})
+			i += end + 1
+		default:
+			op := string(c)
+			if i+1 < len(expr) {
+				if two := expr[i : i+2]; two == "!=" || two == "=~" || two == "!~" {
+					op = two
+				}
+			}
+			if len(op) == 1 && !strings.ContainsRune("+-*/%(){},=", c) {
+				return nil, fmt.Errorf("unexpected %q at position %d", op, i)
+			}
+			tokens = append(tokens, token{tokOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{tokEOF, "end of query", len(expr)}), nil
+}
+
+func isIdentStart(c rune) bool {
+	return c == '_' || c == ':' || (c < unicode.MaxASCII && unicode.IsLetter(c))
+}
+
+// Parser
+
+type queryParser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *queryParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *queryParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is the operator or keyword text
+func (p *queryParser) accept(text string) bool {
+	if tok := p.peek(); (tok.kind == tokOp || tok.kind == tokIdent) && tok.text == text {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *queryParser) expect(text string) error {
+	if !p.accept(text) {
+		tok := p.peek()
+		return fmt.Errorf("expected %q at position %d, got %q", text, tok.pos, tok.text)
+	}
+	return nil
+}
+
+// parseExpr parses additions and subtractions of terms
+func (p *queryParser) parseExpr() (queryNode, error) {
+	lhs, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokOp || (tok.text != "+" && tok.text != "-") {
+			return lhs, nil
+		}
+		p.next()
+		rhs, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		lhs = &binaryNode{op: tok.text, lhs: lhs, rhs: rhs}
+	}
+}
+
+// parseTerm parses multiplications, divisions and modulos of unary expressions
+func (p *queryParser) parseTerm() (queryNode, error) {
+	lhs, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		tok := p.peek()
+		if tok.kind != tokOp || (tok.text != "*" && tok.text != "/" && tok.text != "%") {
+			return lhs, nil
+		}
+		p.next()
+		rhs, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		lhs = &binaryNode{op: tok.text, lhs: lhs, rhs: rhs}
+	}
+}
+
+func (p *queryParser) parseUnary() (queryNode, error) {
+	if p.accept("-") {
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &binaryNode{op: "*", lhs: &numberNode{value: -1}, rhs: expr}, nil
+	}
+	p.accept("+")
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (queryNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNumber:
+		v, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at position %d", tok.text, tok.pos)
+		}
+		return &numberNode{value: v}, nil
+	case tokOp:
+		if tok.text == "(" {
+			expr, err := p.parseExpr()
+			if err != nil {
+				return nil, err
+			}
+			return expr, p.expect(")")
+		}
+		if tok.text == "{" {
+			return nil, fmt.Errorf("selector at position %d needs a metric name", tok.pos)
+		}
+	case tokIdent:
+		switch {
+		case slices.Contains(queryAggregations, tok.text):
+			return p.parseAggregation(tok.text)
+		case slices.Contains(queryRangeFuncs, tok.text):
+			return p.parseRangeFunc(tok.text)
+		}
+		sel, err := p.parseSelector(tok.text)
+		if err != nil {
+			return nil, err
+		}
+		if next := p.peek(); next.kind == tokRange {
+			return nil, fmt.Errorf("range %s[%s] can only be used in %s", tok.text, next.text, strings.Join(queryRangeFuncs, ", "))
+		}
+		return sel, nil
+	case tokEOF:
+		return nil, fmt.Errorf("unexpected end of query")
+	}
+	return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+}
+
+// parseSelector parses the optional {matchers} after a metric name
+func (p *queryParser) parseSelector(metric string) (*selectorNode, error) {
+	sel := &selectorNode{metric: metric}
+	if !p.accept("{") {
+		return sel, nil
+	}
+	for !p.accept("}") {
+		name := p.next()
+		if name.kind != tokIdent {
+			return nil, fmt.Errorf("expected a label name at position %d, got %q", name.pos, name.text)
+		}
+		op := p.next()
+		matchType, ok := map[string]labels.MatchType{
+			"=":  labels.MatchEqual,
+			"!=": labels.MatchNotEqual,
+			"=~": labels.MatchRegexp,
+			"!~": labels.MatchNotRegexp,
+		}[op.text]
+		if !ok || op.kind != tokOp {
+			return nil, fmt.Errorf("expected =, !=, =~ or !~ after %s at position %d", name.text, op.pos)
+		}
+		value := p.next()
+		if value.kind != tokString {
+			return nil, fmt.Errorf("expected a quoted value for %s at position %d", name.text, value.pos)
+		}
+		unquoted, err := unquote(value.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid string %s at position %d", value.text, value.pos)
+		}
+		m, err := labels.NewMatcher(matchType, name.text, unquoted)
+		if err != nil {
+			return nil, fmt.Errorf("invalid matcher for %s: %w", name.text, err)
+		}
+		sel.matchers = append(sel.matchers, m)
+		if !p.accept(",") && p.peek().text != "}" {
+			tok := p.peek()
+			return nil, fmt.Errorf("expected , or } at position %d, got %q", tok.pos, tok.text)
+		}
+	}
+	return sel, nil
+}
+
+func (p *queryParser) parseRangeFunc(fn string) (queryNode, error) {
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	metric := p.next()
+	if metric.kind != tokIdent {
+		return nil, fmt.Errorf("%s needs a metric, got %q at position %d", fn, metric.text, metric.pos)
+	}
+	sel, err := p.parseSelector(metric.text)
+	if err != nil {
+		return nil, err
+	}
+	rng := p.next()
+	if rng.kind != tokRange {
+		return nil, fmt.Errorf("%s needs a range such as %s[5m]", fn, metric.text)
+	}
+	window, err := model.ParseDuration(rng.text)
+	if err != nil || window <= 0 {
+		return nil, fmt.Errorf("invalid range [%s] at position %d", rng.text, rng.pos)
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	return &rangeFuncNode{fn: fn, sel: sel, window: time.Duration(window)}, nil
+}
+
+func (p *queryParser) parseAggregation(op string) (queryNode, error) {
+	agg := &aggregateNode{op: op}
+	grouped, err := p.parseGrouping(agg)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("("); err != nil {
+		return nil, err
+	}
+	if op == "topk" || op == "bottomk" {
+		k := p.next()
+		if k.kind != tokNumber {
+			return nil, fmt.Errorf("%s needs a number of series first, got %q", op, k.text)
+		}
+		if agg.param, err = strconv.ParseFloat(k.text, 64); err != nil || agg.param < 1 {
+			return nil, fmt.Errorf("invalid %s count %q", op, k.text)
+		}
+		if err := p.expect(","); err != nil {
+			return nil, err
+		}
+	}
+	if agg.expr, err = p.parseExpr(); err != nil {
+		return nil, err
+	}
+	if err := p.expect(")"); err != nil {
+		return nil, err
+	}
+	if !grouped {
+		if _, err := p.parseGrouping(agg); err != nil {
+			return nil, err
+		}
+	}
+	return agg, nil
+}
+
+// parseGrouping parses an optional "by (labels)" or "without (labels)"
+func (p *queryParser) parseGrouping(agg *aggregateNode) (bool, error) {
+	switch {
+	case p.accept("by"):
+	case p.accept("without"):
+		agg.without = true
+	default:
+		return false, nil
+	}
+	if err := p.expect("("); err != nil {
+		return false, err
+	}
+	for !p.accept(")") {
+		label := p.next()
+		if label.kind != tokIdent {
+			return false, fmt.Errorf("expected a label name at position %d, got %q", label.pos, label.text)
+		}
+		agg.grouping = append(agg.grouping, label.text)
+		if !p.accept(",") && p.peek().text != ")" {
+			tok := p.peek()
+			return false, fmt.Errorf("expected , or ) at position %d, got %q", tok.pos, tok.text)
+		}
+	}
+	return true, nil
+}
+
+// quotedPrefix returns the string literal s starts with, quoted with the
+// quote character s starts with
+func quotedPrefix(s string) (string, bool) {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case s[0]:
+			return s[:i+1], true
+		}
+	}
+	return "", false
+}
+
+// unquote unquotes a double- or single-quoted PromQL string
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") {
+		s = `"` + strings.ReplaceAll(strings.ReplaceAll(s[1:len(s)-1], `\'`, `'`), `"`, `\"`) + `"`
+	}
+	return strconv.Unquote(s)
+}
+
+// Evaluation
+
+type vectorSample struct {
+	labels labels.Labels
+	value  float64
+}
+
+type evalValue struct {
+	isScalar bool
+	scalar   float64
+	vector   []vectorSample
+}
+
+type storedSeries struct {
+	labels  labels.Labels
+	samples []*MetricSample
+}
+
+// queryEvaluator evaluates a query at successive steps from series fetched
+// once for the whole range
+type queryEvaluator struct {
+	series map[*selectorNode][]storedSeries
+}
+
+// fetch loads the samples of every selector in node, reaching back from
+// start as far as the selector looks
+func (ev *queryEvaluator) fetch(store MetricsStore, node queryNode, start, end time.Time) {
+	switch n := node.(type) {
+	case *selectorNode:
+		ev.fetchSelector(store, n, start.Add(-queryLookback), end)
+	case *rangeFuncNode:
+		ev.fetchSelector(store, n.sel, start.Add(-n.window), end)
+	case *aggregateNode:
+		ev.fetch(store, n.expr, start, end)
+	case *binaryNode:
+		ev.fetch(store, n.lhs, start, end)
+		ev.fetch(store, n.rhs, start, end)
+	}
+}
+
+func (ev *queryEvaluator) fetchSelector(store MetricsStore, sel *selectorNode, from, to time.Time) {
+	// The store matches equality (with * as a wildcard) itself; the other
+	// matchers are applied to the labels of what it returns
+	equal := make(map[string]string)
+	for _, m := range sel.matchers {
+		if m.Type == labels.MatchEqual && !strings.Contains(m.Value, "*") {
+			equal[m.Name] = m.Value
+		}
+	}
+
+	// The store only fails for metrics it doesn't have, which like in
+	// Prometheus select nothing
+	seriesSamples, err := store.QueryRangePerSeries(sel.metric, equal, from, to)
+	if err != nil {
+		return
+	}
+	for key, samples := range seriesSamples {
+		lbls, err := parseSeriesKey(key)
+		if err != nil {
+			continue
+		}
+		matched := true
+		for _, m := range sel.matchers {
+			matched = matched && m.Matches(lbls.Get(m.Name))
+		}
+		if matched {
+			ev.series[sel] = append(ev.series[sel], storedSeries{labels: lbls, samples: samples})
+		}
+	}
+}
+
+func (ev *queryEvaluator) eval(node queryNode, ts int64) (evalValue, error) {
+	switch n := node.(type) {
+	case *numberNode:
+		return evalValue{isScalar: true, scalar: n.value}, nil
+	case *selectorNode:
+		return evalValue{vector: ev.instant(n, ts)}, nil
+	case *rangeFuncNode:
+		return evalValue{vector: ev.rangeFunc(n, ts)}, nil
+	case *aggregateNode:
+		inner, err := ev.eval(n.expr, ts)
+		if err != nil {
+			return evalValue{}, err
+		}
+		if inner.isScalar {
+			return evalValue{}, fmt.Errorf("%s needs series, not a number", n.op)
+		}
+		return evalValue{vector: aggregate(n, inner.vector)}, nil
+	case *binaryNode:
+		lhs, err := ev.eval(n.lhs, ts)
+		if err != nil {
+			return evalValue{}, err
+		}
+		rhs, err := ev.eval(n.rhs, ts)
+		if err != nil {
+			return evalValue{}, err
+		}
+		return binaryOp(n.op, lhs, rhs)
+	}
+	return evalValue{}, fmt.Errorf("unsupported expression %T", node)
+}
+
+// instant returns the latest sample of each series at ts, looking back at
+// most queryLookback
+func (ev *queryEvaluator) instant(sel *selectorNode, ts int64) []vectorSample {
+	var vector []vectorSample
+	from := ts - queryLookback.Milliseconds()
+	for _, s := range ev.series[sel] {
+		for i := len(s.samples) - 1; i >= 0; i-- {
+			sample := s.samples[i]
+			if sample.Timestamp > ts {
+				continue
+			}
+			if sample.Timestamp > from {
+				vector = append(vector, vectorSample{labels: s.labels, value: sample.Value})
+			}
+			break
+		}
+	}
+	return vector
+}
+
+// rangeFunc applies a range function to the samples of each series in
+// (ts-window, ts]. The metric name is dropped as in Prometheus.
+func (ev *queryEvaluator) rangeFunc(n *rangeFuncNode, ts int64) []vectorSample {
+	var vector []vectorSample
+	from := ts - n.window.Milliseconds()
+	for _, s := range ev.series[n.sel] {
+		var window []*MetricSample
+		for _, sample := range s.samples {
+			if sample.Timestamp > from && sample.Timestamp <= ts {
+				window = append(window, sample)
+			}
+		}
+		if n.fn == "irate" && len(window) > 2 {
+			window = window[len(window)-2:]
+		}
+		increase, ok := counterIncrease(window)
+		if !ok {
+			continue
+		}
+		value := increase
+		if n.fn != "increase" {
+			elapsed := float64(window[len(window)-1].Timestamp-window[0].Timestamp) / 1000
+			if elapsed <= 0 {
+				continue
+			}
+			value = increase / elapsed
+		}
+		vector = append(vector, vectorSample{labels: s.labels.MatchLabels(false), value: value})
+	}
+	return vector
+}
+
+func aggregate(n *aggregateNode, vector []vectorSample) []vectorSample {
+	groups := make(map[string][]vectorSample)
+	var keys []string
+	groupLabels := make(map[string]labels.Labels)
+	for _, s := range vector {
+		var lbls labels.Labels
+		if n.without {
+			lbls = s.labels.MatchLabels(false, n.grouping...)
+		} else {
+			lbls = s.labels.MatchLabels(true, n.grouping...)
+		}
+		key := lbls.String()
+		if _, ok := groups[key]; !ok {
+			keys = append(keys, key)
+			groupLabels[key] = lbls
+		}
+		groups[key] = append(groups[key], s)
+	}
+	sort.Strings(keys)
+
+	var result []vectorSample
+	for _, key := range keys {
+		group := groups[key]
+		switch n.op {
+		case "topk", "bottomk":
+			sort.SliceStable(group, func(i, j int) bool {
+				if n.op == "topk" {
+					return group[i].value > group[j].value
+				}
+				return group[i].value < group[j].value
+			})
+			result = append(result, group[:min(len(group), int(n.param))]...)
+			continue
+		}
+
+		v := group[0].value
+		for _, s := range group[1:] {
+			switch n.op {
+			case "sum", "avg":
+				v += s.value
+			case "min":
+				v = math.Min(v, s.value)
+			case "max":
+				v = math.Max(v, s.value)
+			}
+		}
+		switch n.op {
+		case "avg":
+			v /= float64(len(group))
+		case "count":
+			v = float64(len(group))
+		}
+		result = append(result, vectorSample{labels: groupLabels[key], value: v})
+	}
+	return result
+}
+
+// binaryOp applies op to numbers and series. Series on both sides are matched
+// one-to-one on their labels without the metric name.
+func binaryOp(op string, lhs, rhs evalValue) (evalValue, error) {
+	switch {
+	case lhs.isScalar && rhs.isScalar:
+		return evalValue{isScalar: true, scalar: arithmetic(op, lhs.scalar, rhs.scalar)}, nil
+	case rhs.isScalar:
+		vector := make([]vectorSample, len(lhs.vector))
+		for i, s := range lhs.vector {
+			vector[i] = vectorSample{labels: s.labels.MatchLabels(false), value: arithmetic(op, s.value, rhs.scalar)}
+		}
+		return evalValue{vector: vector}, nil
+	case lhs.isScalar:
+		vector := make([]vectorSample, len(rhs.vector))
+		for i, s := range rhs.vector {
+			vector[i] = vectorSample{labels: s.labels.MatchLabels(false), value: arithmetic(op, lhs.scalar, s.value)}
+		}
+		return evalValue{vector: vector}, nil
+	}
+
+	byLabels := make(map[string]float64, len(rhs.vector))
+	for _, s := range rhs.vector {
+		key := s.labels.MatchLabels(false).String()
+		if _, ok := byLabels[key]; ok {
+			return evalValue{}, fmt.Errorf("several series on the right of %s have the labels %s; aggregate them first", op, key)
+		}
+		byLabels[key] = s.value
+	}
+	var vector []vectorSample
+	for _, s := range lhs.vector {
+		lbls := s.labels.MatchLabels(false)
+		if v, ok := byLabels[lbls.String()]; ok {
+			vector = append(vector, vectorSample{labels: lbls, value: arithmetic(op, s.value, v)})
+		}
+	}
+	return evalValue{vector: vector}, nil
+}
+
+func arithmetic(op string, a, b float64) float64 {
+	switch op {
+	case "+":
+		return a + b
+	case "-":
+		return a - b
+	case "*":
+		return a * b
+	case "/":
+		return a / b
+	case "%":
+		return math.Mod(a, b)
+	}
+	return math.NaN()
+}
+
+// parseSeriesKey parses a series key back into labels. Keys are written by
+// labels.Labels.String(): {__name__="metric", code="200"}
+func parseSeriesKey(key string) (labels.Labels, error) {
+	rest := strings.TrimSuffix(strings.TrimPrefix(key, "{"), "}")
+	var lbls []labels.Label
+	for rest != "" {
+		name, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid series key %q", key)
+		}
+		quoted, err := strconv.QuotedPrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid series key %q: %w", key, err)
+		}
+		unquoted, _ := strconv.Unquote(quoted)
+		lbls = append(lbls, labels.Label{Name: strings.TrimSpace(name), Value: unquoted})
+		rest = strings.TrimPrefix(strings.TrimPrefix(value[len(quoted):], ","), " ")
+	}
+	return labels.New(lbls...), nil
+}
@@ -0,0 +1,297 @@
+package prom
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// queryTestStore returns a store with the given series, each with one sample
+// per value spaced 10s apart and ending at now
+func queryTestStore(t *testing.T, now time.Time, series map[string][]float64) *InMemoryStore {
+	t.Helper()
+	store := NewInMemoryStore(DefaultScrapeConfig())
+	for key, values := range series {
+		lbls, err := parseSeriesKey(key)
+		if err != nil {
+			t.Fatalf("parseSeriesKey(%q) failed: %v", key, err)
+		}
+		var samples []MetricSample
+		for i, v := range values {
+			samples = append(samples, MetricSample{
+				Timestamp: now.Add(-time.Duration(len(values)-1-i) * 10 * time.Second).UnixMilli(),
+				Value:     v,
+			})
+		}
+		name := lbls.Get(labels.MetricName)
+		if err := store.AddMetrics(&ScrapedMetrics{Families: map[string]*MetricFamily{
+			name: {Name: name, TimeSeries: []*TimeSeries{createTestTimeSeries(lbls, samples...)}},
+		}}); err != nil {
+			t.Fatalf("AddMetrics failed: %v", err)
+		}
+	}
+	return store
+}
+
+// queryAt evaluates expr at now and returns the value of each series by its
+// labels
+func queryAt(t *testing.T, store MetricsStore, expr string, now time.Time) map[string]float64 {
+	t.Helper()
+	q, err := ParseQuery(expr)
+	if err != nil {
+		t.Fatalf("ParseQuery(%q) failed: %v", expr, err)
+	}
+	results, err := q.Range(store, now, now, time.Second)
+	if err != nil {
+		t.Fatalf("%s: Range failed: %v", expr, err)
+	}
+	values := make(map[string]float64, len(results))
+	for _, r := range results {
+		if len(r.Points) != 1 {
+			t.Fatalf("%s: expected one point for %s, got %d", expr, r.Labels, len(r.Points))
+		}
+		values[r.Labels.String()] = r.Points[0].Value
+	}
+	return values
+}
+
+func TestParseQuery_Errors(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{"", "unexpected end of query"},
+		{"up{", "expected a label name"},
+		{`up{job="a"`, "expected , or }"},
+		{`up{job=a}`, "quoted value"},
+		{`up{job=~"("}`, "invalid matcher"},
+		{"up[5m]", "can only be used in"},
+		{"rate(up)", "needs a range"},
+		{"rate(up[5x])", "invalid range"},
+		{"topk(up)", "needs a number of series"},
+		{"sum by job (up)", `expected "("`},
+		{"up @ 5", `unexpected "@"`},
+		{"up up", `unexpected "up"`},
+		{`up{job="a}`, "unterminated string"},
+	}
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.expr)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseQuery(%q): expected error containing %q, got %v", tt.expr, tt.want, err)
+		}
+	}
+}
+
+func TestQuery_Selectors(t *testing.T) {
+	now := time.Now()
+	store := queryTestStore(t, now, map[string][]float64{
+		`{__name__="http_requests", code="200", job="api"}`: {10},
+		`{__name__="http_requests", code="500", job="api"}`: {2},
+		`{__name__="http_requests", code="200", job="web"}`: {7},
+	})
+
+	tests := []struct {
+		expr string
+		want map[string]float64
+	}{
+		{`http_requests{job="api", code="200"}`, map[string]float64{`{__name__="http_requests", code="200", job="api"}`: 10}},
+		{`http_requests{code!="200"}`, map[string]float64{`{__name__="http_requests", code="500", job="api"}`: 2}},
+		{`http_requests{code=~"2.."}`, map[string]float64{
+			`{__name__="http_requests", code="200", job="api"}`: 10,
+			`{__name__="http_requests", code="200", job="web"}`: 7,
+		}},
+		{`http_requests{job!~'a.*'}`, map[string]float64{`{__name__="http_requests", code="200", job="web"}`: 7}},
+		{`missing_metric`, map[string]float64{}},
+	}
+	for _, tt := range tests {
+		got := queryAt(t, store, tt.expr, now)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.expr, tt.want, got)
+		}
+		for key, want := range tt.want {
+			if got[key] != want {
+				t.Errorf("%s: expected %s = %v, got %v", tt.expr, key, want, got[key])
+			}
+		}
+	}
+}
+
+func TestQuery_RangeFuncs(t *testing.T) {
+	now := time.Now()
+	// 5/s, with a counter reset between the last two samples
+	store := queryTestStore(t, now, map[string][]float64{
+		`{__name__="cpu_seconds_total", pod="a"}`: {100, 150, 50},
+	})
+
+	tests := []struct {
+		expr string
+		want float64
+	}{
+		{"rate(cpu_seconds_total[1m])", 5},
+		{"increase(cpu_seconds_total[1m])", 100},
+		{"irate(cpu_seconds_total[1m])", 5},
+		{"rate(cpu_seconds_total[15s])", 5},
+	}
+	for _, tt := range tests {
+		got := queryAt(t, store, tt.expr, now)
+		// The metric name is dropped
+		if v, ok := got[`{pod="a"}`]; !ok || math.Abs(v-tt.want) > 0.01 {
+			t.Errorf("%s: expected %v, got %v", tt.expr, tt.want, got)
+		}
+	}
+
+	// A single sample in the window has no rate
+	if got := queryAt(t, store, "rate(cpu_seconds_total[5s])", now); len(got) != 0 {
+		t.Errorf("Expected no rate from one sample, got %v", got)
+	}
+}
+
+func TestQuery_Aggregations(t *testing.T) {
+	now := time.Now()
+	store := queryTestStore(t, now, map[string][]float64{
+		`{__name__="mem", namespace="web", pod="a"}`: {100},
+		`{__name__="mem", namespace="web", pod="b"}`: {300},
+		`{__name__="mem", namespace="db", pod="c"}`:  {50},
+	})
+
+	tests := []struct {
+		expr string
+		want map[string]float64
+	}{
+		{"sum(mem)", map[string]float64{"{}": 450}},
+		{"sum by (namespace) (mem)", map[string]float64{`{namespace="web"}`: 400, `{namespace="db"}`: 50}},
+		{"avg(mem) by (namespace)", map[string]float64{`{namespace="web"}`: 200, `{namespace="db"}`: 50}},
+		{"max without (pod) (mem)", map[string]float64{`{namespace="web"}`: 300, `{namespace="db"}`: 50}},
+		{"min(mem)", map[string]float64{"{}": 50}},
+		{"count by (namespace) (mem)", map[string]float64{`{namespace="web"}`: 2, `{namespace="db"}`: 1}},
+		{"topk(2, mem)", map[string]float64{
+			`{__name__="mem", namespace="web", pod="b"}`: 300,
+			`{__name__="mem", namespace="web", pod="a"}`: 100,
+		}},
+		{"bottomk by (namespace) (1, mem)", map[string]float64{
+			`{__name__="mem", namespace="web", pod="a"}`: 100,
+			`{__name__="mem", namespace="db", pod="c"}`:  50,
+		}},
+	}
+	for _, tt := range tests {
+		got := queryAt(t, store, tt.expr, now)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.expr, tt.want, got)
+		}
+		for key, want := range tt.want {
+			if got[key] != want {
+				t.Errorf("%s: expected %s = %v, got %v", tt.expr, key, want, got[key])
+			}
+		}
+	}
+}
+
+func TestQuery_Arithmetic(t *testing.T) {
+	now := time.Now()
+	store := queryTestStore(t, now, map[string][]float64{
+		`{__name__="used", pod="a"}`:  {25},
+		`{__name__="used", pod="b"}`:  {10},
+		`{__name__="limit", pod="a"}`: {100},
+		`{__name__="limit", pod="c"}`: {100},
+	})
+
+	tests := []struct {
+		expr string
+		want map[string]float64
+	}{
+		{"1 + 2 * 3", map[string]float64{"{}": 7}},
+		{"(1 + 2) * 3", map[string]float64{"{}": 9}},
+		{"-used / 5", map[string]float64{`{pod="a"}`: -5, `{pod="b"}`: -2}},
+		{"100 * used / limit", map[string]float64{`{pod="a"}`: 25}},
+		{"used % 4", map[string]float64{`{pod="a"}`: 1, `{pod="b"}`: 2}},
+		{"sum(used) - sum(limit)", map[string]float64{"{}": -165}},
+	}
+	for _, tt := range tests {
+		got := queryAt(t, store, tt.expr, now)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.expr, tt.want, got)
+		}
+		for key, want := range tt.want {
+			if got[key] != want {
+				t.Errorf("%s: expected %s = %v, got %v", tt.expr, key, want, got[key])
+			}
+		}
+	}
+
+	// Series without a match on the other side are left out
+	q, err := ParseQuery("sum(used) / limit")
+	if err != nil {
+		t.Fatalf("ParseQuery failed: %v", err)
+	}
+	if results, err := q.Range(store, now, now, time.Second); err != nil || len(results) != 0 {
+		t.Errorf("Expected no matches between {} and pods, got %v (%v)", results, err)
+	}
+}
+
+func TestQuery_Range(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	store := queryTestStore(t, now, map[string][]float64{
+		`{__name__="up", job="api"}`: {1, 1, 0, 1},
+	})
+
+	q, err := ParseQuery(`up{job="api"}`)
+	if err != nil {
+		t.Fatalf("ParseQuery failed: %v", err)
+	}
+	if q.String() != `up{job="api"}` {
+		t.Errorf("Expected the expression back, got %q", q.String())
+	}
+	results, err := q.Range(store, now.Add(-30*time.Second), now, 10*time.Second)
+	if err != nil {
+		t.Fatalf("Range failed: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Expected 1 series, got %d", len(results))
+	}
+	var values []float64
+	for _, p := range results[0].Points {
+		values = append(values, p.Value)
+	}
+	if len(values) != 4 || values[2] != 0 || values[3] != 1 {
+		t.Errorf("Expected [1 1 0 1], got %v", values)
+	}
+
+	if _, err := q.Range(store, now, now.Add(-time.Minute), time.Second); err == nil {
+		t.Error("Expected error for end before start")
+	}
+	if _, err := q.Range(store, now, now, 0); err == nil {
+		t.Error("Expected error for zero step")
+	}
+}
+
+func TestParseSeriesKey(t *testing.T) {
+	want := labels.FromStrings("__name__", "http_requests", "path", `/a "b", c`, "code", "200")
+	got, err := parseSeriesKey(want.String())
+	if err != nil {
+		t.Fatalf("parseSeriesKey failed: %v", err)
+	}
+	if !labels.Equal(got, want) {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if got, err := parseSeriesKey("{}"); err != nil || len(got) != 0 {
+		t.Errorf("Expected no labels, got %v (%v)", got, err)
+	}
+	if _, err := parseSeriesKey(`{code=200}`); err == nil {
+		t.Error("Expected error for unquoted value")
+	}
+}
+
+func TestGetLabelNames(t *testing.T) {
+	store := queryTestStore(t, time.Now(), map[string][]float64{
+		`{__name__="up", job="api"}`:                 {1},
+		`{__name__="mem", namespace="web", pod="a"}`: {1},
+	})
+	got := store.GetLabelNames()
+	want := []string{"job", "namespace", "pod"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
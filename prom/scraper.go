@@ -3,6 +3,8 @@ package prom
 import (
 	"context"
 	"fmt"
+	"maps"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
@@ -22,6 +24,9 @@ type KubernetesScraper struct {
 	clientset  kubernetes.Interface
 	restClient rest.Interface
 
+	// extraMetrics matches the configured extra metric names per component
+	extraMetrics map[ComponentType]*regexp.Regexp
+
 	// Discovered targets
 	targetsMutex sync.RWMutex
 	targets      map[ComponentType][]*ScrapeTarget
@@ -34,15 +39,21 @@ func NewKubernetesScraper(kubeConfig *rest.Config, config *ScrapeConfig) (*Kuber
 		return nil, fmt.Errorf("creating kubernetes client: %w", err)
 	}
 
+	extraMetrics, err := compileExtraMetrics(config.ExtraMetrics)
+	if err != nil {
+		return nil, err
+	}
+
 	// Use the CoreV1 REST client for all operations
 	restClient := clientset.CoreV1().RESTClient()
 
 	scraper := &KubernetesScraper{
-		config:     config,
-		kubeConfig: kubeConfig,
-		clientset:  clientset,
-		restClient: restClient,
-		targets:    make(map[ComponentType][]*ScrapeTarget),
+		config:       config,
+		kubeConfig:   kubeConfig,
+		clientset:    clientset,
+		restClient:   restClient,
+		extraMetrics: extraMetrics,
+		targets:      make(map[ComponentType][]*ScrapeTarget),
 	}
 
 	return scraper, nil
@@ -78,7 +89,8 @@ func (ks *KubernetesScraper) Stop() error {
 
 // ScrapeComponent manually triggers a scrape for a specific component
 // For node-based components (kubelet, cAdvisor), this scrapes ALL nodes
-// and merges the results with proper node labels added.
+// and merges the results with proper node labels added. Control-plane
+// components are scraped on every pod, labeled with the pod name.
 func (ks *KubernetesScraper) ScrapeComponent(ctx context.Context, component ComponentType) (*ScrapedMetrics, error) {
 	ks.targetsMutex.RLock()
 	targets, exists := ks.targets[component]
@@ -92,13 +104,21 @@ func (ks *KubernetesScraper) ScrapeComponent(ctx context.Context, component Comp
 		return ks.scrapeAllTargets(ctx, targets)
 	}
 
+	// Control-plane components run one pod per control-plane node. Only the
+	// elected leader of the scheduler and controller-manager does any work,
+	// and etcd reports per member, so every pod is scraped.
+	if _, ok := ControlPlaneMetricAllowlist[component]; ok {
+		return ks.scrapeAllTargets(ctx, targets)
+	}
+
 	// For other components, scrape the first available target
 	target := targets[0]
 	return ks.scrapeTarget(ctx, target)
 }
 
 // scrapeAllTargets scrapes all targets IN PARALLEL and merges results into a single ScrapedMetrics
-// This is used for node-based components where we need metrics from all nodes
+// This is used for node-based components where we need metrics from all nodes,
+// and for control-plane components running on several nodes
 func (ks *KubernetesScraper) scrapeAllTargets(ctx context.Context, targets []*ScrapeTarget) (*ScrapedMetrics, error) {
 	if len(targets) == 0 {
 		return nil, fmt.Errorf("no targets to scrape")
@@ -151,6 +171,13 @@ func (ks *KubernetesScraper) scrapeAllTargets(ctx context.Context, targets []*Sc
 						Value: result.target.NodeName,
 					})
 				}
+				// Add pod label if this is a pod-based target
+				if result.target.PodName != "" {
+					ts.Labels = append(ts.Labels, labels.Label{
+						Name:  "pod",
+						Value: result.target.PodName,
+					})
+				}
 			}
 
 			// Merge into existing family or create new
@@ -387,6 +414,10 @@ func (ks *KubernetesScraper) scrapeTarget(ctx context.Context, target *ScrapeTar
 	case ComponentEtcd, ComponentScheduler, ComponentControllerManager, ComponentKubeProxy:
 		// Pod-based components via pod proxy
 		podNameWithPort := fmt.Sprintf("%s:%d", target.PodName, target.Port)
+		// The scheduler and controller-manager only serve metrics over HTTPS
+		if target.Component == ComponentScheduler || target.Component == ComponentControllerManager {
+			podNameWithPort = "https:" + podNameWithPort
+		}
 		endpoint = fmt.Sprintf("namespaces/%s/pods/%s/proxy/%s", target.Namespace, podNameWithPort, target.Path)
 		result = ks.restClient.Get().
 			Namespace(target.Namespace).
@@ -440,11 +471,18 @@ func (ks *KubernetesScraper) scrapeTarget(ctx context.Context, target *ScrapeTar
 	// Convert to our internal format, filtering to only allowed metrics
 	metricFamilies := make(map[string]*MetricFamily)
 	for name, family := range families {
-		if !DefaultMetricAllowlist[name] {
+		family, ok := ks.allowedFamily(target.Component, name, family)
+		if !ok {
 			continue
 		}
-		metricFamily := ks.convertMetricFamily(name, family)
-		metricFamilies[name] = metricFamily
+		switch family.GetType() {
+		case dto.MetricType_HISTOGRAM:
+			maps.Copy(metricFamilies, ks.convertHistogramFamily(name, family))
+		case dto.MetricType_SUMMARY:
+			maps.Copy(metricFamilies, ks.convertSummaryFamily(name, family))
+		default:
+			metricFamilies[name] = ks.convertMetricFamily(name, family)
+		}
 	}
 
 	return &ScrapedMetrics{
@@ -462,7 +500,9 @@ func (ks *KubernetesScraper) parseMetricsBody(body []byte) (map[string]*dto.Metr
 	return parser.TextToMetricFamilies(strings.NewReader(string(body)))
 }
 
-// convertMetricFamily converts Prometheus DTO to our internal format
+// convertMetricFamily converts Prometheus DTO to our internal format.
+// Histograms and summaries go through convertHistogramFamily and
+// convertSummaryFamily, which keep their buckets and quantiles.
 func (ks *KubernetesScraper) convertMetricFamily(name string, family *dto.MetricFamily) *MetricFamily {
 	metricFamily := &MetricFamily{
 		Name:        name,
@@ -497,14 +537,6 @@ func (ks *KubernetesScraper) convertMetricFamily(name string, family *dto.Metric
 			if metric.Gauge != nil {
 				value = metric.Gauge.GetValue()
 			}
-		case dto.MetricType_HISTOGRAM:
-			if metric.Histogram != nil {
-				value = float64(metric.Histogram.GetSampleCount())
-			}
-		case dto.MetricType_SUMMARY:
-			if metric.Summary != nil {
-				value = float64(metric.Summary.GetSampleCount())
-			}
 		case dto.MetricType_UNTYPED:
 			if metric.Untyped != nil {
 				value = metric.Untyped.GetValue()
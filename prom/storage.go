@@ -361,6 +361,22 @@ func (store *InMemoryStore) GetLabelValues(labelName string) []string {
 	return result
 }
 
+// GetLabelNames returns all label names, except the metric name
+func (store *InMemoryStore) GetLabelNames() []string {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	names := make([]string, 0, len(store.labelNames))
+	for name := range store.labelNames {
+		if name != labels.MetricName {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
+
 // Cleanup removes old metrics based on retention policy
 func (store *InMemoryStore) Cleanup() error {
 	store.mutex.Lock()
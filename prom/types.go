@@ -26,14 +26,18 @@ const (
 // DefaultMetricAllowlist contains only the metrics ktop actually uses.
 // All other metrics are filtered out during scraping to reduce memory usage.
 var DefaultMetricAllowlist = map[string]bool{
-	"container_cpu_usage_seconds_total":      true,
-	"container_memory_working_set_bytes":     true,
-	"container_network_receive_bytes_total":  true,
-	"container_network_transmit_bytes_total": true,
-	"container_fs_reads_bytes_total":         true,
-	"container_fs_writes_bytes_total":        true,
-	"kubelet_running_pods":                   true,
-	"container_count":                        true,
+	"container_cpu_usage_seconds_total":         true,
+	"container_memory_working_set_bytes":        true,
+	"container_network_receive_bytes_total":     true,
+	"container_network_transmit_bytes_total":    true,
+	"container_fs_reads_bytes_total":            true,
+	"container_fs_writes_bytes_total":           true,
+	"container_cpu_cfs_periods_total":           true,
+	"container_cpu_cfs_throttled_periods_total": true,
+	"container_oom_events_total":                true,
+	"kubelet_running_pods":                      true,
+	"kubelet_pod_start_duration_seconds":        true,
+	"container_count":                           true,
 }
 
 // MetricSample represents a single metric data point
@@ -87,6 +91,12 @@ type ScrapeConfig struct {
 	RetentionTime time.Duration
 	InsecureTLS   bool
 	Components    []ComponentType // Components to scrape
+	DataDir       string          // When set, history is persisted here (see DiskStore)
+
+	// ExtraMetrics lists, per component, metric names or regular expressions
+	// kept in addition to the built-in allowlists. Patterns must match the
+	// whole metric name.
+	ExtraMetrics map[ComponentType][]string
 }
 
 // MetricsCollector defines the interface for collecting metrics
@@ -130,12 +140,19 @@ type MetricsStore interface {
 	// This is essential for accurate rate calculations on counter metrics.
 	QueryRangePerSeries(metricName string, labelMatchers map[string]string, start, end time.Time) (map[string][]*MetricSample, error)
 
+	// QueryQuantile estimates the q-quantile of a histogram or summary metric,
+	// given by its base name, over the last window across matching series.
+	QueryQuantile(metricName string, labelMatchers map[string]string, q float64, window time.Duration) (float64, error)
+
 	// GetMetricNames returns all available metric names
 	GetMetricNames() []string
 
 	// GetLabelValues returns all values for a given label name
 	GetLabelValues(labelName string) []string
 
+	// GetLabelNames returns all label names, except the metric name
+	GetLabelNames() []string
+
 	// Cleanup removes old metrics based on retention policy
 	Cleanup() error
 }
@@ -152,6 +169,10 @@ type CollectorController struct {
 	running   bool
 	lastError error
 
+	// Collection goroutines started by Start, ended by Stop
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
 	// Component availability
 	availableComponents map[ComponentType]bool
 
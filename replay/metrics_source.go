@@ -0,0 +1,226 @@
+package replay
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/vladimirvivien/ktop/metrics"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// metricsSource serves the metrics samples of a recording as of the
+// player's current position. History is rebuilt from the samples recorded
+// before that position, so sparklines rewind along with a seek.
+type metricsSource struct {
+	player *Player
+}
+
+var _ metrics.MetricsSource = (*metricsSource)(nil)
+
+func (m *metricsSource) GetNodeMetrics(_ context.Context, nodeName string) (*metrics.NodeMetrics, error) {
+	for _, nm := range m.latestNodes() {
+		if nm.NodeName == nodeName {
+			return nm, nil
+		}
+	}
+	return nil, fmt.Errorf("no recorded metrics for node %s", nodeName)
+}
+
+func (m *metricsSource) GetPodMetrics(_ context.Context, namespace, podName string) (*metrics.PodMetrics, error) {
+	for _, pm := range m.latestPods() {
+		if pm.Namespace == namespace && pm.PodName == podName {
+			return pm, nil
+		}
+	}
+	return nil, fmt.Errorf("no recorded metrics for pod %s/%s", namespace, podName)
+}
+
+// GetPodNetworkDiskMetrics returns zero values; recordings don't capture
+// per-pod network and disk rates
+func (m *metricsSource) GetPodNetworkDiskMetrics(context.Context, string, string) (netRx, netTx, diskRead, diskWrite float64, err error) {
+	return 0, 0, 0, 0, nil
+}
+
+func (m *metricsSource) GetMetricsForPod(ctx context.Context, pod metav1.Object) (*metrics.PodMetrics, error) {
+	return m.GetPodMetrics(ctx, pod.GetNamespace(), pod.GetName())
+}
+
+func (m *metricsSource) GetAllPodMetrics(context.Context) ([]*metrics.PodMetrics, error) {
+	return m.latestPods(), nil
+}
+
+func (m *metricsSource) GetAvailableMetrics() []string {
+	return []string{"cpu", "memory"}
+}
+
+// IsHealthy is always true: the samples are already on disk
+func (m *metricsSource) IsHealthy() bool {
+	return true
+}
+
+// GetSourceInfo reports the source the recording was made with, so the
+// header and views behave as they did when recording.
+func (m *metricsSource) GetSourceInfo() metrics.SourceInfo {
+	rec := m.player.rec
+	info := metrics.SourceInfo{
+		Type:    rec.Session.MetricsSource,
+		Version: "replay",
+		Healthy: true,
+	}
+	pos := m.player.Position()
+	if idx := batchAt(rec.nodeMetrics, pos); idx >= 0 {
+		info.LastScrape = rec.nodeMetrics[idx].At
+	}
+	if idx := batchAt(rec.podMetrics, pos); idx >= 0 {
+		info.MetricsCount = len(rec.podMetrics[idx].Items)
+		if rec.podMetrics[idx].At.After(info.LastScrape) {
+			info.LastScrape = rec.podMetrics[idx].At
+		}
+	}
+	return info
+}
+
+// SetHealthCallback is a no-op; a recording's health never changes
+func (m *metricsSource) SetHealthCallback(func(healthy bool, info metrics.SourceInfo)) {}
+
+func (m *metricsSource) GetNodeHistory(_ context.Context, nodeName string, query metrics.HistoryQuery) (*metrics.ResourceHistory, error) {
+	value, err := sampleValue(query.Resource)
+	if err != nil {
+		return nil, err
+	}
+	return historyOf(m.player.rec.nodeMetrics, m.player.Position(), query, func(nm *metrics.NodeMetrics) (float64, bool) {
+		if nm.NodeName != nodeName {
+			return 0, false
+		}
+		return value(nm.CPUUsage, nm.MemoryUsage)
+	}), nil
+}
+
+func (m *metricsSource) GetPodHistory(_ context.Context, namespace, podName string, query metrics.HistoryQuery) (*metrics.ResourceHistory, error) {
+	value, err := sampleValue(query.Resource)
+	if err != nil {
+		return nil, err
+	}
+	return historyOf(m.player.rec.podMetrics, m.player.Position(), query, func(pm *metrics.PodMetrics) (float64, bool) {
+		if pm.Namespace != namespace || pm.PodName != podName {
+			return 0, false
+		}
+		var total float64
+		found := false
+		for _, c := range pm.Containers {
+			if query.Container != "" && c.Name != query.Container {
+				continue
+			}
+			if v, ok := value(c.CPUUsage, c.MemoryUsage); ok {
+				total += v
+				found = true
+			}
+		}
+		return total, found
+	}), nil
+}
+
+func (m *metricsSource) SupportsHistory() bool {
+	return true
+}
+
+func (m *metricsSource) latestNodes() []*metrics.NodeMetrics {
+	rec := m.player.rec
+	if idx := batchAt(rec.nodeMetrics, m.player.Position()); idx >= 0 {
+		return rec.nodeMetrics[idx].Items
+	}
+	return nil
+}
+
+func (m *metricsSource) latestPods() []*metrics.PodMetrics {
+	rec := m.player.rec
+	if idx := batchAt(rec.podMetrics, m.player.Position()); idx >= 0 {
+		return rec.podMetrics[idx].Items
+	}
+	return nil
+}
+
+// sampleValue returns a function extracting the history value for resource
+// from a CPU and memory pair: millicores for CPU, bytes for memory, as the
+// live sources report them.
+func sampleValue(res metrics.ResourceType) (func(cpu, mem *resource.Quantity) (float64, bool), error) {
+	switch res {
+	case metrics.ResourceCPU:
+		return func(cpu, _ *resource.Quantity) (float64, bool) {
+			if cpu == nil {
+				return 0, false
+			}
+			return float64(cpu.MilliValue()), true
+		}, nil
+	case metrics.ResourceMemory:
+		return func(_, mem *resource.Quantity) (float64, bool) {
+			if mem == nil {
+				return 0, false
+			}
+			return float64(mem.Value()), true
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported resource type: %s", res)
+	}
+}
+
+// historyOf collects the values extracted by value from the batches in
+// (pos-query.Duration, pos], averaging down to query.MaxPoints.
+func historyOf[T any](batches []batch[T], pos time.Time, query metrics.HistoryQuery, value func(T) (float64, bool)) *metrics.ResourceHistory {
+	history := &metrics.ResourceHistory{
+		Resource:   query.Resource,
+		DataPoints: []metrics.HistoryDataPoint{},
+		MinValue:   math.MaxFloat64,
+		MaxValue:   -math.MaxFloat64,
+	}
+
+	cutoff := pos.Add(-query.Duration)
+	for i := batchAt(batches, cutoff) + 1; i >= 0 && i < len(batches) && !batches[i].At.After(pos); i++ {
+		for _, item := range batches[i].Items {
+			v, ok := value(item)
+			if !ok {
+				continue
+			}
+			history.DataPoints = append(history.DataPoints, metrics.HistoryDataPoint{Timestamp: batches[i].At, Value: v})
+			history.MinValue = math.Min(history.MinValue, v)
+			history.MaxValue = math.Max(history.MaxValue, v)
+			break
+		}
+	}
+
+	if query.MaxPoints > 0 && len(history.DataPoints) > query.MaxPoints {
+		history.DataPoints = downsample(history.DataPoints, query.MaxPoints)
+	}
+	if len(history.DataPoints) == 0 {
+		history.MinValue = 0
+		history.MaxValue = 0
+	}
+	return history
+}
+
+// downsample reduces points to maxPoints by averaging equal-sized buckets
+func downsample(points []metrics.HistoryDataPoint, maxPoints int) []metrics.HistoryDataPoint {
+	result := make([]metrics.HistoryDataPoint, 0, maxPoints)
+	bucketSize := float64(len(points)) / float64(maxPoints)
+	for i := 0; i < maxPoints; i++ {
+		start, end := int(float64(i)*bucketSize), int(float64(i+1)*bucketSize)
+		if end > len(points) {
+			end = len(points)
+		}
+		if start >= end {
+			continue
+		}
+		var sum float64
+		for _, p := range points[start:end] {
+			sum += p.Value
+		}
+		result = append(result, metrics.HistoryDataPoint{
+			Timestamp: points[end-1].Timestamp,
+			Value:     sum / float64(end-start),
+		})
+	}
+	return result
+}
@@ -0,0 +1,443 @@
+package replay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/vladimirvivien/ktop/k8s"
+	"github.com/vladimirvivien/ktop/metrics"
+	coreV1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ErrNotRecorded is returned for data a recording does not capture: the raw
+// node and pod objects, events, and container logs. Nor can a recording run
+// commands in containers, forward ports to them or change the cluster.
+var ErrNotRecorded = errors.New("not available in a recording")
+
+// tickInterval is how often the player checks for batches to deliver
+const tickInterval = 200 * time.Millisecond
+
+// speeds are the playback rates cycled through by ChangeSpeed
+var speeds = []float64{0.25, 0.5, 1, 2, 4, 8, 16, 32, 64}
+
+// Player plays a Recording back through the Refresh*Func callbacks. It
+// implements k8s.Cluster and k8s.ClusterSource so the application and views
+// can run against it unchanged.
+type Player struct {
+	rec *Recording
+	now func() time.Time
+
+	mu       sync.Mutex
+	pos      time.Time // recording time at anchor
+	anchor   time.Time // wall clock time pos was last set
+	speedIdx int
+	paused   bool
+	started  bool
+	last     [5]int // index of the last batch delivered, per refresh kind
+
+	nodeRefreshFunc      k8s.RefreshNodesFunc
+	podRefreshFunc       k8s.RefreshPodsFunc
+	summaryRefreshFunc   k8s.RefreshSummaryFunc
+	workloadRefreshFunc  k8s.RefreshWorkloadsFunc
+	namespaceRefreshFunc k8s.RefreshNamespacesFunc
+
+	wake chan struct{}
+}
+
+// refresh kinds, indexes into Player.last
+const (
+	deliverNodes = iota
+	deliverPods
+	deliverSummary
+	deliverWorkloads
+	deliverNamespaces
+)
+
+var (
+	_ k8s.Cluster       = (*Player)(nil)
+	_ k8s.ClusterSource = (*Player)(nil)
+)
+
+// NewPlayer returns a player positioned at the start of rec at 1x speed
+func NewPlayer(rec *Recording) *Player {
+	p := &Player{
+		rec:      rec,
+		now:      time.Now,
+		pos:      rec.Start,
+		speedIdx: 2,
+		wake:     make(chan struct{}, 1),
+	}
+	p.anchor = p.now()
+	p.resetDelivered()
+	return p
+}
+
+// Recording returns the recording being played
+func (p *Player) Recording() *Recording {
+	return p.rec
+}
+
+// MetricsSource returns a metrics source serving the recorded samples at the
+// current playback position, or nil if the recording has none.
+func (p *Player) MetricsSource() metrics.MetricsSource {
+	if !p.rec.HasMetrics() {
+		return nil
+	}
+	return &metricsSource{player: p}
+}
+
+// Position returns the current playback position in recording time
+func (p *Player) Position() time.Time {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.position()
+}
+
+// position must be called with mu held. Playback stops at the end of the
+// recording.
+func (p *Player) position() time.Time {
+	if p.paused {
+		return p.pos
+	}
+	elapsed := time.Duration(float64(p.now().Sub(p.anchor)) * speeds[p.speedIdx])
+	pos := p.pos.Add(elapsed)
+	if !pos.Before(p.rec.End) {
+		p.pos, p.anchor, p.paused = p.rec.End, p.now(), true
+		return p.pos
+	}
+	return pos
+}
+
+// setPosition must be called with mu held
+func (p *Player) setPosition(pos time.Time) {
+	switch {
+	case pos.Before(p.rec.Start):
+		pos = p.rec.Start
+	case pos.After(p.rec.End):
+		pos = p.rec.End
+	}
+	p.pos, p.anchor = pos, p.now()
+}
+
+// Paused reports whether playback is paused
+func (p *Player) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.position() // pauses at the end
+	return p.paused
+}
+
+// TogglePause pauses or resumes playback. Resuming at the end of the
+// recording starts over from the beginning.
+func (p *Player) TogglePause() {
+	p.mu.Lock()
+	pos := p.position()
+	if p.paused && !pos.Before(p.rec.End) {
+		pos = p.rec.Start
+	}
+	p.setPosition(pos)
+	p.paused = !p.paused
+	p.mu.Unlock()
+	p.notify()
+}
+
+// Seek moves playback by d, clamped to the recording
+func (p *Player) Seek(d time.Duration) {
+	p.mu.Lock()
+	p.setPosition(p.position().Add(d))
+	p.mu.Unlock()
+	p.notify()
+}
+
+// ChangeSpeed steps the playback rate up or down
+func (p *Player) ChangeSpeed(faster bool) {
+	p.mu.Lock()
+	p.setPosition(p.position()) // re-anchor so elapsed time isn't rescaled
+	if faster && p.speedIdx < len(speeds)-1 {
+		p.speedIdx++
+	}
+	if !faster && p.speedIdx > 0 {
+		p.speedIdx--
+	}
+	p.mu.Unlock()
+}
+
+// Speed returns the playback rate
+func (p *Player) Speed() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return speeds[p.speedIdx]
+}
+
+// Status describes the playback state for the header, e.g.
+// "▶ 2x 12:04:31 (01:31/10:00)"
+func (p *Player) Status() string {
+	p.mu.Lock()
+	pos := p.position()
+	paused, speed := p.paused, speeds[p.speedIdx]
+	p.mu.Unlock()
+
+	state := "▶"
+	if paused {
+		state = "⏸"
+	}
+	return fmt.Sprintf("%s %gx %s (%s/%s)", state, speed,
+		pos.Local().Format("15:04:05"),
+		formatOffset(pos.Sub(p.rec.Start)), formatOffset(p.rec.Duration()))
+}
+
+func formatOffset(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d >= time.Hour {
+		return fmt.Sprintf("%d:%02d:%02d", int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60)
+	}
+	return fmt.Sprintf("%02d:%02d", int(d.Minutes()), int(d.Seconds())%60)
+}
+
+func (p *Player) notify() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (p *Player) resetDelivered() {
+	for i := range p.last {
+		p.last[i] = -2 // never delivered; -1 means "before the first batch"
+	}
+}
+
+// k8s.ClusterSource
+
+func (p *Player) SetMetricsSource(metrics.MetricsSource) {} // samples come from the recording
+
+func (p *Player) SetNodeRefreshFunc(fn k8s.RefreshNodesFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.nodeRefreshFunc = fn
+}
+
+func (p *Player) SetPodRefreshFunc(fn k8s.RefreshPodsFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.podRefreshFunc = fn
+}
+
+func (p *Player) SetWorkloadRefreshFunc(fn k8s.RefreshWorkloadsFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.workloadRefreshFunc = fn
+}
+
+func (p *Player) SetNamespaceRefreshFunc(fn k8s.RefreshNamespacesFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.namespaceRefreshFunc = fn
+}
+
+func (p *Player) SetClusterSummaryRefreshFunc(fn k8s.RefreshSummaryFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.summaryRefreshFunc = fn
+}
+
+// Start begins delivering batches in the background until ctx is done. The
+// resync period has no meaning for a recording and is ignored.
+func (p *Player) Start(ctx context.Context, _ time.Duration) error {
+	p.mu.Lock()
+	p.resetDelivered()
+	started := p.started
+	p.started = true
+	p.mu.Unlock()
+	if started {
+		p.notify() // redeliver to the newly registered callbacks
+		return nil
+	}
+
+	go func() {
+		p.deliver(ctx)
+		ticker := time.NewTicker(tickInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				p.mu.Lock()
+				p.started = false
+				p.mu.Unlock()
+				return
+			case <-ticker.C:
+			case <-p.wake:
+			}
+			p.deliver(ctx)
+		}
+	}()
+	return nil
+}
+
+// deliver calls each refresh function whose batch at the current position
+// differs from the one it last received.
+func (p *Player) deliver(ctx context.Context) {
+	p.mu.Lock()
+	pos := p.position()
+	nodeFn, podFn, summaryFn := p.nodeRefreshFunc, p.podRefreshFunc, p.summaryRefreshFunc
+	workloadFn, namespaceFn := p.workloadRefreshFunc, p.namespaceRefreshFunc
+	var changed [5]int
+	for kind, idx := range [5]int{
+		batchAt(p.rec.nodes, pos),
+		batchAt(p.rec.pods, pos),
+		batchAt(p.rec.summaries, pos),
+		batchAt(p.rec.workloads, pos),
+		batchAt(p.rec.namespaces, pos),
+	} {
+		changed[kind] = -2
+		if idx != p.last[kind] {
+			p.last[kind] = idx
+			changed[kind] = idx
+		}
+	}
+	p.mu.Unlock()
+
+	if idx := changed[deliverSummary]; idx != -2 && summaryFn != nil {
+		summaryFn(ctx, itemAt(p.rec.summaries, idx))
+	}
+	if idx := changed[deliverNodes]; idx != -2 && nodeFn != nil {
+		nodeFn(ctx, itemsAt(p.rec.nodes, idx))
+	}
+	if idx := changed[deliverPods]; idx != -2 && podFn != nil {
+		podFn(ctx, itemsAt(p.rec.pods, idx))
+	}
+	if idx := changed[deliverWorkloads]; idx != -2 && workloadFn != nil {
+		workloadFn(ctx, itemsAt(p.rec.workloads, idx))
+	}
+	if idx := changed[deliverNamespaces]; idx != -2 && namespaceFn != nil {
+		namespaceFn(ctx, itemsAt(p.rec.namespaces, idx))
+	}
+}
+
+// itemsAt returns a copy of the batch at idx so views are free to modify
+// it, or an empty slice before the first batch.
+func itemsAt[T any](batches []batch[T], idx int) []T {
+	if idx < 0 {
+		return []T{}
+	}
+	return append([]T(nil), batches[idx].Items...)
+}
+
+// itemAt returns the single item of the batch at idx
+func itemAt[T any](batches []batch[T], idx int) T {
+	var zero T
+	if idx < 0 || len(batches[idx].Items) == 0 {
+		return zero
+	}
+	return batches[idx].Items[0]
+}
+
+// GetNamespaceList returns the namespaces in the recorded namespace models
+// closest to the current position.
+func (p *Player) GetNamespaceList(_ context.Context) ([]*coreV1.Namespace, error) {
+	idx := batchAt(p.rec.namespaces, p.Position())
+	if idx < 0 && len(p.rec.namespaces) > 0 {
+		idx = 0
+	}
+	list := make([]*coreV1.Namespace, 0)
+	for _, ns := range itemsAt(p.rec.namespaces, idx) {
+		list = append(list, &coreV1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: ns.Name, CreationTimestamp: ns.CreationTime},
+			Status:     coreV1.NamespaceStatus{Phase: coreV1.NamespacePhase(ns.Status)},
+		})
+	}
+	return list, nil
+}
+
+func (p *Player) GetNodeList(context.Context) ([]*coreV1.Node, error) {
+	return nil, ErrNotRecorded
+}
+
+func (p *Player) GetPodList(context.Context) ([]*coreV1.Pod, error) {
+	return nil, ErrNotRecorded
+}
+
+func (p *Player) GetNode(context.Context, string) (*coreV1.Node, error) {
+	return nil, ErrNotRecorded
+}
+
+func (p *Player) GetPod(context.Context, string, string) (*coreV1.Pod, error) {
+	return nil, ErrNotRecorded
+}
+
+func (p *Player) GetEvents(context.Context) ([]coreV1.Event, error) {
+	return nil, ErrNotRecorded
+}
+
+func (p *Player) GetEventsForNode(context.Context, string) ([]coreV1.Event, error) {
+	return nil, ErrNotRecorded
+}
+
+func (p *Player) GetEventsForPod(context.Context, string, string) ([]coreV1.Event, error) {
+	return nil, ErrNotRecorded
+}
+
+func (p *Player) GetPodLogs(context.Context, string, string, k8s.LogOptions) (io.ReadCloser, error) {
+	return nil, ErrNotRecorded
+}
+
+func (p *Player) WatchPods(context.Context, k8s.PodSelector, func(*coreV1.Pod, bool)) error {
+	return ErrNotRecorded
+}
+
+func (p *Player) Exec(context.Context, string, string, k8s.ExecOptions) error {
+	return ErrNotRecorded
+}
+
+func (p *Player) PortForward(context.Context, string, string, k8s.PortForwardOptions) error {
+	return ErrNotRecorded
+}
+
+func (p *Player) CheckAccess(context.Context, k8s.Access) (bool, string, error) {
+	return false, "", ErrNotRecorded
+}
+
+func (p *Player) GetPodsOnNode(context.Context, string) ([]coreV1.Pod, error) {
+	return nil, ErrNotRecorded
+}
+
+func (p *Player) DeletePod(context.Context, string, string) error {
+	return ErrNotRecorded
+}
+
+func (p *Player) EvictPod(context.Context, string, string) error {
+	return ErrNotRecorded
+}
+
+func (p *Player) CordonNode(context.Context, string, bool) error {
+	return ErrNotRecorded
+}
+
+func (p *Player) ScaleWorkload(context.Context, string, string, string, int32) error {
+	return ErrNotRecorded
+}
+
+func (p *Player) RestartWorkload(context.Context, string, string, string) error {
+	return ErrNotRecorded
+}
+
+// k8s.Cluster, from the recorded session
+
+func (p *Player) Namespace() string        { return p.rec.Session.Namespace }
+func (p *Player) ClusterContext() string   { return p.rec.Session.Context }
+func (p *Player) ClusterName() string      { return p.rec.Session.Cluster }
+func (p *Player) Username() string         { return p.rec.Session.User }
+func (p *Player) GetServerVersion() string { return p.rec.Session.ServerVersion }
+func (p *Player) Source() k8s.ClusterSource {
+	return p
+}
+
+// Contexts lists only the recorded context; a replay cannot switch
+func (p *Player) Contexts() []string {
+	return []string{p.rec.Session.Context}
+}
@@ -0,0 +1,190 @@
+// Package replay plays back sessions captured with `ktop record`. A Player
+// stands in for the live cluster connection so the overview and detail
+// views render a recording exactly as they render a cluster.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/vladimirvivien/ktop/headless"
+	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/views/model"
+)
+
+// maxLineSize bounds a single NDJSON record; pod models with many labels and
+// containers can be far larger than bufio's 64KiB default.
+const maxLineSize = 16 << 20
+
+// batch holds the records of one kind written by a single refresh
+type batch[T any] struct {
+	At    time.Time
+	Items []T
+}
+
+// Recording is a loaded `ktop record` file with records grouped into
+// per-kind batches in time order.
+type Recording struct {
+	Session headless.SessionInfo
+	Start   time.Time
+	End     time.Time
+
+	nodes       []batch[model.NodeModel]
+	pods        []batch[model.PodModel]
+	summaries   []batch[model.ClusterSummary]
+	workloads   []batch[model.WorkloadModel]
+	namespaces  []batch[model.NamespaceModel]
+	nodeMetrics []batch[*metrics.NodeMetrics]
+	podMetrics  []batch[*metrics.PodMetrics]
+}
+
+// Duration returns the time covered by the recording
+func (r *Recording) Duration() time.Duration {
+	return r.End.Sub(r.Start)
+}
+
+// HasMetrics reports whether the recording carries metrics samples
+func (r *Recording) HasMetrics() bool {
+	return len(r.nodeMetrics) > 0 || len(r.podMetrics) > 0
+}
+
+// LoadFile reads a recording from path
+func LoadFile(path string) (*Recording, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("replay: %w", err)
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// Load reads a recording from r. Unknown record kinds are skipped so newer
+// recordings still play, and a truncated last line (left behind when the
+// recorder was killed mid-write) is ignored.
+func Load(r io.Reader) (*Recording, error) {
+	rec := &Recording{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxLineSize)
+
+	var pendingErr error
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if pendingErr != nil {
+			return nil, pendingErr // malformed line was not the last one
+		}
+		var record headless.Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			pendingErr = fmt.Errorf("replay: line %d: %w", line, err)
+			continue
+		}
+		rec.add(record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("replay: read recording: %w", err)
+	}
+	if rec.Start.IsZero() {
+		return nil, errors.New("replay: recording contains no data")
+	}
+
+	sortBatches(rec.nodes)
+	sortBatches(rec.pods)
+	sortBatches(rec.summaries)
+	sortBatches(rec.workloads)
+	sortBatches(rec.namespaces)
+	sortBatches(rec.nodeMetrics)
+	sortBatches(rec.podMetrics)
+	return rec, nil
+}
+
+func (r *Recording) add(record headless.Record) {
+	at := record.Timestamp
+	switch record.Kind {
+	case headless.KindSession:
+		if record.Session != nil {
+			r.Session = *record.Session
+		}
+		return // the session record carries no data to play back
+	case headless.KindNode:
+		if record.Node == nil {
+			return
+		}
+		r.nodes = appendItem(r.nodes, at, *record.Node)
+	case headless.KindPod:
+		if record.Pod == nil {
+			return
+		}
+		r.pods = appendItem(r.pods, at, *record.Pod)
+	case headless.KindSummary:
+		if record.Summary == nil {
+			return
+		}
+		r.summaries = appendItem(r.summaries, at, *record.Summary)
+	case headless.KindWorkload:
+		if record.Workload == nil {
+			return
+		}
+		r.workloads = appendItem(r.workloads, at, *record.Workload)
+	case headless.KindNamespace:
+		if record.Namespace == nil {
+			return
+		}
+		r.namespaces = appendItem(r.namespaces, at, *record.Namespace)
+	case headless.KindNodeMetrics:
+		if record.NodeMetrics == nil {
+			return
+		}
+		r.nodeMetrics = appendItem(r.nodeMetrics, at, record.NodeMetrics)
+	case headless.KindPodMetrics:
+		if record.PodMetrics == nil {
+			return
+		}
+		r.podMetrics = appendItem(r.podMetrics, at, record.PodMetrics)
+	default:
+		return
+	}
+
+	if r.Start.IsZero() || at.Before(r.Start) {
+		r.Start = at
+	}
+	if at.After(r.End) {
+		r.End = at
+	}
+}
+
+// appendItem adds item to the batch stamped at, starting a new batch when
+// the timestamp changes. Records from one refresh are written together, so
+// only the last batch needs checking.
+func appendItem[T any](batches []batch[T], at time.Time, item T) []batch[T] {
+	if n := len(batches); n > 0 && batches[n-1].At.Equal(at) {
+		batches[n-1].Items = append(batches[n-1].Items, item)
+		return batches
+	}
+	return append(batches, batch[T]{At: at, Items: []T{item}})
+}
+
+// sortBatches orders batches by time. Refresh goroutines write concurrently,
+// so batches of one kind may be slightly out of order in the file.
+func sortBatches[T any](batches []batch[T]) {
+	sort.SliceStable(batches, func(i, j int) bool {
+		return batches[i].At.Before(batches[j].At)
+	})
+}
+
+// batchAt returns the index of the last batch at or before t, or -1 if
+// there is none yet.
+func batchAt[T any](batches []batch[T], t time.Time) int {
+	i := sort.Search(len(batches), func(i int) bool {
+		return batches[i].At.After(t)
+	})
+	return i - 1
+}
@@ -0,0 +1,307 @@
+package replay
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vladimirvivien/ktop/headless"
+	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var t0 = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func at(seconds int) time.Time {
+	return t0.Add(time.Duration(seconds) * time.Second)
+}
+
+// encode writes records as a recording file
+func encode(t *testing.T, records ...headless.Record) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			t.Fatalf("encode: %v", err)
+		}
+	}
+	return &buf
+}
+
+func nodeRecord(ts time.Time, name string) headless.Record {
+	return headless.Record{Kind: headless.KindNode, Timestamp: ts, Node: &model.NodeModel{Name: name}}
+}
+
+func nodeMetricsRecord(ts time.Time, name, cpu string) headless.Record {
+	q := resource.MustParse(cpu)
+	mem := resource.MustParse("1Gi")
+	return headless.Record{Kind: headless.KindNodeMetrics, Timestamp: ts, NodeMetrics: &metrics.NodeMetrics{
+		NodeName: name, CPUUsage: &q, MemoryUsage: &mem,
+	}}
+}
+
+// testRecording spans 60s with node refreshes every 10s; node-b appears at 30s
+func testRecording(t *testing.T) *Recording {
+	t.Helper()
+	records := []headless.Record{
+		{Kind: headless.KindSession, Timestamp: at(0), Session: &headless.SessionInfo{
+			Context: "kind-dev", Namespace: "default", MetricsSource: metrics.SourceTypeMetricsServer,
+		}},
+	}
+	for s := 0; s <= 60; s += 10 {
+		records = append(records, nodeMetricsRecord(at(s), "node-a", "100m"))
+		records = append(records, nodeRecord(at(s), "node-a"))
+		if s >= 30 {
+			records = append(records, nodeRecord(at(s), "node-b"))
+		}
+	}
+	records = append(records, headless.Record{Kind: headless.KindSummary, Timestamp: at(5), Summary: &model.ClusterSummary{NodesCount: 1}})
+	records = append(records, headless.Record{Kind: headless.KindNamespace, Timestamp: at(0), Namespace: &model.NamespaceModel{Name: "default", Status: "Active"}})
+
+	rec, err := Load(encode(t, records...))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	return rec
+}
+
+// fakeClock is advanced by tests to drive playback
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newTestPlayer(t *testing.T) (*Player, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{now: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	p := NewPlayer(testRecording(t))
+	p.now = clock.Now
+	p.anchor = clock.Now()
+	return p, clock
+}
+
+func TestLoad(t *testing.T) {
+	rec := testRecording(t)
+
+	if rec.Session.Context != "kind-dev" {
+		t.Errorf("Expected session context kind-dev, got %q", rec.Session.Context)
+	}
+	if !rec.Start.Equal(at(0)) || !rec.End.Equal(at(60)) {
+		t.Errorf("Expected span 0s-60s, got %v-%v", rec.Start, rec.End)
+	}
+	if len(rec.nodes) != 7 {
+		t.Fatalf("Expected 7 node batches, got %d", len(rec.nodes))
+	}
+	if got := len(rec.nodes[3].Items); got != 2 {
+		t.Errorf("Expected 2 nodes at 30s, got %d", got)
+	}
+	if !rec.HasMetrics() {
+		t.Error("Expected recording to have metrics")
+	}
+}
+
+func TestLoad_TolerantOfTruncationAndUnknownKinds(t *testing.T) {
+	buf := encode(t,
+		nodeRecord(at(0), "node-a"),
+		headless.Record{Kind: "somethingNew", Timestamp: at(1)},
+		nodeRecord(at(10), "node-a"),
+	)
+	buf.WriteString(`{"kind":"node","timestamp":"2025-01-01T12:00:20Z","node":{"Na`)
+
+	rec, err := Load(buf)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(rec.nodes) != 2 || !rec.End.Equal(at(10)) {
+		t.Errorf("Expected 2 node batches ending at 10s, got %d ending at %v", len(rec.nodes), rec.End)
+	}
+
+	corrupt := encode(t, nodeRecord(at(0), "node-a"))
+	corrupt.WriteString("not json\n")
+	corrupt.Write(encode(t, nodeRecord(at(10), "node-a")).Bytes())
+	if _, err := Load(corrupt); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Expected an error for a malformed line mid-file, got %v", err)
+	}
+
+	if _, err := Load(strings.NewReader("")); err == nil {
+		t.Error("Expected an error for an empty recording")
+	}
+}
+
+func TestPlayer_PositionAndControls(t *testing.T) {
+	p, clock := newTestPlayer(t)
+
+	clock.Advance(5 * time.Second)
+	if got := p.Position(); !got.Equal(at(5)) {
+		t.Errorf("Expected position 5s, got %v", got.Sub(t0))
+	}
+
+	p.ChangeSpeed(true) // 2x
+	clock.Advance(5 * time.Second)
+	if got := p.Position(); !got.Equal(at(15)) {
+		t.Errorf("Expected position 15s at 2x, got %v", got.Sub(t0))
+	}
+
+	p.TogglePause()
+	clock.Advance(time.Minute)
+	if got := p.Position(); !got.Equal(at(15)) {
+		t.Errorf("Expected paused position 15s, got %v", got.Sub(t0))
+	}
+
+	p.Seek(-time.Hour)
+	if got := p.Position(); !got.Equal(at(0)) {
+		t.Errorf("Expected seek to clamp at start, got %v", got.Sub(t0))
+	}
+	p.Seek(40 * time.Second)
+	if got := p.Position(); !got.Equal(at(40)) {
+		t.Errorf("Expected position 40s after seek, got %v", got.Sub(t0))
+	}
+
+	// Playback pauses at the end; resuming starts over
+	p.TogglePause()
+	clock.Advance(time.Minute)
+	if got := p.Position(); !got.Equal(at(60)) || !p.Paused() {
+		t.Errorf("Expected playback paused at the end, got %v (paused %v)", got.Sub(t0), p.Paused())
+	}
+	p.TogglePause()
+	if got := p.Position(); !got.Equal(at(0)) || p.Paused() {
+		t.Errorf("Expected resume at the end to restart, got %v (paused %v)", got.Sub(t0), p.Paused())
+	}
+
+	for i := 0; i < 20; i++ {
+		p.ChangeSpeed(false)
+	}
+	if got := p.Speed(); got != speeds[0] {
+		t.Errorf("Expected speed to bottom out at %v, got %v", speeds[0], got)
+	}
+	if status := p.Status(); !strings.Contains(status, "0.25x") || !strings.Contains(status, "00:00/01:00") {
+		t.Errorf("Unexpected status %q", status)
+	}
+}
+
+func TestPlayer_DeliversBatchesOnChange(t *testing.T) {
+	p, clock := newTestPlayer(t)
+
+	var delivered [][]model.NodeModel
+	p.SetNodeRefreshFunc(func(_ context.Context, items []model.NodeModel) error {
+		delivered = append(delivered, items)
+		return nil
+	})
+	var summaries int
+	p.SetClusterSummaryRefreshFunc(func(context.Context, model.ClusterSummary) error {
+		summaries++
+		return nil
+	})
+
+	ctx := context.Background()
+	p.deliver(ctx)
+	p.deliver(ctx) // nothing changed
+	if len(delivered) != 1 || len(delivered[0]) != 1 {
+		t.Fatalf("Expected one delivery of one node, got %v", delivered)
+	}
+	if summaries != 1 {
+		t.Errorf("Expected an empty summary before the first recorded one, got %d deliveries", summaries)
+	}
+
+	clock.Advance(35 * time.Second)
+	p.deliver(ctx)
+	if len(delivered) != 2 || len(delivered[1]) != 2 {
+		t.Fatalf("Expected a second delivery with two nodes, got %v", delivered)
+	}
+
+	// Seeking back redelivers the earlier batch
+	p.Seek(-30 * time.Second)
+	p.deliver(ctx)
+	if len(delivered) != 3 || len(delivered[2]) != 1 {
+		t.Fatalf("Expected seek to redeliver one node, got %v", delivered)
+	}
+}
+
+func TestPlayer_Cluster(t *testing.T) {
+	p, _ := newTestPlayer(t)
+
+	if p.ClusterContext() != "kind-dev" || p.Namespace() != "default" {
+		t.Errorf("Expected recorded context and namespace, got %q and %q", p.ClusterContext(), p.Namespace())
+	}
+	if p.Source() != p {
+		t.Error("Expected the player to be its own cluster source")
+	}
+
+	list, err := p.GetNamespaceList(context.Background())
+	if err != nil || len(list) != 1 || list[0].Name != "default" {
+		t.Errorf("Expected namespace default, got %v (err %v)", list, err)
+	}
+	if _, err := p.GetPod(context.Background(), "default", "web"); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("Expected ErrNotRecorded, got %v", err)
+	}
+}
+
+func TestMetricsSource(t *testing.T) {
+	p, clock := newTestPlayer(t)
+	source := p.MetricsSource()
+	if source == nil {
+		t.Fatal("Expected a metrics source for a recording with metrics")
+	}
+	if info := source.GetSourceInfo(); info.Type != metrics.SourceTypeMetricsServer {
+		t.Errorf("Expected recorded source type, got %q", info.Type)
+	}
+
+	clock.Advance(30 * time.Second)
+	nm, err := source.GetNodeMetrics(context.Background(), "node-a")
+	if err != nil {
+		t.Fatalf("GetNodeMetrics failed: %v", err)
+	}
+	if got := nm.CPUUsage.MilliValue(); got != 100 {
+		t.Errorf("Expected 100m CPU at 30s, got %dm", got)
+	}
+
+	history, err := source.GetNodeHistory(context.Background(), "node-a", metrics.HistoryQuery{
+		Resource: metrics.ResourceCPU,
+		Duration: 25 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("GetNodeHistory failed: %v", err)
+	}
+	if len(history.DataPoints) != 3 {
+		t.Fatalf("Expected samples at 10s, 20s and 30s, got %d", len(history.DataPoints))
+	}
+	if last := history.DataPoints[2]; !last.Timestamp.Equal(at(30)) || last.Value != 100 {
+		t.Errorf("Expected newest point at 30s with 100m, got %+v", last)
+	}
+
+	history, _ = source.GetNodeHistory(context.Background(), "node-a", metrics.HistoryQuery{
+		Resource:  metrics.ResourceMemory,
+		Duration:  time.Hour,
+		MaxPoints: 2,
+	})
+	if len(history.DataPoints) != 2 || history.DataPoints[0].Value != 1<<30 {
+		t.Errorf("Expected 2 downsampled memory points of 1Gi, got %+v", history.DataPoints)
+	}
+
+	noMetrics, err := Load(encode(t, nodeRecord(at(0), "node-a")))
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if NewPlayer(noMetrics).MetricsSource() != nil {
+		t.Error("Expected no metrics source for a recording without metrics")
+	}
+}
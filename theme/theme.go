@@ -0,0 +1,34 @@
+// Package theme names ktop's color themes and the usage levels their colors
+// change at. It has no UI dependencies, so configuration and alerting can
+// share them without importing package ui, which applies them.
+package theme
+
+import "slices"
+
+// Theme names
+const (
+	Default      = "default" // used when none is configured
+	Light        = "light"
+	HighContrast = "high-contrast"
+)
+
+// Usage levels, in percent, at which cpu and memory usage is drawn yellow
+// and red. The default alert rules fire above UsageHigh so they agree with
+// the red coloring.
+const (
+	UsageMedium = 50
+	UsageHigh   = 90
+)
+
+// names lists the themes, sorted
+var names = []string{Default, HighContrast, Light}
+
+// Names returns the theme names, sorted.
+func Names() []string {
+	return slices.Clone(names)
+}
+
+// IsValid reports whether name is a known theme. Empty means Default.
+func IsValid(name string) bool {
+	return name == "" || slices.Contains(names, name)
+}
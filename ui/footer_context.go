@@ -7,7 +7,7 @@ type FooterContext interface {
 
 // OverviewContext provides footer items for Overview page panels
 type OverviewContext struct {
-	FocusedPanel string // "header", "summary", "nodes", "pods"
+	FocusedPanel string // "header", "summary", "nodes", "namespaces", "workloads", "pods"
 }
 
 // GetItems returns footer items based on focused panel
@@ -17,6 +17,17 @@ func (c OverviewContext) GetItems() []FooterItem {
 		return []FooterItem{
 			{Key: "[Tab]", Action: "next"},
 			{Key: "[/]", Action: "filter"},
+			{Key: "[c]", Action: "context"},
+			{Key: "[n]", Action: "namespace"},
+			{Key: "[a]", Action: "alerts"},
+			{Key: "[p]", Action: "control plane"},
+			{Key: "[q]", Action: "query"},
+			{Key: "[t]", Action: "pressure"},
+			{Key: "[r]", Action: "rightsizing"},
+			{Key: "[b]", Action: "capacity"},
+			{Key: "[e]", Action: "events"},
+			{Key: "[l]", Action: "logs"},
+			{Key: "[f]", Action: "port forwards"},
 			{Key: "[ESC | Ctrl+C]", Action: "quit"},
 		}
 	case "nodes":
@@ -27,6 +38,22 @@ func (c OverviewContext) GetItems() []FooterItem {
 			{Key: "[/]", Action: "filter"},
 			{Key: "[ESC | Ctrl+C]", Action: "quit"},
 		}
+	case "namespaces":
+		return []FooterItem{
+			{Key: "[↑/↓]", Action: "navigate"},
+			{Key: "[Enter]", Action: "filter ns"},
+			{Key: "[Tab]", Action: "next"},
+			{Key: "[/]", Action: "filter"},
+			{Key: "[ESC | Ctrl+C]", Action: "quit"},
+		}
+	case "workloads":
+		return []FooterItem{
+			{Key: "[↑/↓]", Action: "navigate"},
+			{Key: "[Enter]", Action: "pods"},
+			{Key: "[Tab]", Action: "next"},
+			{Key: "[/]", Action: "filter"},
+			{Key: "[ESC | Ctrl+C]", Action: "quit"},
+		}
 	case "pods":
 		return []FooterItem{
 			{Key: "[↑/↓]", Action: "navigate"},
@@ -56,6 +83,7 @@ func (c NodeDetailContext) GetItems() []FooterItem {
 			{Key: "[↑/↓]", Action: "navigate"},
 			{Key: "[Enter]", Action: "pod detail"},
 			{Key: "[Tab]", Action: "next"},
+			{Key: "[x]", Action: "actions"},
 			{Key: "[ESC]", Action: "back"},
 			{Key: "[Ctrl+C]", Action: "quit"},
 		}
@@ -63,12 +91,161 @@ func (c NodeDetailContext) GetItems() []FooterItem {
 		return []FooterItem{
 			{Key: "[↑/↓]", Action: "scroll"},
 			{Key: "[Tab]", Action: "next"},
+			{Key: "[x]", Action: "actions"},
 			{Key: "[ESC]", Action: "back"},
 			{Key: "[Ctrl+C]", Action: "quit"},
 		}
 	}
 }
 
+// WorkloadPodsContext provides footer items for the Workload Pods page
+type WorkloadPodsContext struct{}
+
+// GetItems returns footer items for the workload's pods table
+func (c WorkloadPodsContext) GetItems() []FooterItem {
+	return []FooterItem{
+		{Key: "[↑/↓]", Action: "navigate"},
+		{Key: "[Enter]", Action: "pod detail"},
+		{Key: "[l]", Action: "logs"},
+		{Key: "[x]", Action: "actions"},
+		{Key: "[ESC]", Action: "back"},
+		{Key: "[Ctrl+C]", Action: "quit"},
+	}
+}
+
+// AlertsContext provides footer items for the Alerts page
+type AlertsContext struct{}
+
+// GetItems returns footer items for the alerts tables
+func (c AlertsContext) GetItems() []FooterItem {
+	return []FooterItem{
+		{Key: "[↑/↓]", Action: "navigate"},
+		{Key: "[Enter]", Action: "detail"},
+		{Key: "[Tab]", Action: "next"},
+		{Key: "[ESC]", Action: "back"},
+		{Key: "[Ctrl+C]", Action: "quit"},
+	}
+}
+
+// ControlPlaneContext provides footer items for the Control Plane page
+type ControlPlaneContext struct{}
+
+// GetItems returns footer items for the control-plane page
+func (c ControlPlaneContext) GetItems() []FooterItem {
+	return []FooterItem{
+		{Key: "[↑/↓]", Action: "scroll workqueues"},
+		{Key: "[ESC]", Action: "back"},
+		{Key: "[Ctrl+C]", Action: "quit"},
+	}
+}
+
+// QueryContext provides footer items for the Query page
+type QueryContext struct{}
+
+// GetItems returns footer items for the query console
+func (c QueryContext) GetItems() []FooterItem {
+	return []FooterItem{
+		{Key: "[Enter]", Action: "run"},
+		{Key: "[Tab]", Action: "results"},
+		{Key: "[ESC]", Action: "back"},
+		{Key: "[Ctrl+C]", Action: "quit"},
+	}
+}
+
+// PressureContext provides footer items for the Pressure page
+type PressureContext struct{}
+
+// GetItems returns footer items for the pressure page
+func (c PressureContext) GetItems() []FooterItem {
+	return []FooterItem{
+		{Key: "[↑/↓]", Action: "navigate"},
+		{Key: "[Tab]", Action: "switch table"},
+		{Key: "[Enter]", Action: "container"},
+		{Key: "[ESC]", Action: "back"},
+		{Key: "[Ctrl+C]", Action: "quit"},
+	}
+}
+
+// RightsizingContext provides footer items for the Rightsizing page
+type RightsizingContext struct{}
+
+// GetItems returns footer items for the rightsizing page
+func (c RightsizingContext) GetItems() []FooterItem {
+	return []FooterItem{
+		{Key: "[↑/↓]", Action: "navigate"},
+		{Key: "[Enter]", Action: "workload"},
+		{Key: "[e]", Action: "export patch"},
+		{Key: "[r]", Action: "resample"},
+		{Key: "[ESC]", Action: "back"},
+		{Key: "[Ctrl+C]", Action: "quit"},
+	}
+}
+
+// CapacityContext provides footer items for the Capacity page
+type CapacityContext struct{}
+
+// GetItems returns footer items for the capacity page
+func (c CapacityContext) GetItems() []FooterItem {
+	return []FooterItem{
+		{Key: "[Enter]", Action: "what if"},
+		{Key: "[Tab]", Action: "shape/nodes"},
+		{Key: "[↑/↓]", Action: "navigate"},
+		{Key: "[ESC]", Action: "back"},
+		{Key: "[Ctrl+C]", Action: "quit"},
+	}
+}
+
+// EventsContext provides footer items for the Events page
+type EventsContext struct{}
+
+// GetItems returns footer items for the events page
+func (c EventsContext) GetItems() []FooterItem {
+	return []FooterItem{
+		{Key: "[↑/↓]", Action: "navigate"},
+		{Key: "[Enter]", Action: "object"},
+		{Key: "[w]", Action: "warning/normal"},
+		{Key: "[/]", Action: "filter"},
+		{Key: "[ESC]", Action: "back"},
+		{Key: "[Ctrl+C]", Action: "quit"},
+	}
+}
+
+// LogsContext provides footer items for the aggregated Logs page
+type LogsContext struct{}
+
+// GetItems returns footer items for the logs page
+func (c LogsContext) GetItems() []FooterItem {
+	return []FooterItem{
+		{Key: "[Enter]", Action: "tail pods"},
+		{Key: "[Tab]", Action: "pods/logs"},
+		{Key: "[s]", Action: "stream"},
+		{Key: "[t]", Action: "timestamps"},
+		{Key: "[w]", Action: "wrap"},
+		{Key: "[/]", Action: "filter"},
+		{Key: "[e]", Action: "level"},
+		{Key: "[i]", Action: "highlight"},
+		{Key: "[n/N]", Action: "match"},
+		{Key: "[o]", Action: "structured"},
+		{Key: "[v/V]", Action: "save"},
+		{Key: "[ESC]", Action: "back"},
+	}
+}
+
+// PortForwardsContext provides footer items for the Port Forwards page
+type PortForwardsContext struct{}
+
+// GetItems returns footer items for the port forwards page
+func (c PortForwardsContext) GetItems() []FooterItem {
+	return []FooterItem{
+		{Key: "[↑/↓]", Action: "navigate"},
+		{Key: "[Enter]", Action: "pod"},
+		{Key: "[s]", Action: "start/stop"},
+		{Key: "[d]", Action: "remove"},
+		{Key: "[ESC]", Action: "back"},
+		{Key: "[Ctrl+C]", Action: "quit"},
+	}
+}
+
 // PodDetailContext provides footer items for Pod Detail page
 type PodDetailContext struct {
 	FocusedPanel string // "events", "containers", "volumes"
@@ -83,6 +260,8 @@ func (c PodDetailContext) GetItems() []FooterItem {
 			{Key: "[Enter]", Action: "container"},
 			{Key: "[Tab]", Action: "next"},
 			{Key: "[n]", Action: "node"},
+			{Key: "[f]", Action: "port forward"},
+			{Key: "[x]", Action: "actions"},
 			{Key: "[ESC]", Action: "back"},
 			{Key: "[Ctrl+C]", Action: "quit"},
 		}
@@ -91,6 +270,8 @@ func (c PodDetailContext) GetItems() []FooterItem {
 			{Key: "[↑/↓]", Action: "scroll"},
 			{Key: "[Tab]", Action: "next"},
 			{Key: "[n]", Action: "node"},
+			{Key: "[f]", Action: "port forward"},
+			{Key: "[x]", Action: "actions"},
 			{Key: "[ESC]", Action: "back"},
 			{Key: "[Ctrl+C]", Action: "quit"},
 		}
@@ -113,7 +294,13 @@ func (c ContainerDetailContext) GetItems() []FooterItem {
 			{Key: "[w]", Action: "wrap"},
 			{Key: "[m]", Action: "more"},
 			{Key: "[/]", Action: "filter"},
+			{Key: "[e]", Action: "level"},
+			{Key: "[i]", Action: "highlight"},
+			{Key: "[n/N]", Action: "match"},
+			{Key: "[o]", Action: "structured"},
 			{Key: "[x]", Action: "expand"},
+			{Key: "[v/V]", Action: "save"},
+			{Key: "[d]", Action: "dump"},
 			{Key: "[g/G]", Action: "top/btm"},
 			{Key: "[ESC]", Action: "back"},
 		}
@@ -122,6 +309,7 @@ func (c ContainerDetailContext) GetItems() []FooterItem {
 			{Key: "[↑/↓]", Action: "scroll"},
 			{Key: "[Tab]", Action: "next"},
 			{Key: "[s]", Action: "spec"},
+			{Key: "[c]", Action: "shell"},
 			{Key: "[ESC]", Action: "back"},
 			{Key: "[Ctrl+C]", Action: "quit"},
 		}
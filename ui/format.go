@@ -2,6 +2,7 @@ package ui
 
 import (
 	"fmt"
+	"math"
 
 	"k8s.io/apimachinery/pkg/api/resource"
 )
@@ -65,6 +66,29 @@ func FormatBytes(bytes int64) string {
 	return fmt.Sprintf("%.1fG", float64(bytes)/(1024*1024*1024))
 }
 
+// FormatCompact formats a plain number (e.g., "12", "0.25", "3.4M")
+// Uses k/M/G/T decimal suffixes from 10k up; NaN and infinities show as "-"
+func FormatCompact(v float64) string {
+	abs := math.Abs(v)
+	switch {
+	case math.IsNaN(v) || math.IsInf(v, 0):
+		return "-"
+	case abs >= 1e12:
+		return fmt.Sprintf("%.1fT", v/1e12)
+	case abs >= 1e9:
+		return fmt.Sprintf("%.1fG", v/1e9)
+	case abs >= 1e6:
+		return fmt.Sprintf("%.1fM", v/1e6)
+	case abs >= 1e4:
+		return fmt.Sprintf("%.1fk", v/1e3)
+	case v == math.Trunc(v):
+		return fmt.Sprintf("%.0f", v)
+	case abs >= 10:
+		return fmt.Sprintf("%.1f", v)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
+
 // Truncate truncates a string to max length, adding "..." if truncated
 func Truncate(s string, max int) string {
 	if len(s) <= max {
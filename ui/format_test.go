@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"math"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -95,3 +96,27 @@ func TestFormatMemory(t *testing.T) {
 		})
 	}
 }
+
+func TestFormatCompact(t *testing.T) {
+	tests := []struct {
+		input    float64
+		expected string
+	}{
+		{0, "0"},
+		{42, "42"},
+		{0.256, "0.26"},
+		{12.34, "12.3"},
+		{9999, "9999"},
+		{12500, "12.5k"},
+		{3.4e6, "3.4M"},
+		{-2e9, "-2.0G"},
+		{7.5e12, "7.5T"},
+		{math.NaN(), "-"},
+	}
+
+	for _, tt := range tests {
+		if result := FormatCompact(tt.input); result != tt.expected {
+			t.Errorf("FormatCompact(%v) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,431 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// LogLevel is the severity of a log line
+type LogLevel int
+
+// Log levels, from least to most severe
+const (
+	LogLevelUnknown LogLevel = iota
+	LogLevelDebug
+	LogLevelInfo
+	LogLevelWarn
+	LogLevelError
+)
+
+// String returns the level as shown in the logs view
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	}
+	return "ALL"
+}
+
+// Color returns the tview color of lines of the level, or "" for the default
+func (l LogLevel) Color() string {
+	switch l {
+	case LogLevelDebug:
+		return "gray"
+	case LogLevelWarn:
+		return "yellow"
+	case LogLevelError:
+		return "red"
+	}
+	return ""
+}
+
+// ParseLogLevel reads a level name as loggers spell it: "warning", "W",
+// "err", "fatal" and so on. It returns LogLevelUnknown for other names.
+func ParseLogLevel(s string) LogLevel {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "trace", "debug", "dbg", "d", "t", "verbose":
+		return LogLevelDebug
+	case "info", "inf", "i", "notice", "information":
+		return LogLevelInfo
+	case "warn", "warning", "wrn", "w":
+		return LogLevelWarn
+	case "error", "err", "e", "fatal", "panic", "critical", "crit", "f", "alert", "emergency":
+		return LogLevelError
+	}
+	return LogLevelUnknown
+}
+
+// levelKeys are the fields structured loggers put the level in
+var levelKeys = []string{"level", "lvl", "severity", "log.level", "loglevel"}
+
+// logfmtLevel finds a level field in a logfmt line
+var logfmtLevel = regexp.MustCompile(`(?:^|\s)(?:level|lvl|severity)=("?)([A-Za-z]+)`)
+
+// klogLevel matches the header of klog lines, such as "E0115 10:30:00.000000"
+var klogLevel = regexp.MustCompile(`^([IWEF])\d{4} \d{2}:\d{2}:\d{2}`)
+
+// DetectLogLevel returns the severity of a log line from its level field
+// when it is JSON or logfmt, from its header when it comes from klog, and
+// otherwise from words such as "error" or "warn" in it. A leading RFC3339
+// timestamp, as added by the timestamps toggle, is skipped.
+func DetectLogLevel(line string) LogLevel {
+	line = StripLogTimestamp(line)
+
+	if strings.HasPrefix(line, "{") {
+		var fields map[string]interface{}
+		if json.Unmarshal([]byte(line), &fields) == nil {
+			for _, key := range levelKeys {
+				if value, ok := fields[key]; ok {
+					if level := parseLevelValue(value); level != LogLevelUnknown {
+						return level
+					}
+				}
+			}
+		}
+	}
+	if m := logfmtLevel.FindStringSubmatch(line); m != nil {
+		if level := ParseLogLevel(m[2]); level != LogLevelUnknown {
+			return level
+		}
+	}
+	if m := klogLevel.FindStringSubmatch(line); m != nil {
+		return ParseLogLevel(m[1])
+	}
+
+	lower := strings.ToLower(line)
+	switch {
+	case strings.Contains(lower, "error") || strings.Contains(lower, "fatal") || strings.Contains(lower, "panic"):
+		return LogLevelError
+	case strings.Contains(lower, "warn"):
+		return LogLevelWarn
+	}
+	return LogLevelUnknown
+}
+
+// parseLevelValue reads a level field, named or numeric as bunyan and pino
+// write it
+func parseLevelValue(value interface{}) LogLevel {
+	switch v := value.(type) {
+	case string:
+		return ParseLogLevel(v)
+	case float64:
+		switch {
+		case v >= 50:
+			return LogLevelError
+		case v >= 40:
+			return LogLevelWarn
+		case v >= 30:
+			return LogLevelInfo
+		case v >= 10:
+			return LogLevelDebug
+		}
+	}
+	return LogLevelUnknown
+}
+
+// StripLogTimestamp removes the RFC3339 timestamp the API server puts
+// before each line when asked for timestamps
+func StripLogTimestamp(line string) string {
+	if ts, rest, ok := SplitLogTimestamp(line); ok && ts != "" {
+		return rest
+	}
+	return line
+}
+
+// SplitLogTimestamp splits a line into its leading RFC3339 timestamp, such
+// as 2024-01-15T10:30:00.123456789Z, and the rest
+func SplitLogTimestamp(line string) (timestamp, rest string, ok bool) {
+	if len(line) > 30 && line[4] == '-' && line[7] == '-' && line[10] == 'T' {
+		if idx := strings.IndexByte(line, ' '); idx > 0 && idx < 36 {
+			return line[:idx], line[idx+1:], true
+		}
+	}
+	return "", line, false
+}
+
+// logTerm is a term of a log filter
+type logTerm struct {
+	re     *regexp.Regexp
+	invert bool
+}
+
+// LogFilter matches log lines against a query. The query is made of terms
+// separated by spaces, all of which must match:
+//
+//	timeout       lines containing "timeout", ignoring case
+//	/5\d\d$/      lines matching a regular expression
+//	!healthz      lines not containing "healthz" (also !/regexp/)
+//	level:warn    lines of level WARN or above
+//	-C 3          show 3 lines of context around matching lines
+//
+// Quote a term to include spaces: "connection refused".
+type LogFilter struct {
+	Query    string
+	MinLevel LogLevel
+	Context  int
+
+	terms []logTerm
+}
+
+// ParseLogFilter reads a log filter query
+func ParseLogFilter(query string) (*LogFilter, error) {
+	f := &LogFilter{Query: strings.TrimSpace(query)}
+	tokens, err := splitLogQuery(f.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token == "-C" || (strings.HasPrefix(token, "-C") && isDigits(token[2:])):
+			value := token[2:]
+			if value == "" {
+				if i+1 >= len(tokens) {
+					return nil, fmt.Errorf("-C needs a number of context lines")
+				}
+				i++
+				value = tokens[i]
+			}
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("-C %q: not a number of lines", value)
+			}
+			f.Context = n
+			continue
+		case strings.HasPrefix(strings.ToLower(token), "level:"):
+			level := ParseLogLevel(token[len("level:"):])
+			if level == LogLevelUnknown {
+				return nil, fmt.Errorf("unknown level %q: use debug, info, warn or error", token[len("level:"):])
+			}
+			f.MinLevel = level
+			continue
+		}
+
+		var term logTerm
+		if strings.HasPrefix(token, "!") && len(token) > 1 {
+			term.invert = true
+			token = token[1:]
+		}
+		if len(token) > 1 && strings.HasPrefix(token, "/") && strings.HasSuffix(token, "/") {
+			re, err := regexp.Compile(token[1 : len(token)-1])
+			if err != nil {
+				return nil, fmt.Errorf("regexp %s: %w", token, err)
+			}
+			term.re = re
+		} else {
+			term.re = regexp.MustCompile("(?i)" + regexp.QuoteMeta(token))
+		}
+		f.terms = append(f.terms, term)
+	}
+	return f, nil
+}
+
+// splitLogQuery splits a query at spaces outside of double quotes and
+// /regexp/ terms
+func splitLogQuery(query string) ([]string, error) {
+	var tokens []string
+	var b strings.Builder
+	inQuotes, inRegexp := false, false
+	for i, r := range query {
+		switch {
+		case r == '"' && !inRegexp:
+			inQuotes = !inQuotes
+		case r == '/' && !inQuotes && (inRegexp || ((b.Len() == 0 || b.String() == "!") && strings.Contains(query[i+1:], "/"))):
+			// A slash opens a regexp at the start of a term, when another
+			// one closes it, and closes it unless escaped
+			if inRegexp && i > 0 && query[i-1] == '\\' {
+				b.WriteRune(r)
+				continue
+			}
+			inRegexp = !inRegexp
+			b.WriteRune(r)
+		case r == ' ' && !inQuotes && !inRegexp:
+			if b.Len() > 0 {
+				tokens = append(tokens, b.String())
+				b.Reset()
+			}
+		default:
+			b.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in %q", query)
+	}
+	if b.Len() > 0 {
+		tokens = append(tokens, b.String())
+	}
+	return tokens, nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty reports whether the filter matches every line
+func (f *LogFilter) Empty() bool {
+	return f == nil || (len(f.terms) == 0 && f.MinLevel == LogLevelUnknown)
+}
+
+// Matches reports whether line is at least of MinLevel and matches all the
+// terms
+func (f *LogFilter) Matches(line string) bool {
+	return f.MatchesWith(line, "")
+}
+
+// MatchesWith is like Matches, with the terms also matched against prefix,
+// such as the pod the line comes from
+func (f *LogFilter) MatchesWith(line, prefix string) bool {
+	if f.Empty() {
+		return true
+	}
+	if f.MinLevel != LogLevelUnknown && DetectLogLevel(line) < f.MinLevel {
+		return false
+	}
+	text := line
+	if prefix != "" {
+		text = prefix + " " + line
+	}
+	for _, term := range f.terms {
+		if term.re.MatchString(text) == term.invert {
+			return false
+		}
+	}
+	return true
+}
+
+// Highlight escapes text for a tview.TextView and highlights the parts
+// matched by the filter's terms. color is the color of the rest of the
+// text, "" for the default.
+func (f *LogFilter) Highlight(text, color string) string {
+	if f == nil {
+		return tview.Escape(text)
+	}
+
+	type span struct{ start, end int }
+	var spans []span
+	for _, term := range f.terms {
+		if term.invert {
+			continue
+		}
+		for _, loc := range term.re.FindAllStringIndex(text, -1) {
+			if loc[1] > loc[0] {
+				spans = append(spans, span{loc[0], loc[1]})
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return tview.Escape(text)
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i].start < spans[j].start })
+
+	if color == "" {
+		color = "-"
+	}
+	var b strings.Builder
+	pos := 0
+	for _, s := range spans {
+		if s.end <= pos {
+			continue // Within a span already highlighted
+		}
+		start := max(s.start, pos)
+		b.WriteString(tview.Escape(text[pos:start]))
+		b.WriteString("[black:yellow]")
+		b.WriteString(tview.Escape(text[start:s.end]))
+		b.WriteString("[" + color + ":-]")
+		pos = s.end
+	}
+	b.WriteString(tview.Escape(text[pos:]))
+	return b.String()
+}
+
+// SelectedLine is a log line to show and why
+type SelectedLine[T any] struct {
+	Line  T
+	Match bool // false for a context line
+	Gap   bool // lines were left out before this one
+}
+
+// LogSelection picks the log lines to show as they come in. When hiding,
+// only matching lines and the filter's context lines around them are
+// shown; when highlighting, every line is.
+type LogSelection[T any] struct {
+	filter  *LogFilter
+	match   func(T) bool
+	hide    bool
+	pending []T  // lines left out, kept as context before the next match
+	after   int  // lines still to show as context after the last match
+	skipped bool // lines were left out since the last one shown
+	shown   bool // a line was shown
+}
+
+// NewLogSelection returns a selection of the lines for which match
+// reports true, hiding the others or not. Nothing matches an empty filter.
+func NewLogSelection[T any](filter *LogFilter, hide bool, match func(T) bool) *LogSelection[T] {
+	return &LogSelection[T]{filter: filter, match: match, hide: hide}
+}
+
+// Add returns the lines to show for line: none, line itself, or line
+// preceded by context lines left out before it
+func (s *LogSelection[T]) Add(line T) []SelectedLine[T] {
+	if s.filter.Empty() {
+		return []SelectedLine[T]{{Line: line}}
+	}
+	match := s.match(line)
+	if !s.hide {
+		return []SelectedLine[T]{{Line: line, Match: match}}
+	}
+
+	context := s.filter.Context
+	if !match {
+		if s.after > 0 {
+			s.after--
+			return s.show(SelectedLine[T]{Line: line})
+		}
+		if context > 0 {
+			s.pending = append(s.pending, line)
+			if len(s.pending) > context {
+				s.pending = s.pending[1:]
+				s.skipped = true
+			}
+		} else {
+			s.skipped = true
+		}
+		return nil
+	}
+
+	selected := make([]SelectedLine[T], 0, len(s.pending)+1)
+	for _, p := range s.pending {
+		selected = append(selected, s.show(SelectedLine[T]{Line: p})...)
+	}
+	s.pending = s.pending[:0]
+	s.after = context
+	return append(selected, s.show(SelectedLine[T]{Line: line, Match: true})...)
+}
+
+func (s *LogSelection[T]) show(line SelectedLine[T]) []SelectedLine[T] {
+	line.Gap = s.skipped && s.shown
+	s.skipped = false
+	s.shown = true
+	return []SelectedLine[T]{line}
+}
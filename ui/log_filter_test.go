@@ -0,0 +1,239 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectLogLevel(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want LogLevel
+	}{
+		{"json level", `{"level":"warn","msg":"slow request"}`, LogLevelWarn},
+		{"json severity", `{"severity":"ERROR","message":"boom"}`, LogLevelError},
+		{"json numeric level", `{"level":30,"msg":"listening"}`, LogLevelInfo},
+		{"json level wins over words", `{"level":"info","msg":"retrying after error"}`, LogLevelInfo},
+		{"logfmt", `ts=2024-01-15T10:30:00Z level=debug msg="cache miss"`, LogLevelDebug},
+		{"logfmt quoted", `time="2024-01-15" level="warning" msg=x`, LogLevelWarn},
+		{"klog", `E0115 10:30:00.123456       1 controller.go:42] sync failed`, LogLevelError},
+		{"klog info", `I0115 10:30:00.123456       1 main.go:10] starting`, LogLevelInfo},
+		{"keyword", `panic: runtime error: index out of range`, LogLevelError},
+		{"keyword warn", `WARNING: deprecated flag`, LogLevelWarn},
+		{"plain", `GET /healthz 200`, LogLevelUnknown},
+		{"timestamp prefix", `2024-01-15T10:30:00.123456789Z {"level":"error","msg":"x"}`, LogLevelError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLogLevel(tt.line); got != tt.want {
+				t.Errorf("DetectLogLevel(%q) = %v, want %v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLogFilter(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		matches []string
+		misses  []string
+		context int
+		wantErr bool
+	}{
+		{
+			name:    "plain term ignores case",
+			query:   "Timeout",
+			matches: []string{"dial tcp: i/o timeout"},
+			misses:  []string{"connected"},
+		},
+		{
+			name:    "terms must all match",
+			query:   "GET 500",
+			matches: []string{"GET /api 500"},
+			misses:  []string{"GET /api 200", "POST /api 500"},
+		},
+		{
+			name:    "regexp",
+			query:   `/ 5\d\d$/`,
+			matches: []string{"GET /api 503"},
+			misses:  []string{"GET /api 200", "GET /api 5000x"},
+		},
+		{
+			name:    "regexp with spaces",
+			query:   `/GET .* 5\d\d/`,
+			matches: []string{"GET /api 503"},
+			misses:  []string{"POST /api 503"},
+		},
+		{
+			name:    "leading slash without regexp",
+			query:   "/healthz 200",
+			matches: []string{"GET /healthz 200"},
+			misses:  []string{"GET /healthz 500"},
+		},
+		{
+			name:    "inverted term",
+			query:   "!healthz",
+			matches: []string{"GET /api 200"},
+			misses:  []string{"GET /healthz 200"},
+		},
+		{
+			name:    "inverted regexp",
+			query:   `GET !/ 2\d\d$/`,
+			matches: []string{"GET /api 404"},
+			misses:  []string{"GET /api 200"},
+		},
+		{
+			name:    "quoted term",
+			query:   `"connection refused"`,
+			matches: []string{"dial: connection refused"},
+			misses:  []string{"connection reset, refused to retry"},
+		},
+		{
+			name:    "level",
+			query:   "level:warn",
+			matches: []string{`{"level":"warn"}`, `level=error msg=x`},
+			misses:  []string{`{"level":"info"}`, "plain line"},
+		},
+		{
+			name:    "level and term",
+			query:   "level:error db",
+			matches: []string{`level=error msg="db down"`},
+			misses:  []string{`level=error msg="cache down"`, `level=info msg="db up"`},
+		},
+		{
+			name:    "context",
+			query:   "-C 2 foo",
+			matches: []string{"foo"},
+			context: 2,
+		},
+		{
+			name:    "context attached",
+			query:   "-C3 foo",
+			matches: []string{"foo"},
+			context: 3,
+		},
+		{name: "bad regexp", query: "/a(/", wantErr: true},
+		{name: "bad level", query: "level:loud", wantErr: true},
+		{name: "missing context", query: "foo -C", wantErr: true},
+		{name: "unterminated quote", query: `"foo`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ParseLogFilter(tt.query)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseLogFilter(%q) succeeded, want error", tt.query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLogFilter(%q): %v", tt.query, err)
+			}
+			for _, line := range tt.matches {
+				if !f.Matches(line) {
+					t.Errorf("%q does not match %q", tt.query, line)
+				}
+			}
+			for _, line := range tt.misses {
+				if f.Matches(line) {
+					t.Errorf("%q matches %q", tt.query, line)
+				}
+			}
+			if f.Context != tt.context {
+				t.Errorf("Context = %d, want %d", f.Context, tt.context)
+			}
+		})
+	}
+}
+
+func TestLogFilter_Empty(t *testing.T) {
+	var none *LogFilter
+	if !none.Empty() || !none.Matches("anything") {
+		t.Error("nil filter should be empty and match everything")
+	}
+	f, err := ParseLogFilter("  -C 2 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Empty() {
+		t.Error("filter with only context should be empty")
+	}
+}
+
+func TestLogFilter_MatchesWith(t *testing.T) {
+	f, _ := ParseLogFilter("web-1 level:error")
+	if !f.MatchesWith(`{"level":"error"}`, "web-1 app") {
+		t.Error("terms should match the prefix")
+	}
+	if f.MatchesWith(`{"level":"info"}`, "web-1 app") {
+		t.Error("level should be detected from the line only")
+	}
+}
+
+func TestLogFilter_Highlight(t *testing.T) {
+	f, err := ParseLogFilter("err !skip /[0-9]+/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := f.Highlight("Error 42 [x]", "red")
+	want := "[black:yellow]Err[red:-]or [black:yellow]42[red:-] [x[]"
+	if got != want {
+		t.Errorf("Highlight() = %q, want %q", got, want)
+	}
+
+	// Overlapping matches are highlighted once
+	f, _ = ParseLogFilter("abc bcd")
+	got = f.Highlight("abcde", "")
+	want = "[black:yellow]abc[-:-][black:yellow]d[-:-]e"
+	if got != want {
+		t.Errorf("Highlight() = %q, want %q", got, want)
+	}
+}
+
+func selectLines(f *LogFilter, hide bool, lines ...string) string {
+	s := NewLogSelection(f, hide, f.Matches)
+	var out []string
+	for _, line := range lines {
+		for _, sel := range s.Add(line) {
+			text := sel.Line
+			if sel.Gap {
+				out = append(out, "--")
+			}
+			if sel.Match {
+				text += "*"
+			}
+			out = append(out, text)
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+func TestLogSelection(t *testing.T) {
+	lines := []string{"a", "b", "foo1", "c", "d", "e", "f", "foo2", "g"}
+
+	f, _ := ParseLogFilter("foo")
+	if got, want := selectLines(f, true, lines...), "foo1* -- foo2*"; got != want {
+		t.Errorf("hide: got %q, want %q", got, want)
+	}
+
+	f, _ = ParseLogFilter("foo -C 1")
+	if got, want := selectLines(f, true, lines...), "b foo1* c -- f foo2* g"; got != want {
+		t.Errorf("hide with context: got %q, want %q", got, want)
+	}
+
+	f, _ = ParseLogFilter("foo -C 2")
+	if got, want := selectLines(f, true, lines...), "a b foo1* c d e f foo2* g"; got != want {
+		t.Errorf("hide with overlapping context: got %q, want %q", got, want)
+	}
+
+	f, _ = ParseLogFilter("foo")
+	if got, want := selectLines(f, false, lines...), "a b foo1* c d e f foo2* g"; got != want {
+		t.Errorf("highlight: got %q, want %q", got, want)
+	}
+
+	if got, want := selectLines(nil, true, "a", "b"), "a b"; got != want {
+		t.Errorf("no filter: got %q, want %q", got, want)
+	}
+}
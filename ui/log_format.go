@@ -0,0 +1,302 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rivo/tview"
+)
+
+// LogEntry is a JSON or logfmt log line split into its fields
+type LogEntry struct {
+	Time      string
+	Level     LogLevel
+	LevelName string // the level as written in the line
+	Message   string
+	Fields    []LogField // the other fields, in the order of the line
+}
+
+// LogField is a field of a structured log line. Values that aren't strings
+// are kept as compact JSON.
+type LogField struct {
+	Key   string
+	Value string
+}
+
+// Keys structured loggers put the time and the message in; see levelKeys
+// for the level
+var (
+	timeKeys    = []string{"time", "ts", "timestamp", "@timestamp", "date"}
+	messageKeys = []string{"msg", "message", "@message", "log"}
+)
+
+// ParseLogEntry splits a JSON object or logfmt line into its fields. ok is
+// false for lines that are neither.
+func ParseLogEntry(line string) (entry LogEntry, ok bool) {
+	line = strings.TrimSpace(line)
+	var fields []LogField
+	if strings.HasPrefix(line, "{") {
+		fields, ok = parseJSONFields(line)
+	} else {
+		fields, ok = parseLogfmtFields(line)
+	}
+	if !ok {
+		return LogEntry{}, false
+	}
+
+	take := func(keys []string) (string, bool) {
+		for _, key := range keys {
+			for i, f := range fields {
+				if strings.EqualFold(f.Key, key) {
+					fields = append(fields[:i], fields[i+1:]...)
+					return f.Value, true
+				}
+			}
+		}
+		return "", false
+	}
+	entry.Time, _ = take(timeKeys)
+	if name, found := take(levelKeys); found {
+		entry.LevelName = name
+		entry.Level = ParseLogLevel(name)
+		if n, err := strconv.ParseFloat(name, 64); err == nil {
+			entry.Level = parseLevelValue(n)
+		}
+	}
+	entry.Message, _ = take(messageKeys)
+	if len(fields) > 0 {
+		entry.Fields = fields
+	}
+	return entry, true
+}
+
+// parseJSONFields reads the fields of a JSON object in their order
+func parseJSONFields(line string) ([]LogField, bool) {
+	dec := json.NewDecoder(strings.NewReader(line))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, false
+	}
+
+	var fields []LogField
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return nil, false
+		}
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return nil, false
+		}
+		fields = append(fields, LogField{Key: key, Value: jsonValue(raw)})
+	}
+	if _, err := dec.Token(); err != nil { // Closing brace
+		return nil, false
+	}
+	return fields, true
+}
+
+// jsonValue returns a JSON string as is and other values as compact JSON
+func jsonValue(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var b bytes.Buffer
+	if json.Compact(&b, raw) != nil {
+		return string(raw)
+	}
+	return b.String()
+}
+
+// parseLogfmtFields reads key=value pairs, values quoted when they contain
+// spaces. Lines with anything else, or fewer than two pairs, aren't logfmt.
+func parseLogfmtFields(line string) ([]LogField, bool) {
+	var fields []LogField
+	for i := 0; i < len(line); {
+		if line[i] == ' ' {
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(line) && line[i] != '=' && line[i] != ' ' && line[i] != '"' {
+			i++
+		}
+		if i == start || i >= len(line) || line[i] != '=' {
+			return nil, false
+		}
+		key := line[start:i]
+		i++
+
+		var value string
+		if i < len(line) && line[i] == '"' {
+			end := i + 1
+			for end < len(line) && line[end] != '"' {
+				if line[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(line) {
+				return nil, false
+			}
+			unquoted, err := strconv.Unquote(line[i : end+1])
+			if err != nil {
+				unquoted = line[i+1 : end]
+			}
+			value = unquoted
+			i = end + 1
+		} else {
+			start := i
+			for i < len(line) && line[i] != ' ' {
+				i++
+			}
+			value = line[start:i]
+		}
+		fields = append(fields, LogField{Key: key, Value: value})
+	}
+	return fields, len(fields) >= 2
+}
+
+// logMessageWidth is the width messages are padded to, so that the fields
+// after them line up
+const logMessageWidth = 60
+
+// LogLayout renders structured log lines as aligned columns: the time, the
+// level, the message, the promoted fields and then the other fields
+type LogLayout struct {
+	Fields   []string // keys of the fields promoted after the message
+	Expanded bool     // show the other fields; otherwise only how many there are
+}
+
+// Format renders entry, highlighting what filter matched in it
+func (l *LogLayout) Format(entry LogEntry, filter *LogFilter) string {
+	var b strings.Builder
+
+	b.WriteString("[gray]" + fmt.Sprintf("%-12s", formatLogTime(entry.Time)) + "[-] ")
+
+	levelName := entry.Level.String()
+	if entry.Level == LogLevelUnknown {
+		levelName = strings.ToUpper(entry.LevelName)
+	}
+	if len(levelName) > 5 {
+		levelName = levelName[:5]
+	}
+	color := entry.Level.Color()
+	if color == "" {
+		color = "white"
+	}
+	fmt.Fprintf(&b, "[%s::b]%s[-::-] ", color, tview.Escape(fmt.Sprintf("%-5s", levelName)))
+
+	message := oneLine(entry.Message)
+	b.WriteString(filter.Highlight(message, ""))
+
+	var promoted, others []LogField
+	for _, f := range entry.Fields {
+		if l.promoted(f.Key) {
+			promoted = append(promoted, f)
+		} else {
+			others = append(others, f)
+		}
+	}
+	if len(promoted) == 0 && len(others) == 0 {
+		return b.String()
+	}
+	if pad := logMessageWidth - utf8.RuneCountInString(message); pad > 0 {
+		b.WriteString(strings.Repeat(" ", pad))
+	}
+
+	// Promoted fields in the order they were chosen
+	for _, key := range l.Fields {
+		for _, f := range promoted {
+			if strings.EqualFold(f.Key, key) {
+				fmt.Fprintf(&b, " [aqua]%s[-]=%s", tview.Escape(f.Key), filter.Highlight(oneLine(f.Value), ""))
+			}
+		}
+	}
+	switch {
+	case len(others) == 0:
+	case l.Expanded:
+		for _, f := range others {
+			fmt.Fprintf(&b, " [gray]%s=%s[-]", tview.Escape(f.Key), filter.Highlight(oneLine(f.Value), "gray"))
+		}
+	case len(others) == 1:
+		b.WriteString(" [gray]+1 field[-]")
+	default:
+		fmt.Fprintf(&b, " [gray]+%d fields[-]", len(others))
+	}
+	return b.String()
+}
+
+func (l *LogLayout) promoted(key string) bool {
+	for _, k := range l.Fields {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatLogTime shortens a time field to the time of day, reading RFC3339
+// times and Unix times in seconds, milliseconds or nanoseconds
+func formatLogTime(value string) string {
+	if value == "" {
+		return ""
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.Local().Format("15:04:05.000")
+	}
+	if n, err := strconv.ParseFloat(value, 64); err == nil && n > 0 {
+		var t time.Time
+		switch {
+		case n < 1e11:
+			t = time.Unix(0, int64(n*1e9))
+		case n < 1e14:
+			t = time.UnixMilli(int64(n))
+		default:
+			t = time.Unix(0, int64(n))
+		}
+		return t.Local().Format("15:04:05.000")
+	}
+	return value
+}
+
+func oneLine(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "\r", ""), "\n", `\n`)
+}
+
+// FormatLogLine renders a raw log line for a tview.TextView. With a layout,
+// JSON and logfmt lines are shown as columns; other lines are colored by
+// their level. A leading timestamp, as added by the timestamps toggle, is
+// grayed, and the parts filter matched are highlighted.
+func FormatLogLine(line string, layout *LogLayout, filter *LogFilter) string {
+	timestamp, rest, hasTimestamp := SplitLogTimestamp(line)
+
+	var formatted string
+	entry, structured := LogEntry{}, false
+	if layout != nil {
+		entry, structured = ParseLogEntry(rest)
+	}
+	if structured {
+		formatted = layout.Format(entry, filter)
+	} else {
+		color := DetectLogLevel(rest).Color()
+		formatted = filter.Highlight(rest, color)
+		if color != "" {
+			formatted = "[" + color + "]" + formatted
+		}
+	}
+	if hasTimestamp {
+		formatted = "[gray]" + timestamp + "[-] " + formatted
+	}
+	return formatted + "[-:-:-]"
+}
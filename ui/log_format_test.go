@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLogEntry(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		want  LogEntry
+		notOK bool
+	}{
+		{
+			name: "json",
+			line: `{"ts":"2024-01-15T10:30:00Z","level":"warn","msg":"slow query","ms":1200,"user":"bob","tags":["a", "b"]}`,
+			want: LogEntry{
+				Time: "2024-01-15T10:30:00Z", Level: LogLevelWarn, LevelName: "warn", Message: "slow query",
+				Fields: []LogField{{"ms", "1200"}, {"user", "bob"}, {"tags", `["a","b"]`}},
+			},
+		},
+		{
+			name: "json numeric level",
+			line: `{"level":50,"time":1705314600000,"msg":"boom"}`,
+			want: LogEntry{Time: "1705314600000", Level: LogLevelError, LevelName: "50", Message: "boom"},
+		},
+		{
+			name: "logfmt",
+			line: `time=2024-01-15T10:30:00Z level=info msg="user logged in" user=bob path=/login`,
+			want: LogEntry{
+				Time: "2024-01-15T10:30:00Z", Level: LogLevelInfo, LevelName: "info", Message: "user logged in",
+				Fields: []LogField{{"user", "bob"}, {"path", "/login"}},
+			},
+		},
+		{
+			name: "logfmt escaped quote",
+			line: `level=error msg="bad \"input\"" code=400`,
+			want: LogEntry{
+				Level: LogLevelError, LevelName: "error", Message: `bad "input"`,
+				Fields: []LogField{{"code", "400"}},
+			},
+		},
+		{name: "plain", line: "GET /healthz 200", notOK: true},
+		{name: "single pair", line: "ready=true", notOK: true},
+		{name: "text with pairs", line: "starting server port=8080 tls=false", notOK: true},
+		{name: "broken json", line: `{"level":"info"`, notOK: true},
+		{name: "json array", line: `["a"]`, notOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseLogEntry(tt.line)
+			if ok == tt.notOK {
+				t.Fatalf("ParseLogEntry(%q) ok = %v", tt.line, ok)
+			}
+			if ok && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLogEntry(%q) =\n%+v, want\n%+v", tt.line, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogLayout_Format(t *testing.T) {
+	entry := LogEntry{
+		Level: LogLevelError, Message: "db down",
+		Fields: []LogField{{"host", "db-1"}, {"user", "bob"}, {"retry", "3"}},
+	}
+
+	collapsed := (&LogLayout{Fields: []string{"user"}}).Format(entry, nil)
+	for _, want := range []string{"[red::b]ERROR[-::-]", "db down", "[aqua]user[-]=bob", "+2 fields"} {
+		if !strings.Contains(collapsed, want) {
+			t.Errorf("collapsed %q does not contain %q", collapsed, want)
+		}
+	}
+	if strings.Contains(collapsed, "db-1") {
+		t.Errorf("collapsed %q shows a field that isn't promoted", collapsed)
+	}
+	// Promoted fields line up after the message
+	if i := strings.Index(collapsed, "[aqua]"); i < strings.Index(collapsed, "db down")+logMessageWidth-len("db down") {
+		t.Errorf("promoted fields not aligned in %q", collapsed)
+	}
+
+	expanded := (&LogLayout{Expanded: true}).Format(entry, nil)
+	for _, want := range []string{"[gray]host=db-1[-]", "[gray]user=bob[-]", "[gray]retry=3[-]"} {
+		if !strings.Contains(expanded, want) {
+			t.Errorf("expanded %q does not contain %q", expanded, want)
+		}
+	}
+
+	filter, _ := ParseLogFilter("down")
+	highlighted := (&LogLayout{}).Format(entry, filter)
+	if !strings.Contains(highlighted, "db [black:yellow]down[-:-]") {
+		t.Errorf("match not highlighted in %q", highlighted)
+	}
+}
+
+func TestFormatLogTime(t *testing.T) {
+	at := time.Date(2024, 1, 15, 10, 30, 0, 500_000_000, time.UTC)
+	want := at.Local().Format("15:04:05.000")
+	for _, value := range []string{"2024-01-15T10:30:00.5Z", "1705314600.5", "1705314600500", "1705314600500000000"} {
+		if got := formatLogTime(value); got != want {
+			t.Errorf("formatLogTime(%q) = %q, want %q", value, got, want)
+		}
+	}
+	if got := formatLogTime("yesterday"); got != "yesterday" {
+		t.Errorf("formatLogTime(yesterday) = %q", got)
+	}
+}
+
+func TestFormatLogLine(t *testing.T) {
+	line := `2024-01-15T10:30:00.123456789Z {"level":"error","msg":"boom"}`
+
+	raw := FormatLogLine(line, nil, nil)
+	if want := `[gray]2024-01-15T10:30:00.123456789Z[-] [red]{"level":"error","msg":"boom"}[-:-:-]`; raw != want {
+		t.Errorf("raw = %q, want %q", raw, want)
+	}
+
+	structured := FormatLogLine(line, &LogLayout{}, nil)
+	if !strings.Contains(structured, "[red::b]ERROR[-::-] boom") {
+		t.Errorf("structured = %q", structured)
+	}
+
+	// Lines that aren't structured are shown as they are
+	if got := FormatLogLine("plain [text]", &LogLayout{}, nil); got != "plain [text[][-:-:-]" {
+		t.Errorf("plain = %q", got)
+	}
+}
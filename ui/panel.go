@@ -34,6 +34,14 @@ type EscapablePanel interface {
 	HandleEscape() bool
 }
 
+// SessionPanel is an optional interface for page panels that hold
+// per-cluster state. Rebind is called on the UI goroutine after the
+// application switches kubeconfig context or namespace; ctx is cancelled
+// when that connection is replaced in turn.
+type SessionPanel interface {
+	Rebind(ctx context.Context) error
+}
+
 // FocusablePanel is an optional interface that panels can implement
 // to support visual focus indication with double-border and color change
 type FocusablePanel interface {
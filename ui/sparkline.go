@@ -5,6 +5,7 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/theme"
 )
 
 // Sparkline is a tview primitive that displays a sparkline chart.
@@ -169,5 +170,5 @@ func (s *Sparkline) Draw(screen tcell.Screen) {
 
 // DefaultColorKeys returns standard color thresholds for sparklines.
 func DefaultColorKeys() ColorKeys {
-	return ColorKeys{0: "olivedrab", 50: "yellow", 90: "red"}
+	return ColorKeys{0: "olivedrab", theme.UsageMedium: "yellow", theme.UsageHigh: "red"}
 }
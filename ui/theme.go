@@ -1,6 +1,12 @@
 package ui
 
-import "github.com/gdamore/tcell/v2"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/vladimirvivien/ktop/theme"
+)
 
 // Theme contains all color and style constants for the ktop UI
 // This allows for centralized theming and easy color customization
@@ -134,6 +140,58 @@ var Theme = struct {
 	UnfocusBorderColor: "lightgray",  // Border color when panel is unfocused
 }
 
+// defaultTheme snapshots Theme's initial values so ApplyTheme can reset
+// before layering a preset on top.
+var defaultTheme = Theme
+
+// themePresets adjusts Theme away from the defaults. Presets only touch the
+// fields they care about; thresholds are shared by all themes.
+var themePresets = map[string]func(){
+	theme.Default: func() {},
+	theme.Light: func() {
+		Theme.HeaderForeground = "black"
+		Theme.HeaderBackground = "lightcyan"
+		Theme.HeaderSortIndicator = "black"
+		Theme.SelectionBackground = "darkgray"
+		Theme.SelectionForeground = "white"
+		Theme.StatusOK = "darkgreen"
+		Theme.DataPrimary = "darkblue"
+		Theme.DataSecondary = "black"
+		Theme.DataHighlight = "teal"
+		Theme.BorderColor = "black"
+		Theme.SparklineNormal = "darkgreen"
+		Theme.TrendNormalColor = "darkgreen"
+		Theme.UnfocusBorderColor = "gray"
+	},
+	theme.HighContrast: func() {
+		Theme.HeaderBackground = "blue"
+		Theme.HeaderShortcutKey = "yellow"
+		Theme.SelectionBackground = "white"
+		Theme.StatusOK = "lime"
+		Theme.DataSecondary = "white"
+		Theme.SparklineNormal = "lime"
+		Theme.TrendNormalColor = "lime"
+		Theme.FocusBorderColor = "yellow"
+		Theme.UnfocusBorderColor = "white"
+	},
+}
+
+// ApplyTheme resets Theme to the defaults and applies the named preset, one
+// of theme.Names. It must be called before the UI is built since views read
+// Theme when drawing.
+func ApplyTheme(name string) error {
+	if name == "" {
+		name = theme.Default
+	}
+	preset, ok := themePresets[name]
+	if !ok {
+		return fmt.Errorf("unknown theme: %s (valid: %s)", name, strings.Join(theme.Names(), ", "))
+	}
+	Theme = defaultTheme
+	preset()
+	return nil
+}
+
 // FormatTag returns a tview color/style tag string
 // Usage: FormatTag(Theme.HeaderShortcutKey, "", "b") returns "[orange::b]"
 func FormatTag(foreground, background, attributes string) string {
@@ -0,0 +1,20 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/vladimirvivien/ktop/theme"
+)
+
+func TestApplyTheme(t *testing.T) {
+	defer ApplyTheme(theme.Default)
+
+	for _, name := range theme.Names() {
+		if err := ApplyTheme(name); err != nil {
+			t.Errorf("ApplyTheme(%q): %v", name, err)
+		}
+	}
+	if err := ApplyTheme("solarized"); err == nil {
+		t.Error("expected an error for an unknown theme")
+	}
+}
@@ -0,0 +1,258 @@
+package alerts
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/alerts"
+	"github.com/vladimirvivien/ktop/ui"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// AlertSelectedCallback is called when an alert row is selected
+type AlertSelectedCallback func(alert alerts.Alert)
+
+// Panel lists the firing alerts and the history of resolved ones
+type Panel struct {
+	root    *tview.Flex
+	laidout bool
+
+	firing  []alerts.Alert
+	history []alerts.Alert
+
+	firingPanel  *tview.Flex
+	firingTable  *tview.Table
+	historyPanel *tview.Flex
+	historyTable *tview.Table
+	focusIdx     int // 0 = firing, 1 = history
+
+	setAppFocus func(p tview.Primitive)
+
+	// Callbacks
+	onSelected AlertSelectedCallback
+	onBack     func()
+}
+
+// NewPanel creates a new alerts panel
+func NewPanel() *Panel {
+	p := &Panel{}
+	p.Layout(nil)
+	return p
+}
+
+// SetOnSelected sets the callback for when an alert is selected
+func (p *Panel) SetOnSelected(callback AlertSelectedCallback) {
+	p.onSelected = callback
+}
+
+// SetOnBack sets the callback for when user navigates back
+func (p *Panel) SetOnBack(callback func()) {
+	p.onBack = callback
+}
+
+// SetAppFocus sets the callback used to focus primitives in the tview app
+func (p *Panel) SetAppFocus(fn func(p tview.Primitive)) {
+	p.setAppFocus = fn
+}
+
+// GetTitle returns the panel title
+func (p *Panel) GetTitle() string {
+	return "Alerts"
+}
+
+// Layout initializes the panel UI
+func (p *Panel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.firingTable = p.newTable(func() []alerts.Alert { return p.firing })
+	p.firingPanel = tview.NewFlex().SetDirection(tview.FlexRow)
+	p.firingPanel.SetBorder(true)
+	p.firingPanel.SetTitle(" Firing ")
+	p.firingPanel.SetTitleAlign(tview.AlignLeft)
+	p.firingPanel.AddItem(p.firingTable, 0, 1, true)
+
+	p.historyTable = p.newTable(func() []alerts.Alert { return p.history })
+	p.historyPanel = tview.NewFlex().SetDirection(tview.FlexRow)
+	p.historyPanel.SetBorder(true)
+	p.historyPanel.SetTitle(" History ")
+	p.historyPanel.SetTitleAlign(tview.AlignLeft)
+	p.historyPanel.AddItem(p.historyTable, 0, 1, false)
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.firingPanel, 0, 1, true).
+		AddItem(p.historyPanel, 0, 1, false)
+	p.root.SetBorder(true)
+	p.root.SetTitle(fmt.Sprintf(" %s Alerts ", ui.Icons.TrafficLight))
+	p.root.SetTitleAlign(tview.AlignCenter)
+	p.updateFocusColors()
+	p.laidout = true
+}
+
+// newTable creates an alerts table whose rows come from rows()
+func (p *Panel) newTable(rows func() []alerts.Alert) *tview.Table {
+	table := tview.NewTable()
+	table.SetFixed(1, 0) // Fixed header row
+	table.SetSelectable(true, false)
+	table.SetBorder(false)
+	table.SetBorders(false)
+	table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorLightGray).Foreground(tcell.ColorBlack))
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyTab, tcell.KeyBacktab:
+			p.focusIdx = 1 - p.focusIdx
+			p.InitFocus()
+			return nil
+		case tcell.KeyEscape:
+			if p.onBack != nil {
+				p.onBack()
+				return nil
+			}
+		case tcell.KeyEnter:
+			row, _ := table.GetSelection()
+			list := rows()
+			if row > 0 && row-1 < len(list) && p.onSelected != nil {
+				p.onSelected(list[row-1])
+				return nil
+			}
+		}
+		return event
+	})
+	return table
+}
+
+// DrawHeader draws the header row
+func (p *Panel) DrawHeader(_ interface{}) {}
+
+// DrawBody draws the firing alerts and history from an *alerts.Engine
+func (p *Panel) DrawBody(data interface{}) {
+	engine, ok := data.(*alerts.Engine)
+	if !ok || engine == nil {
+		return
+	}
+	p.firing = engine.Firing()
+	p.history = engine.History()
+
+	p.root.SetTitle(fmt.Sprintf(" %s Alerts (%d rules) ", ui.Icons.TrafficLight, len(engine.Rules())))
+	p.firingPanel.SetTitle(fmt.Sprintf(" Firing (%d) ", len(p.firing)))
+	p.historyPanel.SetTitle(fmt.Sprintf(" History (%d) ", len(p.history)))
+	drawTable(p.firingTable, p.firing, "FIRING FOR", func(a alerts.Alert) string {
+		return since(a.FiredAt)
+	})
+	drawTable(p.historyTable, p.history, "RESOLVED", func(a alerts.Alert) string {
+		return fmt.Sprintf("%s ago (lasted %s)", since(a.ResolvedAt), duration.HumanDuration(a.ResolvedAt.Sub(a.FiredAt)))
+	})
+}
+
+// drawTable draws list into table; timeCol formats the last column
+func drawTable(table *tview.Table, list []alerts.Alert, timeHeader string, timeCol func(alerts.Alert) string) {
+	// Save current selection before clearing
+	selectedRow, selectedCol := table.GetSelection()
+
+	table.Clear()
+	headers := []string{"SEVERITY", "RULE", "TARGET", "SUBJECT", "VALUE", "CONDITION", timeHeader}
+	for col, header := range headers {
+		table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorWhite).
+			SetBackgroundColor(tcell.ColorDarkCyan).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+
+	for row, a := range list {
+		rowIdx := row + 1 // Offset for header
+
+		severityColor := tcell.ColorYellow
+		if a.Severity == alerts.SeverityCritical {
+			severityColor = tcell.ColorRed
+		}
+		if !a.Firing() {
+			severityColor = tcell.ColorGray
+		}
+
+		table.SetCell(rowIdx, 0, tview.NewTableCell(string(a.Severity)).SetTextColor(severityColor))
+		table.SetCell(rowIdx, 1, tview.NewTableCell(a.Rule).SetTextColor(tcell.ColorWhite))
+		table.SetCell(rowIdx, 2, tview.NewTableCell(string(a.Target)).SetTextColor(tcell.ColorWhite))
+		table.SetCell(rowIdx, 3, tview.NewTableCell(a.Subject).SetTextColor(tcell.ColorWhite).SetMaxWidth(50))
+		table.SetCell(rowIdx, 4, tview.NewTableCell(a.Condition.FormatValue(a.Value)).SetTextColor(severityColor))
+		table.SetCell(rowIdx, 5, tview.NewTableCell(a.Condition.String()).SetTextColor(tcell.ColorGray))
+		table.SetCell(rowIdx, 6, tview.NewTableCell(timeCol(a)).SetTextColor(tcell.ColorGray))
+	}
+
+	// Restore selection (clamped to valid range)
+	if len(list) == 0 {
+		return
+	}
+	if selectedRow < 1 {
+		selectedRow = 1
+	} else if selectedRow > len(list) {
+		selectedRow = len(list)
+	}
+	table.Select(selectedRow, selectedCol)
+}
+
+func since(t time.Time) string {
+	return duration.HumanDuration(time.Since(t))
+}
+
+// updateFocusColors highlights the border of the focused table
+func (p *Panel) updateFocusColors() {
+	p.firingPanel.SetBorderColor(tcell.ColorLightGray)
+	p.historyPanel.SetBorderColor(tcell.ColorLightGray)
+	if p.focusIdx == 0 {
+		p.firingPanel.SetBorderColor(tcell.ColorDodgerBlue)
+	} else {
+		p.historyPanel.SetBorderColor(tcell.ColorDodgerBlue)
+	}
+}
+
+// DrawFooter draws the footer
+func (p *Panel) DrawFooter(_ interface{}) {}
+
+// Clear clears the panel
+func (p *Panel) Clear() {
+	p.firingTable.Clear()
+	p.historyTable.Clear()
+	p.firing = nil
+	p.history = nil
+}
+
+// GetRootView returns the root view
+func (p *Panel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+// GetChildrenViews returns child views
+func (p *Panel) GetChildrenViews() []tview.Primitive {
+	return []tview.Primitive{p.firingTable, p.historyTable}
+}
+
+// InitFocus focuses the table selected with Tab, the firing table at first
+func (p *Panel) InitFocus() {
+	p.updateFocusColors()
+	if p.setAppFocus == nil {
+		return
+	}
+	if p.focusIdx == 0 {
+		p.setAppFocus(p.firingTable)
+	} else {
+		p.setAppFocus(p.historyTable)
+	}
+}
+
+// HasEscapableState implements ui.EscapablePanel
+func (p *Panel) HasEscapableState() bool {
+	return true // Always allow ESC to go back
+}
+
+// HandleEscape implements ui.EscapablePanel
+func (p *Panel) HandleEscape() bool {
+	if p.onBack != nil {
+		p.onBack()
+		return true
+	}
+	return false
+}
@@ -0,0 +1,348 @@
+package capacity
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/ui"
+	"github.com/vladimirvivien/ktop/views/model"
+)
+
+// column is a table column; a non-zero key sorts the table by it
+type column struct {
+	name string
+	key  rune
+}
+
+var columns = []column{
+	{"NODE", 'n'}, {"STATUS", 0},
+	{"CPU ALLOC", 0}, {"CPU REQ", 0}, {"CPU FREE", 'c'},
+	{"MEM ALLOC", 0}, {"MEM REQ", 0}, {"MEM FREE", 'm'},
+	{"PODS FREE", 'p'}, {"FITS", 'f'},
+}
+
+// Panel shows the requestable resources left on each node and where a pod
+// of a given shape would fit
+type Panel struct {
+	root    *tview.Flex
+	laidout bool
+
+	input   *tview.InputField
+	message *tview.TextView
+	table   *tview.Table
+
+	nodes      []model.NodeCapacity
+	shape      *model.PodShape
+	sortColumn string
+	sortAsc    bool
+
+	setAppFocus func(p tview.Primitive)
+	onBack      func()
+}
+
+// NewPanel creates a new capacity panel
+func NewPanel() *Panel {
+	p := &Panel{sortColumn: "CPU FREE"}
+	p.Layout(nil)
+	return p
+}
+
+// SetOnBack sets the callback for when user navigates back
+func (p *Panel) SetOnBack(callback func()) {
+	p.onBack = callback
+}
+
+// SetAppFocus sets the callback used to focus primitives in the tview app
+func (p *Panel) SetAppFocus(fn func(p tview.Primitive)) {
+	p.setAppFocus = fn
+}
+
+// GetTitle returns the panel title
+func (p *Panel) GetTitle() string {
+	return "Capacity"
+}
+
+// Layout initializes the panel UI
+func (p *Panel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.input = tview.NewInputField().
+		SetLabel(" What if a pod requests: ").
+		SetLabelColor(tcell.ColorYellow).
+		SetFieldBackgroundColor(tcell.ColorDefault).
+		SetPlaceholder("e.g. 4/16Gi, or cpu=500m memory=1Gi tolerate=dedicated").
+		SetPlaceholderTextColor(tcell.ColorGray)
+	p.input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			p.setShape(strings.TrimSpace(p.input.GetText()))
+		case tcell.KeyTab, tcell.KeyBacktab, tcell.KeyDown:
+			p.focus(p.table)
+		}
+	})
+
+	p.message = tview.NewTextView().SetDynamicColors(true)
+
+	p.table = tview.NewTable()
+	p.table.SetFixed(1, 0) // Fixed header row
+	p.table.SetSelectable(true, false)
+	p.table.SetBorder(false)
+	p.table.SetBorders(false)
+	p.table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorLightGray).Foreground(tcell.ColorBlack))
+	p.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyTab, tcell.KeyBacktab:
+			p.focus(p.input)
+			return nil
+		case tcell.KeyEscape:
+			if p.onBack != nil {
+				p.onBack()
+				return nil
+			}
+		case tcell.KeyRune:
+			if p.toggleSort(event.Rune()) {
+				p.draw()
+				return nil
+			}
+		}
+		return event
+	})
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.input, 1, 0, true).
+		AddItem(p.message, 1, 0, false).
+		AddItem(p.table, 0, 1, false)
+	p.root.SetBorder(true)
+	p.root.SetTitle(fmt.Sprintf(" %s Capacity ", ui.Icons.Package))
+	p.root.SetTitleAlign(tview.AlignCenter)
+	p.laidout = true
+}
+
+func (p *Panel) focus(prim tview.Primitive) {
+	if p.setAppFocus != nil {
+		p.setAppFocus(prim)
+	}
+}
+
+// setShape parses text as the what-if pod shape, or clears it when empty
+func (p *Panel) setShape(text string) {
+	if text == "" {
+		p.shape = nil
+		p.draw()
+		return
+	}
+	shape, err := model.ParsePodShape(text)
+	if err != nil {
+		p.message.SetText(" [red]" + tview.Escape(err.Error()))
+		return
+	}
+	p.shape = &shape
+	p.sortColumn, p.sortAsc = "FITS", false
+	p.draw()
+}
+
+// toggleSort sorts by the column with key, or reverses the direction when
+// the table is already sorted by it. It reports whether key sorts a column.
+func (p *Panel) toggleSort(key rune) bool {
+	for _, col := range columns {
+		if col.key == 0 || col.key != key {
+			continue
+		}
+		if p.sortColumn == col.name {
+			p.sortAsc = !p.sortAsc
+		} else {
+			p.sortColumn, p.sortAsc = col.name, true
+		}
+		return true
+	}
+	return false
+}
+
+// DrawHeader draws the header row
+func (p *Panel) DrawHeader(_ interface{}) {}
+
+// DrawBody draws a []model.NodeCapacity, or the error listing nodes failed with
+func (p *Panel) DrawBody(data interface{}) {
+	if err, ok := data.(error); ok {
+		p.message.SetText(" [red]" + tview.Escape(err.Error()))
+		return
+	}
+	nodes, ok := data.([]model.NodeCapacity)
+	if !ok {
+		return
+	}
+	p.nodes = nodes
+	p.draw()
+}
+
+func (p *Panel) draw() {
+	p.drawMessage()
+	model.SortNodeCapacitiesBy(p.nodes, p.sortColumn, p.sortAsc, p.shape)
+
+	// Save current selection before clearing
+	selectedRow, _ := p.table.GetSelection()
+
+	p.table.Clear()
+	for col, c := range columns {
+		p.table.SetCell(0, col, tview.NewTableCell(p.formatHeader(c)).
+			SetTextColor(tcell.ColorWhite).
+			SetBackgroundColor(tcell.ColorDarkCyan).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+	for i, n := range p.nodes {
+		statusColor := tcell.ColorGreen
+		status := n.Status
+		switch n.Status {
+		case model.CapacityNotReady, model.CapacityCordoned:
+			statusColor = tcell.ColorRed
+		case model.CapacityTainted:
+			statusColor = tcell.ColorYellow
+			status = fmt.Sprintf("Tainted (%s)", formatTaints(n))
+		}
+
+		fits, fitsColor := "-", tcell.ColorGray
+		if p.shape != nil {
+			replicas := n.Replicas(*p.shape)
+			fits = fmt.Sprintf("%d", replicas)
+			if replicas > 0 {
+				fitsColor = tcell.ColorGreen
+			}
+		}
+
+		cells := []*tview.TableCell{
+			tview.NewTableCell(n.Name),
+			tview.NewTableCell(status).SetTextColor(statusColor),
+			tview.NewTableCell(fmt.Sprintf("%dm", n.AllocatableCPU)).SetTextColor(tcell.ColorGray),
+			tview.NewTableCell(fmt.Sprintf("%dm", n.RequestedCPU)),
+			tview.NewTableCell(fmt.Sprintf("%dm", n.FreeCPU())).SetTextColor(freeColor(n.FreeCPU(), n.AllocatableCPU)),
+			tview.NewTableCell(ui.FormatBytes(n.AllocatableMemory)).SetTextColor(tcell.ColorGray),
+			tview.NewTableCell(ui.FormatBytes(n.RequestedMemory)),
+			tview.NewTableCell(ui.FormatBytes(n.FreeMemory())).SetTextColor(freeColor(n.FreeMemory(), n.AllocatableMemory)),
+			tview.NewTableCell(fmt.Sprintf("%d", n.FreePods())).SetTextColor(freeColor(n.FreePods(), n.AllocatablePods)),
+			tview.NewTableCell(fits).SetTextColor(fitsColor),
+		}
+		for col, cell := range cells {
+			p.table.SetCell(i+1, col, cell)
+		}
+	}
+
+	// Restore selection (clamped to valid range)
+	if len(p.nodes) > 0 {
+		p.table.Select(min(max(selectedRow, 1), len(p.nodes)), 0)
+	}
+}
+
+// drawMessage totals the free capacity of schedulable nodes or, with a pod
+// shape, where it fits
+func (p *Panel) drawMessage() {
+	if p.shape == nil {
+		var cpu, memory, pods int64
+		var ready int
+		for _, n := range p.nodes {
+			if n.Status != model.CapacityReady {
+				continue
+			}
+			ready++
+			cpu += n.FreeCPU()
+			memory += n.FreeMemory()
+			pods += n.FreePods()
+		}
+		p.message.SetText(fmt.Sprintf(" [gray]Free on %d of %d schedulable nodes: [white]%dm CPU, %s memory, %d pods",
+			ready, len(p.nodes), cpu, ui.FormatBytes(memory), pods))
+		return
+	}
+
+	var fitting int
+	var replicas int64
+	for _, n := range p.nodes {
+		if r := n.Replicas(*p.shape); r > 0 {
+			fitting++
+			replicas += r
+		}
+	}
+	color := "green"
+	if replicas == 0 {
+		color = "red"
+	}
+	p.message.SetText(fmt.Sprintf(" [white]%s[gray] fits on [%s]%d of %d nodes[gray]; the cluster can absorb [%s]%d replicas",
+		tview.Escape(p.shape.String()), color, fitting, len(p.nodes), color, replicas))
+}
+
+// formatTaints writes the taint keys of a node
+func formatTaints(n model.NodeCapacity) string {
+	keys := make([]string, len(n.Taints))
+	for i, taint := range n.Taints {
+		keys[i] = taint.Key
+	}
+	return strings.Join(keys, ", ")
+}
+
+// freeColor is red when less than a tenth of total is free
+func freeColor(free, total int64) tcell.Color {
+	if total > 0 && free*10 < total {
+		return tcell.ColorRed
+	}
+	return tcell.ColorWhite
+}
+
+// formatHeader highlights the sort key of a column header and marks the
+// column the table is sorted by
+func (p *Panel) formatHeader(c column) string {
+	header := c.name
+	if i := strings.IndexRune(c.name, unicode.ToUpper(c.key)); c.key != 0 && i >= 0 {
+		header = fmt.Sprintf("%s[%s::b]%c[%s::-]%s",
+			c.name[:i], ui.Theme.HeaderShortcutKey, c.name[i], ui.Theme.HeaderForeground, c.name[i+1:])
+	}
+	if c.name == p.sortColumn {
+		if p.sortAsc {
+			header += " ▲"
+		} else {
+			header += " ▼"
+		}
+	}
+	return header
+}
+
+// DrawFooter draws the footer
+func (p *Panel) DrawFooter(_ interface{}) {}
+
+// Clear clears the panel
+func (p *Panel) Clear() {
+	p.nodes = nil
+	p.table.Clear()
+}
+
+// GetRootView returns the root view
+func (p *Panel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+// GetChildrenViews returns child views
+func (p *Panel) GetChildrenViews() []tview.Primitive {
+	return []tview.Primitive{p.input, p.table}
+}
+
+// InitFocus focuses the pod shape input
+func (p *Panel) InitFocus() {
+	p.focus(p.input)
+}
+
+// HasEscapableState implements ui.EscapablePanel
+func (p *Panel) HasEscapableState() bool {
+	return true // Always allow ESC to go back
+}
+
+// HandleEscape implements ui.EscapablePanel
+func (p *Panel) HandleEscape() bool {
+	if p.onBack != nil {
+		p.onBack()
+		return true
+	}
+	return false
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -58,14 +59,22 @@ type DetailPanel struct {
 	wrapText         bool
 	tailLines        int64
 	lineCount        int
-	totalLinesLoaded int64 // Cumulative lines requested for "load more"
-	logsExpanded     bool  // true when logs panel is expanded to full height
+	totalLinesLoaded int64        // Cumulative lines requested for "load more"
+	logsExpanded     bool         // true when logs panel is expanded to full height
+	structured       bool         // render JSON and logfmt lines as columns
+	layout           ui.LogLayout // fields promoted, and whether the others are shown
 
 	// Filter state
-	filterMode  bool
-	filterQuery string
-	allLogs     []string    // Store full logs for filtering
-	filterInput *tview.InputField
+	filterMode   bool                     // true while the filter input is shown
+	filterQuery  string                   // query applied, see ui.LogFilter
+	filter       *ui.LogFilter            // filter applied, nil when none
+	minLevel     ui.LogLevel              // level filter cycled with 'e'
+	highlight    bool                     // highlight matching lines instead of hiding the others
+	selection    *ui.LogSelection[string] // lines of allLogs shown under the filter
+	matchCount   int                      // matching lines shown, each in region "m<index>"
+	currentMatch int                      // match jumped to with n/N, -1 for none
+	allLogs      []string                 // Store full logs for filtering
+	filterInput  *tview.InputField
 
 	// Streaming control
 	cancelFunc context.CancelFunc
@@ -86,6 +95,8 @@ type DetailPanel struct {
 	getPodMetrics         func(ctx context.Context, namespace, podName string) (*metrics.PodMetrics, error)
 	queueUpdate           func(func())
 	getTerminalHeight     func() int
+	onExport              func(name string, write func(w io.Writer) error)
+	onExec                func(namespace, podName, containerName string)
 }
 
 // NewDetailPanel creates a new container detail panel
@@ -96,17 +107,36 @@ func NewDetailPanel() *DetailPanel {
 		wrapText:         false, // Default to no wrap
 		tailLines:        100,  // Default tail lines (fast initial load)
 		totalLinesLoaded: 100,  // Track cumulative for "load more"
+		currentMatch:     -1,
 	}
 
 	p.setupInputCapture()
 	return p
 }
 
+// SetStructuredLogs sets whether JSON and logfmt lines start out rendered
+// as columns, and the fields promoted next to their message
+func (p *DetailPanel) SetStructuredLogs(on bool, fields []string) {
+	p.structured = on
+	p.layout.Fields = fields
+}
+
 // SetOnBack sets the callback for when user wants to go back
 func (p *DetailPanel) SetOnBack(callback func()) {
 	p.onBack = callback
 }
 
+// SetOnExport sets the callback that writes an export file named name with
+// write. It is called on the UI goroutine and should not block.
+func (p *DetailPanel) SetOnExport(callback func(name string, write func(w io.Writer) error)) {
+	p.onExport = callback
+}
+
+// SetOnExec sets the callback for opening a shell in the container
+func (p *DetailPanel) SetOnExec(callback func(namespace, podName, containerName string)) {
+	p.onExec = callback
+}
+
 // SetOnShowSpec sets the callback for when user wants to view container spec
 func (p *DetailPanel) SetOnShowSpec(callback func(namespace, podName, containerName string, containerSpec *corev1.Container)) {
 	p.onShowSpec = callback
@@ -172,6 +202,11 @@ func (p *DetailPanel) ShowContainer(namespace, podName, containerName string) {
 	p.allLogs = nil          // Clear stored logs
 	p.filterMode = false     // Exit filter mode
 	p.filterQuery = ""
+	p.filter = nil
+	p.minLevel = ui.LogLevelUnknown
+	p.selection = nil
+	p.matchCount = 0
+	p.currentMatch = -1
 	p.cpuUsage = ""
 	p.memUsage = ""
 
@@ -248,6 +283,7 @@ func (p *DetailPanel) buildLayout() {
 	p.logsView = tview.NewTextView()
 	p.logsView.SetDynamicColors(true)
 	p.logsView.SetScrollable(true)
+	p.logsView.SetRegions(true) // Matching lines are regions for n/N
 	p.logsView.SetWrap(p.wrapText)
 	p.logsView.SetBorder(true)
 	p.logsView.SetTitle(" Logs ")
@@ -734,16 +770,7 @@ func (p *DetailPanel) addDetailRowColor(table *tview.Table, row int, key, value
 // updateLogsTitle updates the logs panel title with line count and streaming status
 // Use this only when called from the main goroutine (e.g., toggleFollow)
 func (p *DetailPanel) updateLogsTitle() {
-	expandIndicator := ""
-	if p.logsExpanded {
-		expandIndicator = " [blue]expanded[-]"
-	}
-
-	if p.following {
-		p.logsView.SetTitle(fmt.Sprintf(" Logs (%d lines [green]streaming[-])%s ", p.lineCount, expandIndicator))
-	} else {
-		p.logsView.SetTitle(fmt.Sprintf(" Logs (%d lines)%s ", p.lineCount, expandIndicator))
-	}
+	p.updateLogsTitleWithCount(p.lineCount)
 }
 
 // updateLogsTitleWithCount updates the logs panel title with the given line count
@@ -752,6 +779,10 @@ func (p *DetailPanel) updateLogsTitleWithCount(count int) {
 	p.streamMu.Lock()
 	following := p.following
 	expanded := p.logsExpanded
+	structured := p.structured
+	filter := p.activeFilter()
+	highlight := p.highlight
+	matches, current := p.matchCount, p.currentMatch
 	p.streamMu.Unlock()
 
 	expandIndicator := ""
@@ -759,11 +790,29 @@ func (p *DetailPanel) updateLogsTitleWithCount(count int) {
 		expandIndicator = " [blue]expanded[-]"
 	}
 
+	status := fmt.Sprintf("%d lines", count)
+	if !filter.Empty() {
+		status = fmt.Sprintf("%d/%d matching", matches, count)
+		if filter.Query != "" {
+			status += fmt.Sprintf(" \"%s\"", tview.Escape(filter.Query))
+		}
+		if filter.MinLevel != ui.LogLevelUnknown {
+			status += " [yellow]" + filter.MinLevel.String() + "+[-]"
+		}
+		if highlight {
+			status += " highlighted"
+		}
+		if current >= 0 {
+			status += fmt.Sprintf(", match %d", current+1)
+		}
+	}
+	if structured {
+		status += " [aqua]structured[-]"
+	}
 	if following {
-		p.logsView.SetTitle(fmt.Sprintf(" Logs (%d lines [green]streaming[-])%s ", count, expandIndicator))
-	} else {
-		p.logsView.SetTitle(fmt.Sprintf(" Logs (%d lines)%s ", count, expandIndicator))
+		status += " [green]streaming[-]"
 	}
+	p.logsView.SetTitle(fmt.Sprintf(" Logs (%s)%s ", status, expandIndicator))
 }
 
 func (p *DetailPanel) setupInputCapture() {
@@ -803,6 +852,38 @@ func (p *DetailPanel) setupInputCapture() {
 			case 'x', 'X':
 				p.toggleLogsExpand()
 				return nil
+			case 'e', 'E':
+				p.cycleLevel()
+				return nil
+			case 'i', 'I':
+				p.toggleHighlight()
+				return nil
+			case 'o', 'O':
+				p.toggleStructured()
+				return nil
+			case 'f', 'F':
+				p.toggleFields()
+				return nil
+			case 'v':
+				p.saveLogs(true)
+				return nil
+			case 'V':
+				p.saveLogs(false)
+				return nil
+			case 'd', 'D':
+				p.dumpLogs()
+				return nil
+			case 'c', 'C':
+				if p.onExec != nil {
+					p.onExec(p.namespace, p.podName, p.containerName)
+				}
+				return nil
+			case 'n':
+				p.jumpToMatch(1)
+				return nil
+			case 'N':
+				p.jumpToMatch(-1)
+				return nil
 			}
 		}
 		return event
@@ -827,8 +908,7 @@ func (p *DetailPanel) toggleFollow() {
 
 func (p *DetailPanel) showPreviousLogs() {
 	p.stopStream()
-	p.logsView.Clear()
-	p.lineCount = 0
+	p.clearLogs()
 	p.startLogStream(true)
 }
 
@@ -838,9 +918,21 @@ func (p *DetailPanel) toggleTimestamps() {
 	p.streamMu.Unlock()
 
 	p.stopStream()
+	p.clearLogs()
+	p.startLogStream(false)
+}
+
+// clearLogs empties the logs view and the lines kept for filtering
+func (p *DetailPanel) clearLogs() {
+	p.streamMu.Lock()
+	defer p.streamMu.Unlock()
+
 	p.logsView.Clear()
 	p.lineCount = 0
-	p.startLogStream(false)
+	p.allLogs = nil
+	p.selection = p.newSelection()
+	p.matchCount = 0
+	p.currentMatch = -1
 }
 
 func (p *DetailPanel) toggleWrap() {
@@ -902,9 +994,7 @@ func (p *DetailPanel) loadMoreLogs() {
 	p.streamMu.Unlock()
 
 	// Clear and show loading state
-	p.logsView.Clear()
-	p.lineCount = 0
-	p.allLogs = nil
+	p.clearLogs()
 	p.logsView.SetTitle(" Logs (loading...) ")
 
 	// Fetch in background goroutine
@@ -921,7 +1011,7 @@ func (p *DetailPanel) loadMoreLogs() {
 
 		stream, err := p.getLogStream(ctx, p.namespace, p.podName, opts)
 		if err != nil {
-			p.appendLog(fmt.Sprintf("[red]Error loading more logs: %v", err))
+			p.appendNotice(fmt.Sprintf("[red]Error loading more logs: %v", tview.Escape(err.Error())))
 			return
 		}
 		defer stream.Close()
@@ -931,15 +1021,9 @@ func (p *DetailPanel) loadMoreLogs() {
 		scanner.Buffer(buf, 1024*1024)
 
 		for scanner.Scan() {
-			line := scanner.Text()
-			formatted := p.formatLogLine(line)
-
 			p.streamMu.Lock()
-			p.lineCount++
-			p.allLogs = append(p.allLogs, formatted)
+			p.addLine(scanner.Text())
 			p.streamMu.Unlock()
-
-			fmt.Fprintln(p.logsView, formatted)
 		}
 
 		// Calculate scroll position adjustment
@@ -971,6 +1055,84 @@ func (p *DetailPanel) loadMoreLogs() {
 	}()
 }
 
+// saveLogs exports the lines kept, as the filter shows them when filtered
+// is true and a filter is applied, otherwise all of them
+func (p *DetailPanel) saveLogs(filtered bool) {
+	if p.onExport == nil {
+		return
+	}
+
+	p.streamMu.Lock()
+	lines := slices.Clone(p.allLogs)
+	filter := p.activeFilter()
+	p.streamMu.Unlock()
+
+	kind := "buffer"
+	if filtered && !filter.Empty() {
+		kind = "filtered"
+		lines = selectLogLines(lines, filter)
+	}
+	p.onExport(p.exportName(kind), func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		for _, line := range lines {
+			bw.WriteString(line)
+			bw.WriteByte('\n')
+		}
+		return bw.Flush()
+	})
+}
+
+// selectLogLines returns the lines filter shows, with "--" where lines
+// were left out between context lines
+func selectLogLines(lines []string, filter *ui.LogFilter) []string {
+	var selected []string
+	selection := ui.NewLogSelection(filter, true, filter.Matches)
+	for _, line := range lines {
+		for _, sel := range selection.Add(line) {
+			if sel.Gap {
+				selected = append(selected, "--")
+			}
+			selected = append(selected, sel.Line)
+		}
+	}
+	return selected
+}
+
+// dumpLogs exports everything the container logged since it started,
+// fetched again without a tail limit
+func (p *DetailPanel) dumpLogs() {
+	if p.onExport == nil || p.getLogStream == nil {
+		return
+	}
+
+	p.streamMu.Lock()
+	namespace, podName := p.namespace, p.podName
+	opts := k8s.LogOptions{
+		Container:  p.containerName,
+		Timestamps: p.timestamps,
+	}
+	p.streamMu.Unlock()
+
+	p.onExport(p.exportName("full"), func(w io.Writer) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+		defer cancel()
+
+		stream, err := p.getLogStream(ctx, namespace, podName, opts)
+		if err != nil {
+			return fmt.Errorf("get logs: %w", err)
+		}
+		defer stream.Close()
+		_, err = io.Copy(w, stream)
+		return err
+	})
+}
+
+// exportName names the export file of the container's logs
+func (p *DetailPanel) exportName(kind string) string {
+	return fmt.Sprintf("logs-%s-%s-%s-%s-%s.log",
+		p.namespace, p.podName, p.containerName, kind, time.Now().Format("20060102-150405"))
+}
+
 // enterFilterMode enters filter mode and shows the filter input
 func (p *DetailPanel) enterFilterMode() {
 	// Already in filter mode - don't add another input
@@ -984,6 +1146,8 @@ func (p *DetailPanel) enterFilterMode() {
 		p.filterInput.SetLabel("[yellow]/[-] ")
 		p.filterInput.SetFieldBackgroundColor(tcell.ColorDarkBlue)
 		p.filterInput.SetLabelColor(tcell.ColorYellow)
+		p.filterInput.SetPlaceholder(`terms, /regexp/, !exclude, level:warn, -C 3`)
+		p.filterInput.SetPlaceholderTextColor(tcell.ColorGray)
 
 		// Handle Enter and Escape keys via DoneFunc
 		p.filterInput.SetDoneFunc(func(key tcell.Key) {
@@ -992,12 +1156,14 @@ func (p *DetailPanel) enterFilterMode() {
 				query := p.filterInput.GetText()
 				p.applyFilter(query)
 			case tcell.KeyEscape:
-				p.exitFilterMode()
+				p.closeFilterInput()
 			}
 		})
 	}
 
-	p.filterInput.SetText("")
+	// Edit the query applied, if any
+	p.filterInput.SetText(p.filterQuery)
+	p.filterInput.SetLabel("[yellow]/[-] ")
 	p.filterMode = true
 
 	// Add filter input to the layout
@@ -1009,87 +1175,169 @@ func (p *DetailPanel) enterFilterMode() {
 	}
 }
 
-// applyFilter applies the filter and shows only matching lines
-func (p *DetailPanel) applyFilter(query string) {
-	p.streamMu.Lock()
-	p.filterQuery = query
-	logs := make([]string, len(p.allLogs))
-	copy(logs, p.allLogs)
-	p.streamMu.Unlock()
-
-	// Remove filter input from layout but keep filter mode active
-	p.root.RemoveItem(p.filterInput)
+// closeFilterInput removes the filter input, keeping the filter applied
+func (p *DetailPanel) closeFilterInput() {
+	if p.filterInput != nil {
+		p.root.RemoveItem(p.filterInput)
+	}
+	p.filterMode = false
 
 	// Return focus to logs
 	if p.setAppFocus != nil {
 		p.setAppFocus(p.logsView)
 	}
+}
 
-	// Clear and rerender filtered logs
-	p.logsView.Clear()
-
-	matchCount := 0
-	if query == "" {
-		// Empty query - show all logs
-		for _, line := range logs {
-			fmt.Fprintln(p.logsView, p.formatLogLine(line))
-			matchCount++
-		}
-	} else {
-		queryLower := strings.ToLower(query)
-		for _, line := range logs {
-			if strings.Contains(strings.ToLower(line), queryLower) {
-				fmt.Fprintln(p.logsView, p.formatLogLine(line))
-				matchCount++
-			}
-		}
+// applyFilter applies the filter query and redraws the logs. A query that
+// does not parse is reported in the input, which stays open.
+func (p *DetailPanel) applyFilter(query string) {
+	filter, err := ui.ParseLogFilter(query)
+	if err != nil {
+		p.filterInput.SetLabel("[red]" + tview.Escape(err.Error()) + "[-] ")
+		return
 	}
 
-	// Update title with filter info
-	if query != "" {
-		p.logsView.SetTitle(fmt.Sprintf(" Logs (%d/%d matching \"%s\") ", matchCount, len(logs), query))
-	} else {
-		p.updateLogsTitle()
-	}
+	p.streamMu.Lock()
+	p.filterQuery = filter.Query
+	p.filter = filter
+	p.streamMu.Unlock()
 
-	p.logsView.ScrollToEnd()
+	p.closeFilterInput()
+	p.redrawLogs()
 }
 
-// exitFilterMode exits filter mode and shows all logs
+// exitFilterMode clears the filter and the level filter and shows all logs
 func (p *DetailPanel) exitFilterMode() {
-	// Remove filter input from layout if present
-	if p.filterInput != nil {
-		p.root.RemoveItem(p.filterInput)
+	p.closeFilterInput()
+
+	p.streamMu.Lock()
+	p.filterQuery = ""
+	p.filter = nil
+	p.minLevel = ui.LogLevelUnknown
+	p.streamMu.Unlock()
+
+	p.redrawLogs()
+}
+
+// cycleLevel shows all lines, then WARN and above, then ERROR only
+func (p *DetailPanel) cycleLevel() {
+	p.streamMu.Lock()
+	switch p.minLevel {
+	case ui.LogLevelUnknown:
+		p.minLevel = ui.LogLevelWarn
+	case ui.LogLevelWarn:
+		p.minLevel = ui.LogLevelError
+	default:
+		p.minLevel = ui.LogLevelUnknown
 	}
+	p.streamMu.Unlock()
 
-	wasFiltering := p.filterMode && p.filterQuery != ""
+	p.redrawLogs()
+}
 
+// toggleHighlight switches between hiding the lines the filter does not
+// match and showing every line with the matches highlighted
+func (p *DetailPanel) toggleHighlight() {
 	p.streamMu.Lock()
-	p.filterMode = false
-	p.filterQuery = ""
-	logs := make([]string, len(p.allLogs))
-	copy(logs, p.allLogs)
+	p.highlight = !p.highlight
 	p.streamMu.Unlock()
 
-	// Return focus to logs
-	if p.setAppFocus != nil {
-		p.setAppFocus(p.logsView)
+	p.redrawLogs()
+}
+
+// toggleStructured switches between raw lines and JSON and logfmt lines
+// rendered as columns
+func (p *DetailPanel) toggleStructured() {
+	p.streamMu.Lock()
+	p.structured = !p.structured
+	p.streamMu.Unlock()
+
+	p.redrawLogs()
+}
+
+// toggleFields shows or collapses the fields of structured lines that
+// aren't promoted
+func (p *DetailPanel) toggleFields() {
+	p.streamMu.Lock()
+	if !p.structured {
+		p.streamMu.Unlock()
+		return
 	}
+	p.layout.Expanded = !p.layout.Expanded
+	p.streamMu.Unlock()
 
-	// Only re-render if we were actually filtering
-	if wasFiltering {
-		p.logsView.Clear()
-		for _, line := range logs {
-			fmt.Fprintln(p.logsView, p.formatLogLine(line))
-		}
-		p.updateLogsTitle()
-		p.logsView.ScrollToEnd()
+	p.redrawLogs()
+}
+
+// activeFilter returns the filter applied with the level filter, which
+// overrides a level in the query. Callers hold streamMu.
+func (p *DetailPanel) activeFilter() *ui.LogFilter {
+	if p.minLevel == ui.LogLevelUnknown {
+		return p.filter
 	}
+	filter := ui.LogFilter{}
+	if p.filter != nil {
+		filter = *p.filter
+	}
+	filter.MinLevel = p.minLevel
+	return &filter
+}
+
+// newSelection starts selecting the lines to show under the current
+// filter. Callers hold streamMu.
+func (p *DetailPanel) newSelection() *ui.LogSelection[string] {
+	filter := p.activeFilter()
+	return ui.NewLogSelection(filter, !p.highlight, filter.Matches)
+}
+
+// redrawLogs shows the logs kept again under the current filter
+func (p *DetailPanel) redrawLogs() {
+	p.streamMu.Lock()
+	logs := p.allLogs
+	p.allLogs = nil
+	p.lineCount = 0
+	p.logsView.Clear()
+	p.logsView.Highlight()
+	p.selection = p.newSelection()
+	p.matchCount = 0
+	p.currentMatch = -1
+	for _, line := range logs {
+		p.addLine(line)
+	}
+	p.streamMu.Unlock()
+
+	p.updateLogsTitle()
+	p.logsView.ScrollToEnd()
+}
+
+// jumpToMatch highlights the next (dir 1) or previous (dir -1) matching
+// line and scrolls to it, which stops following the stream
+func (p *DetailPanel) jumpToMatch(dir int) {
+	p.streamMu.Lock()
+	if p.activeFilter().Empty() || p.matchCount == 0 {
+		p.streamMu.Unlock()
+		return
+	}
+	switch {
+	case p.currentMatch < 0 && dir < 0:
+		p.currentMatch = p.matchCount - 1 // From the end, where the stream is
+	case p.currentMatch < 0:
+		p.currentMatch = 0
+	default:
+		p.currentMatch = (p.currentMatch + dir + p.matchCount) % p.matchCount
+	}
+	region := fmt.Sprintf("m%d", p.currentMatch)
+	p.following = false
+	p.streamMu.Unlock()
+
+	p.logsView.Highlight(region)
+	p.logsView.ScrollToHighlight()
+	p.updateLogsTitle()
 }
 
 func (p *DetailPanel) startLogStream(previous bool) {
 	if p.getLogStream == nil {
-		p.appendLog("[red]Error: log stream function not configured")
+		p.appendNotice("[red]Error: log stream function not configured")
 		return
 	}
 
@@ -1116,7 +1364,7 @@ func (p *DetailPanel) startLogStream(previous bool) {
 	go func() {
 		stream, err := p.getLogStream(ctx, namespace, podName, opts)
 		if err != nil {
-			p.appendLog(fmt.Sprintf("[red]Error getting logs: %v", err))
+			p.appendNotice(fmt.Sprintf("[red]Error getting logs: %v", tview.Escape(err.Error())))
 			return
 		}
 		defer stream.Close()
@@ -1145,8 +1393,7 @@ func (p *DetailPanel) startLogStream(previous bool) {
 			case <-ctx.Done():
 				return
 			default:
-				line := scanner.Text()
-				p.appendLog(p.formatLogLine(line))
+				p.appendLog(scanner.Text())
 			}
 		}
 
@@ -1155,7 +1402,7 @@ func (p *DetailPanel) startLogStream(previous bool) {
 			case <-ctx.Done():
 				// Context cancelled, don't report error
 			default:
-				p.appendLog(fmt.Sprintf("[yellow]Stream ended: %v", err))
+				p.appendNotice(fmt.Sprintf("[yellow]Stream ended: %v", tview.Escape(err.Error())))
 			}
 		}
 	}()
@@ -1171,59 +1418,72 @@ func (p *DetailPanel) stopStream() {
 	}
 }
 
-func (p *DetailPanel) formatLogLine(line string) string {
-	// Color timestamps if present (RFC3339 format: 2024-01-15T10:30:00Z)
-	if p.timestamps && len(line) > 30 && line[4] == '-' && line[7] == '-' {
-		if idx := strings.Index(line, " "); idx > 0 && idx < 35 {
-			timestamp := line[:idx]
-			rest := line[idx+1:]
-			return fmt.Sprintf("[gray]%s[white] %s", timestamp, rest)
-		}
+// formatLogLine renders a log line, as columns when structured, and
+// highlights what filter matched in it. Callers hold streamMu.
+func (p *DetailPanel) formatLogLine(line string, filter *ui.LogFilter) string {
+	if p.structured {
+		return ui.FormatLogLine(line, &p.layout, filter)
 	}
+	return ui.FormatLogLine(line, nil, filter)
+}
 
-	// Color error/warning lines
-	lineLower := strings.ToLower(line)
-	if strings.Contains(lineLower, "error") || strings.Contains(lineLower, "fatal") {
-		return "[red]" + line
-	}
-	if strings.Contains(lineLower, "warn") {
-		return "[yellow]" + line
+// addLine keeps a raw log line for filtering and writes it if the filter
+// selects it. Callers hold streamMu.
+func (p *DetailPanel) addLine(line string) {
+	p.lineCount++
+	p.allLogs = append(p.allLogs, line)
+	if p.selection == nil {
+		p.selection = p.newSelection()
 	}
 
-	return line
+	filter := p.activeFilter()
+	for _, sel := range p.selection.Add(line) {
+		if sel.Gap {
+			fmt.Fprintln(p.logsView, "[gray]--[-]")
+		}
+		if !sel.Match {
+			fmt.Fprintln(p.logsView, p.formatLogLine(sel.Line, nil))
+			continue
+		}
+		text := fmt.Sprintf(`["m%d"]%s[""]`, p.matchCount, p.formatLogLine(sel.Line, filter))
+		p.matchCount++
+		// Write directly to logsView (tview.TextView is goroutine-safe for writes)
+		fmt.Fprintln(p.logsView, text)
+	}
 }
 
 func (p *DetailPanel) appendLog(line string) {
 	p.streamMu.Lock()
-	p.lineCount++
+	p.addLine(line)
 	count := p.lineCount
 	following := p.following
-	filterMode := p.filterMode
-	filterQuery := p.filterQuery
-	// Store the original line (without formatting) for filtering
-	p.allLogs = append(p.allLogs, line)
 	p.streamMu.Unlock()
 
-	// In filter mode, only show matching lines
-	if filterMode && filterQuery != "" {
-		if !strings.Contains(strings.ToLower(line), strings.ToLower(filterQuery)) {
-			return // Skip non-matching lines
-		}
-	}
+	p.queueLogsUpdate(count, following)
+}
+
+// appendNotice writes a message about the stream, already formatted, to
+// the logs view without keeping it with the logs
+func (p *DetailPanel) appendNotice(text string) {
+	fmt.Fprintln(p.logsView, text+"[-:-:-]")
 
-	// Write directly to logsView (tview.TextView is goroutine-safe for writes)
-	fmt.Fprintln(p.logsView, line)
+	p.streamMu.Lock()
+	count := p.lineCount
+	following := p.following
+	p.streamMu.Unlock()
 
+	p.queueLogsUpdate(count, following)
+}
+
+// queueLogsUpdate updates the title, and follows the stream
+func (p *DetailPanel) queueLogsUpdate(count int, following bool) {
 	// Update title on every line for responsive count display
 	if p.queueUpdate != nil {
-		// Capture values for closure to avoid race conditions
-		capturedCount := count
-		capturedFollowing := following
 		p.queueUpdate(func() {
-			if capturedFollowing {
+			if following {
 				p.logsView.ScrollToEnd()
 			}
-			p.updateLogsTitleWithCount(capturedCount)
+			p.updateLogsTitleWithCount(count)
 		})
 	}
 }
@@ -1372,17 +1632,21 @@ func (p *DetailPanel) SetFocused(focused bool) {
 
 // HasEscapableState implements ui.EscapablePanel
 func (p *DetailPanel) HasEscapableState() bool {
-	return p.filterMode // Has state to clear if in filter mode
+	return p.filterMode || p.filter != nil || p.minLevel != ui.LogLevelUnknown // Has state to clear if filtering
 }
 
 // HandleEscape implements ui.EscapablePanel
 func (p *DetailPanel) HandleEscape() bool {
-	// If in filter mode, exit filter mode first
+	// Close the filter input first, then clear the filter
 	if p.filterMode {
-		p.exitFilterMode()
+		p.closeFilterInput()
 		return true // Handled - don't navigate back
 	}
-	// Not in filter mode - let app handle navigation
+	if p.filter != nil || p.minLevel != ui.LogLevelUnknown {
+		p.exitFilterMode()
+		return true
+	}
+	// Not filtering - let app handle navigation
 	return false
 }
 
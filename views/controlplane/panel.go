@@ -0,0 +1,343 @@
+package controlplane
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/ui"
+)
+
+// etcdDBSizeWarning is the etcd database size shown in yellow; etcd's
+// default backend quota is 2GiB
+const etcdDBSizeWarning = 1.5 * 1024 * 1024 * 1024
+
+// apiLatencyWarning is the p99 latency shown in red (the apiserver SLO for
+// single-object requests)
+const apiLatencyWarning = time.Second
+
+// Panel shows apiserver, etcd, scheduler, controller-manager and kubelet health
+type Panel struct {
+	root    *tview.Flex
+	laidout bool
+
+	apiTable       *tview.Table
+	etcdTable      *tview.Table
+	kubeletTable   *tview.Table
+	schedulerTable *tview.Table
+	queueTable     *tview.Table
+	message        *tview.TextView
+
+	setAppFocus func(p tview.Primitive)
+	onBack      func()
+}
+
+// NewPanel creates a new control-plane panel
+func NewPanel() *Panel {
+	p := &Panel{}
+	p.Layout(nil)
+	return p
+}
+
+// SetOnBack sets the callback for when user navigates back
+func (p *Panel) SetOnBack(callback func()) {
+	p.onBack = callback
+}
+
+// SetAppFocus sets the callback used to focus primitives in the tview app
+func (p *Panel) SetAppFocus(fn func(p tview.Primitive)) {
+	p.setAppFocus = fn
+}
+
+// GetTitle returns the panel title
+func (p *Panel) GetTitle() string {
+	return "Control Plane"
+}
+
+// Layout initializes the panel UI
+func (p *Panel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.apiTable = newTable()
+	p.etcdTable = newTable()
+	p.kubeletTable = newTable()
+	p.schedulerTable = newTable()
+	p.queueTable = newTable()
+	p.queueTable.SetSelectable(true, false)
+	p.queueTable.SetFixed(1, 0)
+	p.queueTable.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorLightGray).Foreground(tcell.ColorBlack))
+	p.queueTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEscape && p.onBack != nil {
+			p.onBack()
+			return nil
+		}
+		return event
+	})
+
+	p.message = tview.NewTextView().SetDynamicColors(true)
+
+	top := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(box(" API Server ", p.apiTable), 0, 1, false).
+		AddItem(box(" etcd ", p.etcdTable), 0, 1, false).
+		AddItem(box(" Kubelet ", p.kubeletTable), 0, 1, false)
+	bottom := tview.NewFlex().SetDirection(tview.FlexColumn).
+		AddItem(box(" Scheduler ", p.schedulerTable), 0, 1, false).
+		AddItem(box(" Controller Manager Workqueues ", p.queueTable), 0, 1, true)
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.message, 1, 0, false).
+		AddItem(top, 0, 1, false).
+		AddItem(bottom, 0, 1, true)
+	p.root.SetBorder(true)
+	p.root.SetTitle(fmt.Sprintf(" %s Control Plane ", ui.Icons.Controller))
+	p.root.SetTitleAlign(tview.AlignCenter)
+	p.laidout = true
+}
+
+func newTable() *tview.Table {
+	table := tview.NewTable()
+	table.SetBorder(false)
+	table.SetBorders(false)
+	return table
+}
+
+func box(title string, table *tview.Table) *tview.Flex {
+	flex := tview.NewFlex().SetDirection(tview.FlexRow)
+	flex.SetBorder(true)
+	flex.SetBorderColor(tcell.ColorLightGray)
+	flex.SetTitle(title)
+	flex.SetTitleAlign(tview.AlignLeft)
+	flex.AddItem(table, 0, 1, true)
+	return flex
+}
+
+// DrawHeader draws the header row
+func (p *Panel) DrawHeader(_ interface{}) {}
+
+// DrawBody draws a *metrics.ControlPlaneMetrics, or an error explaining
+// why there are none
+func (p *Panel) DrawBody(data interface{}) {
+	cp, ok := data.(*metrics.ControlPlaneMetrics)
+	if !ok || cp == nil {
+		msg := "Control-plane metrics are not available"
+		if err, ok := data.(error); ok {
+			msg = err.Error()
+		}
+		p.message.SetText(" [yellow]" + tview.Escape(msg))
+		p.drawAPIServer(nil)
+		p.drawEtcd(nil)
+		p.drawScheduler(nil)
+		p.drawWorkqueues(nil)
+		p.drawKubelet(nil)
+		return
+	}
+
+	p.message.SetText(fmt.Sprintf(" [gray]Rates and latency over the last %s", cp.Window))
+	p.drawAPIServer(cp.APIServer)
+	p.drawEtcd(cp.Etcd)
+	p.drawScheduler(cp.Scheduler)
+	p.drawWorkqueues(cp.ControllerManager)
+	p.drawKubelet(cp.Kubelet)
+}
+
+func (p *Panel) drawAPIServer(api *metrics.APIServerMetrics) {
+	p.apiTable.Clear()
+	if api == nil {
+		notScraped(p.apiTable, "apiserver")
+		return
+	}
+
+	errorColor := tcell.ColorWhite
+	if api.ErrorRate > 0 {
+		errorColor = tcell.ColorRed
+	}
+	setRow(p.apiTable, 0, "Requests", fmt.Sprintf("%.1f/s", api.RequestRate), tcell.ColorWhite)
+	setRow(p.apiTable, 1, "5xx errors", fmt.Sprintf("%.2f/s", api.ErrorRate), errorColor)
+	setRow(p.apiTable, 2, "Latency p50", formatLatency(api.LatencyP50), tcell.ColorWhite)
+	setRow(p.apiTable, 3, "Latency p90", formatLatency(api.LatencyP90), tcell.ColorWhite)
+	setRow(p.apiTable, 4, "Latency p99", formatLatency(api.LatencyP99), latencyColor(api.LatencyP99))
+	setRow(p.apiTable, 5, "Inflight read-only", fmt.Sprintf("%.0f", api.InflightReadOnly), tcell.ColorWhite)
+	setRow(p.apiTable, 6, "Inflight mutating", fmt.Sprintf("%.0f", api.InflightMutating), tcell.ColorWhite)
+}
+
+func (p *Panel) drawEtcd(etcd *metrics.EtcdMetrics) {
+	p.etcdTable.Clear()
+	if etcd == nil {
+		notScraped(p.etcdTable, "etcd")
+		return
+	}
+
+	leader, leaderColor := "yes", tcell.ColorGreen
+	if !etcd.HasLeader {
+		leader, leaderColor = "NO", tcell.ColorRed
+	}
+	sizeColor := tcell.ColorWhite
+	if etcd.DBSizeBytes > etcdDBSizeWarning {
+		sizeColor = tcell.ColorYellow
+	}
+	changesColor := tcell.ColorWhite
+	if etcd.RecentLeaderChanges > 0 {
+		changesColor = tcell.ColorYellow
+	}
+	setRow(p.etcdTable, 0, "Members", fmt.Sprintf("%d", etcd.Members), tcell.ColorWhite)
+	setRow(p.etcdTable, 1, "Has leader", leader, leaderColor)
+	setRow(p.etcdTable, 2, "DB size", ui.FormatBytes(int64(etcd.DBSizeBytes)), sizeColor)
+	setRow(p.etcdTable, 3, "Leader changes (1h)", fmt.Sprintf("%.0f", etcd.RecentLeaderChanges), changesColor)
+	setRow(p.etcdTable, 4, "Leader changes (total)", fmt.Sprintf("%.0f", etcd.LeaderChanges), tcell.ColorWhite)
+}
+
+func (p *Panel) drawScheduler(s *metrics.SchedulerMetrics) {
+	p.schedulerTable.Clear()
+	if s == nil {
+		notScraped(p.schedulerTable, "scheduler")
+		return
+	}
+
+	var total float64
+	for _, n := range s.PendingPods {
+		total += n
+	}
+	setRow(p.schedulerTable, 0, "Pending pods", fmt.Sprintf("%.0f", total), tcell.ColorWhite)
+	for i, queue := range sortedKeys(s.PendingPods, false) {
+		color := tcell.ColorWhite
+		if queue == "unschedulable" && s.PendingPods[queue] > 0 {
+			color = tcell.ColorYellow
+		}
+		setRow(p.schedulerTable, i+1, "  "+queue, fmt.Sprintf("%.0f", s.PendingPods[queue]), color)
+	}
+}
+
+func (p *Panel) drawWorkqueues(cm *metrics.ControllerManagerMetrics) {
+	// Save current selection before clearing
+	selectedRow, _ := p.queueTable.GetSelection()
+	p.queueTable.Clear()
+	if cm == nil {
+		notScraped(p.queueTable, "controller-manager")
+		return
+	}
+
+	for col, header := range []string{"WORKQUEUE", "DEPTH"} {
+		p.queueTable.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorWhite).
+			SetBackgroundColor(tcell.ColorDarkCyan).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+	names := sortedKeys(cm.WorkqueueDepth, true)
+	for i, name := range names {
+		depth := cm.WorkqueueDepth[name]
+		color := tcell.ColorWhite
+		if depth == 0 {
+			color = tcell.ColorGray
+		}
+		p.queueTable.SetCell(i+1, 0, tview.NewTableCell(name).SetTextColor(color))
+		p.queueTable.SetCell(i+1, 1, tview.NewTableCell(fmt.Sprintf("%.0f", depth)).SetTextColor(color))
+	}
+
+	if len(names) > 0 {
+		selectedRow = max(1, min(selectedRow, len(names)))
+		p.queueTable.Select(selectedRow, 0)
+	}
+}
+
+func (p *Panel) drawKubelet(k *metrics.KubeletMetrics) {
+	p.kubeletTable.Clear()
+	if k == nil {
+		notScraped(p.kubeletTable, "kubelet")
+		return
+	}
+
+	window := fmt.Sprintf("%.0fm", k.Window.Minutes())
+	setRow(p.kubeletTable, 0, "Pod start p50 ("+window+")", formatLatency(k.PodStartP50), tcell.ColorWhite)
+	setRow(p.kubeletTable, 1, "Pod start p99 ("+window+")", formatLatency(k.PodStartP99), tcell.ColorWhite)
+}
+
+// sortedKeys returns the keys of values, by descending value when byValue
+// is set, otherwise alphabetically
+func sortedKeys(values map[string]float64, byValue bool) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if byValue && values[keys[i]] != values[keys[j]] {
+			return values[keys[i]] > values[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+func setRow(table *tview.Table, row int, label, value string, color tcell.Color) {
+	table.SetCell(row, 0, tview.NewTableCell(label).SetTextColor(tcell.ColorGray).SetExpansion(1))
+	table.SetCell(row, 1, tview.NewTableCell(value).SetTextColor(color).SetAlign(tview.AlignRight))
+}
+
+// notScraped explains how to enable a component that has no metrics
+func notScraped(table *tview.Table, component string) {
+	table.SetCell(0, 0, tview.NewTableCell("No metrics").SetTextColor(tcell.ColorGray))
+	table.SetCell(1, 0, tview.NewTableCell(fmt.Sprintf("Add %s to --prometheus-components", component)).
+		SetTextColor(tcell.ColorGray))
+}
+
+func formatLatency(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+	return d.Round(time.Millisecond / 10).String()
+}
+
+func latencyColor(d time.Duration) tcell.Color {
+	if d > apiLatencyWarning {
+		return tcell.ColorRed
+	}
+	return tcell.ColorWhite
+}
+
+// DrawFooter draws the footer
+func (p *Panel) DrawFooter(_ interface{}) {}
+
+// Clear clears the panel
+func (p *Panel) Clear() {
+	p.apiTable.Clear()
+	p.etcdTable.Clear()
+	p.kubeletTable.Clear()
+	p.schedulerTable.Clear()
+	p.queueTable.Clear()
+}
+
+// GetRootView returns the root view
+func (p *Panel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+// GetChildrenViews returns child views
+func (p *Panel) GetChildrenViews() []tview.Primitive {
+	return []tview.Primitive{p.queueTable}
+}
+
+// InitFocus focuses the workqueue table, the only one that scrolls
+func (p *Panel) InitFocus() {
+	if p.setAppFocus != nil {
+		p.setAppFocus(p.queueTable)
+	}
+}
+
+// HasEscapableState implements ui.EscapablePanel
+func (p *Panel) HasEscapableState() bool {
+	return true // Always allow ESC to go back
+}
+
+// HandleEscape implements ui.EscapablePanel
+func (p *Panel) HandleEscape() bool {
+	if p.onBack != nil {
+		p.onBack()
+		return true
+	}
+	return false
+}
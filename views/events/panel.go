@@ -0,0 +1,374 @@
+package events
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/ui"
+	"github.com/vladimirvivien/ktop/views/model"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// column is a table column; a non-zero key sorts the table by it
+type column struct {
+	name string
+	key  rune
+}
+
+var columns = []column{
+	{"LAST SEEN", 'l'}, {"TYPE", 't'}, {"NAMESPACE", 'n'}, {"OBJECT", 'o'},
+	{"REASON", 'r'}, {"COUNT", 'c'}, {"MESSAGE", 0},
+}
+
+// typeFilters are the event types shown, cycled with w; "" shows all
+var typeFilters = []string{"", v1.EventTypeWarning, v1.EventTypeNormal}
+
+// Panel streams the events of the cluster, most recent first
+type Panel struct {
+	root    *tview.Flex
+	laidout bool
+
+	table  *tview.Table
+	detail *tview.TextView
+
+	events     []model.EventModel
+	visible    []model.EventModel // events shown, filtered and sorted
+	eventType  string
+	filter     *ui.FilterState
+	sortColumn string
+	sortAsc    bool
+
+	setAppFocus func(p tview.Primitive)
+
+	// Callbacks
+	onSelected func(kind, namespace, name string)
+	onBack     func()
+}
+
+// NewPanel creates a new events panel
+func NewPanel() *Panel {
+	p := &Panel{sortColumn: "LAST SEEN", filter: &ui.FilterState{}}
+	p.Layout(nil)
+	return p
+}
+
+// SetOnSelected sets the callback for when the event of an object is selected
+func (p *Panel) SetOnSelected(callback func(kind, namespace, name string)) {
+	p.onSelected = callback
+}
+
+// SetOnBack sets the callback for when user navigates back
+func (p *Panel) SetOnBack(callback func()) {
+	p.onBack = callback
+}
+
+// SetAppFocus sets the callback used to focus primitives in the tview app
+func (p *Panel) SetAppFocus(fn func(p tview.Primitive)) {
+	p.setAppFocus = fn
+}
+
+// GetTitle returns the panel title
+func (p *Panel) GetTitle() string {
+	return "Events"
+}
+
+// Layout initializes the panel UI
+func (p *Panel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.detail = tview.NewTextView().SetDynamicColors(true)
+
+	p.table = tview.NewTable()
+	p.table.SetFixed(1, 0) // Fixed header row
+	p.table.SetSelectable(true, false)
+	p.table.SetBorder(false)
+	p.table.SetBorders(false)
+	p.table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorLightGray).Foreground(tcell.ColorBlack))
+	p.table.SetSelectionChangedFunc(func(row, _ int) {
+		p.drawDetail(row)
+	})
+
+	// Same key handling as the overview panels: filter editing first, then
+	// ESC to clear an active filter, Enter to drill down and sort keys
+	p.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if p.filter.Editing {
+			switch event.Key() {
+			case tcell.KeyEscape:
+				p.filter.Cancel()
+				p.draw()
+			case tcell.KeyEnter:
+				p.filter.Confirm()
+				p.draw()
+			case tcell.KeyBackspace, tcell.KeyBackspace2:
+				if p.filter.HandleBackspace() {
+					p.draw()
+				}
+			case tcell.KeyRune:
+				p.filter.AppendChar(event.Rune())
+				p.draw()
+			}
+			return nil
+		}
+
+		switch event.Key() {
+		case tcell.KeyEscape:
+			p.HandleEscape()
+			return nil
+		case tcell.KeyEnter:
+			if e, ok := p.selected(); ok && p.onSelected != nil {
+				p.onSelected(e.Kind, e.Namespace, e.Name)
+				return nil
+			}
+		case tcell.KeyRune:
+			switch key := event.Rune(); key {
+			case '/':
+				p.filter.StartEditing()
+				p.draw()
+				return nil
+			case 'w':
+				p.cycleType()
+				p.draw()
+				return nil
+			default:
+				if p.toggleSort(key) {
+					p.draw()
+					return nil
+				}
+			}
+		}
+		return event
+	})
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.table, 0, 1, true).
+		AddItem(p.detail, 2, 0, false)
+	p.root.SetBorder(true)
+	p.root.SetTitleAlign(tview.AlignCenter)
+	p.updateTitle()
+	p.laidout = true
+}
+
+// cycleType shows all events, then only warnings, then only normal events
+func (p *Panel) cycleType() {
+	for i, t := range typeFilters {
+		if t == p.eventType {
+			p.eventType = typeFilters[(i+1)%len(typeFilters)]
+			return
+		}
+	}
+}
+
+// toggleSort sorts by the column with key, or reverses the direction when
+// the table is already sorted by it. It reports whether key sorts a column.
+func (p *Panel) toggleSort(key rune) bool {
+	for _, col := range columns {
+		if col.key == 0 || col.key != key {
+			continue
+		}
+		if p.sortColumn == col.name {
+			p.sortAsc = !p.sortAsc
+		} else {
+			// Most recent and most frequent first
+			p.sortColumn = col.name
+			p.sortAsc = col.name != "LAST SEEN" && col.name != "COUNT"
+		}
+		return true
+	}
+	return false
+}
+
+// DrawHeader draws the header row
+func (p *Panel) DrawHeader(_ interface{}) {}
+
+// DrawBody draws a []model.EventModel, or the error listing events failed with
+func (p *Panel) DrawBody(data interface{}) {
+	if err, ok := data.(error); ok {
+		p.detail.SetText(" [red]" + tview.Escape(err.Error()))
+		return
+	}
+	events, ok := data.([]model.EventModel)
+	if !ok {
+		return
+	}
+	p.events = events
+	p.draw()
+}
+
+func (p *Panel) draw() {
+	// New events arrive at the top: keep the selection there when it is,
+	// otherwise on the event it was on
+	selectedRow, _ := p.table.GetSelection()
+	selectedKey := ""
+	if e, ok := p.selected(); ok && selectedRow > 1 {
+		selectedKey = e.Key()
+	}
+
+	p.visible = p.visible[:0]
+	for _, e := range p.events {
+		if p.eventType != "" && e.Type != p.eventType {
+			continue
+		}
+		if p.filter.IsFiltering() && !p.filter.MatchesRow([]string{e.Namespace, e.Object(), e.Reason}) {
+			continue
+		}
+		p.visible = append(p.visible, e)
+	}
+	p.filter.TotalRows = len(p.events)
+	p.filter.MatchRows = len(p.visible)
+	model.SortEventModelsBy(p.visible, p.sortColumn, p.sortAsc)
+	p.updateTitle()
+
+	p.table.Clear()
+	for col, c := range columns {
+		cell := tview.NewTableCell(p.formatHeader(c)).
+			SetTextColor(tcell.ColorWhite).
+			SetBackgroundColor(tcell.ColorDarkCyan).
+			SetSelectable(false)
+		if c.name == "MESSAGE" {
+			cell.SetExpansion(1)
+		}
+		p.table.SetCell(0, col, cell)
+	}
+
+	now := time.Now()
+	row := 1
+	for i, e := range p.visible {
+		typeColor := tcell.ColorGray
+		if e.Type == v1.EventTypeWarning {
+			typeColor = tcell.ColorYellow
+		}
+		cells := []*tview.TableCell{
+			tview.NewTableCell(duration.HumanDuration(now.Sub(e.LastSeen))),
+			tview.NewTableCell(e.Type).SetTextColor(typeColor),
+			tview.NewTableCell(e.Namespace),
+			tview.NewTableCell(e.Object()),
+			tview.NewTableCell(e.Reason).SetTextColor(typeColor),
+			tview.NewTableCell(fmt.Sprintf("%d", e.Count)),
+			tview.NewTableCell(strings.ReplaceAll(e.Message, "\n", " ")),
+		}
+		for col, cell := range cells {
+			p.table.SetCell(i+1, col, cell)
+		}
+		if selectedKey != "" && e.Key() == selectedKey {
+			row = i + 1
+		}
+	}
+
+	if len(p.visible) == 0 {
+		p.detail.SetText(" [gray]No events")
+		return
+	}
+	p.table.Select(row, 0)
+	p.drawDetail(row)
+}
+
+// formatHeader highlights the sort key of a column header and marks the
+// column the table is sorted by
+func (p *Panel) formatHeader(c column) string {
+	header := c.name
+	if i := strings.IndexRune(c.name, unicode.ToUpper(c.key)); c.key != 0 && i >= 0 {
+		header = fmt.Sprintf("%s[%s::b]%c[%s::-]%s",
+			c.name[:i], ui.Theme.HeaderShortcutKey, c.name[i], ui.Theme.HeaderForeground, c.name[i+1:])
+	}
+	if c.name == p.sortColumn {
+		if p.sortAsc {
+			header += " ▲"
+		} else {
+			header += " ▼"
+		}
+	}
+	return header
+}
+
+// updateTitle shows the event type and the filter in the title
+func (p *Panel) updateTitle() {
+	title := "Events"
+	if p.eventType != "" {
+		title = p.eventType + " Events"
+	}
+	p.root.SetTitle(p.filter.FormatTitle(title, ui.Icons.Clock))
+}
+
+// selected returns the event on the selected row
+func (p *Panel) selected() (model.EventModel, bool) {
+	row, _ := p.table.GetSelection()
+	if row < 1 || row > len(p.visible) {
+		return model.EventModel{}, false
+	}
+	return p.visible[row-1], true
+}
+
+// drawDetail shows the whole message of the event on row, and where and
+// when it was reported
+func (p *Panel) drawDetail(row int) {
+	if row < 1 || row > len(p.visible) {
+		p.detail.SetText("")
+		return
+	}
+	e := p.visible[row-1]
+	text := fmt.Sprintf(" [white]%s[gray]: %s", tview.Escape(e.Object()), tview.Escape(e.Message))
+	text += fmt.Sprintf("\n [gray]%d times since %s ago", e.Count, duration.HumanDuration(time.Since(e.FirstSeen)))
+	if e.Source != "" {
+		text += ", from " + tview.Escape(e.Source)
+	}
+	p.detail.SetText(text)
+}
+
+// DrawFooter draws the footer
+func (p *Panel) DrawFooter(_ interface{}) {}
+
+// Clear clears the panel
+func (p *Panel) Clear() {
+	p.events = nil
+	p.visible = nil
+	p.table.Clear()
+}
+
+// GetRootView returns the root view
+func (p *Panel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+// GetChildrenViews returns child views
+func (p *Panel) GetChildrenViews() []tview.Primitive {
+	return []tview.Primitive{p.table}
+}
+
+// InitFocus focuses the table
+func (p *Panel) InitFocus() {
+	if p.setAppFocus != nil {
+		p.setAppFocus(p.table)
+	}
+}
+
+// HasEscapableState implements ui.EscapablePanel
+func (p *Panel) HasEscapableState() bool {
+	return true // Always allow ESC to go back
+}
+
+// HandleEscape implements ui.EscapablePanel: ESC leaves filter editing,
+// then clears the filter, then goes back
+func (p *Panel) HandleEscape() bool {
+	if p.filter.Editing {
+		p.filter.Cancel()
+		p.draw()
+		return true
+	}
+	if p.filter.Active {
+		p.filter.Clear()
+		p.draw()
+		return true
+	}
+	if p.onBack != nil {
+		p.onBack()
+		return true
+	}
+	return false
+}
@@ -0,0 +1,933 @@
+package logs
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/k8s"
+	"github.com/vladimirvivien/ktop/ui"
+	v1 "k8s.io/api/core/v1"
+)
+
+const (
+	// tailLines is how many lines of each container already running are
+	// shown; containers that start later are shown from their first line
+	tailLines = 50
+
+	// maxLines is how many lines are kept across all containers
+	maxLines = 5000
+)
+
+// prefixColors color the pod and container prefixes of log lines
+var prefixColors = []string{
+	"aqua", "lime", "yellow", "fuchsia", "orange", "dodgerblue",
+	"springgreen", "violet", "gold", "turquoise", "salmon", "lightskyblue",
+}
+
+// logLine is a line from a container, or a notice about a pod when
+// container is empty
+type logLine struct {
+	pod       string
+	container string
+	text      string
+}
+
+// containerStream is the log stream of a container
+type containerStream struct {
+	cancel   context.CancelFunc
+	restarts int32 // restart count of the container when the stream started
+	ended    bool
+}
+
+// Panel tails the logs of every container of the pods picked by a workload
+// or a label selector. Pods are added as they start running and dropped
+// once they finish or are deleted.
+type Panel struct {
+	root    *tview.Flex
+	laidout bool
+
+	input       *tview.InputField
+	info        *tview.TextView
+	logsView    *tview.TextView
+	filterInput *tview.InputField
+
+	// namespace is the namespace typed selectors apply to; "" for all
+	namespace string
+	selector  k8s.PodSelector
+	selected  bool // whether selector was set
+
+	// Log state, guarded by mu
+	mu          sync.Mutex
+	following   bool
+	timestamps  bool
+	wrapText    bool
+	lines       []logLine
+	filterMode  bool                        // true while the filter input is shown
+	filter      *ui.LogFilter               // filter applied, nil when none
+	minLevel    ui.LogLevel                 // level filter cycled with 'e'
+	highlight   bool                        // highlight matching lines instead of hiding the others
+	structured  bool                        // render JSON and logfmt lines as columns
+	layout      ui.LogLayout                // fields promoted, and whether the others are shown
+	selection   *ui.LogSelection[logLine]   // lines shown under the filter
+	matchCount  int                         // matching lines shown, each in region "m<index>"
+	current     int                         // match jumped to with n/N, -1 for none
+	streams     map[string]*containerStream // by namespace/pod/container
+	watchStart  time.Time
+	cancelWatch context.CancelFunc
+
+	setAppFocus func(p tview.Primitive)
+
+	// Callbacks
+	onBack       func()
+	getLogStream func(ctx context.Context, namespace, podName string, opts k8s.LogOptions) (io.ReadCloser, error)
+	watchPods    func(ctx context.Context, sel k8s.PodSelector, onChange func(pod *v1.Pod, deleted bool)) error
+	queueUpdate  func(func())
+	onExport     func(name string, write func(w io.Writer) error)
+}
+
+// NewPanel creates a new aggregated logs panel
+func NewPanel() *Panel {
+	p := &Panel{
+		following: true, // Default to streaming (auto-tail)
+		current:   -1,
+		streams:   make(map[string]*containerStream),
+	}
+	p.Layout(nil)
+	return p
+}
+
+// SetStructuredLogs sets whether JSON and logfmt lines start out rendered
+// as columns, and the fields promoted next to their message
+func (p *Panel) SetStructuredLogs(on bool, fields []string) {
+	p.structured = on
+	p.layout.Fields = fields
+}
+
+// SetOnBack sets the callback for when user navigates back
+func (p *Panel) SetOnBack(callback func()) {
+	p.onBack = callback
+}
+
+// SetAppFocus sets the callback used to focus primitives in the tview app
+func (p *Panel) SetAppFocus(fn func(p tview.Primitive)) {
+	p.setAppFocus = fn
+}
+
+// SetLogStreamFunc sets the function to get log streams
+func (p *Panel) SetLogStreamFunc(fn func(ctx context.Context, namespace, podName string, opts k8s.LogOptions) (io.ReadCloser, error)) {
+	p.getLogStream = fn
+}
+
+// SetWatchPodsFunc sets the function that reports the pods picked by a
+// selector as they change
+func (p *Panel) SetWatchPodsFunc(fn func(ctx context.Context, sel k8s.PodSelector, onChange func(pod *v1.Pod, deleted bool)) error) {
+	p.watchPods = fn
+}
+
+// SetOnExport sets the callback that writes an export file named name with
+// write. It is called on the UI goroutine and should not block.
+func (p *Panel) SetOnExport(callback func(name string, write func(w io.Writer) error)) {
+	p.onExport = callback
+}
+
+// SetQueueUpdateFunc sets the function for queuing UI updates
+func (p *Panel) SetQueueUpdateFunc(fn func(func())) {
+	p.queueUpdate = fn
+}
+
+// GetTitle returns the panel title
+func (p *Panel) GetTitle() string {
+	return "Logs"
+}
+
+// Layout initializes the panel UI
+func (p *Panel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.input = tview.NewInputField().
+		SetLabel(" Pods: ").
+		SetLabelColor(tcell.ColorYellow).
+		SetFieldBackgroundColor(tcell.ColorDefault).
+		SetPlaceholder("a label selector such as app=web, or a workload such as deployment/web").
+		SetPlaceholderTextColor(tcell.ColorGray)
+	p.input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			sel, err := k8s.ParsePodSelector(p.input.GetText(), p.namespace)
+			if err != nil {
+				p.info.SetText(" [red]" + tview.Escape(err.Error()))
+				return
+			}
+			p.start(sel)
+			p.focus(p.logsView)
+		case tcell.KeyTab, tcell.KeyBacktab, tcell.KeyDown:
+			p.focus(p.logsView)
+		}
+	})
+
+	p.info = tview.NewTextView().SetDynamicColors(true)
+
+	p.logsView = tview.NewTextView()
+	p.logsView.SetDynamicColors(true)
+	p.logsView.SetScrollable(true)
+	p.logsView.SetRegions(true) // Matching lines are regions for n/N
+	p.logsView.SetWrap(p.wrapText)
+	p.logsView.SetMaxLines(maxLines)
+	p.logsView.SetBorder(true)
+	p.logsView.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyTab, tcell.KeyBacktab:
+			p.focus(p.input)
+			return nil
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 's', 'S':
+				p.toggleFollow()
+				return nil
+			case 't', 'T':
+				p.toggleTimestamps()
+				return nil
+			case 'w', 'W':
+				p.toggleWrap()
+				return nil
+			case 'g':
+				p.logsView.ScrollToBeginning()
+				return nil
+			case 'G':
+				p.logsView.ScrollToEnd()
+				return nil
+			case '/':
+				p.enterFilterMode()
+				return nil
+			case 'e', 'E':
+				p.cycleLevel()
+				return nil
+			case 'i', 'I':
+				p.toggleHighlight()
+				return nil
+			case 'o', 'O':
+				p.toggleStructured()
+				return nil
+			case 'f', 'F':
+				p.toggleFields()
+				return nil
+			case 'v':
+				p.saveLogs(true)
+				return nil
+			case 'V':
+				p.saveLogs(false)
+				return nil
+			case 'n':
+				p.jumpToMatch(1)
+				return nil
+			case 'N':
+				p.jumpToMatch(-1)
+				return nil
+			}
+		}
+		return event
+	})
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.input, 1, 0, true).
+		AddItem(p.info, 1, 0, false).
+		AddItem(p.logsView, 0, 1, false)
+	p.root.SetBorder(true)
+	p.root.SetTitle(fmt.Sprintf(" %s Logs ", ui.Icons.Info))
+	p.root.SetTitleAlign(tview.AlignCenter)
+	p.updateLogsTitle()
+	p.laidout = true
+}
+
+func (p *Panel) focus(prim tview.Primitive) {
+	if p.setAppFocus != nil {
+		p.setAppFocus(prim)
+	}
+}
+
+// ShowSelector stops any streams and waits for a selector of pods in
+// namespace ("" for all namespaces)
+func (p *Panel) ShowSelector(namespace string) {
+	p.stop()
+	p.namespace = namespace
+	p.selected = false
+	p.input.SetText("")
+	p.clearLines()
+
+	where := "all namespaces"
+	if namespace != "" {
+		where = "namespace " + namespace
+	}
+	p.info.SetText(fmt.Sprintf(" [gray]Enter the pods to tail in %s", tview.Escape(where)))
+	p.focus(p.input)
+}
+
+// ShowWorkload tails the pods of a workload
+func (p *Panel) ShowWorkload(kind, namespace, name string) {
+	p.namespace = namespace
+	p.input.SetText(strings.ToLower(kind) + "/" + name)
+	p.start(k8s.PodSelector{Namespace: namespace, Kind: kind, Name: name})
+	p.focus(p.logsView)
+}
+
+// start stops any streams and tails the containers of the pods sel picks
+func (p *Panel) start(sel k8s.PodSelector) {
+	p.stop()
+	p.selector = sel
+	p.selected = true
+	p.clearLines()
+
+	if p.watchPods == nil || p.getLogStream == nil {
+		p.info.SetText(" [red]Error: log stream function not configured")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.mu.Lock()
+	p.cancelWatch = cancel
+	p.watchStart = time.Now()
+	p.mu.Unlock()
+
+	// The watch reports the pods already running first, then changes
+	err := p.watchPods(ctx, sel, func(pod *v1.Pod, deleted bool) {
+		if ctx.Err() == nil {
+			p.onPodChange(ctx, pod, deleted)
+		}
+	})
+	if err != nil {
+		cancel()
+		p.info.SetText(" [red]" + tview.Escape(fmt.Sprintf("Error watching pods: %v", err)))
+		return
+	}
+	p.drawInfo()
+}
+
+// stop cancels the pod watch and the log streams
+func (p *Panel) stop() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancelWatch != nil {
+		p.cancelWatch()
+		p.cancelWatch = nil
+	}
+	for key, s := range p.streams {
+		s.cancel()
+		delete(p.streams, key)
+	}
+}
+
+func (p *Panel) clearLines() {
+	p.mu.Lock()
+	p.lines = nil
+	p.selection = p.newSelection()
+	p.matchCount = 0
+	p.current = -1
+	p.mu.Unlock()
+	p.logsView.Clear()
+	p.updateLogsTitleWithCount(0)
+}
+
+// onPodChange starts streaming the running containers of pod that aren't
+// streamed yet, including restarted ones, and stops the streams of a pod
+// that finished or was deleted. It runs on the informer's goroutine.
+func (p *Panel) onPodChange(ctx context.Context, pod *v1.Pod, deleted bool) {
+	prefix := pod.Namespace + "/" + pod.Name + "/"
+
+	if deleted || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		p.mu.Lock()
+		dropped := false
+		for key, s := range p.streams {
+			if strings.HasPrefix(key, prefix) {
+				s.cancel()
+				delete(p.streams, key)
+				dropped = true
+			}
+		}
+		p.mu.Unlock()
+		if dropped {
+			p.appendLine(ctx, logLine{pod: pod.Name, text: "pod stopped, no longer tailed"})
+			p.queueInfo()
+		}
+		return
+	}
+
+	started := false
+	for _, status := range pod.Status.ContainerStatuses {
+		running := status.State.Running
+		if running == nil {
+			continue
+		}
+		key := prefix + status.Name
+
+		p.mu.Lock()
+		if s, ok := p.streams[key]; ok && (!s.ended || s.restarts == status.RestartCount) {
+			p.mu.Unlock()
+			continue
+		}
+		opts := k8s.LogOptions{
+			Container:  status.Name,
+			Follow:     true,
+			Timestamps: p.timestamps,
+		}
+		// Containers started since the watch are new: show all their lines
+		if running.StartedAt.Time.Before(p.watchStart) {
+			opts.TailLines = tailLines
+		}
+		streamCtx, cancel := context.WithCancel(ctx)
+		s := &containerStream{cancel: cancel, restarts: status.RestartCount}
+		p.streams[key] = s
+		p.mu.Unlock()
+
+		started = true
+		go p.stream(streamCtx, s, pod.Namespace, pod.Name, opts)
+	}
+	if started {
+		p.queueInfo()
+	}
+}
+
+// stream copies the lines of a container's log stream until it ends or ctx
+// is done
+func (p *Panel) stream(ctx context.Context, s *containerStream, namespace, podName string, opts k8s.LogOptions) {
+	defer func() {
+		p.mu.Lock()
+		s.ended = true
+		p.mu.Unlock()
+		p.queueInfo()
+	}()
+
+	stream, err := p.getLogStream(ctx, namespace, podName, opts)
+	if err != nil {
+		if ctx.Err() == nil {
+			p.appendLine(ctx, logLine{pod: podName, text: fmt.Sprintf("error getting logs of %s: %v", opts.Container, err)})
+		}
+		return
+	}
+	defer stream.Close()
+
+	scanner := bufio.NewScanner(stream)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 1024*1024)
+
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return
+		}
+		p.appendLine(ctx, logLine{pod: podName, container: opts.Container, text: scanner.Text()})
+	}
+}
+
+// appendLine keeps line and shows it unless it is filtered out. Lines from
+// a canceled watch or stream are dropped.
+func (p *Panel) appendLine(ctx context.Context, line logLine) {
+	p.mu.Lock()
+	if ctx.Err() != nil {
+		p.mu.Unlock()
+		return
+	}
+	p.lines = append(p.lines, line)
+	if len(p.lines) > maxLines {
+		p.lines = p.lines[len(p.lines)-maxLines:]
+	}
+	count := len(p.lines)
+	following := p.following
+	text := p.selectLine(line)
+	p.mu.Unlock()
+
+	if text == "" {
+		return
+	}
+
+	// Write directly to logsView (tview.TextView is goroutine-safe for writes)
+	fmt.Fprint(p.logsView, text)
+
+	if p.queueUpdate != nil {
+		p.queueUpdate(func() {
+			if following {
+				p.logsView.ScrollToEnd()
+			}
+			p.updateLogsTitleWithCount(count)
+		})
+	}
+}
+
+// selectLine returns what to write for line under the filter: nothing,
+// the line, or the line after context lines left out before it. Notices
+// are always shown. Must be called with mu held.
+func (p *Panel) selectLine(line logLine) string {
+	if line.container == "" {
+		return p.formatLine(line, nil) + "\n"
+	}
+	if p.selection == nil {
+		p.selection = p.newSelection()
+	}
+
+	var b strings.Builder
+	filter := p.activeFilter()
+	for _, sel := range p.selection.Add(line) {
+		if sel.Gap {
+			b.WriteString("[gray]--[-]\n")
+		}
+		if !sel.Match {
+			b.WriteString(p.formatLine(sel.Line, nil) + "\n")
+			continue
+		}
+		fmt.Fprintf(&b, "[\"m%d\"]%s[\"\"]\n", p.matchCount, p.formatLine(sel.Line, filter))
+		p.matchCount++
+	}
+	return b.String()
+}
+
+// formatLine prefixes a log line with its pod and container, each in a
+// color of its own, renders it as columns when structured and highlights
+// what filter matched in it. Must be called with mu held.
+func (p *Panel) formatLine(line logLine, filter *ui.LogFilter) string {
+	podPrefix := fmt.Sprintf("[%s]%s[-]", colorFor(line.pod), tview.Escape(line.pod))
+	if line.container == "" {
+		return fmt.Sprintf("%s [gray]--- %s[-]", podPrefix, tview.Escape(line.text))
+	}
+	prefix := fmt.Sprintf("%s [%s]%s[-] ", podPrefix, colorFor(line.container), tview.Escape(line.container))
+
+	layout := &p.layout
+	if !p.structured {
+		layout = nil
+	}
+	return prefix + ui.FormatLogLine(line.text, layout, filter)
+}
+
+// colorFor picks the prefix color of a pod or container name
+func colorFor(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	return prefixColors[h.Sum32()%uint32(len(prefixColors))]
+}
+
+func (p *Panel) toggleFollow() {
+	p.mu.Lock()
+	p.following = !p.following
+	following := p.following
+	count := len(p.lines)
+	p.mu.Unlock()
+
+	// Just toggle auto-scroll behavior - streams keep running
+	p.updateLogsTitleWithCount(count)
+	if following {
+		p.logsView.ScrollToEnd()
+	}
+}
+
+func (p *Panel) toggleTimestamps() {
+	p.mu.Lock()
+	p.timestamps = !p.timestamps
+	p.mu.Unlock()
+
+	// Streams are requested with or without timestamps, so start over
+	if p.selected {
+		p.start(p.selector)
+	}
+}
+
+func (p *Panel) toggleWrap() {
+	p.wrapText = !p.wrapText
+	p.logsView.SetWrap(p.wrapText)
+}
+
+// enterFilterMode enters filter mode and shows the filter input
+func (p *Panel) enterFilterMode() {
+	// Already in filter mode - don't add another input
+	if p.filterInput != nil && p.isFiltering() {
+		return
+	}
+
+	// Create filter input if not exists
+	if p.filterInput == nil {
+		p.filterInput = tview.NewInputField()
+		p.filterInput.SetFieldBackgroundColor(tcell.ColorDarkBlue)
+		p.filterInput.SetLabelColor(tcell.ColorYellow)
+		p.filterInput.SetPlaceholder(`terms, /regexp/, !exclude, level:warn, -C 3`)
+		p.filterInput.SetPlaceholderTextColor(tcell.ColorGray)
+
+		// Handle Enter and Escape keys via DoneFunc
+		p.filterInput.SetDoneFunc(func(key tcell.Key) {
+			switch key {
+			case tcell.KeyEnter:
+				p.applyFilter(p.filterInput.GetText())
+			case tcell.KeyEscape:
+				p.closeFilterInput()
+			}
+		})
+	}
+
+	p.mu.Lock()
+	query := ""
+	if p.filter != nil {
+		query = p.filter.Query
+	}
+	p.filterMode = true
+	p.mu.Unlock()
+
+	// Edit the query applied, if any
+	p.filterInput.SetLabel("[yellow]/[-] ")
+	p.filterInput.SetText(query)
+	p.root.AddItem(p.filterInput, 1, 0, true)
+	p.focus(p.filterInput)
+}
+
+func (p *Panel) isFiltering() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.filterMode
+}
+
+// closeFilterInput removes the filter input, keeping the filter applied
+func (p *Panel) closeFilterInput() {
+	if p.filterInput != nil {
+		p.root.RemoveItem(p.filterInput)
+	}
+	p.mu.Lock()
+	p.filterMode = false
+	p.mu.Unlock()
+	p.focus(p.logsView)
+}
+
+// applyFilter shows the lines matching query, in the pod and container
+// names or the line itself. A query that does not parse is reported in the
+// input, which stays open.
+func (p *Panel) applyFilter(query string) {
+	filter, err := ui.ParseLogFilter(query)
+	if err != nil {
+		p.filterInput.SetLabel("[red]" + tview.Escape(err.Error()) + "[-] ")
+		return
+	}
+
+	p.mu.Lock()
+	p.filter = filter
+	p.mu.Unlock()
+
+	p.closeFilterInput()
+	p.redraw()
+}
+
+// exitFilterMode clears the filter and the level filter and shows all lines
+func (p *Panel) exitFilterMode() {
+	p.closeFilterInput()
+
+	p.mu.Lock()
+	p.filter = nil
+	p.minLevel = ui.LogLevelUnknown
+	p.mu.Unlock()
+
+	p.redraw()
+}
+
+// cycleLevel shows all lines, then WARN and above, then ERROR only
+func (p *Panel) cycleLevel() {
+	p.mu.Lock()
+	switch p.minLevel {
+	case ui.LogLevelUnknown:
+		p.minLevel = ui.LogLevelWarn
+	case ui.LogLevelWarn:
+		p.minLevel = ui.LogLevelError
+	default:
+		p.minLevel = ui.LogLevelUnknown
+	}
+	p.mu.Unlock()
+
+	p.redraw()
+}
+
+// toggleHighlight switches between hiding the lines the filter does not
+// match and showing every line with the matches highlighted
+func (p *Panel) toggleHighlight() {
+	p.mu.Lock()
+	p.highlight = !p.highlight
+	p.mu.Unlock()
+
+	p.redraw()
+}
+
+// toggleStructured switches between raw lines and JSON and logfmt lines
+// rendered as columns
+func (p *Panel) toggleStructured() {
+	p.mu.Lock()
+	p.structured = !p.structured
+	p.mu.Unlock()
+
+	p.redraw()
+}
+
+// toggleFields shows or collapses the fields of structured lines that
+// aren't promoted
+func (p *Panel) toggleFields() {
+	p.mu.Lock()
+	if !p.structured {
+		p.mu.Unlock()
+		return
+	}
+	p.layout.Expanded = !p.layout.Expanded
+	p.mu.Unlock()
+
+	p.redraw()
+}
+
+// activeFilter returns the filter applied with the level filter, which
+// overrides a level in the query. Must be called with mu held.
+func (p *Panel) activeFilter() *ui.LogFilter {
+	if p.minLevel == ui.LogLevelUnknown {
+		return p.filter
+	}
+	filter := ui.LogFilter{}
+	if p.filter != nil {
+		filter = *p.filter
+	}
+	filter.MinLevel = p.minLevel
+	return &filter
+}
+
+// newSelection starts selecting the lines to show under the current
+// filter, whose terms also match pod and container names. Must be called
+// with mu held.
+func (p *Panel) newSelection() *ui.LogSelection[logLine] {
+	filter := p.activeFilter()
+	return ui.NewLogSelection(filter, !p.highlight, func(line logLine) bool {
+		return filter.MatchesWith(line.text, line.pod+" "+line.container)
+	})
+}
+
+// saveLogs exports the lines kept, each after its pod and container, as the
+// filter shows them when filtered is true and a filter is applied,
+// otherwise all of them. Notices about pods are left out.
+func (p *Panel) saveLogs(filtered bool) {
+	if p.onExport == nil || !p.selected {
+		return
+	}
+
+	p.mu.Lock()
+	lines := slices.Clone(p.lines)
+	filter := p.activeFilter()
+	selection := ui.NewLogSelection(filter, true, func(line logLine) bool {
+		return filter.MatchesWith(line.text, line.pod+" "+line.container)
+	})
+	p.mu.Unlock()
+
+	kind := "buffer"
+	if filtered && !filter.Empty() {
+		kind = "filtered"
+	}
+	name := fmt.Sprintf("logs-%s-%s-%s.log", p.selector.String(), kind, time.Now().Format("20060102-150405"))
+	p.onExport(name, func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		for _, line := range lines {
+			if line.container == "" {
+				continue
+			}
+			selected := []ui.SelectedLine[logLine]{{Line: line}}
+			if kind == "filtered" {
+				selected = selection.Add(line)
+			}
+			for _, sel := range selected {
+				if sel.Gap {
+					bw.WriteString("--\n")
+				}
+				fmt.Fprintf(bw, "%s %s %s\n", sel.Line.pod, sel.Line.container, sel.Line.text)
+			}
+		}
+		return bw.Flush()
+	})
+}
+
+// redraw writes the kept lines again under the current filter
+func (p *Panel) redraw() {
+	p.mu.Lock()
+	p.selection = p.newSelection()
+	p.matchCount = 0
+	p.current = -1
+	var b strings.Builder
+	for _, line := range p.lines {
+		b.WriteString(p.selectLine(line))
+	}
+	total := len(p.lines)
+	p.mu.Unlock()
+
+	p.logsView.Clear()
+	p.logsView.Highlight()
+	p.logsView.SetText(b.String())
+	p.updateLogsTitleWithCount(total)
+	p.logsView.ScrollToEnd()
+}
+
+// jumpToMatch highlights the next (dir 1) or previous (dir -1) matching
+// line and scrolls to it, which stops following the streams
+func (p *Panel) jumpToMatch(dir int) {
+	p.mu.Lock()
+	if p.activeFilter().Empty() || p.matchCount == 0 {
+		p.mu.Unlock()
+		return
+	}
+	// Lines beyond maxLines are gone from the view, and their matches with them
+	first := max(p.matchCount-maxLines, 0)
+	switch {
+	case p.current < first && dir < 0:
+		p.current = p.matchCount - 1 // From the end, where the streams are
+	case p.current < first:
+		p.current = first
+	default:
+		p.current += dir
+		if p.current < first {
+			p.current = p.matchCount - 1
+		} else if p.current >= p.matchCount {
+			p.current = first
+		}
+	}
+	region := fmt.Sprintf("m%d", p.current)
+	p.following = false
+	count := len(p.lines)
+	p.mu.Unlock()
+
+	p.logsView.Highlight(region)
+	p.logsView.ScrollToHighlight()
+	p.updateLogsTitleWithCount(count)
+}
+
+func (p *Panel) updateLogsTitle() {
+	p.mu.Lock()
+	count := len(p.lines)
+	p.mu.Unlock()
+	p.updateLogsTitleWithCount(count)
+}
+
+// updateLogsTitleWithCount updates the logs title with the given line
+// count, and the filter and its matches when filtering
+func (p *Panel) updateLogsTitleWithCount(count int) {
+	p.mu.Lock()
+	following := p.following
+	structured := p.structured
+	filter := p.activeFilter()
+	highlight := p.highlight
+	matches, current := p.matchCount, p.current
+	p.mu.Unlock()
+
+	status := fmt.Sprintf("%d lines", count)
+	if !filter.Empty() {
+		status = fmt.Sprintf("%d matching", matches)
+		if filter.Query != "" {
+			status += fmt.Sprintf(" \"%s\"", tview.Escape(filter.Query))
+		}
+		if filter.MinLevel != ui.LogLevelUnknown {
+			status += " [yellow]" + filter.MinLevel.String() + "+[-]"
+		}
+		if highlight {
+			status += " highlighted"
+		}
+		if current >= 0 {
+			status += fmt.Sprintf(", match %d", current+1)
+		}
+	}
+	if structured {
+		status += " [aqua]structured[-]"
+	}
+	if following {
+		status += " [green]streaming[-]"
+	}
+	p.logsView.SetTitle(fmt.Sprintf(" Logs (%s) ", status))
+}
+
+// queueInfo redraws the info line on the UI goroutine
+func (p *Panel) queueInfo() {
+	if p.queueUpdate != nil {
+		p.queueUpdate(p.drawInfo)
+	}
+}
+
+// drawInfo shows the selector and how many pods and containers are tailed
+func (p *Panel) drawInfo() {
+	if !p.selected {
+		return
+	}
+	p.mu.Lock()
+	pods := make(map[string]bool)
+	containers := 0
+	for key, s := range p.streams {
+		if s.ended {
+			continue
+		}
+		containers++
+		pods[key[:strings.LastIndex(key, "/")]] = true
+	}
+	p.mu.Unlock()
+
+	where := "all namespaces"
+	if p.selector.Namespace != "" {
+		where = p.selector.Namespace
+	}
+	p.info.SetText(fmt.Sprintf(" [white]%s[gray] in %s: tailing [white]%d[gray] containers of [white]%d[gray] pods",
+		tview.Escape(p.selector.String()), tview.Escape(where), containers, len(pods)))
+}
+
+// DrawHeader draws the header row
+func (p *Panel) DrawHeader(_ interface{}) {}
+
+// DrawBody draws the body
+func (p *Panel) DrawBody(_ interface{}) {}
+
+// DrawFooter draws the footer
+func (p *Panel) DrawFooter(_ interface{}) {}
+
+// Clear clears the panel
+func (p *Panel) Clear() {
+	p.clearLines()
+}
+
+// Cleanup stops the pod watch and log streams
+func (p *Panel) Cleanup() {
+	p.stop()
+}
+
+// GetRootView returns the root view
+func (p *Panel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+// GetChildrenViews returns child views
+func (p *Panel) GetChildrenViews() []tview.Primitive {
+	return []tview.Primitive{p.input, p.logsView}
+}
+
+// HasEscapableState implements ui.EscapablePanel
+func (p *Panel) HasEscapableState() bool {
+	return true // Always allow ESC to go back
+}
+
+// HandleEscape implements ui.EscapablePanel: ESC closes the filter input,
+// then clears the filter, then stops the streams and goes back
+func (p *Panel) HandleEscape() bool {
+	if p.isFiltering() {
+		p.closeFilterInput()
+		return true
+	}
+	p.mu.Lock()
+	filtered := p.filter != nil || p.minLevel != ui.LogLevelUnknown
+	p.mu.Unlock()
+	if filtered {
+		p.exitFilterMode()
+		return true
+	}
+	p.stop()
+	if p.onBack != nil {
+		p.onBack()
+		return true
+	}
+	return false
+}
@@ -0,0 +1,285 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// NodeCapacity is what a node can still take: its allocatable resources
+// minus the requests of the pods bound to it
+type NodeCapacity struct {
+	Name string
+
+	// Status is Ready, NotReady, Cordoned or Tainted; only Ready nodes take
+	// new pods without tolerations
+	Status string
+	// Taints are the NoSchedule and NoExecute taints of the node
+	Taints []v1.Taint
+
+	// CPU in millicores, memory in bytes
+	AllocatableCPU    int64
+	AllocatableMemory int64
+	AllocatablePods   int64
+	RequestedCPU      int64
+	RequestedMemory   int64
+	Pods              int64
+}
+
+// Node statuses for scheduling
+const (
+	CapacityReady    = "Ready"
+	CapacityNotReady = "NotReady"
+	CapacityCordoned = "Cordoned"
+	CapacityTainted  = "Tainted"
+)
+
+// FreeCPU returns the requestable CPU left, in millicores
+func (n NodeCapacity) FreeCPU() int64 {
+	return max(n.AllocatableCPU-n.RequestedCPU, 0)
+}
+
+// FreeMemory returns the requestable memory left, in bytes
+func (n NodeCapacity) FreeMemory() int64 {
+	return max(n.AllocatableMemory-n.RequestedMemory, 0)
+}
+
+// FreePods returns how many more pods the node accepts
+func (n NodeCapacity) FreePods() int64 {
+	return max(n.AllocatablePods-n.Pods, 0)
+}
+
+// Schedulable reports whether a pod tolerating the given taint keys can be
+// scheduled on the node
+func (n NodeCapacity) Schedulable(tolerations []string) bool {
+	switch n.Status {
+	case CapacityReady:
+		return true
+	case CapacityTainted:
+		for _, taint := range n.Taints {
+			if !tolerates(tolerations, taint) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func tolerates(tolerations []string, taint v1.Taint) bool {
+	for _, key := range tolerations {
+		if key == "*" || key == taint.Key {
+			return true
+		}
+	}
+	return false
+}
+
+// Replicas returns how many pods of shape the node can take, 0 when it is
+// not schedulable for shape
+func (n NodeCapacity) Replicas(shape PodShape) int64 {
+	if !n.Schedulable(shape.Tolerations) {
+		return 0
+	}
+	replicas := n.FreePods()
+	if shape.CPU > 0 {
+		replicas = min(replicas, n.FreeCPU()/shape.CPU)
+	}
+	if shape.Memory > 0 {
+		replicas = min(replicas, n.FreeMemory()/shape.Memory)
+	}
+	return replicas
+}
+
+// NewNodeCapacities computes the capacity left on nodes from the requests
+// of pods. Pods that finished or aren't bound to a node don't count.
+func NewNodeCapacities(nodes []*v1.Node, pods []*v1.Pod) []NodeCapacity {
+	type requests struct{ cpu, memory, pods int64 }
+	byNode := make(map[string]*requests, len(nodes))
+	for _, pod := range pods {
+		if pod.Spec.NodeName == "" || pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		r, ok := byNode[pod.Spec.NodeName]
+		if !ok {
+			r = &requests{}
+			byNode[pod.Spec.NodeName] = r
+		}
+		cpu, memory := PodRequests(pod)
+		r.cpu += cpu
+		r.memory += memory
+		r.pods++
+	}
+
+	capacities := make([]NodeCapacity, 0, len(nodes))
+	for _, node := range nodes {
+		c := NodeCapacity{
+			Name:              node.Name,
+			Status:            CapacityReady,
+			AllocatableCPU:    node.Status.Allocatable.Cpu().MilliValue(),
+			AllocatableMemory: node.Status.Allocatable.Memory().Value(),
+			AllocatablePods:   node.Status.Allocatable.Pods().Value(),
+		}
+		if r, ok := byNode[node.Name]; ok {
+			c.RequestedCPU, c.RequestedMemory, c.Pods = r.cpu, r.memory, r.pods
+		}
+		for _, taint := range node.Spec.Taints {
+			if taint.Effect == v1.TaintEffectNoSchedule || taint.Effect == v1.TaintEffectNoExecute {
+				c.Taints = append(c.Taints, taint)
+			}
+		}
+		switch {
+		case GetNodeReadyStatus(node) != string(v1.NodeReady):
+			c.Status = CapacityNotReady
+		case node.Spec.Unschedulable:
+			c.Status = CapacityCordoned
+		case len(c.Taints) > 0:
+			c.Status = CapacityTainted
+		}
+		capacities = append(capacities, c)
+	}
+	return capacities
+}
+
+// PodRequests returns the CPU (millicores) and memory (bytes) a pod requests
+// as the scheduler counts them: the larger of its containers' total and its
+// largest init container, plus the pod overhead
+func PodRequests(pod *v1.Pod) (cpu, memory int64) {
+	for _, c := range pod.Spec.Containers {
+		cpu += c.Resources.Requests.Cpu().MilliValue()
+		memory += c.Resources.Requests.Memory().Value()
+	}
+	for _, c := range pod.Spec.InitContainers {
+		cpu = max(cpu, c.Resources.Requests.Cpu().MilliValue())
+		memory = max(memory, c.Resources.Requests.Memory().Value())
+	}
+	if pod.Spec.Overhead != nil {
+		cpu += pod.Spec.Overhead.Cpu().MilliValue()
+		memory += pod.Spec.Overhead.Memory().Value()
+	}
+	return cpu, memory
+}
+
+// PodShape is the requests of a hypothetical pod, and the taint keys it
+// tolerates ("*" for all)
+type PodShape struct {
+	CPU         int64 // millicores
+	Memory      int64 // bytes
+	Tolerations []string
+}
+
+// String writes the shape the way ParsePodShape reads it
+func (s PodShape) String() string {
+	parts := []string{
+		"cpu=" + resource.NewMilliQuantity(s.CPU, resource.DecimalSI).String(),
+		"memory=" + resource.NewQuantity(s.Memory, resource.BinarySI).String(),
+	}
+	for _, key := range s.Tolerations {
+		parts = append(parts, "tolerate="+key)
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParsePodShape reads a pod shape such as "4/16Gi", "500m 1Gi" or
+// "cpu=4 memory=16Gi tolerate=dedicated". Values without a key are CPU and
+// then memory; fields are separated by spaces, commas or slashes.
+func ParsePodShape(s string) (PodShape, error) {
+	var shape PodShape
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ' ' || r == ',' || r == '/'
+	})
+	if len(fields) == 0 {
+		return shape, fmt.Errorf("empty pod shape")
+	}
+
+	var cpuSet, memorySet bool
+	for _, field := range fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok {
+			key, value = "", field
+			switch {
+			case !cpuSet:
+				key = "cpu"
+			case !memorySet:
+				key = "memory"
+			default:
+				return shape, fmt.Errorf("unexpected %q: cpu and memory are already set", field)
+			}
+		}
+
+		switch strings.ToLower(key) {
+		case "cpu":
+			q, err := resource.ParseQuantity(value)
+			if err != nil {
+				return shape, fmt.Errorf("cpu %q: %w", value, err)
+			}
+			shape.CPU, cpuSet = q.MilliValue(), true
+		case "memory", "mem":
+			q, err := resource.ParseQuantity(value)
+			if err != nil {
+				return shape, fmt.Errorf("memory %q: %w", value, err)
+			}
+			shape.Memory, memorySet = q.Value(), true
+		case "tolerate":
+			if value == "" {
+				return shape, fmt.Errorf("tolerate needs a taint key or *")
+			}
+			shape.Tolerations = append(shape.Tolerations, value)
+		default:
+			return shape, fmt.Errorf("unknown field %q: use cpu, memory or tolerate", key)
+		}
+	}
+	if shape.CPU < 0 || shape.Memory < 0 {
+		return shape, fmt.Errorf("requests can't be negative")
+	}
+	if shape.CPU == 0 && shape.Memory == 0 {
+		return shape, fmt.Errorf("a pod shape needs a cpu or memory request")
+	}
+	return shape, nil
+}
+
+// SortNodeCapacitiesBy sorts node capacities by the specified column and
+// direction. FITS sorts by the replicas of shape each node can take.
+func SortNodeCapacitiesBy(nodes []NodeCapacity, column string, ascending bool, shape *PodShape) {
+	byName := func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	}
+	byInt := func(value func(n NodeCapacity) int64) func(i, j int) bool {
+		return func(i, j int) bool {
+			vi, vj := value(nodes[i]), value(nodes[j])
+			if vi == vj {
+				return byName(i, j)
+			}
+			return vi < vj
+		}
+	}
+
+	var sortFunc func(i, j int) bool
+	switch column {
+	case "CPU FREE":
+		sortFunc = byInt(NodeCapacity.FreeCPU)
+	case "MEM FREE":
+		sortFunc = byInt(NodeCapacity.FreeMemory)
+	case "PODS FREE":
+		sortFunc = byInt(NodeCapacity.FreePods)
+	case "FITS":
+		if shape == nil {
+			sortFunc = byName
+			break
+		}
+		sortFunc = byInt(func(n NodeCapacity) int64 { return n.Replicas(*shape) })
+	default: // NODE
+		sortFunc = byName
+	}
+
+	if ascending {
+		sort.Slice(nodes, sortFunc)
+	} else {
+		sort.Slice(nodes, func(i, j int) bool {
+			return !sortFunc(i, j)
+		})
+	}
+}
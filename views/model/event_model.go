@@ -0,0 +1,169 @@
+package model
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// EventModel is one or more events about the same object with the same
+// type, reason and message
+type EventModel struct {
+	Namespace string
+	Type      string // Normal or Warning
+	Reason    string
+	Message   string
+
+	// The involved object
+	Kind string
+	Name string
+
+	Source    string // component that reported the event, and its host
+	Count     int32  // occurrences across the merged events
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+// Key identifies the events merged into the model
+func (e EventModel) Key() string {
+	return fmt.Sprintf("%s/%s/%s/%s/%s/%s", e.Namespace, e.Kind, e.Name, e.Type, e.Reason, e.Message)
+}
+
+// Object returns the involved object as Kind/name
+func (e EventModel) Object() string {
+	return e.Kind + "/" + e.Name
+}
+
+// NewEventModels merges events about the same object with the same type,
+// reason and message, which the API keeps apart once a series of events
+// outlives its aggregation window, and adds up their counts. The models are
+// ordered most recent first.
+func NewEventModels(events []v1.Event) []EventModel {
+	var models []EventModel
+	index := make(map[string]int)
+	for _, evt := range events {
+		m := EventModel{
+			Namespace: evt.InvolvedObject.Namespace,
+			Type:      evt.Type,
+			Reason:    evt.Reason,
+			Message:   evt.Message,
+			Kind:      evt.InvolvedObject.Kind,
+			Name:      evt.InvolvedObject.Name,
+			Source:    eventSource(evt),
+			Count:     eventCount(evt),
+			FirstSeen: evt.FirstTimestamp.Time,
+			LastSeen:  EventTime(evt),
+		}
+		if m.Namespace == "" {
+			m.Namespace = evt.Namespace
+		}
+		if m.FirstSeen.IsZero() {
+			m.FirstSeen = m.LastSeen
+		}
+
+		key := m.Key()
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(models)
+			models = append(models, m)
+			continue
+		}
+		merged := &models[i]
+		merged.Count += m.Count
+		if m.FirstSeen.Before(merged.FirstSeen) {
+			merged.FirstSeen = m.FirstSeen
+		}
+		if m.LastSeen.After(merged.LastSeen) {
+			merged.LastSeen = m.LastSeen
+			merged.Source = m.Source
+		}
+	}
+
+	SortEventModelsBy(models, "LAST SEEN", false)
+	return models
+}
+
+// EventTime returns when an event last occurred: its last timestamp, or
+// for events.k8s.io events its series' last observed time or event time
+func EventTime(evt v1.Event) time.Time {
+	switch {
+	case !evt.LastTimestamp.IsZero():
+		return evt.LastTimestamp.Time
+	case evt.Series != nil && !evt.Series.LastObservedTime.IsZero():
+		return evt.Series.LastObservedTime.Time
+	case !evt.EventTime.IsZero():
+		return evt.EventTime.Time
+	}
+	return evt.CreationTimestamp.Time
+}
+
+func eventCount(evt v1.Event) int32 {
+	if evt.Series != nil && evt.Series.Count > 0 {
+		return evt.Series.Count
+	}
+	return max(evt.Count, 1)
+}
+
+func eventSource(evt v1.Event) string {
+	component, host := evt.Source.Component, evt.Source.Host
+	if component == "" {
+		component = evt.ReportingController
+	}
+	if host == "" {
+		host = evt.ReportingInstance
+	}
+	if host == "" {
+		return component
+	}
+	return component + ", " + host
+}
+
+// SortEventModelsBy sorts events by the specified column and direction
+func SortEventModelsBy(events []EventModel, column string, ascending bool) {
+	byTime := func(i, j int) bool {
+		if events[i].LastSeen.Equal(events[j].LastSeen) {
+			return events[i].Key() < events[j].Key()
+		}
+		return events[i].LastSeen.Before(events[j].LastSeen)
+	}
+	byString := func(value func(e EventModel) string) func(i, j int) bool {
+		return func(i, j int) bool {
+			vi, vj := value(events[i]), value(events[j])
+			if vi == vj {
+				return byTime(i, j)
+			}
+			return vi < vj
+		}
+	}
+
+	var sortFunc func(i, j int) bool
+	switch column {
+	case "TYPE":
+		sortFunc = byString(func(e EventModel) string { return e.Type })
+	case "REASON":
+		sortFunc = byString(func(e EventModel) string { return e.Reason })
+	case "NAMESPACE":
+		sortFunc = byString(func(e EventModel) string { return e.Namespace })
+	case "OBJECT":
+		sortFunc = byString(EventModel.Object)
+	case "COUNT":
+		sortFunc = func(i, j int) bool {
+			if events[i].Count == events[j].Count {
+				return byTime(i, j)
+			}
+			return events[i].Count < events[j].Count
+		}
+	default: // LAST SEEN
+		sortFunc = byTime
+	}
+
+	if ascending {
+		sort.Slice(events, sortFunc)
+	} else {
+		sort.Slice(events, func(i, j int) bool {
+			return !sortFunc(i, j)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NamespaceModel holds per-namespace pod counts and resource totals
+type NamespaceModel struct {
+	Name         string
+	Status       string
+	TimeSince    string
+	CreationTime metav1.Time
+
+	Pods        int
+	RunningPods int
+	FailedPods  int
+	Restarts    int
+
+	UsageCpuQty     *resource.Quantity
+	UsageMemQty     *resource.Quantity
+	RequestedCpuQty *resource.Quantity
+	RequestedMemQty *resource.Quantity
+	LimitCpuQty     *resource.Quantity
+	LimitMemQty     *resource.Quantity
+
+	// Hard limits from the namespace's ResourceQuotas (the tightest one when
+	// several constrain the same resource). Nil when no quota applies.
+	QuotaRequestsCpuQty *resource.Quantity
+	QuotaRequestsMemQty *resource.Quantity
+	QuotaLimitsCpuQty   *resource.Quantity
+	QuotaLimitsMemQty   *resource.Quantity
+}
+
+// NewNamespaceModel creates an empty model for a namespace
+func NewNamespaceModel(ns *v1.Namespace) *NamespaceModel {
+	return &NamespaceModel{
+		Name:            ns.Name,
+		Status:          string(ns.Status.Phase),
+		TimeSince:       timeSince(ns.CreationTimestamp),
+		CreationTime:    ns.CreationTimestamp,
+		UsageCpuQty:     resource.NewQuantity(0, resource.DecimalSI),
+		UsageMemQty:     resource.NewQuantity(0, resource.BinarySI),
+		RequestedCpuQty: resource.NewQuantity(0, resource.DecimalSI),
+		RequestedMemQty: resource.NewQuantity(0, resource.BinarySI),
+		LimitCpuQty:     resource.NewQuantity(0, resource.DecimalSI),
+		LimitMemQty:     resource.NewQuantity(0, resource.BinarySI),
+	}
+}
+
+// AddPod adds a pod's counts, restarts, requests and limits to the totals.
+// Like ResourceQuota, requests and limits only count pods that have not
+// terminated.
+func (m *NamespaceModel) AddPod(pod *v1.Pod) {
+	m.Pods++
+	switch pod.Status.Phase {
+	case v1.PodRunning:
+		m.RunningPods++
+	case v1.PodFailed:
+		m.FailedPods++
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		m.Restarts += int(status.RestartCount)
+	}
+
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return
+	}
+	for _, container := range pod.Spec.Containers {
+		m.RequestedCpuQty.Add(*container.Resources.Requests.Cpu())
+		m.RequestedMemQty.Add(*container.Resources.Requests.Memory())
+		m.LimitCpuQty.Add(*container.Resources.Limits.Cpu())
+		m.LimitMemQty.Add(*container.Resources.Limits.Memory())
+	}
+}
+
+// AddUsage adds measured pod usage to the totals
+func (m *NamespaceModel) AddUsage(cpu, mem *resource.Quantity) {
+	if cpu != nil {
+		m.UsageCpuQty.Add(*cpu)
+	}
+	if mem != nil {
+		m.UsageMemQty.Add(*mem)
+	}
+}
+
+// AddQuota applies the hard limits of a ResourceQuota in the namespace
+func (m *NamespaceModel) AddQuota(quota *v1.ResourceQuota) {
+	hard := quota.Spec.Hard
+	// "cpu" and "memory" are shorthands for requests.cpu and requests.memory
+	m.QuotaRequestsCpuQty = tightest(m.QuotaRequestsCpuQty, hard, v1.ResourceRequestsCPU, v1.ResourceCPU)
+	m.QuotaRequestsMemQty = tightest(m.QuotaRequestsMemQty, hard, v1.ResourceRequestsMemory, v1.ResourceMemory)
+	m.QuotaLimitsCpuQty = tightest(m.QuotaLimitsCpuQty, hard, v1.ResourceLimitsCPU)
+	m.QuotaLimitsMemQty = tightest(m.QuotaLimitsMemQty, hard, v1.ResourceLimitsMemory)
+}
+
+// tightest returns the smallest of current and the hard limits set for names
+func tightest(current *resource.Quantity, hard v1.ResourceList, names ...v1.ResourceName) *resource.Quantity {
+	for _, name := range names {
+		q, ok := hard[name]
+		if !ok {
+			continue
+		}
+		if current == nil || q.Cmp(*current) < 0 {
+			qc := q.DeepCopy()
+			current = &qc
+		}
+	}
+	return current
+}
+
+// SortNamespaceModelsBy sorts namespaces by the specified column and direction
+func SortNamespaceModelsBy(namespaces []NamespaceModel, column string, ascending bool) {
+	byName := func(i, j int) bool {
+		return namespaces[i].Name < namespaces[j].Name
+	}
+	byInt := func(value func(m NamespaceModel) int64) func(i, j int) bool {
+		return func(i, j int) bool {
+			vi, vj := value(namespaces[i]), value(namespaces[j])
+			if vi == vj {
+				return byName(i, j)
+			}
+			return vi < vj
+		}
+	}
+
+	var sortFunc func(i, j int) bool
+
+	switch column {
+	case "PODS":
+		sortFunc = byInt(func(m NamespaceModel) int64 { return int64(m.Pods) })
+	case "RUNNING":
+		sortFunc = byInt(func(m NamespaceModel) int64 { return int64(m.RunningPods) })
+	case "FAILED":
+		sortFunc = byInt(func(m NamespaceModel) int64 { return int64(m.FailedPods) })
+	case "RST":
+		sortFunc = byInt(func(m NamespaceModel) int64 { return int64(m.Restarts) })
+	case "CPU":
+		sortFunc = byInt(func(m NamespaceModel) int64 { return qtyMilli(m.UsageCpuQty) })
+	case "MEMORY":
+		sortFunc = byInt(func(m NamespaceModel) int64 { return qtyValue(m.UsageMemQty) })
+	case "CPU REQ":
+		sortFunc = byInt(func(m NamespaceModel) int64 { return qtyMilli(m.RequestedCpuQty) })
+	case "CPU LIM":
+		sortFunc = byInt(func(m NamespaceModel) int64 { return qtyMilli(m.LimitCpuQty) })
+	case "MEM REQ":
+		sortFunc = byInt(func(m NamespaceModel) int64 { return qtyValue(m.RequestedMemQty) })
+	case "MEM LIM":
+		sortFunc = byInt(func(m NamespaceModel) int64 { return qtyValue(m.LimitMemQty) })
+	default: // NAMESPACE
+		sortFunc = byName
+	}
+
+	if ascending {
+		sort.Slice(namespaces, sortFunc)
+	} else {
+		sort.Slice(namespaces, func(i, j int) bool {
+			return !sortFunc(i, j)
+		})
+	}
+}
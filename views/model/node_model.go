@@ -50,6 +50,9 @@ type NodeModel struct {
 
 	UsageCpuQty *resource.Quantity
 	UsageMemQty *resource.Quantity
+
+	// Custom holds the values of custom columns by column name
+	Custom map[string]float64
 }
 
 func NewNodeModel(node *coreV1.Node, metrics *v1beta1.NodeMetrics) *NodeModel {
@@ -36,6 +36,13 @@ type PodModel struct {
 	Restarts        int
 	Volumes         int
 	VolMounts       int
+
+	// OOMKills holds, for each container whose current or last
+	// termination was an out-of-memory kill, when that termination finished
+	OOMKills []time.Time
+
+	// Custom holds the values of custom columns by column name
+	Custom map[string]float64
 }
 
 type PodContainerSummary struct {
@@ -49,6 +56,7 @@ type ContainerStatusSummary struct {
 	Ready       int
 	Total       int
 	Restarts    int
+	OOMKills    []time.Time
 	Status      string
 	SomeRunning bool
 }
@@ -261,6 +269,7 @@ func NewPodModel(pod *v1.Pod, podMetrics *metricsV1beta1.PodMetrics, nodeMetrics
 		ReadyContainers:    statusSummary.Ready,
 		TotalContainers:    statusSummary.Total,
 		Restarts:           statusSummary.Restarts,
+		OOMKills:           statusSummary.OOMKills,
 	}
 }
 
@@ -280,6 +289,12 @@ func getContainerStatusSummary(containerStats []v1.ContainerStatus) ContainerSta
 	for _, stat := range containerStats {
 		summary.Restarts += int(stat.RestartCount)
 		switch {
+		case isOOMKilled(stat.State.Terminated):
+			summary.OOMKills = append(summary.OOMKills, stat.State.Terminated.FinishedAt.Time)
+		case isOOMKilled(stat.LastTerminationState.Terminated):
+			summary.OOMKills = append(summary.OOMKills, stat.LastTerminationState.Terminated.FinishedAt.Time)
+		}
+		switch {
 		case stat.Ready && stat.State.Running != nil:
 			summary.Ready++
 			summary.Status = "Running"
@@ -299,6 +314,10 @@ func getContainerStatusSummary(containerStats []v1.ContainerStatus) ContainerSta
 	return summary
 }
 
+func isOOMKilled(state *v1.ContainerStateTerminated) bool {
+	return state != nil && state.Reason == "OOMKilled"
+}
+
 func podIsReady(conds []v1.PodCondition) bool {
 	for _, cond := range conds {
 		if cond.Type == v1.PodReady && cond.Status == v1.ConditionTrue {
@@ -0,0 +1,211 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// ContainerKey identifies a container of a pod
+type ContainerKey struct {
+	Namespace string
+	Pod       string
+	Container string
+}
+
+// ThrottledContainer is a container whose CPU was throttled by its limit
+type ThrottledContainer struct {
+	ContainerKey
+	ThrottledPercent float64
+	CPULimit         *resource.Quantity // nil when the container has no limit
+}
+
+// OOMKilledContainer is a container that ran out of memory, according to
+// its status or to the OOM events of its cgroup
+type OOMKilledContainer struct {
+	ContainerKey
+	OOMEvents   float64 // OOM events reported by the metrics source
+	Reason      string  // reason of the current or last termination
+	ExitCode    int32
+	FinishedAt  time.Time // zero when the container never terminated
+	Restarts    int
+	MemoryLimit *resource.Quantity // nil when the container has no limit
+}
+
+// PressureData is what the pressure page shows
+type PressureData struct {
+	Throttled []ThrottledContainer
+	OOMKilled []OOMKilledContainer
+
+	// MetricsErr explains why throttling and OOM events are missing; the
+	// OOM kills in pod status are listed regardless
+	MetricsErr error
+}
+
+// NewPressureData builds the pressure page from pods and the throttling and
+// OOM events the metrics source reported per container. Containers of pods
+// not in pods are left out.
+func NewPressureData(pods []*v1.Pod, throttled, oomEvents map[ContainerKey]float64, metricsErr error) *PressureData {
+	data := &PressureData{MetricsErr: metricsErr}
+	for _, pod := range pods {
+		statuses := make(map[string]v1.ContainerStatus, len(pod.Status.ContainerStatuses))
+		for _, status := range pod.Status.ContainerStatuses {
+			statuses[status.Name] = status
+		}
+
+		for _, container := range pod.Spec.Containers {
+			key := ContainerKey{Namespace: pod.Namespace, Pod: pod.Name, Container: container.Name}
+			status := statuses[container.Name]
+
+			if pct, ok := throttled[key]; ok {
+				tc := ThrottledContainer{ContainerKey: key, ThrottledPercent: pct}
+				if limit, ok := container.Resources.Limits[v1.ResourceCPU]; ok {
+					tc.CPULimit = &limit
+				}
+				data.Throttled = append(data.Throttled, tc)
+			}
+
+			events := oomEvents[key]
+			terminated := status.State.Terminated
+			if terminated == nil {
+				terminated = status.LastTerminationState.Terminated
+			}
+			if events == 0 && !isOOMKilled(status.State.Terminated) && !isOOMKilled(status.LastTerminationState.Terminated) {
+				continue
+			}
+			oc := OOMKilledContainer{ContainerKey: key, OOMEvents: events, Restarts: int(status.RestartCount)}
+			if terminated != nil {
+				oc.Reason = terminated.Reason
+				oc.ExitCode = terminated.ExitCode
+				oc.FinishedAt = terminated.FinishedAt.Time
+			}
+			if limit, ok := container.Resources.Limits[v1.ResourceMemory]; ok {
+				oc.MemoryLimit = &limit
+			}
+			data.OOMKilled = append(data.OOMKilled, oc)
+		}
+	}
+	return data
+}
+
+// byContainerKey orders containers by namespace, pod and container name
+func byContainerKey(a, b ContainerKey) bool {
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	if a.Pod != b.Pod {
+		return a.Pod < b.Pod
+	}
+	return a.Container < b.Container
+}
+
+// SortThrottledContainersBy sorts throttled containers by the specified
+// column and direction
+func SortThrottledContainersBy(containers []ThrottledContainer, column string, ascending bool) {
+	byKey := func(i, j int) bool {
+		return byContainerKey(containers[i].ContainerKey, containers[j].ContainerKey)
+	}
+	byFloat := func(value func(c ThrottledContainer) float64) func(i, j int) bool {
+		return func(i, j int) bool {
+			vi, vj := value(containers[i]), value(containers[j])
+			if vi == vj {
+				return byKey(i, j)
+			}
+			return vi < vj
+		}
+	}
+
+	var sortFunc func(i, j int) bool
+	switch column {
+	case "POD":
+		sortFunc = func(i, j int) bool {
+			if containers[i].Pod == containers[j].Pod {
+				return byKey(i, j)
+			}
+			return containers[i].Pod < containers[j].Pod
+		}
+	case "CONTAINER":
+		sortFunc = func(i, j int) bool {
+			if containers[i].Container == containers[j].Container {
+				return byKey(i, j)
+			}
+			return containers[i].Container < containers[j].Container
+		}
+	case "THROTTLED":
+		sortFunc = byFloat(func(c ThrottledContainer) float64 { return c.ThrottledPercent })
+	case "CPU LIM":
+		sortFunc = byFloat(func(c ThrottledContainer) float64 { return float64(qtyMilli(c.CPULimit)) })
+	default: // NAMESPACE
+		sortFunc = byKey
+	}
+
+	if ascending {
+		sort.Slice(containers, sortFunc)
+	} else {
+		sort.Slice(containers, func(i, j int) bool {
+			return !sortFunc(i, j)
+		})
+	}
+}
+
+// SortOOMKilledContainersBy sorts OOM killed containers by the specified
+// column and direction
+func SortOOMKilledContainersBy(containers []OOMKilledContainer, column string, ascending bool) {
+	byKey := func(i, j int) bool {
+		return byContainerKey(containers[i].ContainerKey, containers[j].ContainerKey)
+	}
+	byFloat := func(value func(c OOMKilledContainer) float64) func(i, j int) bool {
+		return func(i, j int) bool {
+			vi, vj := value(containers[i]), value(containers[j])
+			if vi == vj {
+				return byKey(i, j)
+			}
+			return vi < vj
+		}
+	}
+
+	var sortFunc func(i, j int) bool
+	switch column {
+	case "POD":
+		sortFunc = func(i, j int) bool {
+			if containers[i].Pod == containers[j].Pod {
+				return byKey(i, j)
+			}
+			return containers[i].Pod < containers[j].Pod
+		}
+	case "CONTAINER":
+		sortFunc = func(i, j int) bool {
+			if containers[i].Container == containers[j].Container {
+				return byKey(i, j)
+			}
+			return containers[i].Container < containers[j].Container
+		}
+	case "OOM EVENTS":
+		sortFunc = byFloat(func(c OOMKilledContainer) float64 { return c.OOMEvents })
+	case "REASON":
+		sortFunc = func(i, j int) bool {
+			if containers[i].Reason == containers[j].Reason {
+				return byKey(i, j)
+			}
+			return containers[i].Reason < containers[j].Reason
+		}
+	case "FINISHED":
+		sortFunc = byFloat(func(c OOMKilledContainer) float64 { return float64(c.FinishedAt.Unix()) })
+	case "RST":
+		sortFunc = byFloat(func(c OOMKilledContainer) float64 { return float64(c.Restarts) })
+	case "MEM LIM":
+		sortFunc = byFloat(func(c OOMKilledContainer) float64 { return float64(qtyValue(c.MemoryLimit)) })
+	default: // NAMESPACE
+		sortFunc = byKey
+	}
+
+	if ascending {
+		sort.Slice(containers, sortFunc)
+	} else {
+		sort.Slice(containers, func(i, j int) bool {
+			return !sortFunc(i, j)
+		})
+	}
+}
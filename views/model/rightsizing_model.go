@@ -0,0 +1,318 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// Rightsizing policy: requests cover the p95 usage and limits the peak, each
+// with some headroom
+const (
+	requestHeadroom = 1.15
+	limitHeadroom   = 1.3
+
+	minCPUMillis   = 10
+	cpuStepMillis  = 5
+	minMemoryBytes = 16 << 20
+	memoryStep     = 1 << 20
+
+	// MinRightsizingSamples is the usage samples a container needs for a
+	// recommendation
+	MinRightsizingSamples = 10
+
+	// A request this many times the recommendation is over-provisioned
+	overProvisionedRatio = 2.0
+	// Memory peaking above this share of the limit is under-provisioned
+	memoryLimitPressure = 0.9
+)
+
+// Rightsizing statuses, from most to least urgent
+const (
+	RightsizingUnder  = "UNDER"
+	RightsizingOver   = "OVER"
+	RightsizingOK     = "OK"
+	RightsizingNoData = "NO DATA"
+)
+
+// Rightsizing compares the requests and limits of a workload container with
+// its usage and recommends new ones
+type Rightsizing struct {
+	Kind      string
+	Namespace string
+	Workload  string
+	Container string
+
+	Pods    int // pods whose usage was sampled
+	Samples int // usage samples across those pods
+
+	// Usage: CPU in millicores, memory in bytes
+	CPUP95    float64
+	CPUMax    float64
+	MemoryP95 float64
+	MemoryMax float64
+
+	// Current resources; nil when unset
+	CPURequest    *resource.Quantity
+	CPULimit      *resource.Quantity
+	MemoryRequest *resource.Quantity
+	MemoryLimit   *resource.Quantity
+
+	// Recommended resources; nil without enough samples. A CPU limit is
+	// only recommended for containers that already have one.
+	RecCPURequest    *resource.Quantity
+	RecCPULimit      *resource.Quantity
+	RecMemoryRequest *resource.Quantity
+	RecMemoryLimit   *resource.Quantity
+
+	Status string
+	Reason string // why the container is over- or under-provisioned
+}
+
+// RightsizingData is what the rightsizing page shows
+type RightsizingData struct {
+	Items []Rightsizing
+
+	// Window is how far back usage was sampled
+	Window time.Duration
+}
+
+// NewRightsizing computes the recommendation for a workload container from
+// its spec and the CPU (millicores) and memory (bytes) usage sampled across
+// the workload's pods
+func NewRightsizing(kind, namespace, workload string, pods int, container v1.Container, cpu, memory []float64) Rightsizing {
+	r := Rightsizing{
+		Kind:      kind,
+		Namespace: namespace,
+		Workload:  workload,
+		Container: container.Name,
+		Pods:      pods,
+		Samples:   min(len(cpu), len(memory)),
+	}
+	if q, ok := container.Resources.Requests[v1.ResourceCPU]; ok {
+		r.CPURequest = &q
+	}
+	if q, ok := container.Resources.Limits[v1.ResourceCPU]; ok {
+		r.CPULimit = &q
+	}
+	if q, ok := container.Resources.Requests[v1.ResourceMemory]; ok {
+		r.MemoryRequest = &q
+	}
+	if q, ok := container.Resources.Limits[v1.ResourceMemory]; ok {
+		r.MemoryLimit = &q
+	}
+
+	if r.Samples < MinRightsizingSamples {
+		r.Status = RightsizingNoData
+		r.Reason = fmt.Sprintf("%d of %d samples", r.Samples, MinRightsizingSamples)
+		return r
+	}
+
+	r.CPUP95, r.CPUMax = Percentile(cpu, 95), Percentile(cpu, 100)
+	r.MemoryP95, r.MemoryMax = Percentile(memory, 95), Percentile(memory, 100)
+
+	r.RecCPURequest = cpuQuantity(r.CPUP95 * requestHeadroom)
+	if r.CPULimit != nil {
+		r.RecCPULimit = cpuQuantity(r.CPUMax * limitHeadroom)
+		if r.RecCPULimit.Cmp(*r.RecCPURequest) < 0 {
+			r.RecCPULimit = r.RecCPURequest
+		}
+	}
+	r.RecMemoryRequest = memoryQuantity(r.MemoryP95 * requestHeadroom)
+	r.RecMemoryLimit = memoryQuantity(r.MemoryMax * limitHeadroom)
+	if r.RecMemoryLimit.Cmp(*r.RecMemoryRequest) < 0 {
+		r.RecMemoryLimit = r.RecMemoryRequest
+	}
+
+	r.Status, r.Reason = r.assess()
+	return r
+}
+
+// assess flags the container as under-provisioned when its usage exceeds
+// what it requests or its memory nears the limit, and as over-provisioned
+// when it requests much more than recommended
+func (r Rightsizing) assess() (string, string) {
+	var under []string
+	switch {
+	case r.CPURequest == nil:
+		under = append(under, "no CPU request")
+	case r.CPUP95 > float64(r.CPURequest.MilliValue()):
+		under = append(under, "CPU p95 above request")
+	}
+	switch {
+	case r.MemoryRequest == nil:
+		under = append(under, "no memory request")
+	case r.MemoryP95 > float64(r.MemoryRequest.Value()):
+		under = append(under, "memory p95 above request")
+	}
+	if r.MemoryLimit != nil && r.MemoryMax > memoryLimitPressure*float64(r.MemoryLimit.Value()) {
+		under = append(under, "memory peak near limit")
+	}
+	if len(under) > 0 {
+		return RightsizingUnder, strings.Join(under, ", ")
+	}
+
+	var over []string
+	if float64(r.CPURequest.MilliValue()) > overProvisionedRatio*float64(r.RecCPURequest.MilliValue()) {
+		over = append(over, "CPU request")
+	}
+	if float64(r.MemoryRequest.Value()) > overProvisionedRatio*float64(r.RecMemoryRequest.Value()) {
+		over = append(over, "memory request")
+	}
+	if len(over) > 0 {
+		return RightsizingOver, strings.Join(over, ", ") + " over twice the recommendation"
+	}
+	return RightsizingOK, ""
+}
+
+// Percentile returns the p-th percentile (0-100) of values by nearest rank,
+// or 0 for no values
+func Percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
+
+// cpuQuantity rounds millicores up to the CPU step, at least the minimum
+func cpuQuantity(millis float64) *resource.Quantity {
+	m := int64(math.Ceil(millis/cpuStepMillis)) * cpuStepMillis
+	return resource.NewMilliQuantity(max(m, minCPUMillis), resource.DecimalSI)
+}
+
+// memoryQuantity rounds bytes up to whole MiB, at least the minimum
+func memoryQuantity(bytes float64) *resource.Quantity {
+	b := int64(math.Ceil(bytes/memoryStep)) * memoryStep
+	return resource.NewQuantity(max(b, minMemoryBytes), resource.BinarySI)
+}
+
+// rightsizingRank orders statuses from most to least urgent
+var rightsizingRank = map[string]int{
+	RightsizingUnder:  0,
+	RightsizingOver:   1,
+	RightsizingOK:     2,
+	RightsizingNoData: 3,
+}
+
+// SortRightsizingBy sorts recommendations by the specified column and
+// direction
+func SortRightsizingBy(items []Rightsizing, column string, ascending bool) {
+	byName := func(i, j int) bool {
+		a, b := items[i], items[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Workload != b.Workload {
+			return a.Workload < b.Workload
+		}
+		return a.Container < b.Container
+	}
+	byFloat := func(value func(r Rightsizing) float64) func(i, j int) bool {
+		return func(i, j int) bool {
+			vi, vj := value(items[i]), value(items[j])
+			if vi == vj {
+				return byName(i, j)
+			}
+			return vi < vj
+		}
+	}
+
+	var sortFunc func(i, j int) bool
+	switch column {
+	case "STATUS":
+		sortFunc = byFloat(func(r Rightsizing) float64 { return float64(rightsizingRank[r.Status]) })
+	case "WORKLOAD":
+		sortFunc = func(i, j int) bool {
+			if items[i].Workload == items[j].Workload {
+				return byName(i, j)
+			}
+			return items[i].Workload < items[j].Workload
+		}
+	case "CPU P95":
+		sortFunc = byFloat(func(r Rightsizing) float64 { return r.CPUP95 })
+	case "MEM P95":
+		sortFunc = byFloat(func(r Rightsizing) float64 { return r.MemoryP95 })
+	default: // NAMESPACE
+		sortFunc = byName
+	}
+
+	if ascending {
+		sort.Slice(items, sortFunc)
+	} else {
+		sort.Slice(items, func(i, j int) bool {
+			return !sortFunc(i, j)
+		})
+	}
+}
+
+// RightsizingPatch writes the recommendations for over- and
+// under-provisioned containers as YAML, one document per workload. Each
+// document is a strategic merge patch for kubectl patch, and names its
+// workload so the whole file can also be applied with kubectl apply
+// --server-side. It returns "" when nothing needs to change.
+func RightsizingPatch(items []Rightsizing) (string, error) {
+	type workload struct {
+		kind, namespace, name string
+		containers            []interface{}
+	}
+	var workloads []*workload
+	index := make(map[string]*workload)
+
+	for _, r := range items {
+		if r.Status != RightsizingUnder && r.Status != RightsizingOver {
+			continue
+		}
+		key := WorkloadKey(r.Kind, r.Namespace, r.Workload)
+		w, ok := index[key]
+		if !ok {
+			w = &workload{kind: r.Kind, namespace: r.Namespace, name: r.Workload}
+			index[key] = w
+			workloads = append(workloads, w)
+		}
+
+		requests := map[string]string{
+			"cpu":    r.RecCPURequest.String(),
+			"memory": r.RecMemoryRequest.String(),
+		}
+		limits := map[string]string{"memory": r.RecMemoryLimit.String()}
+		if r.RecCPULimit != nil {
+			limits["cpu"] = r.RecCPULimit.String()
+		}
+		w.containers = append(w.containers, map[string]interface{}{
+			"name":      r.Container,
+			"resources": map[string]interface{}{"requests": requests, "limits": limits},
+		})
+	}
+
+	var b strings.Builder
+	for i, w := range workloads {
+		doc, err := yaml.Marshal(map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       w.kind,
+			"metadata":   map[string]string{"name": w.name, "namespace": w.namespace},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{"containers": w.containers},
+				},
+			},
+		})
+		if err != nil {
+			return "", fmt.Errorf("marshal patch for %s %s/%s: %w", w.kind, w.namespace, w.name, err)
+		}
+		if i > 0 {
+			b.WriteString("---\n")
+		}
+		fmt.Fprintf(&b, "# %s %s/%s\n", w.kind, w.namespace, w.name)
+		b.Write(doc)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,10 @@
+package model
+
+// WorkloadDetailData contains all data needed to display a workload's pods view
+type WorkloadDetailData struct {
+	// Workload is the workload model with aggregated pod resources
+	Workload *WorkloadModel
+
+	// Pods is the list of pods owned by the workload
+	Pods []*PodModel
+}
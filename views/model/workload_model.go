@@ -0,0 +1,239 @@
+package model
+
+import (
+	"sort"
+	"strings"
+
+	appsV1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Workload kinds shown in the workloads panel
+const (
+	WorkloadKindDeployment  = "Deployment"
+	WorkloadKindStatefulSet = "StatefulSet"
+	WorkloadKindDaemonSet   = "DaemonSet"
+)
+
+// WorkloadModel summarizes a Deployment, StatefulSet or DaemonSet along with
+// the resources of the pods it owns.
+type WorkloadModel struct {
+	Kind         string
+	Namespace    string
+	Name         string
+	TimeSince    string
+	CreationTime metav1.Time
+
+	DesiredReplicas int
+	ReadyReplicas   int
+
+	// Pods holds the "namespace/name" keys of the pods owned by the workload,
+	// resolved through ownerReferences (Deployment pods via their ReplicaSet).
+	Pods []string
+
+	RequestedCpuQty *resource.Quantity
+	RequestedMemQty *resource.Quantity
+	UsageCpuQty     *resource.Quantity
+	UsageMemQty     *resource.Quantity
+}
+
+// NewDeploymentModel creates a workload model for a Deployment
+func NewDeploymentModel(d *appsV1.Deployment) *WorkloadModel {
+	desired := 1
+	if d.Spec.Replicas != nil {
+		desired = int(*d.Spec.Replicas)
+	}
+	return newWorkloadModel(WorkloadKindDeployment, d.ObjectMeta, desired, int(d.Status.ReadyReplicas))
+}
+
+// NewStatefulSetModel creates a workload model for a StatefulSet
+func NewStatefulSetModel(s *appsV1.StatefulSet) *WorkloadModel {
+	desired := 1
+	if s.Spec.Replicas != nil {
+		desired = int(*s.Spec.Replicas)
+	}
+	return newWorkloadModel(WorkloadKindStatefulSet, s.ObjectMeta, desired, int(s.Status.ReadyReplicas))
+}
+
+// NewDaemonSetModel creates a workload model for a DaemonSet
+func NewDaemonSetModel(ds *appsV1.DaemonSet) *WorkloadModel {
+	return newWorkloadModel(WorkloadKindDaemonSet, ds.ObjectMeta, int(ds.Status.DesiredNumberScheduled), int(ds.Status.NumberReady))
+}
+
+func newWorkloadModel(kind string, meta metav1.ObjectMeta, desired, ready int) *WorkloadModel {
+	return &WorkloadModel{
+		Kind:            kind,
+		Namespace:       meta.Namespace,
+		Name:            meta.Name,
+		TimeSince:       timeSince(meta.CreationTimestamp),
+		CreationTime:    meta.CreationTimestamp,
+		DesiredReplicas: desired,
+		ReadyReplicas:   ready,
+	}
+}
+
+// WorkloadKey returns the key identifying a workload: "kind/namespace/name"
+func WorkloadKey(kind, namespace, name string) string {
+	return kind + "/" + namespace + "/" + name
+}
+
+// Key returns the key identifying this workload
+func (w *WorkloadModel) Key() string {
+	return WorkloadKey(w.Kind, w.Namespace, w.Name)
+}
+
+// AggregatePods sums the requests and usage of the workload's pods found in
+// pods. Owned pods missing from pods (not yet cached) are skipped.
+func (w *WorkloadModel) AggregatePods(pods []PodModel) {
+	owned := make(map[string]bool, len(w.Pods))
+	for _, key := range w.Pods {
+		owned[key] = true
+	}
+
+	w.RequestedCpuQty = resource.NewQuantity(0, resource.DecimalSI)
+	w.RequestedMemQty = resource.NewQuantity(0, resource.BinarySI)
+	w.UsageCpuQty = resource.NewQuantity(0, resource.DecimalSI)
+	w.UsageMemQty = resource.NewQuantity(0, resource.BinarySI)
+
+	for _, pod := range pods {
+		if !owned[pod.Namespace+"/"+pod.Name] {
+			continue
+		}
+		addQty(w.RequestedCpuQty, pod.PodRequestedCpuQty)
+		addQty(w.RequestedMemQty, pod.PodRequestedMemQty)
+		addQty(w.UsageCpuQty, pod.PodUsageCpuQty)
+		addQty(w.UsageMemQty, pod.PodUsageMemQty)
+	}
+}
+
+// OwnedPods returns the models of the workload's pods found in pods
+func (w *WorkloadModel) OwnedPods(pods []PodModel) []PodModel {
+	owned := make(map[string]bool, len(w.Pods))
+	for _, key := range w.Pods {
+		owned[key] = true
+	}
+
+	var result []PodModel
+	for _, pod := range pods {
+		if owned[pod.Namespace+"/"+pod.Name] {
+			result = append(result, pod)
+		}
+	}
+	return result
+}
+
+func addQty(total, q *resource.Quantity) {
+	if q != nil {
+		total.Add(*q)
+	}
+}
+
+// SortWorkloadModelsBy sorts workloads by the specified column and direction
+func SortWorkloadModelsBy(workloads []WorkloadModel, column string, ascending bool) {
+	byName := func(i, j int) bool {
+		if workloads[i].Namespace == workloads[j].Namespace {
+			if workloads[i].Name == workloads[j].Name {
+				return workloads[i].Kind < workloads[j].Kind
+			}
+			return workloads[i].Name < workloads[j].Name
+		}
+		return workloads[i].Namespace < workloads[j].Namespace
+	}
+
+	var sortFunc func(i, j int) bool
+
+	switch column {
+	case "KIND":
+		sortFunc = func(i, j int) bool {
+			if workloads[i].Kind == workloads[j].Kind {
+				return byName(i, j)
+			}
+			return workloads[i].Kind < workloads[j].Kind
+		}
+
+	case "WORKLOAD":
+		sortFunc = func(i, j int) bool {
+			if workloads[i].Name == workloads[j].Name {
+				return byName(i, j)
+			}
+			return strings.ToLower(workloads[i].Name) < strings.ToLower(workloads[j].Name)
+		}
+
+	case "READY":
+		// Least ready first makes degraded workloads easy to spot
+		sortFunc = func(i, j int) bool {
+			ri, rj := readyRatio(workloads[i]), readyRatio(workloads[j])
+			if ri == rj {
+				return byName(i, j)
+			}
+			return ri < rj
+		}
+
+	case "PODS":
+		sortFunc = func(i, j int) bool {
+			if len(workloads[i].Pods) == len(workloads[j].Pods) {
+				return byName(i, j)
+			}
+			return len(workloads[i].Pods) < len(workloads[j].Pods)
+		}
+
+	case "AGE":
+		sortFunc = func(i, j int) bool {
+			if workloads[i].CreationTime.Equal(&workloads[j].CreationTime) {
+				return byName(i, j)
+			}
+			return workloads[i].CreationTime.Before(&workloads[j].CreationTime)
+		}
+
+	case "CPU":
+		sortFunc = func(i, j int) bool {
+			ci, cj := qtyMilli(workloads[i].UsageCpuQty), qtyMilli(workloads[j].UsageCpuQty)
+			if ci == cj {
+				return byName(i, j)
+			}
+			return ci < cj
+		}
+
+	case "MEMORY":
+		sortFunc = func(i, j int) bool {
+			mi, mj := qtyValue(workloads[i].UsageMemQty), qtyValue(workloads[j].UsageMemQty)
+			if mi == mj {
+				return byName(i, j)
+			}
+			return mi < mj
+		}
+
+	default:
+		sortFunc = byName
+	}
+
+	if ascending {
+		sort.Slice(workloads, sortFunc)
+	} else {
+		sort.Slice(workloads, func(i, j int) bool {
+			return !sortFunc(i, j)
+		})
+	}
+}
+
+func readyRatio(w WorkloadModel) float64 {
+	if w.DesiredReplicas == 0 {
+		return 1
+	}
+	return float64(w.ReadyReplicas) / float64(w.DesiredReplicas)
+}
+
+func qtyMilli(q *resource.Quantity) int64 {
+	if q == nil {
+		return 0
+	}
+	return q.MilliValue()
+}
+
+func qtyValue(q *resource.Quantity) int64 {
+	if q == nil {
+		return 0
+	}
+	return q.Value()
+}
@@ -60,6 +60,7 @@ type DetailPanel struct {
 
 	// Callbacks
 	onPodSelected         NodeSelectedCallback
+	onActions             func(node *corev1.Node)
 	onBack                func()
 	onFooterContextChange func(focusedPanel string)
 
@@ -80,6 +81,11 @@ func (p *DetailPanel) SetOnPodSelected(callback NodeSelectedCallback) {
 	p.onPodSelected = callback
 }
 
+// SetOnActions sets the callback for the actions on the node
+func (p *DetailPanel) SetOnActions(callback func(node *corev1.Node)) {
+	p.onActions = callback
+}
+
 // SetOnBack sets the callback for when user navigates back
 func (p *DetailPanel) SetOnBack(callback func()) {
 	p.onBack = callback
@@ -188,6 +194,10 @@ func (p *DetailPanel) Layout(_ interface{}) {
 						return nil
 					}
 				}
+			case tcell.KeyRune:
+				if p.handleActionsKey(event.Rune()) {
+					return nil
+				}
 			}
 			return event
 		})
@@ -224,6 +234,10 @@ func (p *DetailPanel) Layout(_ interface{}) {
 					p.onBack()
 					return nil
 				}
+			case tcell.KeyRune:
+				if p.handleActionsKey(event.Rune()) {
+					return nil
+				}
 			}
 			return event
 		})
@@ -232,6 +246,15 @@ func (p *DetailPanel) Layout(_ interface{}) {
 	}
 }
 
+// handleActionsKey shows the actions on the node for x
+func (p *DetailPanel) handleActionsKey(r rune) bool {
+	if (r != 'x' && r != 'X') || p.data == nil || p.data.Node == nil || p.onActions == nil {
+		return false
+	}
+	p.onActions(p.data.Node)
+	return true
+}
+
 // DrawHeader draws the header row
 func (p *DetailPanel) DrawHeader(data interface{}) {
 	// Header is part of the layout
@@ -339,7 +362,7 @@ func (p *DetailPanel) drawSparklines() {
 	node := p.data.NodeModel
 
 	// Update prometheus mode based on metrics source type
-	prometheusMode := (p.data.MetricsSourceType == metrics.SourceTypePrometheus)
+	prometheusMode := metrics.IsPrometheusSource(p.data.MetricsSourceType)
 	p.sparklineRow.SetPrometheusMode(prometheusMode)
 
 	// Calculate CPU and MEM ratios
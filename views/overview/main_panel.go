@@ -4,20 +4,38 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"log/slog"
+	"os"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/actions"
+	"github.com/vladimirvivien/ktop/alerts"
 	"github.com/vladimirvivien/ktop/application"
+	"github.com/vladimirvivien/ktop/config"
+	"github.com/vladimirvivien/ktop/internal/userdir"
 	"github.com/vladimirvivien/ktop/k8s"
 	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/portforward"
 	"github.com/vladimirvivien/ktop/ui"
+	alertsview "github.com/vladimirvivien/ktop/views/alerts"
+	capacityview "github.com/vladimirvivien/ktop/views/capacity"
 	containerdetail "github.com/vladimirvivien/ktop/views/container"
+	controlplaneview "github.com/vladimirvivien/ktop/views/controlplane"
+	eventsview "github.com/vladimirvivien/ktop/views/events"
+	logsview "github.com/vladimirvivien/ktop/views/logs"
 	"github.com/vladimirvivien/ktop/views/model"
 	nodedetail "github.com/vladimirvivien/ktop/views/node"
 	poddetail "github.com/vladimirvivien/ktop/views/pod"
+	portforwardview "github.com/vladimirvivien/ktop/views/portforward"
+	pressureview "github.com/vladimirvivien/ktop/views/pressure"
+	queryview "github.com/vladimirvivien/ktop/views/query"
+	rightsizingview "github.com/vladimirvivien/ktop/views/rightsizing"
+	workloaddetail "github.com/vladimirvivien/ktop/views/workload"
 	v1 "k8s.io/api/core/v1"
-	// metrics package imported for SourceTypePrometheus constant
+	// metrics package imported for IsPrometheusSource
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	metricsV1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
@@ -34,19 +52,40 @@ type MainPanel struct {
 	selPanelIndex       int
 	nodePanel           ui.Panel
 	podPanel            ui.Panel
+	workloadPanel       ui.Panel
+	namespacePanel      ui.Panel
 	clusterSummaryPanel ui.Panel
 	showAllColumns      bool
 	nodeColumns         []string
 	podColumns          []string
-	namespaceFilter     string            // Current namespace filter
-	cachedPodModels     []model.PodModel  // Cached pod models for immediate re-filtering
-	cachedNodeModels    []model.NodeModel // Cached node models for detail view
+	customColumns       []metrics.CustomColumn
+	structuredLogs      bool
+	logFields           []string
+	namespaceFilter     string                 // Current namespace filter
+	cachedPodModels     []model.PodModel       // Cached pod models for immediate re-filtering
+	cachedNodeModels    []model.NodeModel      // Cached node models for detail view
+	cachedWorkloads     []model.WorkloadModel  // Cached workload models, aggregated from cached pods on display
+	cachedNamespaces    []model.NamespaceModel // Cached namespace models, redrawn when the namespace filter changes
 
 	// Detail panels
 	nodeDetailPanel      *nodedetail.DetailPanel
 	podDetailPanel       *poddetail.DetailPanel
 	containerDetailPanel *containerdetail.DetailPanel
 	containerSpecPanel   *containerdetail.SpecPanel
+	workloadDetailPanel  *workloaddetail.DetailPanel
+	alertsPanel          *alertsview.Panel
+	controlPlanePanel    *controlplaneview.Panel
+	queryPanel           *queryview.Panel
+	pressurePanel        *pressureview.Panel
+	rightsizingPanel     *rightsizingview.Panel
+	capacityPanel        *capacityview.Panel
+	eventsPanel          *eventsview.Panel
+	logsPanel            *logsview.Panel
+	portForwardsPanel    *portforwardview.Panel
+
+	// Refreshers of the pages refreshed while in front, registered in Run
+	// before the controller starts
+	pageRefreshers map[application.PageType]pageRefresher
 
 	// Centralized view state manager - single source of truth for current page/resource
 	// Thread-safe, replaces individual tracking variables
@@ -77,11 +116,67 @@ func NewWithColumnOptions(app *application.Application, title string, showAllCol
 	return ctrl
 }
 
-func (p *MainPanel) Layout(data interface{}) {
+// SetCustomColumns sets the user-defined columns added to the node and pod
+// tables. Must be called before Layout.
+func (p *MainPanel) SetCustomColumns(columns []metrics.CustomColumn) {
+	p.customColumns = columns
+}
+
+// SetColumns implements application.ColumnsPanel: it sets the node and pod
+// columns shown, all when none are listed, and the custom columns added to
+// them. Once laid out, the table headers are drawn again with them.
+func (p *MainPanel) SetColumns(columns config.ColumnsConfig) {
+	p.nodeColumns = columns.Node
+	p.podColumns = columns.Pod
+	if len(columns.Node) > 0 || len(columns.Pod) > 0 {
+		p.showAllColumns = false
+	}
+	p.customColumns = columns.Custom
+	if p.nodePanel == nil {
+		return
+	}
+	nodeColumns, podColumns := p.columnsToDisplay()
+	p.nodePanel.DrawHeader(nodeColumns)
+	p.nodePanel.Clear()
+	p.podPanel.DrawHeader(podColumns)
+	p.podPanel.Clear()
+}
+
+// SetStructuredLogs sets whether the log views start out rendering JSON and
+// logfmt lines as columns, and the fields promoted next to their message
+func (p *MainPanel) SetStructuredLogs(on bool, fields []string) {
+	p.structuredLogs = on
+	p.logFields = fields
+}
+
+// columnsToDisplay returns the node and pod columns shown: the built-in
+// ones followed by the custom ones, filtered by the configured lists. Custom
+// columns named like a built-in one are dropped.
+func (p *MainPanel) columnsToDisplay() ([]string, []string) {
 	// Define the default columns
 	allNodeColumns := []string{"NAME", "STATUS", "RST", "PODS", "TAINTS", "PRESSURE", "IP", "VOLS", "DISK", "CPU", "MEM"}
 	allPodColumns := []string{"NAMESPACE", "POD", "READY", "STATUS", "RST", "AGE", "VOLS", "IP", "NODE", "CPU", "MEMORY"}
 
+	// Custom columns go after the built-in ones and can be filtered the same way
+	var customColumns []metrics.CustomColumn
+	for _, col := range p.customColumns {
+		builtin := allPodColumns
+		if col.Table == metrics.ColumnTableNode {
+			builtin = allNodeColumns
+		}
+		if slices.Contains(builtin, col.Name) {
+			slog.Warn("custom column ignored, name is taken by a built-in column", "column", col.Name)
+			continue
+		}
+		if col.Table == metrics.ColumnTableNode {
+			allNodeColumns = append(allNodeColumns, col.Name)
+		} else {
+			allPodColumns = append(allPodColumns, col.Name)
+		}
+		customColumns = append(customColumns, col)
+	}
+	p.customColumns = customColumns
+
 	// Use filtered columns if specified
 	nodeColumnsToDisplay := allNodeColumns
 	podColumnsToDisplay := allPodColumns
@@ -97,6 +192,13 @@ func (p *MainPanel) Layout(data interface{}) {
 			podColumnsToDisplay = filterColumns(allPodColumns, p.podColumns)
 		}
 	}
+	return nodeColumnsToDisplay, podColumnsToDisplay
+}
+
+func (p *MainPanel) Layout(data interface{}) {
+	workloadColumns := []string{"KIND", "NAMESPACE", "WORKLOAD", "READY", "PODS", "AGE", "CPU", "MEMORY"}
+	namespaceColumns := []string{"NAMESPACE", "PODS", "RUNNING", "FAILED", "RST", "CPU", "MEMORY", "CPU REQ", "CPU LIM", "MEM REQ", "MEM LIM"}
+	nodeColumnsToDisplay, podColumnsToDisplay := p.columnsToDisplay()
 
 	p.nodePanel = NewNodePanel(p.app, fmt.Sprintf(" %s Nodes ", ui.Icons.Factory))
 	p.nodePanel.DrawHeader(nodeColumnsToDisplay)
@@ -122,9 +224,34 @@ func (p *MainPanel) Layout(data interface{}) {
 		})
 	}
 
+	p.workloadPanel = NewWorkloadPanel(p.app, fmt.Sprintf(" %s Workloads ", ui.Icons.Rocket))
+	p.workloadPanel.DrawHeader(workloadColumns)
+
+	// Set up workload selection callback to drill into the workload's pods
+	if wp, ok := p.workloadPanel.(*workloadPanel); ok {
+		wp.SetOnWorkloadSelected(func(kind, namespace, name string) {
+			p.app.NavigateToWorkloadPods(kind, namespace, name)
+		})
+	}
+
+	p.namespacePanel = NewNamespacePanel(p.app, fmt.Sprintf(" %s Namespaces ", ui.Icons.Anchor))
+	p.namespacePanel.DrawHeader(namespaceColumns)
+
+	// Selecting a namespace filters the overview to it; selecting it again clears the filter
+	if nsp, ok := p.namespacePanel.(*namespacePanel); ok {
+		nsp.SetOnNamespaceSelected(func(namespace string) {
+			if namespace == p.app.GetNamespaceFilter() {
+				namespace = ""
+			}
+			p.app.SetNamespaceFilter(namespace)
+		})
+	}
+
 	p.children = []tview.Primitive{
 		p.clusterSummaryPanel.GetRootView(),
 		p.nodePanel.GetRootView(),
+		p.namespacePanel.GetRootView(),
+		p.workloadPanel.GetRootView(),
 		p.podPanel.GetRootView(),
 	}
 
@@ -132,13 +259,15 @@ func (p *MainPanel) Layout(data interface{}) {
 	p.childPanels = []ui.Panel{
 		p.clusterSummaryPanel,
 		p.nodePanel,
+		p.namespacePanel,
+		p.workloadPanel,
 		p.podPanel,
 	}
 
 	// Calculate dynamic panel heights based on terminal size
 	// Use default height (50) during initial layout since root doesn't exist yet
 	// The resize check in refreshWorkloadSummary will adjust once app is running
-	isPrometheus := p.metricsSource != nil && p.metricsSource.GetSourceInfo().Type == metrics.SourceTypePrometheus
+	isPrometheus := p.metricsSource != nil && metrics.IsPrometheusSource(p.metricsSource.GetSourceInfo().Type)
 	terminalHeight := 50 // Default to medium during initial layout
 	summaryHeight, nodeHeight := p.calculatePanelHeights(terminalHeight, isPrometheus)
 	p.lastHeightCategory = ui.GetHeightCategory(terminalHeight)
@@ -147,7 +276,9 @@ func (p *MainPanel) Layout(data interface{}) {
 	view := tview.NewFlex().SetDirection(tview.FlexRow).
 		AddItem(p.clusterSummaryPanel.GetRootView(), summaryHeight, 0, false).
 		AddItem(p.nodePanel.GetRootView(), nodeHeight, 0, true).
-		AddItem(p.podPanel.GetRootView(), 0, 1, true) // Takes remaining space
+		AddItem(p.namespacePanel.GetRootView(), 0, 1, true). // Namespaces, workloads and
+		AddItem(p.workloadPanel.GetRootView(), 0, 1, true).  // pods share the remaining
+		AddItem(p.podPanel.GetRootView(), 0, 2, true)        // space
 
 	p.root = view
 }
@@ -213,14 +344,16 @@ func (p *MainPanel) checkAndRebuildLayout() {
 		return
 	}
 
-	isPrometheus := p.metricsSource != nil && p.metricsSource.GetSourceInfo().Type == metrics.SourceTypePrometheus
+	isPrometheus := p.metricsSource != nil && metrics.IsPrometheusSource(p.metricsSource.GetSourceInfo().Type)
 	summaryHeight, nodeHeight := p.calculatePanelHeights(terminalHeight, isPrometheus)
 
 	// Clear and rebuild the flex layout
 	p.root.Clear()
 	p.root.AddItem(p.clusterSummaryPanel.GetRootView(), summaryHeight, 0, false)
 	p.root.AddItem(p.nodePanel.GetRootView(), nodeHeight, 0, true)
-	p.root.AddItem(p.podPanel.GetRootView(), 0, 1, true)
+	p.root.AddItem(p.namespacePanel.GetRootView(), 0, 1, true)
+	p.root.AddItem(p.workloadPanel.GetRootView(), 0, 1, true)
+	p.root.AddItem(p.podPanel.GetRootView(), 0, 2, true)
 
 	p.lastHeightCategory = currentCategory
 	p.lastTerminalHeight = terminalHeight
@@ -257,6 +390,18 @@ func (p *MainPanel) HasEscapableState() bool {
 			return true
 		}
 	}
+	// Check namespace panel
+	if escapable, ok := p.namespacePanel.(ui.EscapablePanel); ok {
+		if escapable.HasEscapableState() {
+			return true
+		}
+	}
+	// Check workload panel
+	if escapable, ok := p.workloadPanel.(ui.EscapablePanel); ok {
+		if escapable.HasEscapableState() {
+			return true
+		}
+	}
 	// Check pod panel
 	if escapable, ok := p.podPanel.(ui.EscapablePanel); ok {
 		if escapable.HasEscapableState() {
@@ -274,6 +419,18 @@ func (p *MainPanel) HandleEscape() bool {
 			return true
 		}
 	}
+	// Try namespace panel
+	if escapable, ok := p.namespacePanel.(ui.EscapablePanel); ok {
+		if escapable.HandleEscape() {
+			return true
+		}
+	}
+	// Try workload panel
+	if escapable, ok := p.workloadPanel.(ui.EscapablePanel); ok {
+		if escapable.HandleEscape() {
+			return true
+		}
+	}
 	// Try pod panel
 	if escapable, ok := p.podPanel.(ui.EscapablePanel); ok {
 		if escapable.HandleEscape() {
@@ -296,35 +453,127 @@ func (p *MainPanel) GetActiveDetailPanel() ui.EscapablePanel {
 	if _, ok := p.viewState.GetNodeDetail(); ok && p.nodeDetailPanel != nil {
 		return p.nodeDetailPanel
 	}
+	if _, _, _, ok := p.viewState.GetWorkloadPods(); ok && p.workloadDetailPanel != nil {
+		return p.workloadDetailPanel
+	}
+	if p.viewState.IsAlerts() && p.alertsPanel != nil {
+		return p.alertsPanel
+	}
+	if p.viewState.IsControlPlane() && p.controlPlanePanel != nil {
+		return p.controlPlanePanel
+	}
+	if p.viewState.IsQuery() && p.queryPanel != nil {
+		return p.queryPanel
+	}
+	if p.viewState.IsPressure() && p.pressurePanel != nil {
+		return p.pressurePanel
+	}
+	if p.viewState.IsRightsizing() && p.rightsizingPanel != nil {
+		return p.rightsizingPanel
+	}
+	if p.viewState.IsCapacity() && p.capacityPanel != nil {
+		return p.capacityPanel
+	}
+	if p.viewState.IsEvents() && p.eventsPanel != nil {
+		return p.eventsPanel
+	}
+	if p.viewState.IsLogs() && p.logsPanel != nil {
+		return p.logsPanel
+	}
+	if p.viewState.IsPortForwards() && p.portForwardsPanel != nil {
+		return p.portForwardsPanel
+	}
 	return nil
 }
 
 func (p *MainPanel) Run(ctx context.Context) error {
 	p.Layout(nil)
-	ctrl := p.app.GetK8sClient().Controller()
-	ctrl.SetMetricsSource(p.metricsSource) // Provide metrics source to controller for cluster summary
-	ctrl.SetClusterSummaryRefreshFunc(p.refreshWorkloadSummary)
-	ctrl.SetNodeRefreshFunc(p.refreshNodeView)
-	ctrl.SetPodRefreshFunc(p.refreshPods)
 
 	// Set up namespace filter callback to update filtering and immediately refresh pods
 	p.app.SetNamespaceFilterCallback(func(namespace string) {
 		p.namespaceFilter = namespace
-		// Immediately re-filter and display pods with the new filter
+		// Immediately re-filter and display pods and workloads with the new filter
 		p.displayFilteredPods()
+		p.displayNamespacesInternal() // Re-mark the selected namespace
 	})
 
 	// Set up navigation callbacks on the app (detail panels created lazily on first use)
 	p.app.SetNodeDetailCallback(p.showNodeDetail)
 	p.app.SetPodDetailCallback(p.showPodDetail)
 	p.app.SetContainerLogsCallback(p.showContainerLogs)
-
-	if err := ctrl.Start(ctx, time.Second*10); err != nil {
+	p.app.SetWorkloadPodsCallback(p.showWorkloadPods)
+	p.app.SetAlertsCallback(p.showAlerts)
+	p.app.SetControlPlaneCallback(p.showControlPlane)
+	p.app.SetQueryCallback(p.showQuery)
+	p.app.SetPressureCallback(p.showPressure)
+	p.app.SetRightsizingCallback(p.showRightsizing)
+	p.app.SetCapacityCallback(p.showCapacity)
+	p.app.SetEventsCallback(p.showEvents)
+	p.app.SetLogsCallback(p.showLogs)
+	p.app.SetPortForwardsCallback(p.showPortForwards)
+
+	p.registerPageRefresher(application.PageControlPlane, p.refreshControlPlane)
+	p.registerPageRefresher(application.PageQuery, p.refreshQuery)
+	p.registerPageRefresher(application.PagePressure, p.refreshPressure)
+	p.registerPageRefresher(application.PageCapacity, p.refreshCapacity)
+	p.registerPageRefresher(application.PageEvents, p.refreshEvents)
+	p.registerPageRefresher(application.PagePortForwards, p.refreshPortForwards)
+
+	if err := p.startController(ctx); err != nil {
 		panic(fmt.Sprintf("main panel: controller start: %s", err))
 	}
 	return nil
 }
 
+// Rebind implements ui.SessionPanel. State cached from the previous
+// connection is dropped and the refresh functions are attached to the new
+// client's controller; the layout and detail panels are reused.
+func (p *MainPanel) Rebind(ctx context.Context) error {
+	if p.containerDetailPanel != nil {
+		p.containerDetailPanel.Cleanup() // Stop log streams from the old cluster
+	}
+	if p.logsPanel != nil {
+		p.logsPanel.Cleanup()
+	}
+	p.viewState.SetOverview()
+	p.metricsSource = p.app.GetMetricsSource()
+	p.namespaceFilter = ""
+	p.cachedNodeModels = nil
+	p.cachedPodModels = nil
+	p.cachedWorkloads = nil
+	p.cachedNamespaces = nil
+	p.nodePanel.Clear()
+	p.nodePanel.DrawBody([]model.NodeModel{})
+	p.podPanel.Clear()
+	p.podPanel.DrawBody([]model.PodModel{})
+	p.workloadPanel.Clear()
+	p.workloadPanel.DrawBody([]model.WorkloadModel{})
+	p.namespacePanel.Clear()
+	p.namespacePanel.DrawBody([]model.NamespaceModel{})
+
+	// Start waits briefly for the initial cache sync; keep that off the UI goroutine
+	go func() {
+		if err := p.startController(ctx); err != nil {
+			slog.Error("main panel: controller start", "error", err)
+		}
+	}()
+	return nil
+}
+
+// startController attaches the panel's refresh functions to the current
+// client's controller and starts it.
+func (p *MainPanel) startController(ctx context.Context) error {
+	ctrl := p.app.GetCluster().Source()
+	ctrl.SetMetricsSource(p.metricsSource) // Provide metrics source to controller for cluster summary
+	ctrl.SetClusterSummaryRefreshFunc(p.refreshWorkloadSummary)
+	ctrl.SetNodeRefreshFunc(p.refreshNodeView)
+	ctrl.SetPodRefreshFunc(p.refreshPods)
+	ctrl.SetWorkloadRefreshFunc(p.refreshWorkloads)
+	ctrl.SetNamespaceRefreshFunc(p.refreshNamespaces)
+	go p.runPageRefreshes(ctx)
+	return ctrl.Start(ctx, time.Second*10)
+}
+
 // ensureNodeDetailPanel creates the node detail panel if not already created
 func (p *MainPanel) ensureNodeDetailPanel() {
 	if p.nodeDetailPanel != nil {
@@ -338,6 +587,9 @@ func (p *MainPanel) ensureNodeDetailPanel() {
 	p.nodeDetailPanel.SetOnPodSelected(func(namespace, podName string) {
 		p.app.NavigateToPodDetail(namespace, podName)
 	})
+	p.nodeDetailPanel.SetOnActions(func(node *v1.Node) {
+		p.app.ShowActions("Node "+node.Name, actions.NodeActions(node))
+	})
 	// Set up focus callback for tab cycling within the detail panel
 	p.nodeDetailPanel.SetAppFocus(func(prim tview.Primitive) {
 		p.app.Focus(prim)
@@ -346,7 +598,7 @@ func (p *MainPanel) ensureNodeDetailPanel() {
 	p.nodeDetailPanel.SetOnFooterContextChange(func(focusedPanel string) {
 		p.app.SetFooterContext(ui.NodeDetailContext{FocusedPanel: focusedPanel})
 	})
-	p.app.AddDetailPage("node_detail", p.nodeDetailPanel.GetRootView())
+	p.app.AddTabbedDetailPage("node_detail", p.nodeDetailPanel.GetRootView())
 }
 
 // ensurePodDetailPanel creates the pod detail panel if not already created
@@ -365,6 +617,10 @@ func (p *MainPanel) ensurePodDetailPanel() {
 	p.podDetailPanel.SetOnContainerSelected(func(namespace, podName, containerName string) {
 		p.app.NavigateToContainerLogs(namespace, podName, containerName)
 	})
+	p.podDetailPanel.SetOnPortForward(p.pickPortForward)
+	p.podDetailPanel.SetOnActions(func(pod *v1.Pod) {
+		p.app.ShowActions("Pod "+pod.Name, actions.PodActions(pod.Namespace, pod.Name))
+	})
 	// Set up focus callback for tab cycling within the detail panel
 	p.podDetailPanel.SetAppFocus(func(prim tview.Primitive) {
 		p.app.Focus(prim)
@@ -373,7 +629,7 @@ func (p *MainPanel) ensurePodDetailPanel() {
 	p.podDetailPanel.SetOnFooterContextChange(func(focusedPanel string) {
 		p.app.SetFooterContext(ui.PodDetailContext{FocusedPanel: focusedPanel})
 	})
-	p.app.AddDetailPage("pod_detail", p.podDetailPanel.GetRootView())
+	p.app.AddTabbedDetailPage("pod_detail", p.podDetailPanel.GetRootView())
 }
 
 // ensureContainerDetailPanel creates the container detail panel if not already created
@@ -382,6 +638,7 @@ func (p *MainPanel) ensureContainerDetailPanel() {
 		return
 	}
 	p.containerDetailPanel = containerdetail.NewDetailPanel()
+	p.containerDetailPanel.SetStructuredLogs(p.structuredLogs, p.logFields)
 	p.containerDetailPanel.SetOnBack(func() {
 		p.containerDetailPanel.Cleanup() // Stop any active streams
 		p.app.NavigateBack()
@@ -393,10 +650,10 @@ func (p *MainPanel) ensureContainerDetailPanel() {
 		p.app.Focus(prim)
 	})
 	p.containerDetailPanel.SetLogStreamFunc(func(ctx context.Context, namespace, podName string, opts k8s.LogOptions) (io.ReadCloser, error) {
-		return p.app.GetK8sClient().Controller().GetPodLogs(ctx, namespace, podName, opts)
+		return p.app.GetCluster().Source().GetPodLogs(ctx, namespace, podName, opts)
 	})
 	p.containerDetailPanel.SetGetPodFunc(func(ctx context.Context, namespace, podName string) (*v1.Pod, error) {
-		return p.app.GetK8sClient().Controller().GetPod(ctx, namespace, podName)
+		return p.app.GetCluster().Source().GetPod(ctx, namespace, podName)
 	})
 	p.containerDetailPanel.SetGetPodMetricsFunc(func(ctx context.Context, namespace, podName string) (*metrics.PodMetrics, error) {
 		if p.metricsSource == nil {
@@ -413,6 +670,8 @@ func (p *MainPanel) ensureContainerDetailPanel() {
 	p.containerDetailPanel.SetOnFooterContextChange(func(focusedPanel string) {
 		p.app.SetFooterContext(ui.ContainerDetailContext{FocusedPanel: focusedPanel})
 	})
+	p.containerDetailPanel.SetOnExport(p.exportLogs)
+	p.containerDetailPanel.SetOnExec(p.app.ExecShell)
 	p.app.AddDetailPage("container_detail", p.containerDetailPanel.GetRootView())
 }
 
@@ -428,6 +687,221 @@ func (p *MainPanel) ensureContainerSpecPanel() {
 	p.app.AddDetailPage("container_spec", p.containerSpecPanel.GetRootView())
 }
 
+// ensureWorkloadDetailPanel creates the workload pods panel if not already created
+func (p *MainPanel) ensureWorkloadDetailPanel() {
+	if p.workloadDetailPanel != nil {
+		return
+	}
+	p.workloadDetailPanel = workloaddetail.NewDetailPanel()
+	p.workloadDetailPanel.SetOnBack(func() {
+		p.viewState.SetOverview() // Clear tracking on back navigation
+		p.app.NavigateBack()
+	})
+	p.workloadDetailPanel.SetOnPodSelected(func(namespace, podName string) {
+		p.app.NavigateToPodDetail(namespace, podName)
+	})
+	p.workloadDetailPanel.SetOnShowLogs(func(kind, namespace, name string) {
+		p.app.NavigateToLogs(kind, namespace, name)
+	})
+	p.workloadDetailPanel.SetOnActions(func(w *model.WorkloadModel) {
+		p.app.ShowActions(w.Kind+" "+w.Name,
+			actions.WorkloadActions(w.Kind, w.Namespace, w.Name, int32(w.DesiredReplicas)))
+	})
+	p.workloadDetailPanel.SetAppFocus(func(prim tview.Primitive) {
+		p.app.Focus(prim)
+	})
+	p.app.AddTabbedDetailPage("workload_pods", p.workloadDetailPanel.GetRootView())
+}
+
+// ensureAlertsPanel creates the alerts panel if not already created
+func (p *MainPanel) ensureAlertsPanel() {
+	if p.alertsPanel != nil {
+		return
+	}
+	p.alertsPanel = alertsview.NewPanel()
+	p.alertsPanel.SetOnBack(func() {
+		p.viewState.SetOverview() // Clear tracking on back navigation
+		p.app.NavigateBack()
+	})
+	p.alertsPanel.SetOnSelected(func(alert alerts.Alert) {
+		switch alert.Target {
+		case alerts.TargetNode:
+			p.app.NavigateToNodeDetail(alert.Subject)
+		case alerts.TargetPod:
+			if namespace, podName, ok := strings.Cut(alert.Subject, "/"); ok {
+				p.app.NavigateToPodDetail(namespace, podName)
+			}
+		}
+	})
+	p.alertsPanel.SetAppFocus(func(prim tview.Primitive) {
+		p.app.Focus(prim)
+	})
+	p.app.AddTabbedDetailPage("alerts", p.alertsPanel.GetRootView())
+}
+
+// ensureControlPlanePanel creates the control-plane panel if not already created
+func (p *MainPanel) ensureControlPlanePanel() {
+	if p.controlPlanePanel != nil {
+		return
+	}
+	p.controlPlanePanel = controlplaneview.NewPanel()
+	p.controlPlanePanel.SetOnBack(func() {
+		p.viewState.SetOverview() // Clear tracking on back navigation
+		p.app.NavigateBack()
+	})
+	p.controlPlanePanel.SetAppFocus(func(prim tview.Primitive) {
+		p.app.Focus(prim)
+	})
+	p.app.AddDetailPage("control_plane", p.controlPlanePanel.GetRootView())
+}
+
+// ensureQueryPanel creates the query console if not already created
+func (p *MainPanel) ensureQueryPanel() {
+	if p.queryPanel != nil {
+		return
+	}
+	p.queryPanel = queryview.NewPanel()
+	p.queryPanel.SetOnBack(func() {
+		p.viewState.SetOverview() // Clear tracking on back navigation
+		p.app.NavigateBack()
+	})
+	p.queryPanel.SetAppFocus(func(prim tview.Primitive) {
+		p.app.Focus(prim)
+	})
+	p.queryPanel.SetOnSubmit(func(expr string) {
+		go p.runQuery(expr)
+	})
+	p.app.AddTabbedDetailPage("query", p.queryPanel.GetRootView())
+}
+
+// ensurePressurePanel creates the pressure panel if not already created
+func (p *MainPanel) ensurePressurePanel() {
+	if p.pressurePanel != nil {
+		return
+	}
+	p.pressurePanel = pressureview.NewPanel()
+	p.pressurePanel.SetOnBack(func() {
+		p.viewState.SetOverview() // Clear tracking on back navigation
+		p.app.NavigateBack()
+	})
+	p.pressurePanel.SetOnSelected(func(namespace, podName, containerName string) {
+		p.app.NavigateToContainerLogs(namespace, podName, containerName)
+	})
+	p.pressurePanel.SetAppFocus(func(prim tview.Primitive) {
+		p.app.Focus(prim)
+	})
+	p.app.AddTabbedDetailPage("pressure", p.pressurePanel.GetRootView())
+}
+
+// ensureRightsizingPanel creates the rightsizing panel if not already created
+func (p *MainPanel) ensureRightsizingPanel() {
+	if p.rightsizingPanel != nil {
+		return
+	}
+	p.rightsizingPanel = rightsizingview.NewPanel()
+	p.rightsizingPanel.SetOnBack(func() {
+		p.viewState.SetOverview() // Clear tracking on back navigation
+		p.app.NavigateBack()
+	})
+	p.rightsizingPanel.SetOnSelected(func(kind, namespace, name string) {
+		p.app.NavigateToWorkloadPods(kind, namespace, name)
+	})
+	p.rightsizingPanel.SetOnExport(p.exportRightsizing)
+	p.rightsizingPanel.SetOnRefresh(p.sampleRightsizing)
+	p.rightsizingPanel.SetAppFocus(func(prim tview.Primitive) {
+		p.app.Focus(prim)
+	})
+	p.app.AddTabbedDetailPage("rightsizing", p.rightsizingPanel.GetRootView())
+}
+
+// ensureCapacityPanel creates the capacity panel if not already created
+func (p *MainPanel) ensureCapacityPanel() {
+	if p.capacityPanel != nil {
+		return
+	}
+	p.capacityPanel = capacityview.NewPanel()
+	p.capacityPanel.SetOnBack(func() {
+		p.viewState.SetOverview() // Clear tracking on back navigation
+		p.app.NavigateBack()
+	})
+	p.capacityPanel.SetAppFocus(func(prim tview.Primitive) {
+		p.app.Focus(prim)
+	})
+	p.app.AddTabbedDetailPage("capacity", p.capacityPanel.GetRootView())
+}
+
+// ensureEventsPanel creates the events panel if not already created
+func (p *MainPanel) ensureEventsPanel() {
+	if p.eventsPanel != nil {
+		return
+	}
+	p.eventsPanel = eventsview.NewPanel()
+	p.eventsPanel.SetOnBack(func() {
+		p.viewState.SetOverview() // Clear tracking on back navigation
+		p.app.NavigateBack()
+	})
+	p.eventsPanel.SetOnSelected(func(kind, namespace, name string) {
+		switch kind {
+		case "Pod":
+			p.app.NavigateToPodDetail(namespace, name)
+		case "Node":
+			p.app.NavigateToNodeDetail(name)
+		default:
+			p.app.ShowToast("No detail page for "+kind+" "+name, ui.ToastInfo, 3*time.Second)
+		}
+	})
+	p.eventsPanel.SetAppFocus(func(prim tview.Primitive) {
+		p.app.Focus(prim)
+	})
+	p.app.AddTabbedDetailPage("events", p.eventsPanel.GetRootView())
+}
+
+// ensurePortForwardsPanel creates the port forwards panel if not already created
+func (p *MainPanel) ensurePortForwardsPanel() {
+	if p.portForwardsPanel != nil {
+		return
+	}
+	p.portForwardsPanel = portforwardview.NewPanel()
+	p.portForwardsPanel.SetOnBack(func() {
+		p.viewState.SetOverview() // Clear tracking on back navigation
+		p.app.NavigateBack()
+	})
+	p.portForwardsPanel.SetOnSelected(func(namespace, podName string) {
+		p.app.NavigateToPodDetail(namespace, podName)
+	})
+	p.portForwardsPanel.SetAppFocus(func(prim tview.Primitive) {
+		p.app.Focus(prim)
+	})
+	p.app.AddTabbedDetailPage("port_forwards", p.portForwardsPanel.GetRootView())
+}
+
+// ensureLogsPanel creates the aggregated logs panel if not already created
+func (p *MainPanel) ensureLogsPanel() {
+	if p.logsPanel != nil {
+		return
+	}
+	p.logsPanel = logsview.NewPanel()
+	p.logsPanel.SetStructuredLogs(p.structuredLogs, p.logFields)
+	p.logsPanel.SetOnBack(func() {
+		p.viewState.SetOverview() // Clear tracking on back navigation
+		p.app.NavigateBack()
+	})
+	p.logsPanel.SetAppFocus(func(prim tview.Primitive) {
+		p.app.Focus(prim)
+	})
+	p.logsPanel.SetLogStreamFunc(func(ctx context.Context, namespace, podName string, opts k8s.LogOptions) (io.ReadCloser, error) {
+		return p.app.GetCluster().Source().GetPodLogs(ctx, namespace, podName, opts)
+	})
+	p.logsPanel.SetWatchPodsFunc(func(ctx context.Context, sel k8s.PodSelector, onChange func(pod *v1.Pod, deleted bool)) error {
+		return p.app.GetCluster().Source().WatchPods(ctx, sel, onChange)
+	})
+	p.logsPanel.SetQueueUpdateFunc(func(fn func()) {
+		p.app.QueueUpdateDraw(fn)
+	})
+	p.logsPanel.SetOnExport(p.exportLogs)
+	p.app.AddTabbedDetailPage("logs", p.logsPanel.GetRootView())
+}
+
 // showContainerSpec navigates to the container spec view
 func (p *MainPanel) showContainerSpec(namespace, podName, containerName string, containerSpec *v1.Container) {
 	// Ensure the container spec panel exists (lazy initialization)
@@ -534,7 +1008,7 @@ func (p *MainPanel) showNodeDetail(nodeName string) {
 	}
 
 	// Fetch the raw Node object for conditions, labels, etc.
-	ctrl := p.app.GetK8sClient().Controller()
+	ctrl := p.app.GetCluster().Source()
 	if node, err := ctrl.GetNode(ctx, nodeName); err == nil {
 		detailData.Node = node
 	}
@@ -625,7 +1099,7 @@ func (p *MainPanel) showPodDetail(namespace, podName string) {
 	}
 
 	// Fetch the full Pod object for detailed info
-	ctrl := p.app.GetK8sClient().Controller()
+	ctrl := p.app.GetCluster().Source()
 	if pod, err := ctrl.GetPod(ctx, namespace, podName); err == nil {
 		detailData.Pod = pod
 	}
@@ -658,6 +1132,563 @@ func (p *MainPanel) showPodDetail(namespace, podName string) {
 	p.podDetailPanel.InitFocus() // Set up initial focus on events panel
 }
 
+// showWorkloadPods navigates to the view listing a workload's pods. It
+// returns false, leaving the current page, when the workload is not cached.
+func (p *MainPanel) showWorkloadPods(kind, namespace, name string) bool {
+	detailData := p.buildWorkloadDetailData(kind, namespace, name)
+	if detailData == nil {
+		return false // Workload not found
+	}
+
+	// Ensure the detail panel exists (lazy initialization)
+	p.ensureWorkloadDetailPanel()
+
+	// Set tracking before drawing so refreshes target this workload
+	p.viewState.SetWorkloadPods(kind, namespace, name)
+
+	p.workloadDetailPanel.DrawBody(detailData)
+	p.app.ShowDetailPage("workload_pods")
+	p.workloadDetailPanel.InitFocus()
+	return true
+}
+
+// showAlerts navigates to the firing alerts and their history
+func (p *MainPanel) showAlerts() {
+	p.ensureAlertsPanel()
+	p.viewState.SetAlerts()
+	p.alertsPanel.DrawBody(p.app.GetAlertEngine())
+	p.app.ShowDetailPage("alerts")
+	p.alertsPanel.InitFocus()
+}
+
+// pageRefresher fetches the data of a page off the UI goroutine and returns
+// the function drawing it, run on the UI goroutine. A nil function leaves
+// the page as it is.
+type pageRefresher func(ctx context.Context) (draw func())
+
+// registerPageRefresher makes page refresh every pageRefreshInterval while
+// it is in front. Must be called before the controller starts.
+func (p *MainPanel) registerPageRefresher(page application.PageType, refresh pageRefresher) {
+	if p.pageRefreshers == nil {
+		p.pageRefreshers = make(map[application.PageType]pageRefresher)
+	}
+	p.pageRefreshers[page] = refresh
+}
+
+// pageRefreshInterval is how often the page in front is refreshed, the
+// cadence of the node and pod refreshes
+const pageRefreshInterval = 5 * time.Second
+
+// runPageRefreshes refreshes the page in front until ctx, the session's
+// context, ends. Nothing is refreshed while the API is disconnected.
+func (p *MainPanel) runPageRefreshes(ctx context.Context) {
+	ticker := time.NewTicker(pageRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !p.app.IsAPIDisconnected() {
+				p.refreshPage(ctx)
+			}
+		}
+	}
+}
+
+// refreshPage runs the refresher of the page in front, if it has one. The
+// result is drawn unless the user has left the page in the meantime.
+func (p *MainPanel) refreshPage(ctx context.Context) {
+	page := p.viewState.Get().PageType
+	refresh, ok := p.pageRefreshers[page]
+	if !ok {
+		return
+	}
+	draw := refresh(ctx)
+	if draw == nil {
+		return
+	}
+	p.app.QueueUpdateDraw(func() {
+		if p.viewState.Get().PageType == page {
+			draw()
+		}
+	})
+}
+
+// drawAlertsIfVisible redraws the alerts page after an evaluation. Must be
+// called on the UI goroutine.
+func (p *MainPanel) drawAlertsIfVisible() {
+	if p.alertsPanel != nil && p.viewState.IsAlerts() {
+		p.alertsPanel.DrawBody(p.app.GetAlertEngine())
+	}
+}
+
+// showControlPlane navigates to the control-plane health page
+func (p *MainPanel) showControlPlane() {
+	p.ensureControlPlanePanel()
+	p.viewState.SetControlPlane()
+	p.app.ShowDetailPage("control_plane")
+	p.controlPlanePanel.InitFocus()
+	go p.refreshPage(context.Background())
+}
+
+// refreshControlPlane is the control-plane page's pageRefresher
+func (p *MainPanel) refreshControlPlane(ctx context.Context) func() {
+	data := p.fetchControlPlane(ctx)
+	return func() {
+		p.controlPlanePanel.DrawBody(data)
+	}
+}
+
+// fetchControlPlane returns the control-plane metrics, or an error for
+// sources that don't scrape the control plane
+func (p *MainPanel) fetchControlPlane(ctx context.Context) interface{} {
+	source, ok := p.metricsSource.(metrics.ControlPlaneSource)
+	if !ok {
+		return fmt.Errorf("control-plane metrics need --metrics-source=prometheus")
+	}
+	cp, err := source.GetControlPlaneMetrics(ctx)
+	if err != nil {
+		return err
+	}
+	return cp
+}
+
+// The query console charts the last queryRange at queryStep resolution
+const (
+	queryRange = 15 * time.Minute
+	queryStep  = 15 * time.Second
+)
+
+// showQuery navigates to the query console
+func (p *MainPanel) showQuery() {
+	p.ensureQueryPanel()
+	p.viewState.SetQuery()
+	p.app.ShowDetailPage("query")
+	p.queryPanel.InitFocus()
+
+	// Names for autocompletion may need a round trip to Prometheus
+	if source, ok := p.metricsSource.(metrics.QuerySource); ok {
+		go func() {
+			ctx := context.Background()
+			metricNames, err := source.MetricNames(ctx)
+			if err != nil {
+				slog.Debug("query console: listing metric names failed", "error", err)
+			}
+			labelNames, err := source.LabelNames(ctx)
+			if err != nil {
+				slog.Debug("query console: listing label names failed", "error", err)
+			}
+			p.app.QueueUpdateDraw(func() {
+				p.queryPanel.SetSuggestions(metricNames, labelNames)
+			})
+		}()
+	}
+}
+
+// runQuery evaluates expr and draws the result on the query console. It
+// makes network calls, so it runs off the UI goroutine.
+func (p *MainPanel) runQuery(expr string) {
+	result := p.fetchQuery(context.Background(), expr)
+	p.app.QueueUpdateDraw(func() {
+		// Skip results of an expression the user has since replaced
+		if p.queryPanel.Expr() == expr {
+			p.queryPanel.DrawBody(result)
+		}
+	})
+}
+
+// refreshQuery is the query console's pageRefresher: it re-runs the
+// current expression, if any
+func (p *MainPanel) refreshQuery(ctx context.Context) func() {
+	expr := p.queryPanel.Expr()
+	if expr == "" {
+		return nil
+	}
+	data := p.fetchQuery(ctx, expr)
+	return func() {
+		// Skip results of an expression the user has since replaced
+		if p.queryPanel.Expr() == expr {
+			p.queryPanel.DrawBody(data)
+		}
+	}
+}
+
+// fetchQuery returns the series of expr over the last queryRange, or an
+// error for sources that can't evaluate PromQL
+func (p *MainPanel) fetchQuery(ctx context.Context, expr string) interface{} {
+	source, ok := p.metricsSource.(metrics.QuerySource)
+	if !ok {
+		return fmt.Errorf("the query console needs --metrics-source=prometheus or prometheus-api")
+	}
+	end := time.Now()
+	series, err := source.QueryRange(ctx, expr, end.Add(-queryRange), end, queryStep)
+	if err != nil {
+		return err
+	}
+	return series
+}
+
+// showPressure navigates to the CPU throttled and OOM killed containers
+func (p *MainPanel) showPressure() {
+	p.ensurePressurePanel()
+	p.viewState.SetPressure()
+	p.app.ShowDetailPage("pressure")
+	p.pressurePanel.InitFocus()
+
+	// The metrics source may need a round trip to Prometheus
+	go p.refreshPage(context.Background())
+}
+
+// refreshPressure is the pressure page's pageRefresher
+func (p *MainPanel) refreshPressure(ctx context.Context) func() {
+	data := p.fetchPressure(ctx)
+	if data == nil {
+		return nil
+	}
+	return func() {
+		p.pressurePanel.DrawBody(data)
+	}
+}
+
+// fetchPressure returns the containers throttled or OOM killed, or nil when
+// the pods can't be listed. Throttling and OOM events are only available
+// from sources that evaluate PromQL; OOM kills in pod status are listed
+// regardless.
+func (p *MainPanel) fetchPressure(ctx context.Context) *model.PressureData {
+	pods, err := p.app.GetCluster().Source().GetPodList(ctx)
+	if err != nil {
+		slog.Debug("pressure: listing pods failed", "error", err)
+		return nil
+	}
+
+	throttled := make(map[model.ContainerKey]float64)
+	oomEvents := make(map[model.ContainerKey]float64)
+	var metricsErr error
+	if source, ok := p.metricsSource.(metrics.QuerySource); ok {
+		pressure, err := metrics.QueryContainerPressure(ctx, source)
+		if err != nil {
+			metricsErr = err
+		}
+		for _, cp := range pressure {
+			key := model.ContainerKey{Namespace: cp.Namespace, Pod: cp.Pod, Container: cp.Container}
+			if cp.ThrottledPercent > 0 {
+				throttled[key] = cp.ThrottledPercent
+			}
+			if cp.OOMEvents > 0 {
+				oomEvents[key] = cp.OOMEvents
+			}
+		}
+	} else {
+		metricsErr = fmt.Errorf("throttling and OOM events need --metrics-source=prometheus or prometheus-api")
+	}
+	return model.NewPressureData(pods, throttled, oomEvents, metricsErr)
+}
+
+// showCapacity navigates to the capacity left on each node
+func (p *MainPanel) showCapacity() {
+	p.ensureCapacityPanel()
+	p.viewState.SetCapacity()
+	p.app.ShowDetailPage("capacity")
+	p.capacityPanel.InitFocus()
+	go p.refreshPage(context.Background())
+}
+
+// refreshCapacity is the capacity page's pageRefresher; requests change as
+// pods come and go
+func (p *MainPanel) refreshCapacity(ctx context.Context) func() {
+	data := p.fetchCapacity(ctx)
+	return func() {
+		p.capacityPanel.DrawBody(data)
+	}
+}
+
+// fetchCapacity returns the []model.NodeCapacity of the cluster, or the
+// error listing nodes or pods failed with
+func (p *MainPanel) fetchCapacity(ctx context.Context) interface{} {
+	source := p.app.GetCluster().Source()
+	nodes, err := source.GetNodeList(ctx)
+	if err != nil {
+		return fmt.Errorf("listing nodes: %w", err)
+	}
+	pods, err := source.GetPodList(ctx)
+	if err != nil {
+		return fmt.Errorf("listing pods: %w", err)
+	}
+	return model.NewNodeCapacities(nodes, pods)
+}
+
+// showEvents navigates to the events of the cluster
+func (p *MainPanel) showEvents() {
+	p.ensureEventsPanel()
+	p.viewState.SetEvents()
+	p.app.ShowDetailPage("events")
+	p.eventsPanel.InitFocus()
+	go p.refreshPage(context.Background())
+}
+
+// refreshEvents is the events page's pageRefresher; new events stream in
+// between refreshes
+func (p *MainPanel) refreshEvents(ctx context.Context) func() {
+	data := p.fetchEvents(ctx)
+	return func() {
+		p.eventsPanel.DrawBody(data)
+	}
+}
+
+// showPortForwards navigates to the port forwards
+func (p *MainPanel) showPortForwards() {
+	p.ensurePortForwardsPanel()
+	p.viewState.SetPortForwards()
+	p.app.ShowDetailPage("port_forwards")
+	p.portForwardsPanel.InitFocus()
+	go p.refreshPage(context.Background())
+}
+
+// refreshPortForwards is the port forwards page's pageRefresher, updating
+// the traffic counts
+func (p *MainPanel) refreshPortForwards(ctx context.Context) func() {
+	return func() {
+		p.portForwardsPanel.DrawBody(p.app.GetPortForwards())
+	}
+}
+
+// pickPortForward lists the TCP ports of the containers of pod and forwards
+// the one picked
+func (p *MainPanel) pickPortForward(pod *v1.Pod) {
+	specs := portforward.PodSpecs(pod)
+	if len(specs) == 0 {
+		p.app.ShowToast("No TCP container ports declared by "+pod.Name, ui.ToastInfo, 3*time.Second)
+		return
+	}
+
+	labels := make([]string, len(specs))
+	for i, spec := range specs {
+		local := "any free port"
+		if spec.LocalPort != 0 {
+			local = fmt.Sprintf("localhost:%d", spec.LocalPort)
+		}
+		labels[i] = fmt.Sprintf("%s %d", spec.Container, spec.RemotePort)
+		if spec.PortName != "" {
+			labels[i] += "/" + spec.PortName
+		}
+		labels[i] += " ← " + local
+	}
+	p.app.ShowPicker("Forward port", labels, func(index int) {
+		p.app.StartPortForward(specs[index])
+	})
+}
+
+// fetchEvents returns the []model.EventModel of the cluster, or the error
+// listing events failed with. Events come from the informer cache, so this
+// makes no API call.
+func (p *MainPanel) fetchEvents(ctx context.Context) interface{} {
+	events, err := p.app.GetCluster().Source().GetEvents(ctx)
+	if err != nil {
+		return fmt.Errorf("listing events: %w", err)
+	}
+	return model.NewEventModels(events)
+}
+
+// showLogs navigates to the aggregated logs of a workload's pods, or of the
+// pods matching a label selector in the namespace filtered on when kind is
+// empty
+func (p *MainPanel) showLogs(kind, namespace, name string) {
+	p.ensureLogsPanel()
+	p.viewState.SetLogs()
+	p.app.ShowDetailPage("logs")
+	if kind == "" {
+		p.logsPanel.ShowSelector(p.namespaceFilter)
+		return
+	}
+	p.logsPanel.ShowWorkload(kind, namespace, name)
+}
+
+// defaultRightsizingWindow is how far back rightsizing samples usage from
+// sources that don't report their retention
+const defaultRightsizingWindow = 24 * time.Hour
+
+// showRightsizing navigates to the request and limit recommendations
+func (p *MainPanel) showRightsizing() {
+	p.ensureRightsizingPanel()
+	p.viewState.SetRightsizing()
+	p.app.ShowDetailPage("rightsizing")
+	p.rightsizingPanel.InitFocus()
+	p.sampleRightsizing()
+}
+
+// sampleRightsizing computes the recommendations for the workloads shown on
+// the Overview. Sampling makes a history query per container and pod, so it
+// runs off the UI goroutine and only when asked, not on every refresh. Must
+// be called on the UI goroutine.
+func (p *MainPanel) sampleRightsizing() {
+	if p.metricsSource == nil || !p.metricsSource.SupportsHistory() {
+		p.rightsizingPanel.SetMessage("[yellow]Rightsizing needs a metrics source with usage history")
+		return
+	}
+	p.rightsizingPanel.SetLoading()
+
+	filter := strings.ToLower(p.namespaceFilter)
+	var workloads []model.WorkloadModel
+	for _, w := range p.cachedWorkloads {
+		if filter == "" || strings.Contains(strings.ToLower(w.Namespace), filter) {
+			workloads = append(workloads, w)
+		}
+	}
+
+	go func() {
+		data, err := p.fetchRightsizing(context.Background(), workloads)
+		p.app.QueueUpdateDraw(func() {
+			if !p.viewState.IsRightsizing() {
+				return
+			}
+			if err != nil {
+				p.rightsizingPanel.SetMessage("[red]" + tview.Escape(err.Error()))
+				return
+			}
+			p.rightsizingPanel.DrawBody(data)
+		})
+	}()
+}
+
+// fetchRightsizing samples the usage history of each container of
+// workloads across the workload's pods. The containers come from the spec
+// of the first pod, as a workload's pods share a template.
+func (p *MainPanel) fetchRightsizing(ctx context.Context, workloads []model.WorkloadModel) (*model.RightsizingData, error) {
+	pods, err := p.app.GetCluster().Source().GetPodList(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list pods: %w", err)
+	}
+	podsByKey := make(map[string]*v1.Pod, len(pods))
+	for _, pod := range pods {
+		if pod.Status.Phase == v1.PodRunning {
+			podsByKey[pod.Namespace+"/"+pod.Name] = pod
+		}
+	}
+
+	window := defaultRightsizingWindow
+	if source, ok := p.metricsSource.(metrics.RetentionSource); ok {
+		window = source.Retention()
+	}
+
+	data := &model.RightsizingData{Window: window}
+	for _, w := range workloads {
+		var owned []*v1.Pod
+		for _, key := range w.Pods {
+			if pod, ok := podsByKey[key]; ok {
+				owned = append(owned, pod)
+			}
+		}
+		if len(owned) == 0 {
+			continue
+		}
+
+		for _, container := range owned[0].Spec.Containers {
+			var cpu, memory []float64
+			sampled := 0
+			for _, pod := range owned {
+				cpuHistory, err := p.metricsSource.GetPodHistory(ctx, pod.Namespace, pod.Name, metrics.HistoryQuery{
+					Resource:  metrics.ResourceCPU,
+					Duration:  window,
+					Container: container.Name,
+				})
+				if err != nil {
+					slog.Debug("rightsizing: cpu history failed", "pod", pod.Name, "container", container.Name, "error", err)
+					continue
+				}
+				memHistory, err := p.metricsSource.GetPodHistory(ctx, pod.Namespace, pod.Name, metrics.HistoryQuery{
+					Resource:  metrics.ResourceMemory,
+					Duration:  window,
+					Container: container.Name,
+				})
+				if err != nil {
+					slog.Debug("rightsizing: memory history failed", "pod", pod.Name, "container", container.Name, "error", err)
+					continue
+				}
+				if len(cpuHistory.DataPoints) > 0 || len(memHistory.DataPoints) > 0 {
+					sampled++
+				}
+				for _, dp := range cpuHistory.DataPoints {
+					cpu = append(cpu, dp.Value)
+				}
+				for _, dp := range memHistory.DataPoints {
+					memory = append(memory, dp.Value)
+				}
+			}
+			data.Items = append(data.Items, model.NewRightsizing(w.Kind, w.Namespace, w.Name, sampled, container, cpu, memory))
+		}
+	}
+	return data, nil
+}
+
+// exportRightsizing writes the changes recommended for items as a YAML patch
+// in the ktop exports directory. Must be called on the UI goroutine.
+func (p *MainPanel) exportRightsizing(items []model.Rightsizing) {
+	patch, err := model.RightsizingPatch(items)
+	if err != nil {
+		p.app.ShowToast(fmt.Sprintf("Export failed: %v", err), ui.ToastError, 5*time.Second)
+		return
+	}
+	if patch == "" {
+		p.app.ShowToast("No over- or under-provisioned containers to export", ui.ToastInfo, 3*time.Second)
+		return
+	}
+
+	name := fmt.Sprintf("rightsizing-%s-%s.yaml", p.app.GetCluster().ClusterContext(), time.Now().Format("20060102-150405"))
+	path, err := userdir.ExportPath(name)
+	if err == nil {
+		err = os.WriteFile(path, []byte(patch), 0o600)
+	}
+	if err != nil {
+		p.app.ShowToast(fmt.Sprintf("Export failed: %v", err), ui.ToastError, 5*time.Second)
+		return
+	}
+	p.rightsizingPanel.SetMessage("[green]Exported to " + tview.Escape(path))
+	p.app.ShowToast("Rightsizing patch exported to "+path, ui.ToastSuccess, 5*time.Second)
+}
+
+// exportLogs writes a logs export file named name in the ktop exports
+// directory. write runs in the background since it may fetch logs from the
+// API server; a toast shows the written path or the error. Must be called on
+// the UI goroutine.
+func (p *MainPanel) exportLogs(name string, write func(w io.Writer) error) {
+	path, err := userdir.ExportPath(name)
+	if err != nil {
+		p.app.ShowToast(fmt.Sprintf("Export failed: %v", err), ui.ToastError, 5*time.Second)
+		return
+	}
+
+	toastID := p.app.ShowToast("Saving logs...", ui.ToastInfo, 0)
+	go func() {
+		err := writeExport(path, write)
+		p.app.QueueUpdateDraw(func() {
+			p.app.DismissToast(toastID)
+			if err != nil {
+				slog.Error("logs export failed", "path", path, "error", err)
+				p.app.ShowToast(fmt.Sprintf("Export failed: %v", err), ui.ToastError, 5*time.Second)
+				return
+			}
+			p.app.ShowToast("Logs saved to "+path, ui.ToastSuccess, 5*time.Second)
+		})
+	}()
+}
+
+// writeExport creates the file at path with write, removing it if write fails
+func writeExport(path string, write func(w io.Writer) error) error {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	err = write(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
+}
+
 func (p *MainPanel) refreshNodeView(ctx context.Context, models []model.NodeModel) error {
 	// The controller passes us models, but we need to rebuild them with fresh metrics
 	// from our MetricsSource. We'll extract the node objects from the models.
@@ -685,6 +1716,17 @@ func (p *MainPanel) refreshNodeView(ctx context.Context, models []model.NodeMode
 		nodeModels = append(nodeModels, existingModel)
 	}
 
+	if custom := p.evaluateCustomColumns(ctx, metrics.ColumnTableNode); custom != nil {
+		for i := range nodeModels {
+			nodeModels[i].Custom = p.customValues(custom, "", nodeModels[i].Name)
+		}
+	}
+
+	// Alerts use the metrics just fetched
+	if engine := p.app.GetAlertEngine(); engine != nil {
+		engine.EvaluateNodes(nodeModels)
+	}
+
 	// Pre-fetch node detail data if detail view is visible (do network calls outside QueueUpdateDraw)
 	// Use ViewStateManager for thread-safe state access
 	// Capture the node name at fetch time so we can verify it later
@@ -702,6 +1744,7 @@ func (p *MainPanel) refreshNodeView(ctx context.Context, models []model.NodeMode
 		p.cachedNodeModels = nodeModels
 		p.nodePanel.Clear()
 		p.nodePanel.DrawBody(nodeModels)
+		p.drawAlertsIfVisible()
 
 		// If node detail is currently displayed, update it with pre-fetched data
 		// CRITICAL: Re-verify the view state matches what we fetched - user may have
@@ -765,6 +1808,19 @@ func (p *MainPanel) refreshPods(ctx context.Context, models []model.PodModel) er
 		}
 	}
 
+	if custom := p.evaluateCustomColumns(ctx, metrics.ColumnTablePod); custom != nil {
+		withCustom := make([]model.PodModel, len(updatedModels))
+		for i, m := range updatedModels {
+			m.Custom = p.customValues(custom, m.Namespace, m.Name)
+			withCustom[i] = m
+		}
+		updatedModels = withCustom
+	}
+
+	if engine := p.app.GetAlertEngine(); engine != nil {
+		engine.EvaluatePods(updatedModels)
+	}
+
 	// Pre-fetch pod detail data if detail view is visible (do network calls outside QueueUpdateDraw)
 	// Use ViewStateManager for thread-safe state access
 	// Capture the pod key at fetch time so we can verify it later
@@ -819,6 +1875,9 @@ func (p *MainPanel) refreshPods(ctx context.Context, models []model.PodModel) er
 		p.cachedPodModels = updatedModels
 		// Apply namespace filter and display
 		p.displayFilteredPodsInternal()
+		// Workload totals are aggregated from the pods just cached
+		p.displayWorkloadsInternal()
+		p.drawAlertsIfVisible()
 
 		// If pod detail is currently displayed, update it with pre-fetched data
 		// CRITICAL: Re-verify the view state matches what we fetched - user may have
@@ -854,6 +1913,45 @@ func (p *MainPanel) refreshPods(ctx context.Context, models []model.PodModel) er
 	return nil
 }
 
+// evaluateCustomColumns evaluates the custom columns of a table, returning
+// each column's values keyed by row. Returns nil when the table has no
+// custom columns or the metrics source can't evaluate them.
+func (p *MainPanel) evaluateCustomColumns(ctx context.Context, table string) map[string]map[string]float64 {
+	source, ok := p.metricsSource.(metrics.QuerySource)
+	if !ok {
+		return nil
+	}
+
+	var results map[string]map[string]float64
+	for _, col := range p.customColumns {
+		if col.Table != table {
+			continue
+		}
+		values, err := metrics.QueryCustomColumn(ctx, source, col)
+		if err != nil {
+			slog.Debug("custom column not evaluated", "column", col.Name, "error", err)
+			continue
+		}
+		if results == nil {
+			results = make(map[string]map[string]float64)
+		}
+		results[col.Name] = values
+	}
+	return results
+}
+
+// customValues picks the values of one node or pod out of the results of
+// evaluateCustomColumns
+func (p *MainPanel) customValues(results map[string]map[string]float64, namespace, name string) map[string]float64 {
+	values := make(map[string]float64, len(results))
+	for _, col := range p.customColumns {
+		if v, ok := results[col.Name][col.RowKey(namespace, name)]; ok {
+			values[col.Name] = v
+		}
+	}
+	return values
+}
+
 // displayFilteredPods filters cached pod models and displays them
 // Called from namespace filter callback (runs on main goroutine)
 func (p *MainPanel) displayFilteredPods() {
@@ -863,6 +1961,7 @@ func (p *MainPanel) displayFilteredPods() {
 
 	// Already on main goroutine from input handler, safe to update directly
 	p.displayFilteredPodsInternal()
+	p.displayWorkloadsInternal()
 
 	// Refresh screen
 	if p.refresh != nil {
@@ -894,6 +1993,87 @@ func (p *MainPanel) displayFilteredPodsInternal() {
 	p.podPanel.DrawBody(filteredModels)
 }
 
+func (p *MainPanel) refreshWorkloads(ctx context.Context, models []model.WorkloadModel) error {
+	// Queue UI update on main goroutine to avoid race with Draw()
+	p.app.QueueUpdateDraw(func() {
+		p.cachedWorkloads = models
+		p.displayWorkloadsInternal()
+	})
+	return nil
+}
+
+func (p *MainPanel) refreshNamespaces(ctx context.Context, models []model.NamespaceModel) error {
+	// Queue UI update on main goroutine to avoid race with Draw()
+	p.app.QueueUpdateDraw(func() {
+		p.cachedNamespaces = models
+		p.displayNamespacesInternal()
+	})
+	return nil
+}
+
+// displayNamespacesInternal redraws the namespaces panel from the cached
+// models. Namespaces are not narrowed by the namespace filter so that another
+// namespace can still be selected. Must be called on the UI goroutine.
+func (p *MainPanel) displayNamespacesInternal() {
+	if p.cachedNamespaces == nil {
+		return
+	}
+	p.namespacePanel.Clear()
+	p.namespacePanel.DrawBody(p.cachedNamespaces)
+}
+
+// displayWorkloadsInternal aggregates the cached pod models into the cached
+// workloads, applies the namespace filter and redraws the workloads panel,
+// along with the workload's pods view when it is displayed.
+// Must be called from main goroutine (either directly or via QueueUpdateDraw)
+func (p *MainPanel) displayWorkloadsInternal() {
+	if p.cachedWorkloads == nil {
+		return
+	}
+
+	filterLower := strings.ToLower(p.namespaceFilter)
+	workloads := make([]model.WorkloadModel, 0, len(p.cachedWorkloads))
+	for _, w := range p.cachedWorkloads {
+		if filterLower != "" && !strings.Contains(strings.ToLower(w.Namespace), filterLower) {
+			continue
+		}
+		w.AggregatePods(p.cachedPodModels)
+		workloads = append(workloads, w)
+	}
+
+	p.workloadPanel.Clear()
+	p.workloadPanel.DrawBody(workloads)
+
+	if kind, namespace, name, ok := p.viewState.GetWorkloadPods(); ok && p.workloadDetailPanel != nil {
+		if detailData := p.buildWorkloadDetailData(kind, namespace, name); detailData != nil {
+			p.workloadDetailPanel.DrawBody(detailData)
+		}
+	}
+}
+
+// buildWorkloadDetailData builds the workload pods view data from cached
+// models. It makes no network calls and is safe to call on the main goroutine.
+func (p *MainPanel) buildWorkloadDetailData(kind, namespace, name string) *model.WorkloadDetailData {
+	key := model.WorkloadKey(kind, namespace, name)
+	for i := range p.cachedWorkloads {
+		if p.cachedWorkloads[i].Key() != key {
+			continue
+		}
+		// Copy the model to avoid pointer to slice element issues
+		w := p.cachedWorkloads[i]
+		w.AggregatePods(p.cachedPodModels)
+
+		owned := w.OwnedPods(p.cachedPodModels)
+		model.SortPodModelsBy(owned, "POD", true)
+		pods := make([]*model.PodModel, len(owned))
+		for j := range owned {
+			pods[j] = &owned[j]
+		}
+		return &model.WorkloadDetailData{Workload: &w, Pods: pods}
+	}
+	return nil
+}
+
 // buildNodeDetailData builds the node detail data for live updates.
 // This performs network calls and must be called outside QueueUpdateDraw.
 func (p *MainPanel) buildNodeDetailData(ctx context.Context, nodeName string, nodeModels []model.NodeModel) *model.NodeDetailData {
@@ -977,7 +2157,7 @@ func (p *MainPanel) buildNodeDetailData(ctx context.Context, nodeName string, no
 	}
 
 	// Fetch the raw Node object for conditions, labels, etc.
-	ctrl := p.app.GetK8sClient().Controller()
+	ctrl := p.app.GetCluster().Source()
 	if node, err := ctrl.GetNode(ctx, nodeName); err == nil {
 		detailData.Node = node
 	}
@@ -1076,7 +2256,7 @@ func (p *MainPanel) buildPodDetailData(ctx context.Context, podKey string, podMo
 	}
 
 	// Fetch the full Pod object for detailed info
-	ctrl := p.app.GetK8sClient().Controller()
+	ctrl := p.app.GetCluster().Source()
 	if pod, err := ctrl.GetPod(ctx, namespace, podName); err == nil {
 		detailData.Pod = pod
 	}
@@ -0,0 +1,392 @@
+package overview
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/application"
+	"github.com/vladimirvivien/ktop/ui"
+	"github.com/vladimirvivien/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// NamespaceSelectedCallback is called when a namespace is selected (Enter pressed)
+type NamespaceSelectedCallback func(namespace string)
+
+type namespacePanel struct {
+	app         *application.Application
+	title       string
+	root        *tview.Flex
+	children    []tview.Primitive
+	listCols    []string
+	list        *tview.Table
+	laidout     bool
+	colMap      map[string]int         // Maps column name to position index
+	sortColumn  string                 // Current sort column
+	sortAsc     bool                   // Sort direction: true=ascending, false=descending
+	currentData []model.NamespaceModel // Store current data for re-sorting
+	filter      *ui.FilterState        // Filter state for row filtering
+
+	// Callback for namespace selection
+	onNamespaceSelected NamespaceSelectedCallback
+}
+
+// namespaceColumnKeys maps column names to their sort shortcut
+var namespaceColumnKeys = map[string]rune{
+	"NAMESPACE": 'n',
+	"PODS":      'p',
+	"RUNNING":   'u',
+	"FAILED":    'f',
+	"RST":       't',
+	"CPU":       'c',
+	"MEMORY":    'm',
+	"CPU REQ":   'r',
+	"CPU LIM":   'l',
+	"MEM REQ":   'q',
+	"MEM LIM":   'i',
+}
+
+func NewNamespacePanel(app *application.Application, title string) ui.Panel {
+	p := &namespacePanel{
+		app:        app,
+		title:      title,
+		sortColumn: "NAMESPACE",
+		sortAsc:    true,
+		filter:     &ui.FilterState{},
+	}
+	p.Layout(nil)
+
+	return p
+}
+
+func (p *namespacePanel) GetTitle() string {
+	return p.title
+}
+
+// SetOnNamespaceSelected sets the callback for when a namespace is selected (Enter pressed)
+func (p *namespacePanel) SetOnNamespaceSelected(callback NamespaceSelectedCallback) {
+	p.onNamespaceSelected = callback
+}
+
+// visibleNamespaces returns the current data filtered and sorted as displayed
+func (p *namespacePanel) visibleNamespaces() []model.NamespaceModel {
+	namespaces := p.currentData
+	if p.filter.IsFiltering() && p.filter.Text != "" {
+		namespaces = nil
+		for _, ns := range p.currentData {
+			if p.filter.MatchesRow([]string{ns.Name, ns.Status}) {
+				namespaces = append(namespaces, ns)
+			}
+		}
+	}
+	model.SortNamespaceModelsBy(namespaces, p.sortColumn, p.sortAsc)
+	return namespaces
+}
+
+func (p *namespacePanel) Layout(_ interface{}) {
+	if !p.laidout {
+		p.list = tview.NewTable()
+		p.list.SetFixed(1, 0)
+		p.list.SetBorder(false)
+		p.list.SetBorders(false)
+		p.list.SetFocusFunc(func() {
+			p.list.SetSelectable(true, false)
+			p.list.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorLightGray).Foreground(tcell.ColorBlack))
+			p.list.Select(1, 0)
+		})
+		p.list.SetBlurFunc(func() {
+			p.list.SetSelectable(false, false)
+		})
+
+		p.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if p.filter.Editing {
+				switch event.Key() {
+				case tcell.KeyEscape:
+					p.filter.Cancel()
+					p.redrawWithFilter()
+					return nil
+				case tcell.KeyEnter:
+					p.filter.Confirm()
+					p.redrawWithFilter()
+					return nil
+				case tcell.KeyBackspace, tcell.KeyBackspace2:
+					if p.filter.HandleBackspace() {
+						p.redrawWithFilter()
+					}
+					return nil
+				case tcell.KeyRune:
+					p.filter.AppendChar(event.Rune())
+					p.redrawWithFilter()
+					return nil
+				}
+				return nil
+			}
+
+			if event.Key() == tcell.KeyEscape && p.filter.Active {
+				p.filter.Clear()
+				p.redrawWithFilter()
+				return nil
+			}
+
+			if event.Key() == tcell.KeyRune && event.Rune() == '/' {
+				p.filter.StartEditing()
+				p.redrawWithFilter()
+				return nil
+			}
+
+			// Select the namespace as the Overview's namespace filter
+			if event.Key() == tcell.KeyEnter {
+				row, _ := p.list.GetSelection()
+				namespaces := p.visibleNamespaces()
+				if row > 0 && row-1 < len(namespaces) && p.onNamespaceSelected != nil {
+					p.onNamespaceSelected(namespaces[row-1].Name)
+					return nil
+				}
+			}
+
+			if event.Key() == tcell.KeyRune {
+				if p.handleSortKey(event.Rune()) {
+					return nil
+				}
+			}
+			return event
+		})
+
+		p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(p.list, 0, 1, true)
+		p.root.SetBorder(true)
+		p.root.SetTitle(p.GetTitle())
+		p.root.SetTitleAlign(tview.AlignLeft)
+		p.laidout = true
+	}
+}
+
+func (p *namespacePanel) DrawHeader(data interface{}) {
+	cols, ok := data.([]string)
+	if !ok {
+		panic(fmt.Sprintf("namespacePanel.DrawHeader got unexpected data type %T", data))
+	}
+
+	p.colMap = make(map[string]int)
+	p.listCols = cols
+
+	for i, col := range p.listCols {
+		p.list.SetCell(0, i,
+			tview.NewTableCell(formatSortableHeader(col, namespaceColumnKeys[col], p.sortColumn, p.sortAsc)).
+				SetTextColor(tcell.ColorWhite).
+				SetBackgroundColor(tcell.ColorDarkCyan).
+				SetAlign(tview.AlignLeft).
+				SetExpansion(100).
+				SetSelectable(true),
+		)
+		p.colMap[col] = i
+	}
+	p.list.SetFixed(1, 0)
+}
+
+// toggleSort toggles the sort column and direction
+func (p *namespacePanel) toggleSort(columnName string) {
+	if columnName == p.sortColumn {
+		p.sortAsc = !p.sortAsc
+	} else {
+		p.sortColumn = columnName
+		p.sortAsc = true
+	}
+
+	if len(p.currentData) > 0 {
+		p.list.Clear()
+		p.DrawHeader(p.listCols)
+		p.DrawBody(p.currentData)
+		if p.app != nil {
+			p.app.Refresh()
+		}
+	}
+}
+
+// handleSortKey processes keyboard shortcuts for sorting
+// Returns true if the key was handled, false otherwise
+func (p *namespacePanel) handleSortKey(key rune) bool {
+	for _, col := range p.listCols {
+		if namespaceColumnKeys[col] == key {
+			p.toggleSort(col)
+			return true
+		}
+	}
+	return false
+}
+
+func (p *namespacePanel) DrawBody(data interface{}) {
+	namespaces, ok := data.([]model.NamespaceModel)
+	if !ok {
+		panic(fmt.Sprintf("namespacePanel.DrawBody got unexpected type %T", data))
+	}
+
+	p.currentData = namespaces
+	p.filter.TotalRows = len(namespaces)
+	filtered := p.visibleNamespaces()
+	p.filter.MatchRows = len(filtered)
+
+	p.updateTitle(len(filtered))
+
+	selected := p.app.GetNamespaceFilter()
+	for i, ns := range filtered {
+		rowIdx := i + 1 // offset for header row
+
+		rowColor := tcell.ColorYellow
+		if ns.FailedPods > 0 {
+			rowColor = ui.GetTcellColor(ui.Theme.StatusError)
+		}
+
+		for _, colName := range p.listCols {
+			colIdx, exists := p.colMap[colName]
+			if !exists {
+				continue
+			}
+
+			var text string
+			maxWidth := 0
+			switch colName {
+			case "NAMESPACE":
+				text, maxWidth = ns.Name, 24
+				if selected != "" && ns.Name == selected {
+					text = "[::b]" + ns.Name + "[::-]"
+				}
+			case "PODS":
+				text = fmt.Sprintf("%d", ns.Pods)
+			case "RUNNING":
+				text = fmt.Sprintf("%d", ns.RunningPods)
+			case "FAILED":
+				text = fmt.Sprintf("%d", ns.FailedPods)
+			case "RST":
+				text = fmt.Sprintf("%d", ns.Restarts)
+			case "CPU":
+				text = formatCPUQty(ns.UsageCpuQty)
+			case "MEMORY":
+				text = ui.FormatMemory(ns.UsageMemQty)
+			case "CPU REQ":
+				text = formatQuotaUse(ns.RequestedCpuQty, ns.QuotaRequestsCpuQty, formatCPUQty)
+			case "CPU LIM":
+				text = formatQuotaUse(ns.LimitCpuQty, ns.QuotaLimitsCpuQty, formatCPUQty)
+			case "MEM REQ":
+				text = formatQuotaUse(ns.RequestedMemQty, ns.QuotaRequestsMemQty, ui.FormatMemory)
+			case "MEM LIM":
+				text = formatQuotaUse(ns.LimitMemQty, ns.QuotaLimitsMemQty, ui.FormatMemory)
+			}
+
+			p.list.SetCell(rowIdx, colIdx, &tview.TableCell{
+				Text:     text,
+				Color:    rowColor,
+				Align:    tview.AlignLeft,
+				MaxWidth: maxWidth,
+			})
+		}
+	}
+}
+
+func formatCPUQty(q *resource.Quantity) string {
+	return fmt.Sprintf("%5dm", quantityMilli(q))
+}
+
+// formatQuotaUse renders a total against its ResourceQuota hard limit, e.g.
+// "500m / 2000m 25.0%". Totals without a quota are rendered alone.
+func formatQuotaUse(used, hard *resource.Quantity, format func(*resource.Quantity) string) string {
+	text := format(used)
+	if hard == nil {
+		return text
+	}
+	usedMilli, hardMilli := quantityMilli(used), quantityMilli(hard)
+	if hardMilli <= 0 {
+		return fmt.Sprintf("%s / %s", text, format(hard))
+	}
+	pct := float64(usedMilli) / float64(hardMilli) * 100
+	return fmt.Sprintf("%s / %s [%s]%5.1f%%[white]", text, format(hard), ui.GetResourcePercentageColor(pct), pct)
+}
+
+func (p *namespacePanel) DrawFooter(_ interface{}) {}
+
+func (p *namespacePanel) Clear() {
+	p.list.Clear()
+	p.Layout(nil)
+	p.DrawHeader(p.listCols)
+}
+
+func (p *namespacePanel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+func (p *namespacePanel) GetChildrenViews() []tview.Primitive {
+	return p.children
+}
+
+// SetFocused implements ui.FocusablePanel - updates visual focus state
+func (p *namespacePanel) SetFocused(focused bool) {
+	ui.SetFlexFocused(p.root, focused)
+}
+
+// redrawWithFilter redraws the table with current filter applied
+func (p *namespacePanel) redrawWithFilter() {
+	p.list.Clear()
+	p.DrawHeader(p.listCols)
+	if p.currentData != nil {
+		p.DrawBody(p.currentData)
+	} else {
+		p.updateTitle(0)
+	}
+	if p.app != nil {
+		p.app.Refresh()
+	}
+}
+
+// HasEscapableState implements ui.EscapablePanel
+func (p *namespacePanel) HasEscapableState() bool {
+	return p.filter.HasEscapableState()
+}
+
+// HandleEscape implements ui.EscapablePanel - handles ESC key press
+func (p *namespacePanel) HandleEscape() bool {
+	if p.filter.Editing {
+		p.filter.Cancel()
+		p.redrawWithFilter()
+		return true
+	}
+	if p.filter.Active {
+		p.filter.Clear()
+		p.redrawWithFilter()
+		return true
+	}
+	return false
+}
+
+// updateTitle updates the panel title with scroll position indicator and filter state
+func (p *namespacePanel) updateTitle(totalRows int) {
+	_, _, _, height := p.list.GetInnerRect()
+	visibleRows := height - 1 // Subtract header row
+	offset, _ := p.list.GetOffset()
+
+	var disconnectedSuffix string
+	if p.app.IsAPIDisconnected() {
+		disconnectedSuffix = " [red][DISCONNECTED - Press R to reconnect][-]"
+	}
+
+	firstVisible := offset + 1
+	lastVisible := min(offset+visibleRows, totalRows)
+	if totalRows == 0 {
+		firstVisible = 0
+		lastVisible = 0
+	}
+
+	var scrollIndicator string
+	hasAbove := offset > 0
+	hasBelow := (offset + visibleRows) < totalRows
+	if hasAbove && hasBelow {
+		scrollIndicator = " ↑↓"
+	} else if hasAbove {
+		scrollIndicator = " ↑"
+	} else if hasBelow {
+		scrollIndicator = " ↓"
+	}
+
+	title := p.filter.FormatTitleWithScroll("Namespaces", ui.Icons.Anchor, firstVisible, lastVisible, totalRows, scrollIndicator, disconnectedSuffix)
+	p.root.SetTitle(title)
+}
@@ -237,9 +237,8 @@ func (p *nodePanel) formatColumnHeader(col string) string {
 				ui.Theme.HeaderShortcutKey, col[0], ui.Theme.HeaderForeground, col[1:])
 		}
 	} else {
-		// No mapping found, highlight first character
-		formatted = fmt.Sprintf("[%s::b]%c[%s::-]%s",
-			ui.Theme.HeaderShortcutKey, col[0], ui.Theme.HeaderForeground, col[1:])
+		// No shortcut (custom columns): nothing to highlight
+		formatted = tview.Escape(col)
 	}
 
 	// Add sort indicator if this is the active sort column
@@ -391,7 +390,7 @@ func (p *nodePanel) DrawBody(data interface{}) {
 	var cpuRatio, memRatio ui.Ratio
 	var cpuGraph, memGraph string
 	var cpuMetrics, memMetrics string
-	colorKeys := ui.ColorKeys{0: "olivedrab", 50: "yellow", 90: "red"}
+	colorKeys := ui.DefaultColorKeys()
 
 	// Apply filter and track counts
 	p.filter.TotalRows = len(nodes)
@@ -650,6 +649,22 @@ func (p *nodePanel) DrawBody(data interface{}) {
 						Align: tview.AlignLeft,
 					},
 				)
+
+			default:
+				// Custom column; "-" when the query has no value for this node
+				customText := "-"
+				if v, ok := node.Custom[colName]; ok {
+					customText = ui.FormatCompact(v)
+				}
+				p.list.SetCell(
+					rowIdx, colIdx,
+					&tview.TableCell{
+						Text:     customText,
+						Color:    rowColor,
+						Align:    tview.AlignLeft,
+						MaxWidth: 10,
+					},
+				)
 			}
 		}
 	}
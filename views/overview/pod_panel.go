@@ -238,9 +238,8 @@ func (p *podPanel) formatColumnHeader(col string) string {
 				ui.Theme.HeaderShortcutKey, col[0], ui.Theme.HeaderForeground, col[1:])
 		}
 	} else {
-		// No mapping found, highlight first character
-		formatted = fmt.Sprintf("[%s::b]%c[%s::-]%s",
-			ui.Theme.HeaderShortcutKey, col[0], ui.Theme.HeaderForeground, col[1:])
+		// No shortcut (custom columns): nothing to highlight
+		formatted = tview.Escape(col)
 	}
 
 	// Add sort indicator if this is the active sort column
@@ -370,7 +369,7 @@ func (p *podPanel) DrawBody(data interface{}) {
 	// Sort pods according to current sort state
 	model.SortPodModelsBy(pods, p.sortColumn, p.sortAsc)
 
-	colorKeys := ui.ColorKeys{0: "olivedrab", 50: "yellow", 90: "red"}
+	colorKeys := ui.DefaultColorKeys()
 	var cpuRatio, memRatio ui.Ratio
 	var cpuGraph, memGraph string
 	var cpuMetrics, memMetrics string
@@ -626,6 +625,22 @@ func (p *podPanel) DrawBody(data interface{}) {
 						Align: tview.AlignLeft,
 					},
 				)
+
+			default:
+				// Custom column; "-" when the query has no value for this pod
+				customText := "-"
+				if v, ok := pod.Custom[colName]; ok {
+					customText = ui.FormatCompact(v)
+				}
+				p.list.SetCell(
+					rowIdx, colIdx,
+					&tview.TableCell{
+						Text:     customText,
+						Color:    rowColor,
+						Align:    tview.AlignLeft,
+						MaxWidth: 10,
+					},
+				)
 			}
 		}
 	}
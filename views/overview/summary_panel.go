@@ -51,7 +51,7 @@ func (p *clusterSummaryPanel) Layout(data interface{}) {
 	metricsSource := p.app.GetMetricsSource()
 	if metricsSource != nil {
 		info := metricsSource.GetSourceInfo()
-		p.prometheusMode = (info.Type == metrics.SourceTypePrometheus)
+		p.prometheusMode = metrics.IsPrometheusSource(info.Type)
 	}
 
 	// Get actual terminal height from application (not panel height)
@@ -321,12 +321,26 @@ func (p *clusterSummaryPanel) drawEnhancedStats(summary model.ClusterSummary) {
 		pressureColor = "red"
 	}
 
+	oomColor := "green"
+	if summary.OOMKillCount > 0 {
+		oomColor = "red"
+	}
+
+	throttledColor := "green"
+	if summary.CPUThrottledPercent >= 50 {
+		throttledColor = "red"
+	} else if summary.CPUThrottledPercent >= 25 {
+		throttledColor = "yellow"
+	}
+
 	enhancedText := fmt.Sprintf(
-		"[yellow]Restarts: [%s]%d[yellow] │ Failures: [%s]%d[yellow] │ Evicted: [%s]%d[yellow] │ Pressure: [%s]%d",
+		"[yellow]Restarts: [%s]%d[yellow] │ Failures: [%s]%d[yellow] │ Evicted: [%s]%d[yellow] │ Pressure: [%s]%d[yellow] │ OOMKilled: [%s]%d[yellow] │ Throttled: [%s]%.1f%%",
 		restartsColor, summary.ContainerRestarts,
 		failuresColor, summary.FailedPods,
 		evictedColor, summary.EvictedPods,
 		pressureColor, summary.NodePressureCount,
+		oomColor, summary.OOMKillCount,
+		throttledColor, summary.CPUThrottledPercent,
 	)
 	p.enhancedStats.SetText(enhancedText)
 }
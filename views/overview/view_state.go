@@ -64,6 +64,79 @@ func (m *ViewStateManager) SetContainerLogs(namespace, podName, containerName st
 	m.mu.Unlock()
 }
 
+// SetWorkloadPods transitions to viewing the pods of a workload
+func (m *ViewStateManager) SetWorkloadPods(kind, namespace, name string) {
+	m.mu.Lock()
+	m.current = ViewState{
+		PageType:   application.PageWorkloadPods,
+		ResourceID: kind + "/" + namespace + "/" + name,
+	}
+	m.mu.Unlock()
+}
+
+// SetAlerts transitions to the alerts page
+func (m *ViewStateManager) SetAlerts() {
+	m.mu.Lock()
+	m.current = ViewState{PageType: application.PageAlerts}
+	m.mu.Unlock()
+}
+
+// SetControlPlane transitions to the control-plane page
+func (m *ViewStateManager) SetControlPlane() {
+	m.mu.Lock()
+	m.current = ViewState{PageType: application.PageControlPlane}
+	m.mu.Unlock()
+}
+
+// SetQuery transitions to the query console
+func (m *ViewStateManager) SetQuery() {
+	m.mu.Lock()
+	m.current = ViewState{PageType: application.PageQuery}
+	m.mu.Unlock()
+}
+
+// SetPressure transitions to the pressure page
+func (m *ViewStateManager) SetPressure() {
+	m.mu.Lock()
+	m.current = ViewState{PageType: application.PagePressure}
+	m.mu.Unlock()
+}
+
+// SetRightsizing transitions to the rightsizing page
+func (m *ViewStateManager) SetRightsizing() {
+	m.mu.Lock()
+	m.current = ViewState{PageType: application.PageRightsizing}
+	m.mu.Unlock()
+}
+
+// SetCapacity transitions to the capacity page
+func (m *ViewStateManager) SetCapacity() {
+	m.mu.Lock()
+	m.current = ViewState{PageType: application.PageCapacity}
+	m.mu.Unlock()
+}
+
+// SetEvents transitions to the events page
+func (m *ViewStateManager) SetEvents() {
+	m.mu.Lock()
+	m.current = ViewState{PageType: application.PageEvents}
+	m.mu.Unlock()
+}
+
+// SetLogs transitions to the aggregated logs page
+func (m *ViewStateManager) SetLogs() {
+	m.mu.Lock()
+	m.current = ViewState{PageType: application.PageLogs}
+	m.mu.Unlock()
+}
+
+// SetPortForwards transitions to the port forwards page
+func (m *ViewStateManager) SetPortForwards() {
+	m.mu.Lock()
+	m.current = ViewState{PageType: application.PagePortForwards}
+	m.mu.Unlock()
+}
+
 // Get returns the current view state (thread-safe snapshot)
 func (m *ViewStateManager) Get() ViewState {
 	m.mu.RLock()
@@ -108,3 +181,62 @@ func (m *ViewStateManager) GetContainerLogs() (namespace, podName, containerName
 	}
 	return parts[0], parts[1], parts[2], true
 }
+
+// GetWorkloadPods returns the workload identity if currently viewing its pods.
+// Returns ("", "", "", false) if not on a workload pods page.
+func (m *ViewStateManager) GetWorkloadPods() (kind, namespace, name string, ok bool) {
+	state := m.Get()
+	if state.PageType != application.PageWorkloadPods {
+		return "", "", "", false
+	}
+	parts := strings.SplitN(state.ResourceID, "/", 3)
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+// IsAlerts reports whether the alerts page is being viewed
+func (m *ViewStateManager) IsAlerts() bool {
+	return m.Get().PageType == application.PageAlerts
+}
+
+// IsControlPlane reports whether the control-plane page is being viewed
+func (m *ViewStateManager) IsControlPlane() bool {
+	return m.Get().PageType == application.PageControlPlane
+}
+
+// IsQuery reports whether the query console is being viewed
+func (m *ViewStateManager) IsQuery() bool {
+	return m.Get().PageType == application.PageQuery
+}
+
+// IsPressure reports whether the pressure page is being viewed
+func (m *ViewStateManager) IsPressure() bool {
+	return m.Get().PageType == application.PagePressure
+}
+
+// IsRightsizing reports whether the rightsizing page is being viewed
+func (m *ViewStateManager) IsRightsizing() bool {
+	return m.Get().PageType == application.PageRightsizing
+}
+
+// IsCapacity reports whether the capacity page is being viewed
+func (m *ViewStateManager) IsCapacity() bool {
+	return m.Get().PageType == application.PageCapacity
+}
+
+// IsEvents reports whether the events page is being viewed
+func (m *ViewStateManager) IsEvents() bool {
+	return m.Get().PageType == application.PageEvents
+}
+
+// IsLogs reports whether the aggregated logs page is being viewed
+func (m *ViewStateManager) IsLogs() bool {
+	return m.Get().PageType == application.PageLogs
+}
+
+// IsPortForwards reports whether the port forwards page is being viewed
+func (m *ViewStateManager) IsPortForwards() bool {
+	return m.Get().PageType == application.PagePortForwards
+}
@@ -0,0 +1,445 @@
+package overview
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/application"
+	"github.com/vladimirvivien/ktop/ui"
+	"github.com/vladimirvivien/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// WorkloadSelectedCallback is called when a workload is selected (Enter pressed)
+type WorkloadSelectedCallback func(kind, namespace, name string)
+
+type workloadPanel struct {
+	app         *application.Application
+	title       string
+	root        *tview.Flex
+	children    []tview.Primitive
+	listCols    []string
+	list        *tview.Table
+	laidout     bool
+	colMap      map[string]int        // Maps column name to position index
+	sortColumn  string                // Current sort column
+	sortAsc     bool                  // Sort direction: true=ascending, false=descending
+	currentData []model.WorkloadModel // Store current data for re-sorting
+	filter      *ui.FilterState       // Filter state for row filtering
+
+	// Callback for workload selection
+	onWorkloadSelected WorkloadSelectedCallback
+}
+
+func NewWorkloadPanel(app *application.Application, title string) ui.Panel {
+	p := &workloadPanel{
+		app:        app,
+		title:      title,
+		sortColumn: "NAMESPACE",
+		sortAsc:    true,
+		filter:     &ui.FilterState{},
+	}
+	p.Layout(nil)
+
+	return p
+}
+
+func (p *workloadPanel) GetTitle() string {
+	return p.title
+}
+
+// SetOnWorkloadSelected sets the callback for when a workload is selected (Enter pressed)
+func (p *workloadPanel) SetOnWorkloadSelected(callback WorkloadSelectedCallback) {
+	p.onWorkloadSelected = callback
+}
+
+// visibleWorkloads returns the current data filtered and sorted as displayed
+func (p *workloadPanel) visibleWorkloads() []model.WorkloadModel {
+	workloads := p.currentData
+	if p.filter.IsFiltering() && p.filter.Text != "" {
+		workloads = nil
+		for _, w := range p.currentData {
+			if p.filter.MatchesRow(p.getWorkloadCells(w)) {
+				workloads = append(workloads, w)
+			}
+		}
+	}
+	model.SortWorkloadModelsBy(workloads, p.sortColumn, p.sortAsc)
+	return workloads
+}
+
+func (p *workloadPanel) Layout(_ interface{}) {
+	if !p.laidout {
+		p.list = tview.NewTable()
+		p.list.SetFixed(1, 0)
+		p.list.SetBorder(false)
+		p.list.SetBorders(false)
+		p.list.SetFocusFunc(func() {
+			p.list.SetSelectable(true, false)
+			p.list.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorLightGray).Foreground(tcell.ColorBlack))
+			p.list.Select(1, 0)
+		})
+		p.list.SetBlurFunc(func() {
+			p.list.SetSelectable(false, false)
+		})
+
+		// Same key handling as the pods panel: filter editing first, then
+		// ESC to clear an active filter, Enter to drill down and sort keys
+		p.list.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if p.filter.Editing {
+				switch event.Key() {
+				case tcell.KeyEscape:
+					p.filter.Cancel()
+					p.redrawWithFilter()
+					return nil
+				case tcell.KeyEnter:
+					p.filter.Confirm()
+					p.redrawWithFilter()
+					return nil
+				case tcell.KeyBackspace, tcell.KeyBackspace2:
+					if p.filter.HandleBackspace() {
+						p.redrawWithFilter()
+					}
+					return nil
+				case tcell.KeyRune:
+					p.filter.AppendChar(event.Rune())
+					p.redrawWithFilter()
+					return nil
+				}
+				return nil
+			}
+
+			if event.Key() == tcell.KeyEscape && p.filter.Active {
+				p.filter.Clear()
+				p.redrawWithFilter()
+				return nil
+			}
+
+			if event.Key() == tcell.KeyRune && event.Rune() == '/' {
+				p.filter.StartEditing()
+				p.redrawWithFilter()
+				return nil
+			}
+
+			// Drill into the workload's pods
+			if event.Key() == tcell.KeyEnter {
+				row, _ := p.list.GetSelection()
+				workloads := p.visibleWorkloads()
+				if row > 0 && row-1 < len(workloads) && p.onWorkloadSelected != nil {
+					w := workloads[row-1]
+					p.onWorkloadSelected(w.Kind, w.Namespace, w.Name)
+					return nil
+				}
+			}
+
+			if event.Key() == tcell.KeyRune {
+				if p.handleSortKey(event.Rune()) {
+					return nil
+				}
+			}
+			return event
+		})
+
+		p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(p.list, 0, 1, true)
+		p.root.SetBorder(true)
+		p.root.SetTitle(p.GetTitle())
+		p.root.SetTitleAlign(tview.AlignLeft)
+		p.laidout = true
+	}
+}
+
+// workloadColumnKeys maps column names to their sort shortcut
+var workloadColumnKeys = map[string]rune{
+	"KIND":      'k',
+	"NAMESPACE": 'n',
+	"WORKLOAD":  'w',
+	"READY":     'r',
+	"PODS":      'p',
+	"AGE":       'a',
+	"CPU":       'c',
+	"MEMORY":    'm',
+}
+
+// formatColumnHeader highlights the column's shortcut key and adds the sort indicator
+func (p *workloadPanel) formatColumnHeader(col string) string {
+	return formatSortableHeader(col, workloadColumnKeys[col], p.sortColumn, p.sortAsc)
+}
+
+// formatSortableHeader highlights key (a lowercase shortcut) at its first
+// uppercase occurrence in col, falling back to the first character, and
+// appends the sort indicator when col is the sort column.
+func formatSortableHeader(col string, key rune, sortColumn string, sortAsc bool) string {
+	if len(col) == 0 {
+		return col
+	}
+
+	keyPos := 0
+	if i := strings.IndexRune(col, unicode.ToUpper(key)); key != 0 && i >= 0 {
+		keyPos = i
+	}
+	formatted := fmt.Sprintf("%s[%s::b]%c[%s::-]%s",
+		col[:keyPos], ui.Theme.HeaderShortcutKey, col[keyPos], ui.Theme.HeaderForeground, col[keyPos+1:])
+
+	if col == sortColumn {
+		if sortAsc {
+			formatted += " ▲"
+		} else {
+			formatted += " ▼"
+		}
+	}
+	return formatted
+}
+
+func (p *workloadPanel) DrawHeader(data interface{}) {
+	cols, ok := data.([]string)
+	if !ok {
+		panic(fmt.Sprintf("workloadPanel.DrawHeader got unexpected data type %T", data))
+	}
+
+	p.colMap = make(map[string]int)
+	p.listCols = cols
+
+	for i, col := range p.listCols {
+		p.list.SetCell(0, i,
+			tview.NewTableCell(p.formatColumnHeader(col)).
+				SetTextColor(tcell.ColorWhite).
+				SetBackgroundColor(tcell.ColorDarkCyan).
+				SetAlign(tview.AlignLeft).
+				SetExpansion(100).
+				SetSelectable(true),
+		)
+		p.colMap[col] = i
+	}
+	p.list.SetFixed(1, 0)
+}
+
+// toggleSort toggles the sort column and direction
+func (p *workloadPanel) toggleSort(columnName string) {
+	if columnName == p.sortColumn {
+		p.sortAsc = !p.sortAsc
+	} else {
+		p.sortColumn = columnName
+		p.sortAsc = true
+	}
+
+	if len(p.currentData) > 0 {
+		p.list.Clear()
+		p.DrawHeader(p.listCols)
+		p.DrawBody(p.currentData)
+		if p.app != nil {
+			p.app.Refresh()
+		}
+	}
+}
+
+// handleSortKey processes keyboard shortcuts for sorting
+// Returns true if the key was handled, false otherwise
+func (p *workloadPanel) handleSortKey(key rune) bool {
+	for _, col := range p.listCols {
+		if workloadColumnKeys[col] == key {
+			p.toggleSort(col)
+			return true
+		}
+	}
+	return false
+}
+
+func (p *workloadPanel) DrawBody(data interface{}) {
+	workloads, ok := data.([]model.WorkloadModel)
+	if !ok {
+		panic(fmt.Sprintf("workloadPanel.DrawBody got unexpected type %T", data))
+	}
+
+	p.currentData = workloads
+	p.filter.TotalRows = len(workloads)
+	filtered := p.visibleWorkloads()
+	p.filter.MatchRows = len(filtered)
+
+	p.updateTitle(len(filtered))
+
+	for i, w := range filtered {
+		rowIdx := i + 1 // offset for header row
+		rowColor := workloadRowColor(w)
+
+		for _, colName := range p.listCols {
+			colIdx, exists := p.colMap[colName]
+			if !exists {
+				continue
+			}
+
+			var text string
+			maxWidth := 0
+			switch colName {
+			case "KIND":
+				text, maxWidth = w.Kind, 11
+			case "NAMESPACE":
+				text, maxWidth = w.Namespace, 14
+			case "WORKLOAD":
+				text, maxWidth = w.Name, 36
+			case "READY":
+				text, maxWidth = fmt.Sprintf("%d/%d", w.ReadyReplicas, w.DesiredReplicas), 7
+			case "PODS":
+				text, maxWidth = fmt.Sprintf("%d", len(w.Pods)), 4
+			case "AGE":
+				text, maxWidth = w.TimeSince, 5
+			case "CPU":
+				text = formatUsageOfRequest(
+					fmt.Sprintf("%5dm", quantityMilli(w.UsageCpuQty)),
+					fmt.Sprintf("%5dm", quantityMilli(w.RequestedCpuQty)),
+					quantityMilli(w.UsageCpuQty), quantityMilli(w.RequestedCpuQty),
+				)
+			case "MEMORY":
+				text = formatUsageOfRequest(
+					ui.FormatMemory(w.UsageMemQty),
+					ui.FormatMemory(w.RequestedMemQty),
+					quantityValue(w.UsageMemQty), quantityValue(w.RequestedMemQty),
+				)
+			}
+
+			p.list.SetCell(rowIdx, colIdx, &tview.TableCell{
+				Text:     text,
+				Color:    rowColor,
+				Align:    tview.AlignLeft,
+				MaxWidth: maxWidth,
+			})
+		}
+	}
+}
+
+// workloadRowColor highlights workloads with fewer ready replicas than desired
+func workloadRowColor(w model.WorkloadModel) tcell.Color {
+	switch {
+	case w.DesiredReplicas > 0 && w.ReadyReplicas == 0:
+		return ui.GetTcellColor(ui.Theme.StatusError)
+	case w.ReadyReplicas < w.DesiredReplicas:
+		return ui.GetTcellColor(ui.Theme.StatusWarning)
+	default:
+		return tcell.ColorYellow
+	}
+}
+
+// formatUsageOfRequest renders "usage / request" with usage as a percentage
+// of the request; workloads without requests show usage only.
+func formatUsageOfRequest(usageText, requestText string, usage, request int64) string {
+	if request <= 0 {
+		return fmt.Sprintf("%s /      -", usageText)
+	}
+	pct := float64(usage) / float64(request) * 100
+	return fmt.Sprintf("%s / %s [%s]%5.1f%%[white]", usageText, requestText, ui.GetResourcePercentageColor(pct), pct)
+}
+
+func quantityMilli(q *resource.Quantity) int64 {
+	if q == nil {
+		return 0
+	}
+	return q.MilliValue()
+}
+
+func quantityValue(q *resource.Quantity) int64 {
+	if q == nil {
+		return 0
+	}
+	return q.Value()
+}
+
+func (p *workloadPanel) DrawFooter(_ interface{}) {}
+
+func (p *workloadPanel) Clear() {
+	p.list.Clear()
+	p.Layout(nil)
+	p.DrawHeader(p.listCols)
+}
+
+func (p *workloadPanel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+func (p *workloadPanel) GetChildrenViews() []tview.Primitive {
+	return p.children
+}
+
+// SetFocused implements ui.FocusablePanel - updates visual focus state
+func (p *workloadPanel) SetFocused(focused bool) {
+	ui.SetFlexFocused(p.root, focused)
+}
+
+// getWorkloadCells extracts text values from a workload model for filter matching
+func (p *workloadPanel) getWorkloadCells(w model.WorkloadModel) []string {
+	return []string{
+		w.Kind,
+		w.Namespace,
+		w.Name,
+		w.TimeSince,
+	}
+}
+
+// redrawWithFilter redraws the table with current filter applied
+func (p *workloadPanel) redrawWithFilter() {
+	p.list.Clear()
+	p.DrawHeader(p.listCols)
+	if p.currentData != nil {
+		p.DrawBody(p.currentData)
+	} else {
+		p.updateTitle(0)
+	}
+	if p.app != nil {
+		p.app.Refresh()
+	}
+}
+
+// HasEscapableState implements ui.EscapablePanel
+func (p *workloadPanel) HasEscapableState() bool {
+	return p.filter.HasEscapableState()
+}
+
+// HandleEscape implements ui.EscapablePanel - handles ESC key press
+func (p *workloadPanel) HandleEscape() bool {
+	if p.filter.Editing {
+		p.filter.Cancel()
+		p.redrawWithFilter()
+		return true
+	}
+	if p.filter.Active {
+		p.filter.Clear()
+		p.redrawWithFilter()
+		return true
+	}
+	return false
+}
+
+// updateTitle updates the panel title with scroll position indicator and filter state
+func (p *workloadPanel) updateTitle(totalRows int) {
+	_, _, _, height := p.list.GetInnerRect()
+	visibleRows := height - 1 // Subtract header row
+	offset, _ := p.list.GetOffset()
+
+	var disconnectedSuffix string
+	if p.app.IsAPIDisconnected() {
+		disconnectedSuffix = " [red][DISCONNECTED - Press R to reconnect][-]"
+	}
+
+	firstVisible := offset + 1
+	lastVisible := min(offset+visibleRows, totalRows)
+	if totalRows == 0 {
+		firstVisible = 0
+		lastVisible = 0
+	}
+
+	var scrollIndicator string
+	hasAbove := offset > 0
+	hasBelow := (offset + visibleRows) < totalRows
+	if hasAbove && hasBelow {
+		scrollIndicator = " ↑↓"
+	} else if hasAbove {
+		scrollIndicator = " ↑"
+	} else if hasBelow {
+		scrollIndicator = " ↓"
+	}
+
+	title := p.filter.FormatTitleWithScroll("Workloads", ui.Icons.Rocket, firstVisible, lastVisible, totalRows, scrollIndicator, disconnectedSuffix)
+	p.root.SetTitle(title)
+}
@@ -67,6 +67,8 @@ type DetailPanel struct {
 	// Callbacks
 	onNodeNavigate        NodeNavigationCallback
 	onContainerSelected   ContainerSelectedCallback
+	onPortForward         func(pod *corev1.Pod)
+	onActions             func(pod *corev1.Pod)
 	onBack                func()
 	onFooterContextChange func(focusedPanel string)
 }
@@ -88,6 +90,16 @@ func (p *DetailPanel) SetOnContainerSelected(callback ContainerSelectedCallback)
 	p.onContainerSelected = callback
 }
 
+// SetOnPortForward sets the callback for forwarding a port of the pod
+func (p *DetailPanel) SetOnPortForward(callback func(pod *corev1.Pod)) {
+	p.onPortForward = callback
+}
+
+// SetOnActions sets the callback for the actions on the pod
+func (p *DetailPanel) SetOnActions(callback func(pod *corev1.Pod)) {
+	p.onActions = callback
+}
+
 // SetOnBack sets the callback for when user navigates back
 func (p *DetailPanel) SetOnBack(callback func()) {
 	p.onBack = callback
@@ -219,6 +231,16 @@ func (p *DetailPanel) Layout(_ interface{}) {
 						p.onNodeNavigate(p.data.PodModel.Node)
 						return nil
 					}
+				case 'f', 'F':
+					if p.data != nil && p.data.Pod != nil && p.onPortForward != nil {
+						p.onPortForward(p.data.Pod)
+						return nil
+					}
+				case 'x', 'X':
+					if p.data != nil && p.data.Pod != nil && p.onActions != nil {
+						p.onActions(p.data.Pod)
+						return nil
+					}
 				}
 			}
 			return event
@@ -263,6 +285,18 @@ func (p *DetailPanel) Layout(_ interface{}) {
 						return nil
 					}
 				}
+				if event.Rune() == 'f' || event.Rune() == 'F' {
+					if p.data != nil && p.data.Pod != nil && p.onPortForward != nil {
+						p.onPortForward(p.data.Pod)
+						return nil
+					}
+				}
+				if event.Rune() == 'x' || event.Rune() == 'X' {
+					if p.data != nil && p.data.Pod != nil && p.onActions != nil {
+						p.onActions(p.data.Pod)
+						return nil
+					}
+				}
 			}
 			return event
 		})
@@ -403,7 +437,7 @@ func (p *DetailPanel) drawSparklines() {
 	pod := p.data.PodModel
 
 	// Update prometheus mode based on metrics source type
-	prometheusMode := (p.data.MetricsSourceType == metrics.SourceTypePrometheus)
+	prometheusMode := metrics.IsPrometheusSource(p.data.MetricsSourceType)
 	p.sparklineRow.SetPrometheusMode(prometheusMode)
 
 	// Calculate CPU and MEM ratios
@@ -5,7 +5,6 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"strings"
 	"sync"
 	"time"
 
@@ -32,6 +31,8 @@ type LogsPanel struct {
 	following     bool
 	timestamps    bool
 	wrapText      bool
+	structured    bool         // render JSON and logfmt lines as columns
+	layout        ui.LogLayout // fields promoted, and whether the others are shown
 	tailLines     int64
 	lineCount     int
 
@@ -58,6 +59,13 @@ func NewLogsPanel() *LogsPanel {
 	return p
 }
 
+// SetStructuredLogs sets whether JSON and logfmt lines start out rendered
+// as columns, and the fields promoted next to their message
+func (p *LogsPanel) SetStructuredLogs(on bool, fields []string) {
+	p.structured = on
+	p.layout.Fields = fields
+}
+
 // SetOnBack sets the callback for when user wants to go back
 func (p *LogsPanel) SetOnBack(callback func()) {
 	p.onBack = callback
@@ -175,6 +183,7 @@ func (p *LogsPanel) buildFooter() {
 		{"p", "previous"},
 		{"t", "timestamps"},
 		{"w", "wrap"},
+		{"j", "structured"},
 		{"g/G", "top/bottom"},
 		{"ESC", "back"},
 	}
@@ -212,6 +221,9 @@ func (p *LogsPanel) setupInputCapture() {
 			case 'w', 'W':
 				p.toggleWrap()
 				return nil
+			case 'o', 'O':
+				p.toggleStructured()
+				return nil
 			case 'g':
 				p.logsView.ScrollToBeginning()
 				return nil
@@ -260,6 +272,19 @@ func (p *LogsPanel) toggleTimestamps() {
 	p.startLogStream(false)
 }
 
+// toggleStructured switches between raw lines and JSON and logfmt lines
+// rendered as columns. Lines aren't kept, so the stream starts over.
+func (p *LogsPanel) toggleStructured() {
+	p.streamMu.Lock()
+	p.structured = !p.structured
+	p.streamMu.Unlock()
+
+	p.stopStream()
+	p.logsView.Clear()
+	p.lineCount = 0
+	p.startLogStream(false)
+}
+
 func (p *LogsPanel) toggleWrap() {
 	p.wrapText = !p.wrapText
 	p.logsView.SetWrap(p.wrapText)
@@ -291,6 +316,10 @@ func (p *LogsPanel) startLogStream(previous bool) {
 
 	namespace := p.namespace
 	podName := p.podName
+	var layout *ui.LogLayout
+	if p.structured {
+		layout = &ui.LogLayout{Fields: p.layout.Fields}
+	}
 	p.streamMu.Unlock()
 
 	go func() {
@@ -311,8 +340,7 @@ func (p *LogsPanel) startLogStream(previous bool) {
 			case <-ctx.Done():
 				return
 			default:
-				line := scanner.Text()
-				p.appendLog(p.formatLogLine(line))
+				p.appendLog(ui.FormatLogLine(scanner.Text(), layout, nil))
 			}
 		}
 
@@ -337,29 +365,6 @@ func (p *LogsPanel) stopStream() {
 	}
 }
 
-func (p *LogsPanel) formatLogLine(line string) string {
-	// Color timestamps if present (RFC3339 format: 2024-01-15T10:30:00Z)
-	if p.timestamps && len(line) > 30 && line[4] == '-' && line[7] == '-' {
-		// Try to find the timestamp separator
-		if idx := strings.Index(line, " "); idx > 0 && idx < 35 {
-			timestamp := line[:idx]
-			rest := line[idx+1:]
-			return fmt.Sprintf("[gray]%s[white] %s", timestamp, rest)
-		}
-	}
-
-	// Color error/warning lines
-	lineLower := strings.ToLower(line)
-	if strings.Contains(lineLower, "error") || strings.Contains(lineLower, "fatal") {
-		return "[red]" + line
-	}
-	if strings.Contains(lineLower, "warn") {
-		return "[yellow]" + line
-	}
-
-	return line
-}
-
 func (p *LogsPanel) appendLog(line string) {
 	p.streamMu.Lock()
 	p.lineCount++
@@ -0,0 +1,273 @@
+package portforward
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/portforward"
+	"github.com/vladimirvivien/ktop/ui"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+var headers = []string{"LOCAL", "POD", "CONTAINER", "PORT", "STATE", "IN", "OUT", "AGE"}
+
+// Panel lists the port forwards and starts, stops and removes them
+type Panel struct {
+	root    *tview.Flex
+	laidout bool
+
+	table  *tview.Table
+	detail *tview.TextView
+
+	manager  *portforward.Manager
+	forwards []portforward.Forward
+
+	setAppFocus func(p tview.Primitive)
+
+	// Callbacks
+	onSelected func(namespace, podName string)
+	onBack     func()
+}
+
+// NewPanel creates a new port forwards panel
+func NewPanel() *Panel {
+	p := &Panel{}
+	p.Layout(nil)
+	return p
+}
+
+// SetOnSelected sets the callback for when the pod of a forward is selected
+func (p *Panel) SetOnSelected(callback func(namespace, podName string)) {
+	p.onSelected = callback
+}
+
+// SetOnBack sets the callback for when user navigates back
+func (p *Panel) SetOnBack(callback func()) {
+	p.onBack = callback
+}
+
+// SetAppFocus sets the callback used to focus primitives in the tview app
+func (p *Panel) SetAppFocus(fn func(p tview.Primitive)) {
+	p.setAppFocus = fn
+}
+
+// GetTitle returns the panel title
+func (p *Panel) GetTitle() string {
+	return "Port Forwards"
+}
+
+// Layout initializes the panel UI
+func (p *Panel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.detail = tview.NewTextView().SetDynamicColors(true)
+
+	p.table = tview.NewTable()
+	p.table.SetFixed(1, 0) // Fixed header row
+	p.table.SetSelectable(true, false)
+	p.table.SetBorder(false)
+	p.table.SetBorders(false)
+	p.table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorLightGray).Foreground(tcell.ColorBlack))
+	p.table.SetSelectionChangedFunc(func(row, _ int) {
+		p.drawDetail(row)
+	})
+	p.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			p.HandleEscape()
+			return nil
+		case tcell.KeyEnter:
+			if f, ok := p.selected(); ok && p.onSelected != nil {
+				p.onSelected(f.Namespace, f.Pod)
+				return nil
+			}
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 's', 'S':
+				p.toggleSelected()
+				return nil
+			case 'd', 'D':
+				if f, ok := p.selected(); ok {
+					p.manager.Remove(f.ID)
+					p.draw()
+				}
+				return nil
+			}
+		}
+		return event
+	})
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.table, 0, 1, true).
+		AddItem(p.detail, 2, 0, false)
+	p.root.SetBorder(true)
+	p.root.SetTitle(fmt.Sprintf(" %s Port Forwards ", ui.Icons.Plane))
+	p.root.SetTitleAlign(tview.AlignCenter)
+	p.laidout = true
+}
+
+// toggleSelected stops the selected forward when it runs and starts it
+// again otherwise
+func (p *Panel) toggleSelected() {
+	f, ok := p.selected()
+	if !ok {
+		return
+	}
+	switch f.State {
+	case portforward.StateStarting, portforward.StateActive:
+		p.manager.Stop(f.ID)
+	default:
+		p.manager.Resume(f.ID)
+	}
+	p.draw()
+}
+
+// DrawHeader draws the header row
+func (p *Panel) DrawHeader(_ interface{}) {}
+
+// DrawBody draws the forwards of a *portforward.Manager
+func (p *Panel) DrawBody(data interface{}) {
+	manager, ok := data.(*portforward.Manager)
+	if !ok || manager == nil {
+		return
+	}
+	p.manager = manager
+	p.draw()
+}
+
+func (p *Panel) draw() {
+	if p.manager == nil {
+		return
+	}
+	// Keep the selection on the forward it was on
+	selectedID := 0
+	if f, ok := p.selected(); ok {
+		selectedID = f.ID
+	}
+	p.forwards = p.manager.List()
+	p.root.SetTitle(fmt.Sprintf(" %s Port Forwards (%d active) ", ui.Icons.Plane, p.manager.Active()))
+
+	p.table.Clear()
+	for col, header := range headers {
+		p.table.SetCell(0, col, tview.NewTableCell(header).
+			SetTextColor(tcell.ColorWhite).
+			SetBackgroundColor(tcell.ColorDarkCyan).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+
+	now := time.Now()
+	row := 1
+	for i, f := range p.forwards {
+		stateColor := tcell.ColorGray
+		switch f.State {
+		case portforward.StateActive:
+			stateColor = tcell.ColorGreen
+		case portforward.StateStarting:
+			stateColor = tcell.ColorYellow
+		case portforward.StateFailed:
+			stateColor = tcell.ColorRed
+		}
+		local := "-"
+		if f.LocalPort != 0 {
+			local = fmt.Sprintf("localhost:%d", f.LocalPort)
+		}
+		port := fmt.Sprintf("%d", f.RemotePort)
+		if f.PortName != "" {
+			port += "/" + f.PortName
+		}
+		cells := []*tview.TableCell{
+			tview.NewTableCell(local).SetTextColor(tcell.ColorWhite),
+			tview.NewTableCell(f.Namespace + "/" + f.Pod).SetTextColor(tcell.ColorWhite).SetMaxWidth(50),
+			tview.NewTableCell(f.Container).SetTextColor(tcell.ColorWhite),
+			tview.NewTableCell(port).SetTextColor(tcell.ColorWhite),
+			tview.NewTableCell(f.State.String()).SetTextColor(stateColor),
+			tview.NewTableCell(ui.FormatBytes(f.BytesIn)).SetTextColor(tcell.ColorWhite),
+			tview.NewTableCell(ui.FormatBytes(f.BytesOut)).SetTextColor(tcell.ColorWhite),
+			tview.NewTableCell(duration.HumanDuration(now.Sub(f.StartedAt))).SetTextColor(tcell.ColorGray),
+		}
+		for col, cell := range cells {
+			p.table.SetCell(i+1, col, cell)
+		}
+		if f.ID == selectedID {
+			row = i + 1
+		}
+	}
+
+	if len(p.forwards) == 0 {
+		p.detail.SetText(" [gray]No port forwards. Press f on Pod Detail to forward a container port.")
+		return
+	}
+	p.table.Select(row, 0)
+	p.drawDetail(row)
+}
+
+// selected returns the forward on the selected row
+func (p *Panel) selected() (portforward.Forward, bool) {
+	row, _ := p.table.GetSelection()
+	if row < 1 || row > len(p.forwards) {
+		return portforward.Forward{}, false
+	}
+	return p.forwards[row-1], true
+}
+
+// drawDetail shows where the forward on row goes, and why it failed
+func (p *Panel) drawDetail(row int) {
+	if row < 1 || row > len(p.forwards) {
+		p.detail.SetText("")
+		return
+	}
+	f := p.forwards[row-1]
+	text := " [white]" + tview.Escape(f.Target())
+	if f.LocalPort != 0 {
+		text = fmt.Sprintf(" [white]localhost:%d[gray] → [white]%s", f.LocalPort, tview.Escape(f.Target()))
+	}
+	if f.Err != nil {
+		text += "\n [red]" + tview.Escape(f.Err.Error())
+	}
+	p.detail.SetText(text)
+}
+
+// DrawFooter draws the footer
+func (p *Panel) DrawFooter(_ interface{}) {}
+
+// Clear clears the panel
+func (p *Panel) Clear() {
+	p.forwards = nil
+	p.table.Clear()
+}
+
+// GetRootView returns the root view
+func (p *Panel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+// GetChildrenViews returns child views
+func (p *Panel) GetChildrenViews() []tview.Primitive {
+	return []tview.Primitive{p.table}
+}
+
+// InitFocus focuses the table
+func (p *Panel) InitFocus() {
+	if p.setAppFocus != nil {
+		p.setAppFocus(p.table)
+	}
+}
+
+// HasEscapableState implements ui.EscapablePanel
+func (p *Panel) HasEscapableState() bool {
+	return true // Always allow ESC to go back
+}
+
+// HandleEscape implements ui.EscapablePanel
+func (p *Panel) HandleEscape() bool {
+	if p.onBack != nil {
+		p.onBack()
+		return true
+	}
+	return false
+}
@@ -0,0 +1,384 @@
+package pressure
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/ui"
+	"github.com/vladimirvivien/ktop/views/model"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// Throttling at or above these percentages is shown in yellow and red
+const (
+	throttledWarning  = 25.0
+	throttledCritical = 50.0
+)
+
+// ContainerSelectedCallback is called when a container row is selected
+type ContainerSelectedCallback func(namespace, podName, containerName string)
+
+// column is a table column; key sorts the table by it
+type column struct {
+	name string
+	key  rune
+}
+
+var (
+	throttledColumns = []column{
+		{"NAMESPACE", 'n'}, {"POD", 'p'}, {"CONTAINER", 'c'}, {"THROTTLED", 't'}, {"CPU LIM", 'l'},
+	}
+	oomColumns = []column{
+		{"NAMESPACE", 'n'}, {"POD", 'p'}, {"CONTAINER", 'c'}, {"OOM EVENTS", 'o'},
+		{"REASON", 'r'}, {"EXIT", 'x'}, {"FINISHED", 'f'}, {"RST", 't'}, {"MEM LIM", 'm'},
+	}
+)
+
+// sortState is the sort column and direction of a table
+type sortState struct {
+	column    string
+	ascending bool
+}
+
+// toggle sorts by the column with key, or reverses the direction when it
+// already sorts by it. It reports whether key belongs to a column.
+func (s *sortState) toggle(columns []column, key rune) bool {
+	for _, col := range columns {
+		if col.key != key {
+			continue
+		}
+		if s.column == col.name {
+			s.ascending = !s.ascending
+		} else {
+			s.column, s.ascending = col.name, true
+		}
+		return true
+	}
+	return false
+}
+
+// Panel lists the containers throttled by their CPU limit and the
+// containers that ran out of memory
+type Panel struct {
+	root    *tview.Flex
+	laidout bool
+
+	data *model.PressureData
+
+	message        *tview.TextView
+	throttledPanel *tview.Flex
+	throttledTable *tview.Table
+	throttledSort  sortState
+	oomPanel       *tview.Flex
+	oomTable       *tview.Table
+	oomSort        sortState
+	focusIdx       int // 0 = throttled, 1 = OOM killed
+
+	setAppFocus func(p tview.Primitive)
+
+	// Callbacks
+	onSelected ContainerSelectedCallback
+	onBack     func()
+}
+
+// NewPanel creates a new pressure panel
+func NewPanel() *Panel {
+	p := &Panel{
+		throttledSort: sortState{column: "THROTTLED"},
+		oomSort:       sortState{column: "FINISHED"},
+	}
+	p.Layout(nil)
+	return p
+}
+
+// SetOnSelected sets the callback for when a container is selected
+func (p *Panel) SetOnSelected(callback ContainerSelectedCallback) {
+	p.onSelected = callback
+}
+
+// SetOnBack sets the callback for when user navigates back
+func (p *Panel) SetOnBack(callback func()) {
+	p.onBack = callback
+}
+
+// SetAppFocus sets the callback used to focus primitives in the tview app
+func (p *Panel) SetAppFocus(fn func(p tview.Primitive)) {
+	p.setAppFocus = fn
+}
+
+// GetTitle returns the panel title
+func (p *Panel) GetTitle() string {
+	return "Pressure"
+}
+
+// Layout initializes the panel UI
+func (p *Panel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.message = tview.NewTextView().SetDynamicColors(true)
+
+	p.throttledTable = p.newTable(throttledColumns, &p.throttledSort, func(row int) (model.ContainerKey, bool) {
+		if p.data == nil || row < 1 || row > len(p.data.Throttled) {
+			return model.ContainerKey{}, false
+		}
+		return p.data.Throttled[row-1].ContainerKey, true
+	})
+	p.throttledPanel = tview.NewFlex().SetDirection(tview.FlexRow)
+	p.throttledPanel.SetBorder(true)
+	p.throttledPanel.SetTitle(" CPU Throttled ")
+	p.throttledPanel.SetTitleAlign(tview.AlignLeft)
+	p.throttledPanel.AddItem(p.throttledTable, 0, 1, true)
+
+	p.oomTable = p.newTable(oomColumns, &p.oomSort, func(row int) (model.ContainerKey, bool) {
+		if p.data == nil || row < 1 || row > len(p.data.OOMKilled) {
+			return model.ContainerKey{}, false
+		}
+		return p.data.OOMKilled[row-1].ContainerKey, true
+	})
+	p.oomPanel = tview.NewFlex().SetDirection(tview.FlexRow)
+	p.oomPanel.SetBorder(true)
+	p.oomPanel.SetTitle(" OOM Killed ")
+	p.oomPanel.SetTitleAlign(tview.AlignLeft)
+	p.oomPanel.AddItem(p.oomTable, 0, 1, false)
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.message, 1, 0, false).
+		AddItem(p.throttledPanel, 0, 1, true).
+		AddItem(p.oomPanel, 0, 1, false)
+	p.root.SetBorder(true)
+	p.root.SetTitle(fmt.Sprintf(" %s Pressure ", ui.Icons.Thermometer))
+	p.root.SetTitleAlign(tview.AlignCenter)
+	p.updateFocusColors()
+	p.laidout = true
+}
+
+// newTable creates a table sorted by the column keys; container returns
+// the container shown on a row
+func (p *Panel) newTable(columns []column, sorting *sortState, container func(row int) (model.ContainerKey, bool)) *tview.Table {
+	table := tview.NewTable()
+	table.SetFixed(1, 0) // Fixed header row
+	table.SetSelectable(true, false)
+	table.SetBorder(false)
+	table.SetBorders(false)
+	table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorLightGray).Foreground(tcell.ColorBlack))
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyTab, tcell.KeyBacktab:
+			p.focusIdx = 1 - p.focusIdx
+			p.InitFocus()
+			return nil
+		case tcell.KeyEscape:
+			if p.onBack != nil {
+				p.onBack()
+				return nil
+			}
+		case tcell.KeyEnter:
+			row, _ := table.GetSelection()
+			if key, ok := container(row); ok && p.onSelected != nil {
+				p.onSelected(key.Namespace, key.Pod, key.Container)
+				return nil
+			}
+		case tcell.KeyRune:
+			if sorting.toggle(columns, event.Rune()) {
+				p.DrawBody(p.data)
+				return nil
+			}
+		}
+		return event
+	})
+	return table
+}
+
+// DrawHeader draws the header row
+func (p *Panel) DrawHeader(_ interface{}) {}
+
+// DrawBody draws a *model.PressureData
+func (p *Panel) DrawBody(data interface{}) {
+	pressure, ok := data.(*model.PressureData)
+	if !ok || pressure == nil {
+		return
+	}
+	p.data = pressure
+
+	if pressure.MetricsErr != nil {
+		p.message.SetText(" [yellow]CPU throttling and OOM events unavailable: " + tview.Escape(pressure.MetricsErr.Error()))
+	} else {
+		p.message.SetText(fmt.Sprintf(" [gray]Throttling over the last %s, OOM events over the last %s; press a highlighted letter to sort",
+			duration.HumanDuration(metrics.ThrottleWindow), duration.HumanDuration(metrics.OOMWindow)))
+	}
+
+	model.SortThrottledContainersBy(pressure.Throttled, p.throttledSort.column, p.throttledSort.ascending)
+	p.throttledPanel.SetTitle(fmt.Sprintf(" CPU Throttled (%d) ", len(pressure.Throttled)))
+	drawTable(p.throttledTable, throttledColumns, p.throttledSort, len(pressure.Throttled), func(row int) []*tview.TableCell {
+		c := pressure.Throttled[row]
+		color := tcell.ColorWhite
+		switch {
+		case c.ThrottledPercent >= throttledCritical:
+			color = tcell.ColorRed
+		case c.ThrottledPercent >= throttledWarning:
+			color = tcell.ColorYellow
+		}
+		limit := "-"
+		if c.CPULimit != nil {
+			limit = fmt.Sprintf("%dm", c.CPULimit.MilliValue())
+		}
+		return []*tview.TableCell{
+			tview.NewTableCell(c.Namespace),
+			tview.NewTableCell(c.Pod),
+			tview.NewTableCell(c.Container),
+			tview.NewTableCell(fmt.Sprintf("%.1f%%", c.ThrottledPercent)).SetTextColor(color),
+			tview.NewTableCell(limit),
+		}
+	})
+
+	model.SortOOMKilledContainersBy(pressure.OOMKilled, p.oomSort.column, p.oomSort.ascending)
+	p.oomPanel.SetTitle(fmt.Sprintf(" OOM Killed (%d) ", len(pressure.OOMKilled)))
+	drawTable(p.oomTable, oomColumns, p.oomSort, len(pressure.OOMKilled), func(row int) []*tview.TableCell {
+		c := pressure.OOMKilled[row]
+		events := "-"
+		if pressure.MetricsErr == nil {
+			events = fmt.Sprintf("%.0f", c.OOMEvents)
+		}
+		reason, exit, finished := "-", "-", "-"
+		if c.Reason != "" {
+			reason = c.Reason
+			exit = fmt.Sprintf("%d", c.ExitCode)
+		}
+		if !c.FinishedAt.IsZero() {
+			finished = duration.HumanDuration(time.Since(c.FinishedAt)) + " ago"
+		}
+		reasonColor := tcell.ColorWhite
+		if reason == "OOMKilled" {
+			reasonColor = tcell.ColorRed
+		}
+		limit := "-"
+		if c.MemoryLimit != nil {
+			limit = ui.FormatMemory(c.MemoryLimit)
+		}
+		return []*tview.TableCell{
+			tview.NewTableCell(c.Namespace),
+			tview.NewTableCell(c.Pod),
+			tview.NewTableCell(c.Container),
+			tview.NewTableCell(events),
+			tview.NewTableCell(reason).SetTextColor(reasonColor),
+			tview.NewTableCell(exit),
+			tview.NewTableCell(finished).SetTextColor(tcell.ColorGray),
+			tview.NewTableCell(fmt.Sprintf("%d", c.Restarts)),
+			tview.NewTableCell(limit),
+		}
+	})
+}
+
+// drawTable draws rows rows of cells into table, under headers showing the
+// sort keys and order
+func drawTable(table *tview.Table, columns []column, sorting sortState, rows int, cells func(row int) []*tview.TableCell) {
+	// Save current selection before clearing
+	selectedRow, selectedCol := table.GetSelection()
+
+	table.Clear()
+	for col, c := range columns {
+		table.SetCell(0, col, tview.NewTableCell(formatHeader(c, sorting)).
+			SetTextColor(tcell.ColorWhite).
+			SetBackgroundColor(tcell.ColorDarkCyan).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+	for row := 0; row < rows; row++ {
+		for col, cell := range cells(row) {
+			table.SetCell(row+1, col, cell)
+		}
+	}
+
+	// Restore selection (clamped to valid range)
+	if rows == 0 {
+		return
+	}
+	if selectedRow < 1 {
+		selectedRow = 1
+	} else if selectedRow > rows {
+		selectedRow = rows
+	}
+	table.Select(selectedRow, selectedCol)
+}
+
+// formatHeader highlights the sort key of a column header and marks the
+// column the table is sorted by
+func formatHeader(c column, sorting sortState) string {
+	header := c.name
+	if pos := strings.IndexRune(strings.ToLower(c.name), c.key); pos >= 0 {
+		header = fmt.Sprintf("%s[%s::b]%s[%s::-]%s",
+			c.name[:pos], ui.Theme.HeaderShortcutKey, c.name[pos:pos+1], ui.Theme.HeaderForeground, c.name[pos+1:])
+	}
+	if c.name == sorting.column {
+		if sorting.ascending {
+			header += " ▲"
+		} else {
+			header += " ▼"
+		}
+	}
+	return header
+}
+
+// updateFocusColors highlights the border of the focused table
+func (p *Panel) updateFocusColors() {
+	p.throttledPanel.SetBorderColor(tcell.ColorLightGray)
+	p.oomPanel.SetBorderColor(tcell.ColorLightGray)
+	if p.focusIdx == 0 {
+		p.throttledPanel.SetBorderColor(tcell.ColorDodgerBlue)
+	} else {
+		p.oomPanel.SetBorderColor(tcell.ColorDodgerBlue)
+	}
+}
+
+// DrawFooter draws the footer
+func (p *Panel) DrawFooter(_ interface{}) {}
+
+// Clear clears the panel
+func (p *Panel) Clear() {
+	p.throttledTable.Clear()
+	p.oomTable.Clear()
+	p.data = nil
+}
+
+// GetRootView returns the root view
+func (p *Panel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+// GetChildrenViews returns child views
+func (p *Panel) GetChildrenViews() []tview.Primitive {
+	return []tview.Primitive{p.throttledTable, p.oomTable}
+}
+
+// InitFocus focuses the table selected with Tab, the throttled table at first
+func (p *Panel) InitFocus() {
+	p.updateFocusColors()
+	if p.setAppFocus == nil {
+		return
+	}
+	if p.focusIdx == 0 {
+		p.setAppFocus(p.throttledTable)
+	} else {
+		p.setAppFocus(p.oomTable)
+	}
+}
+
+// HasEscapableState implements ui.EscapablePanel
+func (p *Panel) HasEscapableState() bool {
+	return true // Always allow ESC to go back
+}
+
+// HandleEscape implements ui.EscapablePanel
+func (p *Panel) HandleEscape() bool {
+	if p.onBack != nil {
+		p.onBack()
+		return true
+	}
+	return false
+}
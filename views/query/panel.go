@@ -0,0 +1,365 @@
+package query
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/metrics"
+	"github.com/vladimirvivien/ktop/prom"
+	"github.com/vladimirvivien/ktop/ui"
+)
+
+// maxCompletions limits the autocomplete drop-down
+const maxCompletions = 15
+
+// chartHeight is the number of rows of the chart, border excluded
+const chartHeight = 8
+
+// inGrouping matches text that ends inside the labels of by (...) or
+// without (...)
+var inGrouping = regexp.MustCompile(`\b(by|without)\s*\([^)]*$`)
+
+// Panel is the query console: an expression input with autocompletion, a
+// chart of the selected series and a table of all series
+type Panel struct {
+	root    *tview.Flex
+	laidout bool
+
+	input   *tview.InputField
+	message *tview.TextView
+	chart   *ui.Sparkline
+	table   *tview.Table
+
+	expr        string
+	series      []metrics.QuerySeries
+	metricNames []string
+	labelNames  []string
+
+	setAppFocus func(p tview.Primitive)
+	onSubmit    func(expr string)
+	onBack      func()
+}
+
+// NewPanel creates a new query console panel
+func NewPanel() *Panel {
+	p := &Panel{}
+	p.Layout(nil)
+	return p
+}
+
+// SetOnSubmit sets the callback for when the user runs an expression
+func (p *Panel) SetOnSubmit(callback func(expr string)) {
+	p.onSubmit = callback
+}
+
+// SetOnBack sets the callback for when user navigates back
+func (p *Panel) SetOnBack(callback func()) {
+	p.onBack = callback
+}
+
+// SetAppFocus sets the callback used to focus primitives in the tview app
+func (p *Panel) SetAppFocus(fn func(p tview.Primitive)) {
+	p.setAppFocus = fn
+}
+
+// SetSuggestions sets the metric and label names offered by autocompletion
+func (p *Panel) SetSuggestions(metricNames, labelNames []string) {
+	p.metricNames = metricNames
+	p.labelNames = labelNames
+}
+
+// Expr returns the expression last submitted, or "" before the first one
+func (p *Panel) Expr() string {
+	return p.expr
+}
+
+// GetTitle returns the panel title
+func (p *Panel) GetTitle() string {
+	return "Query"
+}
+
+// Layout initializes the panel UI
+func (p *Panel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.input = tview.NewInputField().
+		SetLabel(" > ").
+		SetLabelColor(tcell.ColorYellow).
+		SetFieldBackgroundColor(tcell.ColorDefault).
+		SetPlaceholder(`e.g. topk(5, sum by (pod) (rate(container_cpu_usage_seconds_total[5m])))`).
+		SetPlaceholderTextColor(tcell.ColorGray)
+	p.input.SetAutocompleteFunc(p.complete)
+	p.input.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			expr := strings.TrimSpace(p.input.GetText())
+			if expr == "" || p.onSubmit == nil {
+				return
+			}
+			p.expr = expr
+			p.message.SetText(" [gray]Running...")
+			p.onSubmit(expr)
+		case tcell.KeyTab, tcell.KeyBacktab, tcell.KeyDown:
+			p.focus(p.table)
+		}
+	})
+
+	p.message = tview.NewTextView().SetDynamicColors(true)
+	p.message.SetText(" [gray]Enter a PromQL expression; metric and label names complete as you type")
+
+	p.chart = ui.NewSparkline().
+		SetBorder(true).
+		SetBorderColor(tcell.ColorLightGray).
+		SetTitleAlign(tview.AlignLeft)
+
+	p.table = tview.NewTable()
+	p.table.SetFixed(1, 0)
+	p.table.SetSelectable(true, false)
+	p.table.SetBorder(true)
+	p.table.SetBorderColor(tcell.ColorLightGray)
+	p.table.SetTitleAlign(tview.AlignLeft)
+	p.table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorLightGray).Foreground(tcell.ColorBlack))
+	p.table.SetSelectionChangedFunc(func(row, _ int) {
+		if row > 0 && row-1 < len(p.series) {
+			p.drawChart(p.series[row-1])
+		}
+	})
+	p.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyTab, tcell.KeyBacktab:
+			p.focus(p.input)
+			return nil
+		case tcell.KeyUp:
+			if row, _ := p.table.GetSelection(); row <= 1 {
+				p.focus(p.input)
+				return nil
+			}
+		}
+		return event
+	})
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.input, 1, 0, true).
+		AddItem(p.message, 1, 0, false).
+		AddItem(p.chart, chartHeight+2, 0, false).
+		AddItem(p.table, 0, 1, false)
+	p.root.SetBorder(true)
+	p.root.SetTitle(fmt.Sprintf(" %s Query ", ui.Icons.Knobs))
+	p.root.SetTitleAlign(tview.AlignCenter)
+	p.laidout = true
+}
+
+func (p *Panel) focus(prim tview.Primitive) {
+	if p.setAppFocus != nil {
+		p.setAppFocus(prim)
+	}
+}
+
+// complete returns the autocompletion entries for text: the identifier it
+// ends with completed to label names inside {...} and by (...), otherwise
+// to metric names and functions. Entries are the whole text, as the input
+// field replaces its text with the selected entry.
+func (p *Panel) complete(text string) []string {
+	start := len(text)
+	for start > 0 && isIdentChar(text[start-1]) {
+		start--
+	}
+	word := text[start:]
+	if word == "" {
+		return nil
+	}
+
+	before := text[:start]
+	candidates := slices.Concat(p.metricNames, prom.QueryKeywords())
+	if strings.Count(before, "{") > strings.Count(before, "}") || inGrouping.MatchString(before) {
+		candidates = p.labelNames
+	}
+
+	var entries []string
+	for _, c := range candidates {
+		if c != word && strings.HasPrefix(c, word) {
+			entries = append(entries, before+c)
+			if len(entries) == maxCompletions {
+				break
+			}
+		}
+	}
+	return entries
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == ':' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// DrawHeader draws the header row
+func (p *Panel) DrawHeader(_ interface{}) {}
+
+// DrawBody draws a []metrics.QuerySeries, or the error the query failed with
+func (p *Panel) DrawBody(data interface{}) {
+	if err, ok := data.(error); ok {
+		p.message.SetText(" [red]" + tview.Escape(err.Error()))
+		return
+	}
+	series, ok := data.([]metrics.QuerySeries)
+	if !ok {
+		return
+	}
+
+	// Highest latest value first, like topk
+	series = append([]metrics.QuerySeries(nil), series...)
+	sort.SliceStable(series, func(i, j int) bool {
+		return latest(series[i]) > latest(series[j])
+	})
+
+	selected := 0
+	if row, _ := p.table.GetSelection(); row > 0 && row-1 < len(p.series) {
+		// Keep the same series selected across refreshes
+		name := seriesName(p.series[row-1])
+		for i, s := range series {
+			if seriesName(s) == name {
+				selected = i
+				break
+			}
+		}
+	}
+	p.series = series
+
+	p.message.SetText(fmt.Sprintf(" [gray]%d series", len(series)))
+	p.table.Clear()
+	p.table.SetTitle(fmt.Sprintf(" Series (%d) ", len(series)))
+	for col, title := range []string{"SERIES", "VALUE", "MIN", "MAX"} {
+		cell := tview.NewTableCell(title).
+			SetTextColor(tcell.ColorYellow).
+			SetAttributes(tcell.AttrBold).
+			SetSelectable(false)
+		if col > 0 {
+			cell.SetAlign(tview.AlignRight)
+		}
+		p.table.SetCell(0, col, cell)
+	}
+	for i, s := range series {
+		lo, hi := bounds(s)
+		p.table.SetCell(i+1, 0, tview.NewTableCell(tview.Escape(seriesName(s))).SetExpansion(1))
+		p.table.SetCell(i+1, 1, tview.NewTableCell(formatValue(latest(s))).SetAlign(tview.AlignRight))
+		p.table.SetCell(i+1, 2, tview.NewTableCell(formatValue(lo)).SetAlign(tview.AlignRight).SetTextColor(tcell.ColorGray))
+		p.table.SetCell(i+1, 3, tview.NewTableCell(formatValue(hi)).SetAlign(tview.AlignRight).SetTextColor(tcell.ColorGray))
+	}
+
+	if len(series) == 0 {
+		p.chart.SetTitle(" No data ")
+		p.chart.Clear()
+		return
+	}
+	p.table.Select(selected+1, 0)
+	p.drawChart(series[selected])
+}
+
+// drawChart charts s scaled to its maximum
+func (p *Panel) drawChart(s metrics.QuerySeries) {
+	lo, hi := bounds(s)
+	p.chart.SetTitle(fmt.Sprintf(" %s  [gray]min %s  max %s ", tview.Escape(seriesName(s)), formatValue(lo), formatValue(hi)))
+
+	_, _, width, _ := p.chart.GetInnerRect()
+	p.chart.SetDimensions(max(width, len(s.DataPoints)), chartHeight)
+	for _, point := range s.DataPoints {
+		v := 0.0
+		if hi > 0 {
+			v = point.Value / hi
+		}
+		p.chart.Push(v)
+	}
+}
+
+// seriesName writes a series as PromQL: metric{label="value", ...}
+func seriesName(s metrics.QuerySeries) string {
+	names := make([]string, 0, len(s.Labels))
+	for name := range s.Labels {
+		if name != "__name__" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(s.Labels["__name__"])
+	b.WriteString("{")
+	for i, name := range names {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "%s=%q", name, s.Labels[name])
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+func latest(s metrics.QuerySeries) float64 {
+	if len(s.DataPoints) == 0 {
+		return math.NaN()
+	}
+	return s.DataPoints[len(s.DataPoints)-1].Value
+}
+
+func bounds(s metrics.QuerySeries) (lo, hi float64) {
+	lo, hi = math.Inf(1), math.Inf(-1)
+	for _, point := range s.DataPoints {
+		lo = math.Min(lo, point.Value)
+		hi = math.Max(hi, point.Value)
+	}
+	return lo, hi
+}
+
+// formatValue writes v with up to four significant digits
+func formatValue(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return "-"
+	}
+	return fmt.Sprintf("%.4g", v)
+}
+
+// DrawFooter draws the footer
+func (p *Panel) DrawFooter(_ interface{}) {}
+
+// Clear clears the results
+func (p *Panel) Clear() {
+	p.series = nil
+	p.table.Clear()
+	p.chart.Clear()
+}
+
+// GetRootView returns the root view
+func (p *Panel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+// GetChildrenViews returns child views
+func (p *Panel) GetChildrenViews() []tview.Primitive {
+	return []tview.Primitive{p.input, p.table}
+}
+
+// InitFocus focuses the expression input
+func (p *Panel) InitFocus() {
+	p.focus(p.input)
+}
+
+// HasEscapableState implements ui.EscapablePanel
+func (p *Panel) HasEscapableState() bool {
+	return true // Always allow ESC to go back
+}
+
+// HandleEscape implements ui.EscapablePanel
+func (p *Panel) HandleEscape() bool {
+	if p.onBack != nil {
+		p.onBack()
+		return true
+	}
+	return false
+}
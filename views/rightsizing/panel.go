@@ -0,0 +1,366 @@
+package rightsizing
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/ui"
+	"github.com/vladimirvivien/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// column is a table column; a non-zero key sorts the table by it
+type column struct {
+	name string
+	key  rune
+}
+
+var columns = []column{
+	{"NAMESPACE", 'n'}, {"WORKLOAD", 'w'}, {"CONTAINER", 0}, {"PODS", 0},
+	{"CPU P95", 'c'}, {"CPU MAX", 0}, {"CPU REQ/LIM", 0}, {"→ REC", 0},
+	{"MEM P95", 'm'}, {"MEM MAX", 0}, {"MEM REQ/LIM", 0}, {"→ REC", 0},
+	{"STATUS", 's'},
+}
+
+// Panel lists the usage of workload containers against their requests and
+// limits, with recommended ones
+type Panel struct {
+	root    *tview.Flex
+	laidout bool
+
+	message *tview.TextView
+	table   *tview.Table
+	reason  *tview.TextView
+
+	data       *model.RightsizingData
+	sortColumn string
+	sortAsc    bool
+
+	setAppFocus func(p tview.Primitive)
+
+	// Callbacks
+	onSelected func(kind, namespace, name string)
+	onExport   func(items []model.Rightsizing)
+	onRefresh  func()
+	onBack     func()
+}
+
+// NewPanel creates a new rightsizing panel
+func NewPanel() *Panel {
+	p := &Panel{sortColumn: "STATUS", sortAsc: true}
+	p.Layout(nil)
+	return p
+}
+
+// SetOnSelected sets the callback for when a workload is selected
+func (p *Panel) SetOnSelected(callback func(kind, namespace, name string)) {
+	p.onSelected = callback
+}
+
+// SetOnExport sets the callback for exporting the recommendations
+func (p *Panel) SetOnExport(callback func(items []model.Rightsizing)) {
+	p.onExport = callback
+}
+
+// SetOnRefresh sets the callback for sampling usage again
+func (p *Panel) SetOnRefresh(callback func()) {
+	p.onRefresh = callback
+}
+
+// SetOnBack sets the callback for when user navigates back
+func (p *Panel) SetOnBack(callback func()) {
+	p.onBack = callback
+}
+
+// SetAppFocus sets the callback used to focus primitives in the tview app
+func (p *Panel) SetAppFocus(fn func(p tview.Primitive)) {
+	p.setAppFocus = fn
+}
+
+// GetTitle returns the panel title
+func (p *Panel) GetTitle() string {
+	return "Rightsizing"
+}
+
+// Layout initializes the panel UI
+func (p *Panel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.message = tview.NewTextView().SetDynamicColors(true)
+	p.reason = tview.NewTextView().SetDynamicColors(true)
+
+	p.table = tview.NewTable()
+	p.table.SetFixed(1, 0) // Fixed header row
+	p.table.SetSelectable(true, false)
+	p.table.SetBorder(false)
+	p.table.SetBorders(false)
+	p.table.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorLightGray).Foreground(tcell.ColorBlack))
+	p.table.SetSelectionChangedFunc(func(row, _ int) {
+		p.drawReason(row)
+	})
+	p.table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyEscape:
+			if p.onBack != nil {
+				p.onBack()
+				return nil
+			}
+		case tcell.KeyEnter:
+			if r, ok := p.selected(); ok && p.onSelected != nil {
+				p.onSelected(r.Kind, r.Namespace, r.Workload)
+				return nil
+			}
+		case tcell.KeyRune:
+			switch key := event.Rune(); key {
+			case 'e':
+				if p.data != nil && p.onExport != nil {
+					p.onExport(p.data.Items)
+				}
+				return nil
+			case 'r':
+				if p.onRefresh != nil {
+					p.SetLoading()
+					p.onRefresh()
+				}
+				return nil
+			default:
+				if p.toggleSort(key) {
+					p.DrawBody(p.data)
+					return nil
+				}
+			}
+		}
+		return event
+	})
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.message, 1, 0, false).
+		AddItem(p.table, 0, 1, true).
+		AddItem(p.reason, 1, 0, false)
+	p.root.SetBorder(true)
+	p.root.SetTitle(fmt.Sprintf(" %s Rightsizing ", ui.Icons.Knobs))
+	p.root.SetTitleAlign(tview.AlignCenter)
+	p.laidout = true
+}
+
+// toggleSort sorts by the column with key, or reverses the direction when
+// the table is already sorted by it. It reports whether key sorts a column.
+func (p *Panel) toggleSort(key rune) bool {
+	for _, col := range columns {
+		if col.key == 0 || col.key != key {
+			continue
+		}
+		if p.sortColumn == col.name {
+			p.sortAsc = !p.sortAsc
+		} else {
+			p.sortColumn, p.sortAsc = col.name, true
+		}
+		return true
+	}
+	return false
+}
+
+// SetLoading shows that usage is being sampled
+func (p *Panel) SetLoading() {
+	p.message.SetText(" [gray]Sampling container usage...")
+}
+
+// SetMessage shows msg, such as the outcome of an export
+func (p *Panel) SetMessage(msg string) {
+	p.message.SetText(" " + msg)
+}
+
+// DrawHeader draws the header row
+func (p *Panel) DrawHeader(_ interface{}) {}
+
+// DrawBody draws a *model.RightsizingData
+func (p *Panel) DrawBody(data interface{}) {
+	rs, ok := data.(*model.RightsizingData)
+	if !ok || rs == nil {
+		return
+	}
+	p.data = rs
+
+	var under, over int
+	for _, r := range rs.Items {
+		switch r.Status {
+		case model.RightsizingUnder:
+			under++
+		case model.RightsizingOver:
+			over++
+		}
+	}
+	p.message.SetText(fmt.Sprintf(
+		" [gray]Usage over the last %s: [red]%d under-provisioned[gray], [yellow]%d over-provisioned[gray]; press e to export the changes as a YAML patch, r to sample again",
+		duration.HumanDuration(rs.Window), under, over))
+
+	model.SortRightsizingBy(rs.Items, p.sortColumn, p.sortAsc)
+
+	// Save current selection before clearing
+	selectedRow, _ := p.table.GetSelection()
+
+	p.table.Clear()
+	for col, c := range columns {
+		p.table.SetCell(0, col, tview.NewTableCell(p.formatHeader(c)).
+			SetTextColor(tcell.ColorWhite).
+			SetBackgroundColor(tcell.ColorDarkCyan).
+			SetSelectable(false).
+			SetExpansion(1))
+	}
+	for i, r := range rs.Items {
+		row := i + 1
+		statusColor := tcell.ColorGreen
+		switch r.Status {
+		case model.RightsizingUnder:
+			statusColor = tcell.ColorRed
+		case model.RightsizingOver:
+			statusColor = tcell.ColorYellow
+		case model.RightsizingNoData:
+			statusColor = tcell.ColorGray
+		}
+
+		usage := func(v float64, format func(float64) string) string {
+			if r.Status == model.RightsizingNoData {
+				return "-"
+			}
+			return format(v)
+		}
+		cells := []*tview.TableCell{
+			tview.NewTableCell(r.Namespace),
+			tview.NewTableCell(r.Workload),
+			tview.NewTableCell(r.Container),
+			tview.NewTableCell(fmt.Sprintf("%d", r.Pods)),
+			tview.NewTableCell(usage(r.CPUP95, formatMillis)),
+			tview.NewTableCell(usage(r.CPUMax, formatMillis)).SetTextColor(tcell.ColorGray),
+			tview.NewTableCell(formatCPU(r.CPURequest) + "/" + formatCPU(r.CPULimit)),
+			tview.NewTableCell(formatCPU(r.RecCPURequest) + "/" + formatCPU(r.RecCPULimit)).SetTextColor(statusColor),
+			tview.NewTableCell(usage(r.MemoryP95, formatBytes)),
+			tview.NewTableCell(usage(r.MemoryMax, formatBytes)).SetTextColor(tcell.ColorGray),
+			tview.NewTableCell(formatMemory(r.MemoryRequest) + "/" + formatMemory(r.MemoryLimit)),
+			tview.NewTableCell(formatMemory(r.RecMemoryRequest) + "/" + formatMemory(r.RecMemoryLimit)).SetTextColor(statusColor),
+			tview.NewTableCell(r.Status).SetTextColor(statusColor),
+		}
+		for col, cell := range cells {
+			p.table.SetCell(row, col, cell)
+		}
+	}
+
+	// Restore selection (clamped to valid range)
+	if len(rs.Items) == 0 {
+		p.reason.SetText(" [gray]No workload pods to sample")
+		return
+	}
+	selectedRow = min(max(selectedRow, 1), len(rs.Items))
+	p.table.Select(selectedRow, 0)
+	p.drawReason(selectedRow)
+}
+
+// formatHeader highlights the sort key of a column header and marks the
+// column the table is sorted by
+func (p *Panel) formatHeader(c column) string {
+	header := c.name
+	if i := strings.IndexRune(c.name, unicode.ToUpper(c.key)); c.key != 0 && i >= 0 {
+		header = fmt.Sprintf("%s[%s::b]%c[%s::-]%s",
+			c.name[:i], ui.Theme.HeaderShortcutKey, c.name[i], ui.Theme.HeaderForeground, c.name[i+1:])
+	}
+	if c.name == p.sortColumn {
+		if p.sortAsc {
+			header += " ▲"
+		} else {
+			header += " ▼"
+		}
+	}
+	return header
+}
+
+// selected returns the recommendation on the selected row
+func (p *Panel) selected() (model.Rightsizing, bool) {
+	row, _ := p.table.GetSelection()
+	if p.data == nil || row < 1 || row > len(p.data.Items) {
+		return model.Rightsizing{}, false
+	}
+	return p.data.Items[row-1], true
+}
+
+// drawReason explains the status of the recommendation on row
+func (p *Panel) drawReason(row int) {
+	if p.data == nil || row < 1 || row > len(p.data.Items) {
+		p.reason.SetText("")
+		return
+	}
+	r := p.data.Items[row-1]
+	text := fmt.Sprintf(" [white]%s %s/%s[gray]: %d samples from %d pods",
+		r.Kind, r.Namespace, r.Workload, r.Samples, r.Pods)
+	if r.Reason != "" {
+		text += "; " + r.Reason
+	}
+	p.reason.SetText(text)
+}
+
+func formatMillis(v float64) string {
+	return fmt.Sprintf("%.0fm", v)
+}
+
+func formatBytes(v float64) string {
+	return ui.FormatBytes(int64(v))
+}
+
+func formatCPU(q *resource.Quantity) string {
+	if q == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%dm", q.MilliValue())
+}
+
+func formatMemory(q *resource.Quantity) string {
+	if q == nil {
+		return "-"
+	}
+	return strings.TrimSpace(ui.FormatMemory(q))
+}
+
+// DrawFooter draws the footer
+func (p *Panel) DrawFooter(_ interface{}) {}
+
+// Clear clears the panel
+func (p *Panel) Clear() {
+	p.table.Clear()
+	p.data = nil
+}
+
+// GetRootView returns the root view
+func (p *Panel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+// GetChildrenViews returns child views
+func (p *Panel) GetChildrenViews() []tview.Primitive {
+	return []tview.Primitive{p.table}
+}
+
+// InitFocus focuses the table
+func (p *Panel) InitFocus() {
+	if p.setAppFocus != nil {
+		p.setAppFocus(p.table)
+	}
+}
+
+// HasEscapableState implements ui.EscapablePanel
+func (p *Panel) HasEscapableState() bool {
+	return true // Always allow ESC to go back
+}
+
+// HandleEscape implements ui.EscapablePanel
+func (p *Panel) HandleEscape() bool {
+	if p.onBack != nil {
+		p.onBack()
+		return true
+	}
+	return false
+}
@@ -0,0 +1,313 @@
+package workload
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/vladimirvivien/ktop/ui"
+	"github.com/vladimirvivien/ktop/views/model"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// PodSelectedCallback is called when a pod is selected in the workload view
+type PodSelectedCallback func(namespace, podName string)
+
+// DetailPanel displays a workload's aggregated resources and the pods it owns
+type DetailPanel struct {
+	root    *tview.Flex
+	data    *model.WorkloadDetailData
+	laidout bool
+
+	// Track current workload to reset the selection when it changes
+	currentKey string
+
+	infoPanel *tview.Flex
+	infoTable *tview.Table
+	podsPanel *tview.Flex
+	podsTable *tview.Table
+
+	setAppFocus func(p tview.Primitive)
+
+	// Callbacks
+	onPodSelected PodSelectedCallback
+	onShowLogs    func(kind, namespace, name string)
+	onActions     func(workload *model.WorkloadModel)
+	onBack        func()
+}
+
+// NewDetailPanel creates a new workload detail panel
+func NewDetailPanel() *DetailPanel {
+	p := &DetailPanel{}
+	p.Layout(nil)
+	return p
+}
+
+// SetOnPodSelected sets the callback for when a pod is selected
+func (p *DetailPanel) SetOnPodSelected(callback PodSelectedCallback) {
+	p.onPodSelected = callback
+}
+
+// SetOnShowLogs sets the callback for tailing the logs of the workload's pods
+func (p *DetailPanel) SetOnShowLogs(callback func(kind, namespace, name string)) {
+	p.onShowLogs = callback
+}
+
+// SetOnActions sets the callback for the actions on the workload
+func (p *DetailPanel) SetOnActions(callback func(workload *model.WorkloadModel)) {
+	p.onActions = callback
+}
+
+// SetOnBack sets the callback for when user navigates back
+func (p *DetailPanel) SetOnBack(callback func()) {
+	p.onBack = callback
+}
+
+// SetAppFocus sets the callback used to focus primitives in the tview app
+func (p *DetailPanel) SetAppFocus(fn func(p tview.Primitive)) {
+	p.setAppFocus = fn
+}
+
+// GetTitle returns the panel title
+func (p *DetailPanel) GetTitle() string {
+	if p.data != nil && p.data.Workload != nil {
+		return fmt.Sprintf("%s: %s", p.data.Workload.Kind, p.data.Workload.Name)
+	}
+	return "Workload Detail"
+}
+
+// Layout initializes the panel UI
+func (p *DetailPanel) Layout(_ interface{}) {
+	if p.laidout {
+		return
+	}
+
+	p.infoTable = tview.NewTable()
+	p.infoTable.SetBorder(false)
+	p.infoTable.SetBorders(false)
+	p.infoTable.SetSelectable(false, false)
+
+	p.infoPanel = tview.NewFlex().SetDirection(tview.FlexRow)
+	p.infoPanel.SetBorder(true)
+	p.infoPanel.SetTitle(" Info ")
+	p.infoPanel.SetTitleAlign(tview.AlignLeft)
+	p.infoPanel.SetBorderColor(tcell.ColorLightGray)
+	p.infoPanel.AddItem(p.infoTable, 0, 1, false)
+
+	p.podsTable = tview.NewTable()
+	p.podsTable.SetFixed(1, 0) // Fixed header row
+	p.podsTable.SetSelectable(true, false)
+	p.podsTable.SetBorder(false)
+	p.podsTable.SetBorders(false)
+	p.podsTable.SetSelectedStyle(tcell.StyleDefault.Background(tcell.ColorLightGray).Foreground(tcell.ColorBlack))
+	p.podsTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyTab, tcell.KeyBacktab:
+			return nil // Only the pods table is focusable
+		case tcell.KeyEscape:
+			if p.onBack != nil {
+				p.onBack()
+				return nil
+			}
+		case tcell.KeyEnter:
+			row, _ := p.podsTable.GetSelection()
+			if row > 0 && p.data != nil && row-1 < len(p.data.Pods) {
+				pod := p.data.Pods[row-1]
+				if p.onPodSelected != nil {
+					p.onPodSelected(pod.Namespace, pod.Name)
+					return nil
+				}
+			}
+		case tcell.KeyRune:
+			if event.Rune() == 'l' && p.data != nil && p.data.Workload != nil && p.onShowLogs != nil {
+				w := p.data.Workload
+				p.onShowLogs(w.Kind, w.Namespace, w.Name)
+				return nil
+			}
+			if event.Rune() == 'x' && p.data != nil && p.data.Workload != nil && p.onActions != nil {
+				p.onActions(p.data.Workload)
+				return nil
+			}
+		}
+		return event
+	})
+
+	p.podsPanel = tview.NewFlex().SetDirection(tview.FlexRow)
+	p.podsPanel.SetBorder(true)
+	p.podsPanel.SetTitle(" Pods ")
+	p.podsPanel.SetTitleAlign(tview.AlignLeft)
+	p.podsPanel.SetBorderColor(tcell.ColorDodgerBlue)
+	p.podsPanel.AddItem(p.podsTable, 0, 1, true)
+
+	p.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(p.infoPanel, 6, 0, false).
+		AddItem(p.podsPanel, 0, 1, true)
+	p.root.SetBorder(true)
+	p.root.SetTitle(fmt.Sprintf(" %s Workload Detail ", ui.Icons.Rocket))
+	p.root.SetTitleAlign(tview.AlignCenter)
+	p.laidout = true
+}
+
+// DrawHeader draws the header row
+func (p *DetailPanel) DrawHeader(_ interface{}) {}
+
+// DrawBody draws the workload info and its pods
+func (p *DetailPanel) DrawBody(data interface{}) {
+	detailData, ok := data.(*model.WorkloadDetailData)
+	if !ok || detailData.Workload == nil {
+		return
+	}
+	p.data = detailData
+
+	w := detailData.Workload
+	if key := w.Key(); key != p.currentKey {
+		p.currentKey = key
+		p.podsTable.Select(1, 0)
+		p.podsTable.ScrollToBeginning()
+	}
+
+	p.root.SetTitle(fmt.Sprintf(" %s Workloads > %s > [::b]%s[::] ", ui.Icons.Rocket, w.Kind, w.Name))
+	p.drawInfo()
+	p.drawPodsTable()
+}
+
+// drawInfo draws workload identity, replica counts and aggregated resources
+func (p *DetailPanel) drawInfo() {
+	p.infoTable.Clear()
+	w := p.data.Workload
+
+	readyColor := tcell.ColorGreen
+	if w.ReadyReplicas < w.DesiredReplicas {
+		readyColor = tcell.ColorYellow
+	}
+	if w.ReadyReplicas == 0 && w.DesiredReplicas > 0 {
+		readyColor = tcell.ColorRed
+	}
+
+	p.addDetailRow(0, 0, "Kind", w.Kind, tcell.ColorWhite)
+	p.addDetailRow(1, 0, "Namespace", w.Namespace, tcell.ColorWhite)
+	p.addDetailRow(2, 0, "Name", w.Name, tcell.ColorWhite)
+	p.addDetailRow(3, 0, "Age", w.TimeSince, tcell.ColorWhite)
+
+	p.addDetailRow(0, 2, "Ready", fmt.Sprintf("%d/%d", w.ReadyReplicas, w.DesiredReplicas), readyColor)
+	p.addDetailRow(1, 2, "Pods", fmt.Sprintf("%d", len(w.Pods)), tcell.ColorWhite)
+	p.addDetailRow(2, 2, "CPU", fmt.Sprintf("%s used / %s requested", formatCPU(w.UsageCpuQty), formatCPU(w.RequestedCpuQty)), tcell.ColorWhite)
+	p.addDetailRow(3, 2, "Memory", fmt.Sprintf("%s used / %s requested", formatMemory(w.UsageMemQty), formatMemory(w.RequestedMemQty)), tcell.ColorWhite)
+}
+
+// addDetailRow adds a key-value pair starting at the given column
+func (p *DetailPanel) addDetailRow(row, col int, key, value string, color tcell.Color) {
+	paddedKey := fmt.Sprintf("%-10s", key)
+	p.infoTable.SetCell(row, col, tview.NewTableCell(paddedKey).SetTextColor(tcell.ColorGray).SetSelectable(false))
+	p.infoTable.SetCell(row, col+1, tview.NewTableCell(value).SetTextColor(color).SetSelectable(false).SetExpansion(1))
+}
+
+// drawPodsTable draws the scrollable pods table
+func (p *DetailPanel) drawPodsTable() {
+	// Save current selection before clearing
+	selectedRow, selectedCol := p.podsTable.GetSelection()
+
+	p.podsTable.Clear()
+	p.podsPanel.SetTitle(fmt.Sprintf(" Pods (%d) ", len(p.data.Pods)))
+
+	headers := []string{"NAME", "STATUS", "READY", "RESTARTS", "NODE", "CPU", "MEM", "AGE"}
+	for col, header := range headers {
+		cell := tview.NewTableCell(header).
+			SetTextColor(tcell.ColorWhite).
+			SetBackgroundColor(tcell.ColorDarkCyan).
+			SetSelectable(false).
+			SetExpansion(1)
+		p.podsTable.SetCell(0, col, cell)
+	}
+
+	if len(p.data.Pods) == 0 {
+		return
+	}
+
+	for row, pod := range p.data.Pods {
+		rowIdx := row + 1 // Offset for header
+
+		statusColor := ui.GetTcellColor(ui.GetStatusColor(pod.Status, "pod"))
+		restartColor := tcell.ColorGreen
+		if pod.Restarts > 0 {
+			restartColor = tcell.ColorYellow
+		}
+		if pod.Restarts > 5 {
+			restartColor = tcell.ColorRed
+		}
+
+		p.podsTable.SetCell(rowIdx, 0, tview.NewTableCell(pod.Name).SetTextColor(tcell.ColorWhite).SetMaxWidth(40))
+		p.podsTable.SetCell(rowIdx, 1, tview.NewTableCell(pod.Status).SetTextColor(statusColor))
+		p.podsTable.SetCell(rowIdx, 2, tview.NewTableCell(fmt.Sprintf("%d/%d", pod.ReadyContainers, pod.TotalContainers)).SetTextColor(tcell.ColorWhite))
+		p.podsTable.SetCell(rowIdx, 3, tview.NewTableCell(fmt.Sprintf("%d", pod.Restarts)).SetTextColor(restartColor))
+		p.podsTable.SetCell(rowIdx, 4, tview.NewTableCell(pod.Node).SetTextColor(tcell.ColorWhite).SetMaxWidth(20))
+		p.podsTable.SetCell(rowIdx, 5, tview.NewTableCell(formatCPU(pod.PodUsageCpuQty)).SetTextColor(tcell.ColorWhite))
+		p.podsTable.SetCell(rowIdx, 6, tview.NewTableCell(formatMemory(pod.PodUsageMemQty)).SetTextColor(tcell.ColorWhite))
+		p.podsTable.SetCell(rowIdx, 7, tview.NewTableCell(pod.TimeSince).SetTextColor(tcell.ColorGray))
+	}
+
+	// Restore selection (clamped to valid range)
+	maxRow := len(p.data.Pods)
+	if selectedRow < 1 {
+		selectedRow = 1
+	} else if selectedRow > maxRow {
+		selectedRow = maxRow
+	}
+	p.podsTable.Select(selectedRow, selectedCol)
+}
+
+func formatCPU(q *resource.Quantity) string {
+	if q == nil {
+		return "n/a"
+	}
+	return fmt.Sprintf("%dm", q.MilliValue())
+}
+
+func formatMemory(q *resource.Quantity) string {
+	if q == nil {
+		return "n/a"
+	}
+	return ui.FormatMemory(q)
+}
+
+// DrawFooter draws the footer
+func (p *DetailPanel) DrawFooter(_ interface{}) {}
+
+// Clear clears the panel
+func (p *DetailPanel) Clear() {
+	p.infoTable.Clear()
+	p.podsTable.Clear()
+	p.data = nil
+	p.currentKey = ""
+}
+
+// GetRootView returns the root view
+func (p *DetailPanel) GetRootView() tview.Primitive {
+	return p.root
+}
+
+// GetChildrenViews returns child views
+func (p *DetailPanel) GetChildrenViews() []tview.Primitive {
+	return nil
+}
+
+// InitFocus focuses the pods table when the page is shown
+func (p *DetailPanel) InitFocus() {
+	if p.setAppFocus != nil {
+		p.setAppFocus(p.podsTable)
+	}
+}
+
+// HasEscapableState implements ui.EscapablePanel
+func (p *DetailPanel) HasEscapableState() bool {
+	return true // Always allow ESC to go back
+}
+
+// HandleEscape implements ui.EscapablePanel
+func (p *DetailPanel) HandleEscape() bool {
+	if p.onBack != nil {
+		p.onBack()
+		return true
+	}
+	return false
+}